@@ -0,0 +1,58 @@
+// Package main 提供知识库存证合约部署工具
+// 使用 blockchain 配置中的节点与签名私钥部署 contracts/KnowledgeAnchor.easm，
+// 部署账户成为合约 owner，输出的合约地址需填入 blockchain.contract_address
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+)
+
+var (
+	configPath string
+	timeout    time.Duration
+)
+
+func init() {
+	flag.StringVar(&configPath, "config", "config.yaml", "配置文件路径")
+	flag.DurationVar(&timeout, "timeout", 5*time.Minute, "等待部署交易确认的超时时间")
+}
+
+func main() {
+	flag.Parse()
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Printf("加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 部署前合约地址尚不存在，忽略已配置的地址
+	chainCfg := cfg.Blockchain
+	chainCfg.ContractAddress = ""
+
+	c, err := client.NewBlockchainClient(&chainCfg)
+	if err != nil {
+		fmt.Printf("连接区块链节点失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	fmt.Printf("部署账户: %s\n", c.Account())
+	address, err := c.DeployAnchorContract(ctx)
+	if err != nil {
+		fmt.Printf("部署存证合约失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("存证合约已部署: %s\n", address)
+	fmt.Printf("请设置 LEXVERITAS_BLOCKCHAIN_CONTRACT_ADDRESS=%s\n", address)
+}
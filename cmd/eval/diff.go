@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// printDiff 对比两次评测的指标、节点耗时与各问题的变化并输出到 out
+// 仅对比两次都包含的问题 (按 id 匹配)
+func printDiff(out io.Writer, base, head *runReport) {
+	fmt.Fprintf(out, "对比: %s (%s) → %s (%s)\n", reportLabel(base), base.CreatedAt.Format(time.DateTime), reportLabel(head), head.CreatedAt.Format(time.DateTime))
+	if base.K != head.K {
+		fmt.Fprintf(out, "注意: 两次评测的 k 不同 (%d / %d)，recall@k 不可直接比较\n", base.K, head.K)
+	}
+	if b, h := settingFlags(base.Settings).String(), settingFlags(head.Settings).String(); b != h {
+		fmt.Fprintf(out, "系统配置覆盖: [%s] → [%s]\n", b, h)
+	}
+	if base.Golden != head.Golden {
+		fmt.Fprintf(out, "注意: 两次评测使用的黄金问答集不同 (%s / %s)\n", base.Golden, head.Golden)
+	}
+
+	b, h := base.Summary, head.Summary
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "指标\tbase\thead\t变化\t")
+	for _, m := range []struct {
+		name       string
+		base, head float64
+	}{
+		{fmt.Sprintf("recall@%d", head.K), b.RecallAtK, h.RecallAtK},
+		{"MRR", b.MRR, h.MRR},
+		{"引用精确率", b.CitationPrecision, h.CitationPrecision},
+		{"拒答率", b.RefusalRate, h.RefusalRate},
+		{"误拒率", b.FalseRefusalRate, h.FalseRefusalRate},
+		{"正确拒答率", b.CorrectRefusalRate, h.CorrectRefusalRate},
+	} {
+		fmt.Fprintf(w, "%s\t%.3f\t%.3f\t%+.3f\t\n", m.name, m.base, m.head, m.head-m.base)
+	}
+	_ = w.Flush()
+
+	fmt.Fprintln(out, "\n耗时 P50 / P95 (ms):")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "节点\tbase\thead\t变化 (P50)\t")
+	fmt.Fprintf(w, "(总计)\t%d / %d\t%d / %d\t%+d\t\n", b.Latency.P50Ms, b.Latency.P95Ms, h.Latency.P50Ms, h.Latency.P95Ms, h.Latency.P50Ms-b.Latency.P50Ms)
+	baseNodes := make(map[string]nodeLatency, len(b.Nodes))
+	for _, n := range b.Nodes {
+		baseNodes[n.Name] = n
+	}
+	seen := make(map[string]bool, len(h.Nodes))
+	for _, n := range h.Nodes {
+		seen[n.Name] = true
+		if bn, ok := baseNodes[n.Name]; ok {
+			fmt.Fprintf(w, "%s\t%d / %d\t%d / %d\t%+d\t\n", n.Name, bn.P50Ms, bn.P95Ms, n.P50Ms, n.P95Ms, n.P50Ms-bn.P50Ms)
+		} else {
+			fmt.Fprintf(w, "%s\t-\t%d / %d\t新增\t\n", n.Name, n.P50Ms, n.P95Ms)
+		}
+	}
+	for _, n := range b.Nodes {
+		if !seen[n.Name] {
+			fmt.Fprintf(w, "%s\t%d / %d\t-\t移除\t\n", n.Name, n.P50Ms, n.P95Ms)
+		}
+	}
+	_ = w.Flush()
+
+	printItemChanges(out, base, head)
+}
+
+// printItemChanges 输出检索或拒答结果发生变化的问题
+func printItemChanges(out io.Writer, base, head *runReport) {
+	baseItems := make(map[string]itemResult, len(base.Items))
+	for _, item := range base.Items {
+		baseItems[item.ID] = item
+	}
+
+	var improved, regressed []string
+	for _, hi := range head.Items {
+		bi, ok := baseItems[hi.ID]
+		if !ok {
+			continue
+		}
+		delta := itemScore(hi) - itemScore(bi)
+		if delta == 0 && hi.Refused == bi.Refused {
+			continue
+		}
+		line := fmt.Sprintf("  %s recall %.2f → %.2f，RR %.2f → %.2f", hi.ID, bi.Recall, hi.Recall, bi.ReciprocalRank, hi.ReciprocalRank)
+		if hi.Refused != bi.Refused {
+			line += fmt.Sprintf("，拒答 %s → %s", refusalLabel(bi), refusalLabel(hi))
+		}
+		if bi.Error == "" && hi.Error != "" {
+			line += "，出错: " + hi.Error
+		}
+		if delta > 0 {
+			improved = append(improved, line)
+		} else {
+			regressed = append(regressed, line)
+		}
+	}
+
+	if len(improved) == 0 && len(regressed) == 0 {
+		fmt.Fprintln(out, "\n各问题结果无变化")
+		return
+	}
+	if len(regressed) > 0 {
+		fmt.Fprintf(out, "\n退化 (%d):\n", len(regressed))
+		for _, line := range regressed {
+			fmt.Fprintln(out, line)
+		}
+	}
+	if len(improved) > 0 {
+		fmt.Fprintf(out, "\n改善 (%d):\n", len(improved))
+		for _, line := range improved {
+			fmt.Fprintln(out, line)
+		}
+	}
+}
+
+// itemScore 单个问题的综合得分，用于判断改善或退化
+// 应拒答的问题以是否拒答计分，其余以 recall@k 与倒数名次计分；出错计 0 分
+func itemScore(item itemResult) float64 {
+	if item.Error != "" {
+		return 0
+	}
+	if item.ExpectRefusal {
+		if item.Refused {
+			return 1
+		}
+		return 0
+	}
+	if item.Refused {
+		return 0
+	}
+	return item.Recall + item.ReciprocalRank
+}
+
+// refusalLabel 拒答情况描述
+func refusalLabel(item itemResult) string {
+	if !item.Refused {
+		return "否"
+	}
+	return item.RefusalReason
+}
+
+// reportLabel 评测来源描述
+func reportLabel(r *runReport) string {
+	label := r.LLM
+	if r.Model != "" {
+		label += " " + r.Model
+	}
+	if r.PromptVersion != "" {
+		label += ", " + r.PromptVersion
+	}
+	return label
+}
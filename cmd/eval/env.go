@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/docparse"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	// manifestFile 语料目录中说明各文件法律名称与时效的清单
+	manifestFile = "manifest.jsonl"
+	// dateLayout 清单与黄金问答集中的日期格式
+	dateLayout = "2006-01-02"
+	// evalUploader 评测语料的上传者标识
+	evalUploader = "eval"
+)
+
+// environment 离线评测环境: SQLite 内存数据库 + 哈希向量化 + 内存向量库
+type environment struct {
+	cfg      *config.Config
+	llmMode  string
+	settings map[string]string
+	graph    *graph.Builder
+	docSvc   service.DocumentService
+	docRepo  repository.DocumentRepository
+	fakeLLM  *httptest.Server
+}
+
+// newEnvironment 创建评测环境并构建 RAG Graph
+// 检索与查询理解沿用配置文件，settings 覆盖系统配置 (拒答阈值、提示词版本等)；
+// 重排依赖外部服务，离线评测不启用
+func newEnvironment(ctx context.Context, cfg *config.Config, llmMode string, settings map[string]string) (*environment, error) {
+	if err := openDatabase(ctx); err != nil {
+		return nil, err
+	}
+
+	env := &environment{
+		cfg:      cfg,
+		llmMode:  llmMode,
+		settings: settings,
+		docRepo:  repository.NewDocumentRepository(),
+	}
+	systemConfigSvc := service.NewSystemConfigService()
+	for key, value := range settings {
+		if _, err := systemConfigSvc.Update(ctx, key, &dto.UpdateSystemConfigRequest{Value: value}, evalUploader); err != nil {
+			env.Close()
+			return nil, fmt.Errorf("覆盖系统配置 %s 失败: %w", key, err)
+		}
+	}
+
+	llmCfg := cfg.LLM
+	if llmMode == llmFake {
+		env.fakeLLM = newFakeLLM()
+		llmCfg = config.LLMConfig{BaseURL: env.fakeLLM.URL, Model: fakeModel}
+	}
+
+	dimension := cfg.Milvus.Dimension
+	embedder := client.NewHashEmbedder(dimension)
+	store := client.NewMemoryVectorStore(dimension)
+	env.docSvc = service.NewDocumentService(&cfg.Document, client.NewCachedEmbedder(embedder, client.NewMemoryEmbeddingCache()), store)
+
+	llmNode := nodes.NewLLMNode(client.NewLLMClient(&llmCfg))
+	graphNodes := graph.Nodes{
+		Retriever: nodes.NewRetriever(embedder, store, nodes.RetrieverOptions{
+			TopK:       cfg.Retrieval.TopK,
+			Hybrid:     cfg.Retrieval.Hybrid,
+			CandidateK: cfg.Retrieval.CandidateK,
+			RRFK:       cfg.Retrieval.RRFK,
+		}),
+		Validity: nodes.NewValidityChecker(),
+		Verifier: nodes.NewVerifier(nil),
+		Refusal:  nodes.NewRefusalPolicy(systemConfigSvc),
+		Prompt:   nodes.NewPromptBuilder(systemConfigSvc),
+		LLM:      llmNode,
+		// 离线评测没有链上验证，全部条文均为未验证: 引用校验固定为标注模式且不重新生成，
+		// 否则回答中的引用会被删除或每题都重新生成一次
+		Checker: nodes.NewCitationChecker(nodes.CitationModeFlag, false),
+	}
+	if cfg.Retrieval.ExpandHops > 0 {
+		graphNodes.Expander = nodes.NewReferenceExpander(cfg.Retrieval.ExpandHops, cfg.Retrieval.ExpandMax)
+	}
+	if qa := cfg.Graph.QueryAnalysis; qa.Enabled {
+		graphNodes.Analyzer = nodes.NewQueryAnalyzer(llmNode, nodes.QueryAnalyzerOptions{
+			MaxQueries:    qa.MaxQueries,
+			MinConfidence: qa.MinConfidence,
+			LawTypes:      qa.LawTypes,
+		})
+	}
+	env.graph = graph.NewBuilder(graphNodes, cfg.Graph.Timeouts)
+	if err := env.graph.Build(); err != nil {
+		env.Close()
+		return nil, err
+	}
+	return env, nil
+}
+
+// openDatabase 打开 SQLite 内存数据库并建表、写入系统配置默认值
+// PostgreSQL 全文检索在 SQLite 中不可用，混合检索降级为向量检索 + 条号精确检索
+func openDatabase(ctx context.Context) error {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:                 gormlogger.Default.LogMode(gormlogger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		return fmt.Errorf("打开 SQLite 内存数据库失败: %w", err)
+	}
+	// 内存数据库按连接隔离，只能使用单个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		return fmt.Errorf("建表失败: %w", err)
+	}
+	database.Use(db)
+	return repository.NewSystemConfigRepository().CreateMissing(ctx, model.DefaultSystemConfigs())
+}
+
+// Close 关闭模拟 LLM 与数据库
+func (e *environment) Close() {
+	if e.fakeLLM != nil {
+		e.fakeLLM.Close()
+	}
+	_ = database.Close()
+}
+
+// corpusEntry 语料清单条目，未列入清单的文件以文件名作为法律名称
+type corpusEntry struct {
+	File          string `json:"file"`
+	LawName       string `json:"lawName"`
+	LawType       string `json:"lawType"`
+	EffectiveDate string `json:"effectiveDate"` // YYYY-MM-DD
+	ExpiryDate    string `json:"expiryDate"`    // YYYY-MM-DD
+}
+
+// ingest 登记语料目录中的文档并通过正式导入流水线分块、向量化，返回文档数与分块数
+func (e *environment) ingest(ctx context.Context, dir string) (int, int, error) {
+	manifest, err := loadManifest(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return 0, 0, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ids []string
+	for _, f := range files {
+		if f.IsDir() || f.Name() == manifestFile {
+			continue
+		}
+		docType, ok := docparse.DetectType(f.Name())
+		if !ok {
+			continue
+		}
+		entry, ok := manifest[f.Name()]
+		if !ok {
+			entry = corpusEntry{LawName: strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))}
+		}
+		doc, err := newCorpusDocument(filepath.Join(dir, f.Name()), docType, entry)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+		if err := e.docRepo.Create(ctx, doc); err != nil {
+			return 0, 0, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	if len(ids) == 0 {
+		return 0, 0, errors.New("语料目录中没有可导入的文档")
+	}
+
+	if _, err := e.docSvc.ProcessPending(ctx); err != nil {
+		return 0, 0, err
+	}
+	docs, err := e.docRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return 0, 0, err
+	}
+	chunks := 0
+	for _, doc := range docs {
+		if doc.Status == model.DocStatusError {
+			return 0, 0, fmt.Errorf("%s 导入失败: %s", doc.Name, doc.ProcessError)
+		}
+		chunks += doc.ChunkCount
+	}
+	return len(docs), chunks, nil
+}
+
+// newCorpusDocument 构造待导入的语料文档
+func newCorpusDocument(path string, docType model.DocumentType, entry corpusEntry) (*model.Document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	effectiveDate, err := parseOptionalDate(entry.EffectiveDate)
+	if err != nil {
+		return nil, err
+	}
+	expiryDate, err := parseOptionalDate(entry.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Document{
+		ID:            uuid.New().String(),
+		Name:          entry.LawName,
+		OriginalName:  filepath.Base(path),
+		Type:          docType,
+		Size:          info.Size(),
+		FilePath:      path,
+		LawName:       entry.LawName,
+		LawType:       entry.LawType,
+		EffectiveDate: effectiveDate,
+		ExpiryDate:    expiryDate,
+		Status:        model.DocStatusPending,
+		UploadedBy:    evalUploader,
+	}, nil
+}
+
+// loadManifest 读取语料清单，文件不存在时返回空清单
+func loadManifest(path string) (map[string]corpusEntry, error) {
+	manifest := make(map[string]corpusEntry)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var entry corpusEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, fmt.Errorf("%s 第 %d 行: %w", manifestFile, line, err)
+		}
+		if entry.LawName == "" {
+			entry.LawName = strings.TrimSuffix(entry.File, filepath.Ext(entry.File))
+		}
+		manifest[entry.File] = entry
+	}
+	return manifest, scanner.Err()
+}
+
+// parseOptionalDate 解析 YYYY-MM-DD，为空时返回 nil
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("日期格式应为 YYYY-MM-DD: %s", value)
+	}
+	return &t, nil
+}
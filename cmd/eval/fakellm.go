@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+)
+
+// LLM 来源 (-llm)
+const (
+	llmFake   = "fake"   // 内置确定性模拟模型
+	llmConfig = "config" // 配置文件中的 LLM 服务
+)
+
+const (
+	// fakeModel 模拟模型名称
+	fakeModel = "eval-fake"
+	// analysisQuestionPrefix 查询理解请求中最新问题的前缀
+	analysisQuestionPrefix = "最新问题："
+	// fakeNoContextAnswer 提示词中没有参考法条时的回答
+	fakeNoContextAnswer = "现有条文不足以回答该问题。"
+)
+
+// promptChunkPattern 提示词中参考法条的序号行，如 "[1] 中华人民共和国民法典【未验证】"
+var promptChunkPattern = regexp.MustCompile(`(?m)^\[(\d+)\] `)
+
+// fakeRequest 模拟模型接收的请求 (OpenAI Chat Completions 格式)
+type fakeRequest struct {
+	Messages []client.Message `json:"messages"`
+	Stream   bool             `json:"stream"`
+}
+
+// newFakeLLM 启动 OpenAI 兼容接口的确定性模拟模型
+// 查询理解请求原样返回问题 (不分类、不改写)；回答请求依次引用提示词中的全部参考法条。
+// 模拟模型不衡量生成质量，此时引用精确率反映装入提示词的法条质量
+func newFakeLLM() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req fakeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) == 0 {
+			http.Error(w, `{"error":{"message":"invalid request"}}`, http.StatusBadRequest)
+			return
+		}
+
+		var content string
+		if question, ok := analysisQuestion(req.Messages); ok && !req.Stream {
+			out, _ := json.Marshal(map[string]interface{}{
+				"domain":     "other",
+				"confidence": 0,
+				"statutes":   []interface{}{},
+				"queries":    []string{question},
+			})
+			content = string(out)
+		} else {
+			content = fakeAnswer(req.Messages[0].Content)
+		}
+
+		var prompt strings.Builder
+		for _, m := range req.Messages {
+			prompt.WriteString(m.Content)
+		}
+		usage := map[string]int{
+			"prompt_tokens":     client.EstimateTokens(prompt.String()),
+			"completion_tokens": client.EstimateTokens(content),
+		}
+		usage["total_tokens"] = usage["prompt_tokens"] + usage["completion_tokens"]
+
+		if !req.Stream {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"model": fakeModel,
+				"choices": []map[string]interface{}{{
+					"message":       map[string]string{"role": "assistant", "content": content},
+					"finish_reason": "stop",
+				}},
+				"usage": usage,
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, piece := range []map[string]interface{}{
+			{"choices": []map[string]interface{}{{"delta": map[string]string{"content": content}}}},
+			{"choices": []map[string]interface{}{}, "usage": usage},
+		} {
+			data, _ := json.Marshal(piece)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+// analysisQuestion 识别查询理解请求并取出最新问题
+func analysisQuestion(messages []client.Message) (string, bool) {
+	last := messages[len(messages)-1].Content
+	idx := strings.LastIndex(last, analysisQuestionPrefix)
+	if idx < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(last[idx+len(analysisQuestionPrefix):]), true
+}
+
+// fakeAnswer 依次引用系统提示词中的全部参考法条
+func fakeAnswer(systemPrompt string) string {
+	matches := promptChunkPattern.FindAllStringSubmatch(systemPrompt, -1)
+	if len(matches) == 0 {
+		return fakeNoContextAnswer
+	}
+	var sb strings.Builder
+	sb.WriteString("根据参考法条")
+	for _, m := range matches {
+		sb.WriteString(" [" + m[1] + "]")
+	}
+	sb.WriteString("，上述问题应依照相关规定处理。")
+	return sb.String()
+}
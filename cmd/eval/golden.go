@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+)
+
+// goldenItem 黄金问答集条目 (JSONL 每行一条)
+//
+//	{"id": "labor-001", "question": "老板拖欠工资怎么办", "expected": ["劳动合同法:30", "劳动合同法:85"]}
+//	{"id": "ctx-002", "question": "那它的诉讼时效呢", "history": [{"role": "user", "content": "..."}], "expected": ["民法典:188"]}
+//	{"id": "oos-001", "question": "今天天气怎么样", "expectRefusal": true}
+type goldenItem struct {
+	ID            string       `json:"id"`
+	Question      string       `json:"question"`
+	History       []goldenTurn `json:"history,omitempty"`
+	AsOfDate      string       `json:"asOfDate,omitempty"`      // YYYY-MM-DD，按该日有效的法律回答
+	Expected      []string     `json:"expected,omitempty"`      // 应检索到的条文 "法律名称:条号"，法律名称可省略 "中华人民共和国"
+	ExpectRefusal bool         `json:"expectRefusal,omitempty"` // 是否应当拒答 (知识库外的问题)
+	expected      []articleKey // 规范化后的 Expected
+}
+
+// goldenTurn 对话历史消息
+type goldenTurn struct {
+	Role    string `json:"role"` // user | assistant
+	Content string `json:"content"`
+}
+
+// articleKey 条文标识 "法律名称:条号" (法律名称去除书名号与 "中华人民共和国" 前缀，条号为阿拉伯数字)
+// 以法律与条号而非分块 ID 标识条文，调整分块方式后黄金问答集仍然有效
+type articleKey string
+
+// newArticleKey 构造条文标识
+func newArticleKey(law, article string) articleKey {
+	law = strings.TrimPrefix(strings.Trim(strings.TrimSpace(law), "《》"), "中华人民共和国")
+	return articleKey(law + ":" + article)
+}
+
+// parseArticleKey 解析 "法律名称:条号"，条号兼容 "1165"、"第一千一百六十五条"
+func parseArticleKey(value string) (articleKey, error) {
+	value = strings.ReplaceAll(value, "：", ":")
+	idx := strings.LastIndex(value, ":")
+	if idx <= 0 || idx == len(value)-1 {
+		return "", fmt.Errorf("条文标识应为 \"法律名称:条号\": %s", value)
+	}
+	law, article := value[:idx], strings.TrimSpace(value[idx+1:])
+	if !strings.HasPrefix(article, "第") {
+		article = "第" + article
+	}
+	if !strings.HasSuffix(article, "条") {
+		article += "条"
+	}
+	refs := chunker.FindReferences(article)
+	if len(refs) != 1 || refs[0].ToArticle != "" {
+		return "", fmt.Errorf("无法解析条号: %s", value)
+	}
+	return newArticleKey(law, refs[0].Article), nil
+}
+
+// loadGolden 读取黄金问答集
+func loadGolden(path string) ([]goldenItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		items []goldenItem
+		ids   = make(map[string]bool)
+	)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "//") {
+			continue
+		}
+		var item goldenItem
+		if err := json.Unmarshal([]byte(text), &item); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", line, err)
+		}
+		if item.Question == "" {
+			return nil, fmt.Errorf("第 %d 行: 缺少 question", line)
+		}
+		if item.ID == "" {
+			item.ID = fmt.Sprintf("line-%d", line)
+		}
+		if ids[item.ID] {
+			return nil, fmt.Errorf("第 %d 行: id 重复: %s", line, item.ID)
+		}
+		ids[item.ID] = true
+		if _, err := parseOptionalDate(item.AsOfDate); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", line, err)
+		}
+		if len(item.Expected) == 0 && !item.ExpectRefusal {
+			return nil, fmt.Errorf("第 %d 行: expected 为空时应设置 expectRefusal", line)
+		}
+		for _, e := range item.Expected {
+			key, err := parseArticleKey(e)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", line, err)
+			}
+			item.expected = append(item.expected, key)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("黄金问答集为空")
+	}
+	return items, nil
+}
@@ -0,0 +1,165 @@
+// Package main 提供 RAG 离线评测工具
+// 使用确定性哈希向量化、内存向量库与 SQLite 内存数据库导入评测语料，对黄金问答集逐题执行 RAG Graph，
+// 统计 recall@k、MRR、引用精确率、拒答率与各节点耗时，并可与历史评测结果对比，
+// 用于评估分块、提示词、检索参数与模型改动的影响。
+//
+// 执行评测并保存结果:
+//
+//	go run ./cmd/eval -golden eval/golden.jsonl -corpus eval/corpus -out eval/runs/baseline.json
+//
+// 执行评测并与历史结果对比，或仅对比两次结果:
+//
+//	go run ./cmd/eval -golden eval/golden.jsonl -corpus eval/corpus -base eval/runs/baseline.json
+//	go run ./cmd/eval -base eval/runs/baseline.json -head eval/runs/new.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+)
+
+var (
+	configPath string
+	goldenPath string
+	corpusDir  string
+	outPath    string
+	basePath   string
+	headPath   string
+	llmMode    string
+	topK       int
+	logLevel   string
+	settings   = make(settingFlags)
+)
+
+func init() {
+	flag.StringVar(&configPath, "config", "config.yaml", "配置文件路径 (使用其中的检索、查询理解、引用校验与 LLM 配置)")
+	flag.StringVar(&goldenPath, "golden", "", "黄金问答集 JSONL 文件路径")
+	flag.StringVar(&corpusDir, "corpus", "", "评测语料目录 (法律文本文件，可选 manifest.jsonl 说明各文件的法律名称与时效)")
+	flag.StringVar(&outPath, "out", "", "评测结果 JSON 输出路径 (可选)")
+	flag.StringVar(&basePath, "base", "", "用于对比的历史评测结果 (可选)")
+	flag.StringVar(&headPath, "head", "", "与 -base 对比的评测结果，指定后不执行评测 (可选)")
+	flag.StringVar(&llmMode, "llm", llmFake, "LLM 来源: fake 使用内置确定性模拟模型; config 使用配置文件中的 LLM 服务")
+	flag.IntVar(&topK, "k", 5, "recall@k 的 k")
+	flag.StringVar(&logLevel, "log-level", "error", "日志级别 (节点降级等告警默认不输出)")
+	flag.Var(settings, "set", "覆盖系统配置，可重复，如 -set prompt.version=legal_qa_v2 -set retrieval.min_score=0.3")
+}
+
+// settingFlags 系统配置覆盖 (key=value)
+type settingFlags map[string]string
+
+// String 实现 flag.Value
+func (s settingFlags) String() string {
+	parts := make([]string, 0, len(s))
+	for k, v := range s {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// Set 实现 flag.Value
+func (s settingFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("应为 key=value: %s", value)
+	}
+	s[key] = val
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	if headPath != "" {
+		if basePath == "" {
+			fmt.Println("请使用 -base 指定对比的历史评测结果")
+			os.Exit(2)
+		}
+		base, err := loadReport(basePath)
+		if err != nil {
+			fmt.Printf("读取评测结果失败: %v\n", err)
+			os.Exit(1)
+		}
+		head, err := loadReport(headPath)
+		if err != nil {
+			fmt.Printf("读取评测结果失败: %v\n", err)
+			os.Exit(1)
+		}
+		printDiff(os.Stdout, base, head)
+		return
+	}
+
+	if goldenPath == "" || corpusDir == "" {
+		fmt.Println("请使用 -golden 指定黄金问答集、-corpus 指定评测语料目录")
+		os.Exit(2)
+	}
+	if llmMode != llmFake && llmMode != llmConfig {
+		fmt.Printf("不支持的 LLM 来源: %s (可选 fake、config)\n", llmMode)
+		os.Exit(2)
+	}
+	if topK <= 0 {
+		fmt.Println("-k 必须大于 0")
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Printf("加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+	_ = logger.Init(&logger.Config{
+		Level:  logLevel,
+		Format: "console",
+		Output: "stdout",
+	})
+
+	items, err := loadGolden(goldenPath)
+	if err != nil {
+		fmt.Printf("读取黄金问答集失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	env, err := newEnvironment(ctx, cfg, llmMode, settings)
+	if err != nil {
+		fmt.Printf("初始化评测环境失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer env.Close()
+
+	docs, chunks, err := env.ingest(ctx, corpusDir)
+	if err != nil {
+		fmt.Printf("导入评测语料失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("已导入语料: %d 个文档，%d 个分块\n", docs, chunks)
+
+	report := env.run(ctx, items, topK)
+	report.Golden = goldenPath
+	report.Corpus = corpusDir
+	printSummary(report)
+
+	if outPath != "" {
+		if err := saveReport(outPath, report); err != nil {
+			fmt.Printf("保存评测结果失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("评测结果已保存: %s\n", outPath)
+	}
+	if basePath != "" {
+		base, err := loadReport(basePath)
+		if err != nil {
+			fmt.Printf("读取评测结果失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println()
+		printDiff(os.Stdout, base, report)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runReport 一次评测的结果
+type runReport struct {
+	CreatedAt     time.Time         `json:"createdAt"`
+	Golden        string            `json:"golden"`
+	Corpus        string            `json:"corpus"`
+	LLM           string            `json:"llm"`             // fake | config
+	Model         string            `json:"model,omitempty"` // 使用配置的 LLM 服务时的模型名称
+	PromptVersion string            `json:"promptVersion,omitempty"`
+	Settings      map[string]string `json:"settings,omitempty"` // 覆盖的系统配置
+	K             int               `json:"k"`
+	Summary       summary           `json:"summary"`
+	Items         []itemResult      `json:"items"`
+}
+
+// summary 评测指标汇总 (执行出错的问题不计入指标)
+type summary struct {
+	Questions          int           `json:"questions"`
+	Answerable         int           `json:"answerable"` // 有期望条文的问题数
+	Errors             int           `json:"errors"`
+	RecallAtK          float64       `json:"recallAtK"`          // 应回答问题的平均 recall@k
+	MRR                float64       `json:"mrr"`                // 应回答问题的平均倒数名次
+	CitationPrecision  float64       `json:"citationPrecision"`  // 有引用的应回答问题的平均引用精确率
+	RefusalRate        float64       `json:"refusalRate"`        // 全部问题中拒答的比例
+	FalseRefusalRate   float64       `json:"falseRefusalRate"`   // 应回答问题中拒答的比例
+	CorrectRefusalRate float64       `json:"correctRefusalRate"` // 应拒答问题中拒答的比例
+	Latency            latencyStats  `json:"latency"`            // 完整问答耗时
+	Nodes              []nodeLatency `json:"nodes"`              // 各节点耗时 (按执行顺序)
+}
+
+// latencyStats 耗时统计 (毫秒)
+type latencyStats struct {
+	Count  int     `json:"count"`
+	MeanMs float64 `json:"meanMs"`
+	P50Ms  int64   `json:"p50Ms"`
+	P95Ms  int64   `json:"p95Ms"`
+	MaxMs  int64   `json:"maxMs"`
+}
+
+// nodeLatency 节点耗时统计，跳过的节点不计入
+type nodeLatency struct {
+	Name     string `json:"name"`
+	Failures int    `json:"failures"` // 失败或超时次数
+	latencyStats
+}
+
+// summarize 汇总各问题的评测结果
+func summarize(items []itemResult) summary {
+	var (
+		s                                   summary
+		total                               []int64
+		recall, rr, precision               float64
+		precisionCount, refused, answerable int
+		falseRefused                        int
+		expectRefusal, refusedWhenExpected  int
+		nodeOrder                           []string
+		nodeSamples                         = make(map[string][]int64)
+		nodeFailures                        = make(map[string]int)
+	)
+	s.Questions = len(items)
+	for _, item := range items {
+		if len(item.Expected) > 0 {
+			s.Answerable++
+		}
+		if item.Error != "" {
+			s.Errors++
+			continue
+		}
+		total = append(total, item.LatencyMs)
+		for _, n := range item.Nodes {
+			if n.Status == "skipped" {
+				continue
+			}
+			if _, ok := nodeSamples[n.Name]; !ok {
+				nodeOrder = append(nodeOrder, n.Name)
+			}
+			nodeSamples[n.Name] = append(nodeSamples[n.Name], n.LatencyMs)
+			if n.Status == "failed" || n.Status == "timeout" {
+				nodeFailures[n.Name]++
+			}
+		}
+
+		if item.Refused {
+			refused++
+		}
+		if item.ExpectRefusal {
+			expectRefusal++
+			if item.Refused {
+				refusedWhenExpected++
+			}
+		}
+		if len(item.Expected) == 0 {
+			continue
+		}
+		answerable++
+		recall += item.Recall
+		rr += item.ReciprocalRank
+		if item.CitationPrecision != nil {
+			precision += *item.CitationPrecision
+			precisionCount++
+		}
+		if item.Refused && !item.ExpectRefusal {
+			falseRefused++
+		}
+	}
+
+	evaluated := s.Questions - s.Errors
+	s.RefusalRate = ratio(float64(refused), evaluated)
+	s.RecallAtK = ratio(recall, answerable)
+	s.MRR = ratio(rr, answerable)
+	s.CitationPrecision = ratio(precision, precisionCount)
+	s.FalseRefusalRate = ratio(float64(falseRefused), answerable)
+	s.CorrectRefusalRate = ratio(float64(refusedWhenExpected), expectRefusal)
+	s.Latency = newLatencyStats(total)
+	for _, name := range nodeOrder {
+		s.Nodes = append(s.Nodes, nodeLatency{
+			Name:         name,
+			Failures:     nodeFailures[name],
+			latencyStats: newLatencyStats(nodeSamples[name]),
+		})
+	}
+	return s
+}
+
+// ratio 平均值，分母为 0 时返回 0
+func ratio(sum float64, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// newLatencyStats 计算耗时统计，分位数取最近秩
+func newLatencyStats(samples []int64) latencyStats {
+	if len(samples) == 0 {
+		return latencyStats{}
+	}
+	sorted := append([]int64(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum int64
+	for _, v := range sorted {
+		sum += v
+	}
+	percentile := func(p float64) int64 {
+		idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return sorted[idx]
+	}
+	return latencyStats{
+		Count:  len(sorted),
+		MeanMs: float64(sum) / float64(len(sorted)),
+		P50Ms:  percentile(0.5),
+		P95Ms:  percentile(0.95),
+		MaxMs:  sorted[len(sorted)-1],
+	}
+}
+
+// printSummary 输出评测指标与未完全召回的问题
+func printSummary(r *runReport) {
+	s := r.Summary
+	fmt.Printf("\n评测结果 (LLM: %s", r.LLM)
+	if r.Model != "" {
+		fmt.Printf(" %s", r.Model)
+	}
+	if r.PromptVersion != "" {
+		fmt.Printf("，提示词: %s", r.PromptVersion)
+	}
+	fmt.Println(")")
+	if len(r.Settings) > 0 {
+		fmt.Printf("系统配置覆盖: %s\n", settingFlags(r.Settings))
+	}
+	fmt.Printf("问题数: %d (应回答 %d，出错 %d)\n", s.Questions, s.Answerable, s.Errors)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "recall@%d\t%.3f\n", r.K, s.RecallAtK)
+	fmt.Fprintf(w, "MRR\t%.3f\n", s.MRR)
+	fmt.Fprintf(w, "引用精确率\t%.3f\n", s.CitationPrecision)
+	fmt.Fprintf(w, "拒答率\t%.3f\n", s.RefusalRate)
+	fmt.Fprintf(w, "误拒率\t%.3f\n", s.FalseRefusalRate)
+	fmt.Fprintf(w, "正确拒答率\t%.3f\n", s.CorrectRefusalRate)
+	_ = w.Flush()
+
+	fmt.Println("\n耗时 (ms):")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "节点\t次数\t失败\t平均\tP50\tP95\t最大\t")
+	fmt.Fprintf(w, "%s\t%d\t-\t%.1f\t%d\t%d\t%d\t\n", "(总计)", s.Latency.Count, s.Latency.MeanMs, s.Latency.P50Ms, s.Latency.P95Ms, s.Latency.MaxMs)
+	for _, n := range s.Nodes {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%d\t%d\t%d\t\n", n.Name, n.Count, n.Failures, n.MeanMs, n.P50Ms, n.P95Ms, n.MaxMs)
+	}
+	_ = w.Flush()
+
+	var misses []string
+	for _, item := range r.Items {
+		switch {
+		case item.Error != "":
+			misses = append(misses, fmt.Sprintf("  [出错] %s: %s", item.ID, item.Error))
+		case item.ExpectRefusal && !item.Refused:
+			misses = append(misses, fmt.Sprintf("  [未拒答] %s: %s", item.ID, item.Question))
+		case len(item.Expected) > 0 && item.Refused && !item.ExpectRefusal:
+			misses = append(misses, fmt.Sprintf("  [误拒] %s (%s): %s", item.ID, item.RefusalReason, item.Question))
+		case len(item.Expected) > 0 && item.Recall < 1:
+			misses = append(misses, fmt.Sprintf("  [漏检] %s recall=%.2f 缺少 %s", item.ID, item.Recall, joinKeys(missingKeys(item, r.K))))
+		}
+	}
+	if len(misses) > 0 {
+		fmt.Println("\n未通过的问题:")
+		for _, m := range misses {
+			fmt.Println(m)
+		}
+	}
+}
+
+// missingKeys 前 k 个检索结果中缺少的期望条文
+func missingKeys(item itemResult, k int) []articleKey {
+	top := item.Retrieved
+	if len(top) > k {
+		top = top[:k]
+	}
+	found := make(map[articleKey]bool, len(top))
+	for _, key := range top {
+		found[key] = true
+	}
+	var missing []articleKey
+	for _, key := range item.Expected {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// joinKeys 以顿号连接条文标识
+func joinKeys(keys []articleKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = string(k)
+	}
+	return strings.Join(parts, "、")
+}
+
+// saveReport 保存评测结果
+func saveReport(path string, r *runReport) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// loadReport 读取评测结果
+func loadReport(path string) (*runReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r runReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/graph"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+)
+
+// indexMarkerPattern 回答中的参考法条序号引用，如 "[1]"
+var indexMarkerPattern = regexp.MustCompile(`\[(\d{1,3})\]`)
+
+// itemResult 单个问题的评测结果
+type itemResult struct {
+	ID                string            `json:"id"`
+	Question          string            `json:"question"`
+	Expected          []articleKey      `json:"expected,omitempty"`
+	ExpectRefusal     bool              `json:"expectRefusal,omitempty"`
+	Retrieved         []articleKey      `json:"retrieved"`                   // 装入提示词的条文 (拒答时为最接近的候选)，按序号去重
+	Cited             []articleKey      `json:"cited,omitempty"`             // 回答实际引用的条文
+	Refused           bool              `json:"refused"`                     // 是否拒答
+	RefusalReason     string            `json:"refusalReason,omitempty"`     // 拒答原因
+	Recall            float64           `json:"recall"`                      // recall@k
+	ReciprocalRank    float64           `json:"reciprocalRank"`              // 首个期望条文名次的倒数，未检索到时为 0
+	CitationPrecision *float64          `json:"citationPrecision,omitempty"` // 引用中期望条文的比例，无引用时为空
+	LatencyMs         int64             `json:"latencyMs"`                   // 完整问答耗时
+	Nodes             []graph.NodeTrace `json:"nodes,omitempty"`
+	Error             string            `json:"error,omitempty"`
+
+	promptVersion string
+}
+
+// run 逐题执行 RAG Graph 并汇总指标
+func (e *environment) run(ctx context.Context, items []goldenItem, k int) *runReport {
+	report := &runReport{
+		CreatedAt: time.Now(),
+		LLM:       e.llmMode,
+		Settings:  e.settings,
+		K:         k,
+	}
+	if e.llmMode == llmConfig {
+		report.Model = e.cfg.LLM.Model
+	}
+	for _, item := range items {
+		res := e.evaluate(ctx, item, k)
+		if report.PromptVersion == "" {
+			report.PromptVersion = res.promptVersion
+		}
+		report.Items = append(report.Items, res)
+	}
+	report.Summary = summarize(report.Items)
+	return report
+}
+
+// evaluate 执行单个问题并计算指标
+func (e *environment) evaluate(ctx context.Context, item goldenItem, k int) itemResult {
+	res := itemResult{
+		ID:            item.ID,
+		Question:      item.Question,
+		Expected:      item.expected,
+		ExpectRefusal: item.ExpectRefusal,
+	}
+	in := graph.Input{Question: item.Question}
+	for _, t := range item.History {
+		in.History = append(in.History, nodes.ChatHistoryItem{Role: t.Role, Content: t.Content})
+	}
+	if asOf, _ := parseOptionalDate(item.AsOfDate); asOf != nil {
+		in.AsOf = *asOf
+	}
+
+	start := time.Now()
+	events, err := e.graph.Stream(ctx, in)
+	if err != nil {
+		res.LatencyMs = time.Since(start).Milliseconds()
+		res.Error = err.Error()
+		return res
+	}
+
+	var (
+		citations []graph.CitationData
+		check     *graph.CheckData
+		answer    strings.Builder
+	)
+	for ev := range events {
+		switch data := ev.Data.(type) {
+		case graph.TokenData:
+			answer.WriteString(data.Content)
+		case graph.CitationData:
+			citations = append(citations, data)
+		case graph.RefusalData:
+			res.Refused = true
+			res.RefusalReason = data.Reason
+			citations = data.Candidates
+		case graph.CheckData:
+			check = &data
+		case graph.TraceData:
+			res.Nodes = data.Nodes
+			res.promptVersion = data.PromptVersion
+		case graph.ErrorData:
+			res.Error = data.Message
+		}
+	}
+	res.LatencyMs = time.Since(start).Milliseconds()
+
+	res.Retrieved = citationKeys(citations)
+	if check != nil {
+		res.Cited = citationKeys(graph.CitedOnly(citations, citedIndexes(answer.String(), check)))
+	}
+	score(&res, k)
+	return res
+}
+
+// citationKeys 引用条文的标识 (按出现顺序去重，忽略无条号的分块)
+func citationKeys(citations []graph.CitationData) []articleKey {
+	keys := make([]articleKey, 0, len(citations))
+	seen := make(map[articleKey]bool)
+	for _, c := range citations {
+		if c.ArticleNumber == "" {
+			continue
+		}
+		key := newArticleKey(c.Source, c.ArticleNumber)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// citedIndexes 回答引用的参考法条序号
+// check 事件只计入通过链上验证的条文，离线评测中全部条文未验证，以回答中的序号引用 [n] 补充
+func citedIndexes(answer string, check *graph.CheckData) *graph.CheckData {
+	cited := &graph.CheckData{Cited: append([]int(nil), check.Cited...)}
+	for _, m := range indexMarkerPattern.FindAllStringSubmatch(answer, -1) {
+		n, _ := strconv.Atoi(m[1])
+		cited.Cited = append(cited.Cited, n)
+	}
+	return cited
+}
+
+// score 计算 recall@k、倒数名次与引用精确率
+func score(res *itemResult, k int) {
+	if len(res.Expected) == 0 {
+		return
+	}
+	expected := make(map[articleKey]bool, len(res.Expected))
+	for _, key := range res.Expected {
+		expected[key] = true
+	}
+
+	hits := 0
+	for i, key := range res.Retrieved {
+		if !expected[key] {
+			continue
+		}
+		if res.ReciprocalRank == 0 {
+			res.ReciprocalRank = 1 / float64(i+1)
+		}
+		if i < k {
+			hits++
+		}
+	}
+	res.Recall = float64(hits) / float64(len(expected))
+
+	if len(res.Cited) > 0 {
+		correct := 0
+		for _, key := range res.Cited {
+			if expected[key] {
+				correct++
+			}
+		}
+		precision := float64(correct) / float64(len(res.Cited))
+		res.CitationPrecision = &precision
+	}
+}
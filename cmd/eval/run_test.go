@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+)
+
+const (
+	testGolden = "testdata/golden.jsonl"
+	testCorpus = "testdata/corpus"
+	testK      = 5
+)
+
+// testConfig 评测配置: 检索与查询理解同 config.yaml 默认值，向量维度取较小值
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Milvus.Dimension = 256
+	cfg.Retrieval = config.RetrievalConfig{TopK: 5, Hybrid: true, CandidateK: 10, RRFK: 60, ExpandHops: 1, ExpandMax: 5}
+	cfg.Graph.QueryAnalysis = config.QueryAnalysisConfig{Enabled: true, MaxQueries: 3, MinConfidence: 0.7}
+	cfg.Document.ProcessTimeout = time.Minute
+	return cfg
+}
+
+// runEval 使用哈希向量化、内存向量库与模拟 LLM 导入测试语料并执行黄金问答集
+func runEval(t *testing.T, settings map[string]string) *runReport {
+	t.Helper()
+	ctx := context.Background()
+	env, err := newEnvironment(ctx, testConfig(), llmFake, settings)
+	if err != nil {
+		t.Fatalf("newEnvironment() error = %v", err)
+	}
+	defer env.Close()
+
+	docs, chunks, err := env.ingest(ctx, testCorpus)
+	if err != nil {
+		t.Fatalf("ingest() error = %v", err)
+	}
+	if docs != 2 || chunks != 12 {
+		t.Fatalf("ingest() = %d 个文档 %d 个分块, want 2 / 12", docs, chunks)
+	}
+	items, err := loadGolden(testGolden)
+	if err != nil {
+		t.Fatalf("loadGolden() error = %v", err)
+	}
+	report := env.run(ctx, items, testK)
+	report.Golden = testGolden
+	report.Corpus = testCorpus
+	return report
+}
+
+func TestRunGolden(t *testing.T) {
+	report := runEval(t, map[string]string{})
+
+	want := []struct {
+		id        string
+		recall    float64
+		rr        float64
+		precision float64 // -1 表示无引用
+		refused   string  // 拒答原因，空表示未拒答
+	}{
+		{id: "labor-001", recall: 1, rr: 1, precision: 1},
+		{id: "labor-002", recall: 1, rr: 1, precision: 0.5},
+		{id: "civil-001", recall: 1, rr: 1, precision: 0.5},
+		{id: "civil-002", recall: 1, rr: 1, precision: 1},
+		{id: "oos-001", precision: -1, refused: "out_of_scope"},
+		// 第四十六条不在语料中，仅召回一半；相似度低于阈值而误拒
+		{id: "labor-003", recall: 0.5, rr: 1, precision: -1, refused: "low_similarity"},
+		// 期望条文排在第二位
+		{id: "civil-003", recall: 1, rr: 0.5, precision: -1, refused: "out_of_scope"},
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("items = %d, want %d", len(report.Items), len(want))
+	}
+	for i, w := range want {
+		got := report.Items[i]
+		if got.ID != w.id {
+			t.Fatalf("items[%d].id = %s, want %s", i, got.ID, w.id)
+		}
+		if got.Error != "" {
+			t.Errorf("%s: 执行出错: %s", w.id, got.Error)
+		}
+		if !approx(got.Recall, w.recall) || !approx(got.ReciprocalRank, w.rr) {
+			t.Errorf("%s: recall = %.3f, RR = %.3f, want %.3f, %.3f (retrieved %v)", w.id, got.Recall, got.ReciprocalRank, w.recall, w.rr, got.Retrieved)
+		}
+		switch {
+		case w.precision < 0 && got.CitationPrecision != nil:
+			t.Errorf("%s: citationPrecision = %.3f, want 无引用", w.id, *got.CitationPrecision)
+		case w.precision >= 0 && (got.CitationPrecision == nil || !approx(*got.CitationPrecision, w.precision)):
+			t.Errorf("%s: citationPrecision = %v, want %.3f (cited %v)", w.id, got.CitationPrecision, w.precision, got.Cited)
+		}
+		if got.Refused != (w.refused != "") || got.RefusalReason != w.refused {
+			t.Errorf("%s: refused = %v (%s), want %q", w.id, got.Refused, got.RefusalReason, w.refused)
+		}
+	}
+
+	// 期望条文按 "法律名称:条号" 规范化，并按检索名次计分
+	if got := report.Items[0].Retrieved; !reflect.DeepEqual(got, []articleKey{"劳动合同法:87", "劳动合同法:47"}) {
+		t.Errorf("labor-001 retrieved = %v", got)
+	}
+	if got := report.Items[2].Expected; !reflect.DeepEqual(got, []articleKey{"民法典:1165"}) {
+		t.Errorf("civil-001 expected = %v", got)
+	}
+
+	s := report.Summary
+	if s.Questions != 7 || s.Answerable != 6 || s.Errors != 0 {
+		t.Errorf("summary questions = %d, answerable = %d, errors = %d, want 7 / 6 / 0", s.Questions, s.Answerable, s.Errors)
+	}
+	for _, m := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"recallAtK", s.RecallAtK, 5.5 / 6},
+		{"mrr", s.MRR, 5.5 / 6},
+		{"citationPrecision", s.CitationPrecision, 3.0 / 4},
+		{"refusalRate", s.RefusalRate, 3.0 / 7},
+		{"falseRefusalRate", s.FalseRefusalRate, 2.0 / 6},
+		{"correctRefusalRate", s.CorrectRefusalRate, 1},
+	} {
+		if !approx(m.got, m.want) {
+			t.Errorf("summary %s = %.4f, want %.4f", m.name, m.got, m.want)
+		}
+	}
+
+	// 各节点耗时: 拒答的问题不执行生成，未拒答的问题不执行拒答节点
+	if s.Latency.Count != 7 {
+		t.Errorf("latency count = %d, want 7", s.Latency.Count)
+	}
+	nodeCounts := make(map[string]int, len(s.Nodes))
+	for _, n := range s.Nodes {
+		nodeCounts[n.Name] = n.Count
+		if n.Failures != 0 {
+			t.Errorf("node %s failures = %d", n.Name, n.Failures)
+		}
+	}
+	for name, count := range map[string]int{"analyze": 7, "retrieve": 7, "generate": 4, "check": 4, "refuse": 3} {
+		if nodeCounts[name] != count {
+			t.Errorf("node %s count = %d, want %d", name, nodeCounts[name], count)
+		}
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	// 基线结果保存后重新读取，与命令行 -base 的用法一致
+	path := filepath.Join(t.TempDir(), "base.json")
+	if err := saveReport(path, runEval(t, map[string]string{})); err != nil {
+		t.Fatal(err)
+	}
+	base, err := loadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 降低相似度阈值后 labor-003 不再误拒
+	head := runEval(t, map[string]string{"retrieval.min_score": "0.4"})
+
+	var buf bytes.Buffer
+	printDiff(&buf, base, head)
+	out := buf.String()
+
+	if !strings.Contains(out, "系统配置覆盖: [] → [retrieval.min_score=0.4]") {
+		t.Errorf("缺少系统配置覆盖对比:\n%s", out)
+	}
+	for name, want := range map[string][]string{
+		"recall@5": {"0.917", "0.917", "+0.000"},
+		"MRR":      {"0.917", "0.917", "+0.000"},
+		"拒答率":      {"0.429", "0.286", "-0.143"},
+		"误拒率":      {"0.333", "0.167", "-0.167"},
+		"正确拒答率":    {"1.000", "1.000", "+0.000"},
+	} {
+		if got := diffRow(out, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("指标 %s = %v, want %v", name, got, want)
+		}
+	}
+	for _, node := range []string{"(总计)", "retrieve", "generate", "refuse"} {
+		if diffRow(out, node) == nil {
+			t.Errorf("缺少节点 %s 的耗时对比", node)
+		}
+	}
+	if !strings.Contains(out, "改善 (1):\n  labor-003 recall 0.50 → 0.50，RR 1.00 → 1.00，拒答 low_similarity → 否\n") || strings.Contains(out, "退化") {
+		t.Errorf("各问题变化不符合预期:\n%s", out)
+	}
+
+	// 反向对比为退化
+	buf.Reset()
+	printDiff(&buf, head, base)
+	if out := buf.String(); !strings.Contains(out, "退化 (1):\n  labor-003 recall 0.50 → 0.50，RR 1.00 → 1.00，拒答 否 → low_similarity\n") || strings.Contains(out, "改善") {
+		t.Errorf("反向对比不符合预期:\n%s", out)
+	}
+
+	// 与自身对比无变化
+	buf.Reset()
+	printDiff(&buf, base, base)
+	if out := buf.String(); !strings.Contains(out, "各问题结果无变化") || strings.Contains(out, "系统配置覆盖") {
+		t.Errorf("与自身对比不符合预期:\n%s", out)
+	}
+}
+
+// diffRow 返回对比输出中以 name 开头的表格行除名称外的各列，不存在时返回 nil
+func diffRow(out, name string) []string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == name {
+			return fields[1:]
+		}
+	}
+	return nil
+}
+
+// approx 浮点数近似相等
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -2,14 +2,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
 	"github.com/lexveritas/lex-veritas-backend/internal/config"
 	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
 	"go.uber.org/zap"
 )
 
@@ -50,5 +54,128 @@ func main() {
 		logger.Fatal("数据库迁移失败", zap.Error(err))
 	}
 
+	// 写入系统配置默认值 (已存在的配置项不覆盖)
+	configRepo := repository.NewSystemConfigRepository()
+	if err := configRepo.CreateMissing(context.Background(), model.DefaultSystemConfigs()); err != nil {
+		logger.Fatal("写入系统配置默认值失败", zap.Error(err))
+	}
+	if err := upgradeDefaults(context.Background(), configRepo); err != nil {
+		logger.Fatal("更新系统配置默认值失败", zap.Error(err))
+	}
+
+	// 全文检索索引与历史分块分词回填
+	docRepo := repository.NewDocumentRepository()
+	if err := docRepo.EnsureChunkSearchIndex(context.Background()); err != nil {
+		logger.Fatal("创建全文检索索引失败", zap.Error(err))
+	}
+	filled, err := backfillSearchText(context.Background(), docRepo)
+	if err != nil {
+		logger.Fatal("回填全文检索分词失败", zap.Error(err))
+	}
+	if filled > 0 {
+		logger.Info("已回填分块全文检索分词", zap.Int("chunks", filled))
+	}
+
+	// 条文时效: 引入条文级时效前导入的分块沿用文档日期
+	validity, err := docRepo.BackfillChunkValidity(context.Background())
+	if err != nil {
+		logger.Fatal("回填分块时效失败", zap.Error(err))
+	}
+	if validity > 0 {
+		logger.Info("已回填分块时效", zap.Int64("chunks", validity))
+	}
+
+	// 验证记录: 早期记录的 LeafHash 存的是 ContentHash，改为 Merkle 叶子哈希
+	leaves, err := backfillLeafHash(context.Background(), repository.NewProofRepository())
+	if err != nil {
+		logger.Fatal("回填验证记录叶子哈希失败", zap.Error(err))
+	}
+	if leaves > 0 {
+		logger.Info("已回填验证记录叶子哈希", zap.Int("records", leaves))
+	}
+
 	logger.Info("数据库迁移完成")
 }
+
+// upgradeDefaults 将仍为旧默认值的配置项更新为当前默认值
+// 管理员修改过的配置项保持不变，仅提示新默认值
+func upgradeDefaults(ctx context.Context, configRepo repository.SystemConfigRepository) error {
+	defaults := make(map[string]string)
+	for _, c := range model.DefaultSystemConfigs() {
+		defaults[c.Key] = c.Value
+	}
+	for _, old := range model.SupersededDefaults() {
+		value, ok := defaults[old.Key]
+		if !ok || value == old.Value {
+			continue
+		}
+		replaced, err := configRepo.ReplaceDefault(ctx, old.Key, old.Value, value)
+		if err != nil {
+			return err
+		}
+		if replaced {
+			logger.Info("已更新系统配置默认值", zap.String("key", old.Key), zap.String("from", old.Value), zap.String("to", value))
+			continue
+		}
+		if cfg, err := configRepo.FindByKey(ctx, old.Key); err == nil && cfg.Value == old.Value {
+			logger.Warn("配置项由管理员设为旧默认值，保持不变", zap.String("key", old.Key), zap.String("value", old.Value), zap.String("default", value))
+		}
+	}
+	return nil
+}
+
+// backfillBatchSize 回填全文检索分词的批大小
+const backfillBatchSize = 500
+
+// backfillSearchText 为启用混合检索前导入的分块生成全文检索分词
+func backfillSearchText(ctx context.Context, docRepo repository.DocumentRepository) (int, error) {
+	var (
+		afterID int64
+		filled  int
+	)
+	for {
+		chunks, err := docRepo.ListChunksWithoutSearchText(ctx, afterID, backfillBatchSize)
+		if err != nil {
+			return filled, err
+		}
+		if len(chunks) == 0 {
+			return filled, nil
+		}
+		for _, c := range chunks {
+			if err := docRepo.UpdateChunkSearchText(ctx, c.ID, chunker.SearchText(c.LawHierarchy, c.Content)); err != nil {
+				return filled, err
+			}
+			filled++
+		}
+		afterID = chunks[len(chunks)-1].ID
+	}
+}
+
+// backfillLeafHash 将历史验证记录的 LeafHash (ContentHash) 移入 ContentHash，并写入 Merkle 叶子哈希
+func backfillLeafHash(ctx context.Context, proofRepo repository.ProofRepository) (int, error) {
+	var (
+		afterID int64
+		filled  int
+	)
+	for {
+		records, err := proofRepo.ListLegacyLeafHash(ctx, afterID, backfillBatchSize)
+		if err != nil {
+			return filled, err
+		}
+		if len(records) == 0 {
+			return filled, nil
+		}
+		for _, r := range records {
+			leaf, err := merkle.ContentLeaf(r.LeafHash)
+			if err != nil {
+				logger.Warn("跳过无法解析的验证记录", zap.Int64("id", r.ID), zap.Error(err))
+				continue
+			}
+			if err := proofRepo.UpdateHashes(ctx, r.ID, r.LeafHash, leaf.Hex()); err != nil {
+				return filled, err
+			}
+			filled++
+		}
+		afterID = records[len(records)-1].ID
+	}
+}
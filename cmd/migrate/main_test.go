@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// setupConfigs 使用 SQLite 内存数据库写入系统配置默认值，并将 prompt.version 设为 value
+// updatedBy 非空表示由管理员修改
+func setupConfigs(t *testing.T, value, updatedBy string) repository.SystemConfigRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:                 gormlogger.Default.LogMode(gormlogger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&model.SystemConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	database.Use(db)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	repo := repository.NewSystemConfigRepository()
+	ctx := context.Background()
+	if err := repo.CreateMissing(ctx, model.DefaultSystemConfigs()); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateValue(ctx, model.ConfigPromptVersion, value, updatedBy); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestUpgradeDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		updatedBy string
+		want      string
+	}{
+		{name: "旧默认值更新为当前默认值", value: "legal_qa_v2", want: "legal_qa_v3"},
+		{name: "管理员设置的旧版本保持不变", value: "legal_qa_v2", updatedBy: "admin", want: "legal_qa_v2"},
+		{name: "其他版本保持不变", value: "legal_qa_v1", want: "legal_qa_v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := setupConfigs(t, tt.value, tt.updatedBy)
+			ctx := context.Background()
+			// 重复执行结果不变
+			for i := 0; i < 2; i++ {
+				if err := upgradeDefaults(ctx, repo); err != nil {
+					t.Fatalf("upgradeDefaults() error = %v", err)
+				}
+			}
+			cfg, err := repo.FindByKey(ctx, model.ConfigPromptVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Value != tt.want || cfg.UpdatedBy != tt.updatedBy {
+				t.Errorf("prompt.version = %s (updatedBy %q), want %s", cfg.Value, cfg.UpdatedBy, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
@@ -93,12 +94,27 @@ func main() {
 		logger.Warn("Milvus 连接失败（后续请求可能受影响）",
 			zap.Error(err),
 		)
+	} else if err := client.NewMilvusStore(&cfg.Milvus).EnsureCollection(context.Background()); err != nil {
+		if errors.Is(err, client.ErrDimensionMismatch) {
+			logger.Fatal("Milvus 集合维度与配置不一致", zap.Error(err))
+		}
+		logger.Warn("Milvus 集合初始化失败（检索功能不可用）",
+			zap.Error(err),
+		)
 	}
 
-	// 6. 设置路由
-	r := router.Setup(cfg)
+	// 6. 校验向量化维度（与向量库不一致时拒绝启动）
+	checkEmbeddingDimension(cfg)
+
+	// 7. 设置路由
+	r, startWorkers := router.Setup(cfg)
 
-	// 7. 创建 HTTP 服务器
+	// 8. 启动后台任务（收到关闭信号时取消，中断的文档导入保持处理中状态，租约过期后重新导入）
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	startWorkers(workerCtx)
+
+	// 9. 创建 HTTP 服务器
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:      r,
@@ -106,7 +122,7 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
-	// 8. 启动服务器（异步）
+	// 10. 启动服务器（异步）
 	go func() {
 		baseURL := fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
 		logger.Info("HTTP 服务器启动",
@@ -122,14 +138,17 @@ func main() {
 		}
 	}()
 
-	// 9. 等待中断信号
+	// 11. 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("正在关闭服务器...")
 
-	// 10. 优雅关闭（等待处理中的请求完成）
+	// 先停止后台任务，不再领取新的导入、审计任务
+	stopWorkers()
+
+	// 12. 优雅关闭（等待处理中的请求完成）
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -137,7 +156,7 @@ func main() {
 		logger.Error("服务器关闭失败", zap.Error(err))
 	}
 
-	// 11. 关闭资源连接
+	// 13. 关闭资源连接
 	if err := database.Close(); err != nil {
 		logger.Error("关闭数据库连接失败", zap.Error(err))
 	}
@@ -152,3 +171,25 @@ func main() {
 
 	logger.Info("服务器已安全关闭")
 }
+
+// checkEmbeddingDimension 校验向量化输出维度与 Milvus 集合维度一致
+// 维度不一致时直接退出；向量化服务暂不可用时仅告警
+func checkEmbeddingDimension(cfg *config.Config) {
+	if cfg.Embedding.Dimensions > 0 && cfg.Embedding.Dimensions != cfg.Milvus.Dimension {
+		logger.Fatal("向量化维度与向量库不一致",
+			zap.Int("embedding_dimensions", cfg.Embedding.Dimensions),
+			zap.Int("milvus_dimension", cfg.Milvus.Dimension),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	embedder := client.NewEmbedder(&cfg.Embedding, cfg.Milvus.Dimension)
+	if err := client.CheckEmbeddingDimension(ctx, embedder, cfg.Milvus.Dimension); err != nil {
+		if errors.Is(err, client.ErrDimensionMismatch) {
+			logger.Fatal("向量化维度与向量库不一致", zap.Error(err))
+		}
+		logger.Warn("向量化服务检查失败（检索与导入功能可能不可用）", zap.Error(err))
+	}
+}
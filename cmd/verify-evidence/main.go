@@ -0,0 +1,151 @@
+// Package main 提供问答证据包离线验证工具
+// 无需访问服务端数据库: 校验服务端签名、由引用原文重新计算哈希并沿 Merkle 证明折叠出 Root，
+// 指定 -rpc 时再到链上存证合约读取各版本 Root 比对
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/evidence"
+)
+
+var (
+	filePath       string
+	expectedSigner string
+	rpcURL         string
+	contract       string
+	timeout        time.Duration
+)
+
+func init() {
+	flag.StringVar(&filePath, "file", "", "证据包 ZIP 文件路径")
+	flag.StringVar(&expectedSigner, "signer", "", "期望的服务端签名地址 (可选)")
+	flag.StringVar(&rpcURL, "rpc", "", "区块链节点 JSON-RPC 地址，指定后比对链上存证 Root (可选)")
+	flag.StringVar(&contract, "contract", "", "存证合约地址，默认使用证据包中记录的地址 (可选)")
+	flag.DurationVar(&timeout, "timeout", 30*time.Second, "链上查询超时时间")
+}
+
+func main() {
+	flag.Parse()
+
+	if filePath == "" {
+		fmt.Println("请使用 -file 指定证据包路径")
+		os.Exit(2)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		fmt.Printf("读取证据包失败: %v\n", err)
+		os.Exit(1)
+	}
+	ok, err := verifyBundle(os.Stdout, data)
+	if err != nil {
+		fmt.Printf("解析证据包失败: %v\n", err)
+		os.Exit(1)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// verifyBundle 验证证据包并将各项结果输出到 out，返回是否全部通过
+// 证据包无法解析 (文件缺失、多余或格式错误) 时返回错误
+func verifyBundle(out io.Writer, data []byte) (bool, error) {
+	bundle, err := evidence.Open(data)
+	if err != nil {
+		return false, err
+	}
+
+	m := &bundle.Manifest
+	fmt.Fprintf(out, "消息 ID: %s\n", m.MessageID)
+	fmt.Fprintf(out, "问题: %s\n", m.Question)
+	fmt.Fprintf(out, "生成时间: %s\n", m.GeneratedAt.Format(time.RFC3339))
+
+	res := bundle.Verify(expectedSigner)
+	ok := res.OK()
+
+	// 签名
+	if res.SignatureError != nil {
+		fmt.Fprintf(out, "[FAIL] 签名: %v\n", res.SignatureError)
+	} else {
+		fmt.Fprintf(out, "[ OK ] 签名: %s\n", res.Signer)
+	}
+
+	// 引用
+	for _, c := range res.Citations {
+		if c.Verified {
+			fmt.Fprintf(out, "[ OK ] 引用 %d: chunk %s, 版本 %d, root %s\n", c.Index, c.ChunkID, c.VersionID, c.ComputedRoot)
+		} else {
+			fmt.Fprintf(out, "[FAIL] 引用 %d: chunk %s, 版本 %d, %s\n", c.Index, c.ChunkID, c.VersionID, c.Reason)
+		}
+	}
+
+	// 链上存证
+	if rpcURL != "" {
+		if !verifyOnChain(out, m) {
+			ok = false
+		}
+	} else {
+		fmt.Fprintln(out, "[SKIP] 链上存证: 未指定 -rpc，仅完成离线校验")
+	}
+
+	if !ok {
+		fmt.Fprintln(out, "验证未通过")
+		return false, nil
+	}
+	fmt.Fprintln(out, "验证通过")
+	return true, nil
+}
+
+// verifyOnChain 比对证据包中各版本 Root 与链上存证
+func verifyOnChain(out io.Writer, m *evidence.Manifest) bool {
+	address := contract
+	if address == "" {
+		address = m.Chain.ContractAddress
+	}
+	if address == "" {
+		fmt.Fprintln(out, "[FAIL] 链上存证: 证据包未记录存证合约地址，请使用 -contract 指定")
+		return false
+	}
+
+	// 只读客户端，不配置签名私钥
+	c, err := client.NewBlockchainClient(&config.BlockchainConfig{
+		RPCURL:          rpcURL,
+		ChainID:         m.Chain.ChainID,
+		ContractAddress: address,
+	})
+	if err != nil {
+		fmt.Fprintf(out, "[FAIL] 链上存证: %v\n", err)
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ok := true
+	for _, v := range m.Versions {
+		root, err := c.GetMerkleRoot(ctx, int64(v.ID))
+		switch {
+		case errors.Is(err, client.ErrRootNotAnchored):
+			fmt.Fprintf(out, "[FAIL] 链上存证: 版本 %d 未在合约 %s 存证\n", v.ID, address)
+			ok = false
+		case err != nil:
+			fmt.Fprintf(out, "[FAIL] 链上存证: 版本 %d: %v\n", v.ID, err)
+			ok = false
+		case !strings.EqualFold(root, v.MerkleRoot):
+			fmt.Fprintf(out, "[FAIL] 链上存证: 版本 %d 链上 Root %s 与证据包 %s 不一致\n", v.ID, root, v.MerkleRoot)
+			ok = false
+		default:
+			fmt.Fprintf(out, "[ OK ] 链上存证: 版本 %d, root %s\n", v.ID, root)
+		}
+	}
+	return ok
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/evidence"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+)
+
+// newBundle 生成引用单条条文的签名证据包，返回证据包与签名地址
+func newBundle(t *testing.T) ([]byte, string) {
+	t.Helper()
+	text := "第一千一百六十五条　行为人因过错侵害他人民事权益造成损害的，应当承担侵权责任。"
+	hash := chunker.ContentHash(text)
+	tree, err := merkle.FromContentHashes([]string{hash, chunker.ContentHash("第一千一百六十六条")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.ProofHex(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := evidence.NewSigner(common.Bytes2Hex(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &evidence.Manifest{
+		MessageID: "msg-1",
+		Question:  "因过错侵害他人民事权益应当承担什么责任",
+		Answer:    "应当承担侵权责任 [1]。",
+		Citations: []evidence.Citation{{
+			Index: 1, ChunkID: "civil-1165", Source: "中华人民共和国民法典", ArticleNumber: "1165",
+			Text: text, ContentHash: hash, VersionID: 1, MerkleProof: proof, Verified: true,
+		}},
+		Versions:    []evidence.Version{{ID: 1, MerkleRoot: tree.Root().Hex(), ChunkCount: 2, Status: string(model.VersionStatusConfirmed)}},
+		GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	var buf bytes.Buffer
+	if err := evidence.Write(&buf, m, signer); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), signer.Address()
+}
+
+// editBundle 逐个改写证据包中的文件后重新打包: edit 返回 nil 时删除该文件，extra 为追加的文件名
+func editBundle(t *testing.T, data []byte, edit func(name string, b []byte) []byte, extra ...string) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name string, b []byte) {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := edit(f.Name, b); b != nil {
+			write(f.Name, b)
+		}
+	}
+	for _, name := range extra {
+		write(name, []byte("extra"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyBundle(t *testing.T) {
+	data, address := newBundle(t)
+	defer func(signer string) { expectedSigner = signer }(expectedSigner)
+
+	tampered := editBundle(t, data, func(name string, b []byte) []byte {
+		if name != evidence.ManifestFile {
+			return b
+		}
+		return bytes.Replace(b, []byte("[1]。"), []byte("[1]！"), 1)
+	})
+
+	tests := []struct {
+		name   string
+		data   []byte
+		signer string
+		ok     bool
+		want   []string
+	}{
+		{name: "通过", data: data, signer: address, ok: true, want: []string{"[ OK ] 签名: " + address, "[ OK ] 引用 1: chunk civil-1165", "[SKIP] 链上存证", "验证通过"}},
+		{name: "不指定签名地址", data: data, ok: true, want: []string{"验证通过"}},
+		{name: "签名地址不符", data: data, signer: common.HexToAddress("0x01").Hex(), want: []string{"[FAIL] 签名: 签名账户不是期望的服务端地址", "验证未通过"}},
+		{name: "回答被改动", data: tampered, signer: address, want: []string{"[FAIL] 签名: evidence.json 摘要与签名记录不一致", "验证未通过"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectedSigner = tt.signer
+			var out bytes.Buffer
+			ok, err := verifyBundle(&out, tt.data)
+			if err != nil {
+				t.Fatalf("verifyBundle() error = %v", err)
+			}
+			if ok != tt.ok {
+				t.Errorf("verifyBundle() = %v, want %v\n%s", ok, tt.ok, out.String())
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("输出缺少 %q:\n%s", w, out.String())
+				}
+			}
+		})
+	}
+
+	// 增删文件的证据包无法解析
+	keep := func(_ string, b []byte) []byte { return b }
+	if _, err := verifyBundle(io.Discard, editBundle(t, data, keep, "note.txt")); !errors.Is(err, evidence.ErrUnexpectedEntry) {
+		t.Errorf("新增文件 error = %v, want ErrUnexpectedEntry", err)
+	}
+	removed := editBundle(t, data, func(name string, b []byte) []byte {
+		if name == evidence.ReadmeFile {
+			return nil
+		}
+		return b
+	})
+	if _, err := verifyBundle(io.Discard, removed); !errors.Is(err, evidence.ErrMissingEntry) {
+		t.Errorf("删除 README.txt error = %v, want ErrMissingEntry", err)
+	}
+}
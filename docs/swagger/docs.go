@@ -18,14 +18,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/users": {
+        "/admin/configs": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "分页查询用户列表,支持按邮箱、角色、状态筛选",
+                "description": "按分类列出运行时系统配置，尚未修改过的配置项返回默认值",
                 "consumes": [
                     "application/json"
                 ],
@@ -33,56 +33,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理员"
+                    "系统配置"
                 ],
-                "summary": "获取用户列表",
+                "summary": "获取系统配置列表",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "邮箱筛选",
-                        "name": "email",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "user",
-                            "admin",
-                            "super_admin"
-                        ],
-                        "type": "string",
-                        "description": "角色筛选",
-                        "name": "role",
-                        "in": "query"
-                    },
                     {
                         "enum": [
-                            "active",
-                            "inactive",
-                            "banned"
+                            "retrieval",
+                            "prompt"
                         ],
                         "type": "string",
-                        "description": "状态筛选",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "关键词搜索(邮箱或姓名)",
-                        "name": "keyword",
+                        "description": "配置分类",
+                        "name": "category",
                         "in": "query"
                     }
                 ],
@@ -98,7 +60,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserListResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SystemConfigResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -120,14 +85,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/users/{id}": {
-            "get": {
+        "/admin/configs/{key}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "根据用户ID获取详细信息",
+                "description": "更新配置值，值需符合配置类型 (int/float/bool/json)。多实例部署时其他实例在 30 秒内生效",
                 "consumes": [
                     "application/json"
                 ],
@@ -135,21 +100,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理员"
+                    "系统配置"
                 ],
-                "summary": "获取用户详情",
+                "summary": "更新系统配置",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "用户ID",
-                        "name": "id",
+                        "example": "retrieval.min_score",
+                        "description": "配置键",
+                        "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "配置值",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateSystemConfigRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "获取成功",
+                        "description": "更新成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -159,13 +134,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserResponse"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SystemConfigResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "配置值与类型不匹配",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
                     "401": {
                         "description": "未授权",
                         "schema": {
@@ -179,20 +160,22 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "用户不存在",
+                        "description": "配置项不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/documents": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "软删除指定用户",
+                "description": "分页查询知识库文档，支持按状态、类型、法律类型筛选",
                 "consumes": [
                     "application/json"
                 ],
@@ -200,29 +183,80 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理员"
+                    "知识库"
                 ],
-                "summary": "删除用户",
+                "summary": "获取文档列表",
                 "parameters": [
                     {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "processing",
+                            "indexed",
+                            "minted",
+                            "error"
+                        ],
                         "type": "string",
-                        "description": "用户ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "状态筛选",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pdf",
+                            "docx",
+                            "txt",
+                            "url",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "description": "类型筛选",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "法律类型筛选",
+                        "name": "lawType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "关键词搜索(文档名称或法律名称)",
+                        "name": "keyword",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
+                        "description": "获取成功",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentListResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "401": {
@@ -236,61 +270,92 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
-                    },
-                    "404": {
-                        "description": "用户不存在",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
-                        }
                     }
                 }
-            }
-        },
-        "/admin/users/{id}/quota": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "调整指定用户的Token配额",
+                "description": "上传 PDF、DOCX、TXT 或 Markdown 文件，登记后由后台异步完成解析、分块、向量化与索引。可通过文档详情查询处理状态 (pending → processing → indexed / error)",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "管理员"
+                    "知识库"
                 ],
-                "summary": "调整用户Token配额",
+                "summary": "上传文档",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "用户ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "文档文件 (.pdf/.docx/.txt/.md)",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     },
                     {
-                        "description": "配额调整请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.AdjustQuotaRequest"
-                        }
+                        "type": "string",
+                        "description": "文档名称，为空时使用文件名",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "法律名称，如 民法典",
+                        "name": "lawName",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "法律类型，如 法律、行政法规、司法解释",
+                        "name": "lawType",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "生效日期 (YYYY-MM-DD)",
+                        "name": "effectiveDate",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "失效日期 (YYYY-MM-DD)，导入已失效的历史法律时填写",
+                        "name": "expiryDate",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "发布机关",
+                        "name": "publishOrg",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "调整成功",
+                        "description": "上传成功",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "请求参数错误",
+                        "description": "请求参数错误或文档类型不支持",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -306,24 +371,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
-                    },
-                    "404": {
-                        "description": "用户不存在",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
-                        }
                     }
                 }
             }
         },
-        "/admin/users/{id}/role": {
-            "put": {
+        "/admin/documents/url": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "修改用户角色,需要super_admin权限",
+                "description": "登记法规网页 (HTML、纯文本或 PDF)，由后台异步抓取并导入，重试时重新抓取",
                 "consumes": [
                     "application/json"
                 ],
@@ -331,32 +390,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理员"
+                    "知识库"
                 ],
-                "summary": "修改用户角色",
+                "summary": "登记网页文档",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "用户ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "角色更新请求",
+                        "description": "网页文档登记请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateRoleRequest"
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.RegisterURLRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "修改成功",
+                        "description": "登记成功",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -376,24 +440,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
-                    },
-                    "404": {
-                        "description": "用户不存在",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
-                        }
                     }
                 }
             }
         },
-        "/admin/users/{id}/status": {
-            "put": {
+        "/admin/documents/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "修改用户的激活状态",
+                "description": "获取文档信息与处理状态，处理失败时 processError 为失败原因",
                 "consumes": [
                     "application/json"
                 ],
@@ -401,36 +459,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理员"
+                    "知识库"
                 ],
-                "summary": "修改用户状态",
+                "summary": "获取文档详情",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "用户ID",
+                        "description": "文档ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "状态更新请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateStatusRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "修改成功",
+                        "description": "获取成功",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "文档不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "删除文档及其分块与向量，删除后不再参与检索",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "知识库"
+                ],
+                "summary": "删除文档",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "文档ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -448,7 +556,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "用户不存在",
+                        "description": "文档不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -456,9 +564,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/admin/documents/{id}/retry": {
             "post": {
-                "description": "使用邮箱和密码登录系统",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "将处理失败 (error) 的文档重新放入待处理队列",
                 "consumes": [
                     "application/json"
                 ],
@@ -466,23 +579,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "知识库"
                 ],
-                "summary": "用户登录",
+                "summary": "重试导入文档",
                 "parameters": [
                     {
-                        "description": "登录请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "文档ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "已重新排队",
                         "schema": {
                             "allOf": [
                                 {
@@ -492,7 +603,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse"
                                         }
                                     }
                                 }
@@ -500,19 +611,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求参数错误",
+                        "description": "文档状态不允许重试",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
                     "401": {
-                        "description": "邮箱或密码错误",
+                        "description": "未授权",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
                     "403": {
-                        "description": "账户被禁用或锁定",
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "文档不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -520,9 +637,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login/phone": {
-            "post": {
-                "description": "使用手机号和验证码登录系统",
+        "/admin/documents/{id}/validity": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "设置法律的生效、失效日期与替代文档 (如《合同法》自 2021-01-01 起失效并由《民法典》替代)，可按条号单独设置被修改或删除的条文。每次请求为全量设置，未列出的条文沿用文档的时效。问答仅检索适用日期有效的条文",
                 "consumes": [
                     "application/json"
                 ],
@@ -530,23 +652,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "知识库"
                 ],
-                "summary": "手机验证码登录",
+                "summary": "设置文档时效",
                 "parameters": [
                     {
-                        "description": "手机登录请求",
+                        "type": "string",
+                        "description": "文档ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "时效设置",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.PhoneLoginRequest"
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateDocumentValidityRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "设置成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -556,7 +685,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse"
                                         }
                                     }
                                 }
@@ -570,7 +699,19 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "验证码错误",
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "文档不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -578,14 +719,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
+        "/admin/integrity/audits": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "登出当前用户，使访问令牌失效",
+                "description": "分页查询完整性审计报告，新报告在前",
                 "consumes": [
                     "application/json"
                 ],
@@ -593,27 +734,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "完整性审计"
+                ],
+                "summary": "获取审计报告列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
                 ],
-                "summary": "用户登出",
                 "responses": {
                     "200": {
-                        "description": "登出成功",
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityReportListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/auth/me": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "获取当前登录用户的详细信息",
+                "description": "在后台重新验证已确认版本中的全部分块 (内容哈希 + Merkle 证明 → 链上 Root)，结果写入验证记录与审计报告。有未完成的审计时从其检查点继续",
                 "consumes": [
                     "application/json"
                 ],
@@ -621,12 +800,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "完整性审计"
                 ],
-                "summary": "获取当前用户",
+                "summary": "立即执行完整性审计",
                 "responses": {
                     "200": {
-                        "description": "获取成功",
+                        "description": "审计已开始",
                         "schema": {
                             "allOf": [
                                 {
@@ -636,21 +815,33 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserResponse"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityReportResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "审计正在执行中",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
                     "401": {
                         "description": "未授权",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
-                    "404": {
-                        "description": "用户不存在",
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "未启用区块链存证",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -658,9 +849,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/oauth/{provider}": {
+        "/admin/integrity/audits/{id}": {
             "get": {
-                "description": "重定向到第三方OAuth授权页面",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "获取审计进度、统计与未通过的分块",
                 "consumes": [
                     "application/json"
                 ],
@@ -668,29 +864,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "完整性审计"
                 ],
-                "summary": "OAuth 第三方登录",
+                "summary": "获取审计报告",
                 "parameters": [
                     {
-                        "enum": [
-                            "google",
-                            "github",
-                            "wechat"
-                        ],
-                        "type": "string",
-                        "description": "OAuth提供商",
-                        "name": "provider",
+                        "type": "integer",
+                        "description": "报告ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "重定向到OAuth授权页面"
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityReportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    "501": {
-                        "description": "OAuth登录尚未实现",
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "报告不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -698,50 +916,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/oauth/{provider}/callback": {
+        "/admin/users": {
             "get": {
-                "description": "处理OAuth授权回调,完成登录",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "分页查询用户列表,支持按邮箱、角色、状态筛选",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "管理员"
                 ],
-                "summary": "OAuth 回调",
+                "summary": "获取用户列表",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "邮箱筛选",
+                        "name": "email",
+                        "in": "query"
+                    },
                     {
                         "enum": [
-                            "google",
-                            "github",
-                            "wechat"
+                            "user",
+                            "admin",
+                            "super_admin"
                         ],
                         "type": "string",
-                        "description": "OAuth提供商",
-                        "name": "provider",
-                        "in": "path",
-                        "required": true
+                        "description": "角色筛选",
+                        "name": "role",
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "active",
+                            "inactive",
+                            "banned"
+                        ],
                         "type": "string",
-                        "description": "授权码",
-                        "name": "code",
-                        "in": "query",
-                        "required": true
+                        "description": "状态筛选",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "状态码",
-                        "name": "state",
-                        "in": "query",
-                        "required": true
+                        "description": "关键词搜索(邮箱或姓名)",
+                        "name": "keyword",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "获取成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -751,21 +996,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserListResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "参数错误",
+                    "401": {
+                        "description": "未授权",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
-                    "501": {
-                        "description": "OAuth回调尚未实现",
+                    "403": {
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -773,9 +1018,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
-            "post": {
-                "description": "使用刷新令牌获取新的访问令牌",
+        "/admin/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "根据用户ID获取详细信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -783,23 +1033,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "管理员"
                 ],
-                "summary": "刷新令牌",
+                "summary": "获取用户详情",
                 "parameters": [
                     {
-                        "description": "刷新令牌请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.RefreshRequest"
-                        }
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "刷新成功",
+                        "description": "获取成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -809,31 +1057,40 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.TokenPair"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
+                    "401": {
+                        "description": "未授权",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
-                    "401": {
-                        "description": "刷新令牌无效或已过期",
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "使用邮箱注册新用户，需先获取验证码",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "软删除指定用户",
                 "consumes": [
                     "application/json"
                 ],
@@ -841,47 +1098,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "管理员"
                 ],
-                "summary": "用户注册",
+                "summary": "删除用户",
                 "parameters": [
                     {
-                        "description": "注册请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "注册成功",
+                        "description": "删除成功",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
                     "400": {
-                        "description": "请求参数错误或验证码无效",
+                        "description": "请求参数错误",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
-                    "429": {
-                        "description": "验证码尝试次数过多",
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -889,9 +1144,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/send-code": {
-            "post": {
-                "description": "发送邮箱验证码，用于注册或重置密码",
+        "/admin/users/{id}/quota": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "调整指定用户的Token配额",
                 "consumes": [
                     "application/json"
                 ],
@@ -899,23 +1159,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "管理员"
                 ],
-                "summary": "发送验证码",
+                "summary": "调整用户Token配额",
                 "parameters": [
                     {
-                        "description": "发送验证码请求",
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "配额调整请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SendCodeRequest"
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.AdjustQuotaRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "验证码发送成功",
+                        "description": "调整成功",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -926,8 +1193,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
-                    "429": {
-                        "description": "请求过于频繁",
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -935,14 +1214,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
+        "/admin/users/{id}/role": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "更新当前用户的姓名、手机、头像等信息",
+                "description": "修改用户角色,需要super_admin权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -950,23 +1229,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户"
+                    "管理员"
                 ],
-                "summary": "更新个人资料",
+                "summary": "修改用户角色",
                 "parameters": [
                     {
-                        "description": "更新请求",
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "角色更新请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateProfileRequest"
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新成功",
+                        "description": "修改成功",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -982,18 +1268,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/users/me/password": {
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/status": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "修改当前用户密码",
+                "description": "修改用户的激活状态",
                 "consumes": [
                     "application/json"
                 ],
@@ -1001,29 +1299,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户"
+                    "管理员"
                 ],
-                "summary": "修改密码",
+                "summary": "修改用户状态",
                 "parameters": [
                     {
-                        "description": "修改密码请求",
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "状态更新请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChangePasswordRequest"
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateStatusRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "密码修改成功",
+                        "description": "修改成功",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
                     },
                     "400": {
-                        "description": "旧密码错误或请求参数错误",
+                        "description": "请求参数错误",
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
@@ -1033,18 +1338,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
                     }
                 }
             }
         },
-        "/users/me/quota": {
+        "/admin/versions": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "查看当前用户的Token配额使用情况",
+                "description": "分页查询知识库版本，新版本在前",
                 "consumes": [
                     "application/json"
                 ],
@@ -1052,9 +1369,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户"
+                    "知识库版本"
+                ],
+                "summary": "获取版本列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed"
+                        ],
+                        "type": "string",
+                        "description": "状态筛选",
+                        "name": "status",
+                        "in": "query"
+                    }
                 ],
-                "summary": "获取当前用户Token配额",
                 "responses": {
                     "200": {
                         "description": "获取成功",
@@ -1067,7 +1410,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UsageStats"
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionListResponse"
                                         }
                                     }
                                 }
@@ -1079,68 +1422,2068 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
                         }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.AdjustQuotaRequest": {
-            "type": "object",
-            "required": [
-                "newQuota",
-                "userId"
-            ],
-            "properties": {
-                "newQuota": {
-                    "type": "integer",
-                    "minimum": 0
-                },
-                "userId": {
-                    "type": "string"
-                }
-            }
-        },
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.ChangePasswordRequest": {
-            "type": "object",
-            "required": [
-                "newPassword",
-                "oldPassword"
-            ],
-            "properties": {
-                "newPassword": {
-                    "type": "string",
-                    "minLength": 8
-                },
-                "oldPassword": {
-                    "type": "string"
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "冻结当前所有已索引文档的分块集合，计算 Merkle Root 与每个分块的 Merkle 证明。新版本为 pending 状态，上链确认后分块证明才对外生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "知识库版本"
+                ],
+                "summary": "创建知识库版本",
+                "parameters": [
+                    {
+                        "description": "版本描述",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.CreateVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "没有已索引的文档或内容未变化",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
                 }
             }
         },
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.LoginRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+        "/admin/versions/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "按分块对比两个版本，返回新增、删除、修改的分块与未变化的分块数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "知识库版本"
+                ],
+                "summary": "对比版本",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "基准版本ID",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "目标版本ID",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "对比成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionDiffResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "版本不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
                 }
             }
         },
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse": {
-            "type": "object",
-            "properties": {
+        "/admin/versions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "获取版本信息及其包含的文档",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "知识库版本"
+                ],
+                "summary": "获取版本详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "版本ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionDetailResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "版本不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/versions/{id}/anchor": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "将版本 Merkle Root 写入链上存证合约。交易发出后立即返回 (版本仍为 pending 并带 txHash)，后台等待回执达到确认数后版本自动变为 confirmed；链上已存证该版本时直接确认",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "知识库版本"
+                ],
+                "summary": "提交版本上链",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "版本ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "交易已提交",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "版本不是待确认状态",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "版本不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "未启用区块链存证",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "区块链节点错误或链上存证不一致",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/versions/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "记录版本 Merkle Root 的上链交易，版本变为 confirmed，其文档标记为已存证 (minted)。启用区块链时核对交易回执中的存证事件与合约 getRoot，二者均须等于版本 Merkle Root，区块号以回执为准；未启用区块链时仅登记交易信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "知识库版本"
+                ],
+                "summary": "确认版本上链",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "版本ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "上链交易信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ConfirmVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "确认成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、版本不是待确认状态或上链交易无效",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "版本不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "链上 Merkle Root 与版本不一致或链上查询失败",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "使用邮箱和密码登录系统",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "用户登录",
+                "parameters": [
+                    {
+                        "description": "登录请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "邮箱或密码错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "账户被禁用或锁定",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login/phone": {
+            "post": {
+                "description": "使用手机号和验证码登录系统",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "手机验证码登录",
+                "parameters": [
+                    {
+                        "description": "手机登录请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.PhoneLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "验证码错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "登出当前用户，使访问令牌失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "用户登出",
+                "responses": {
+                    "200": {
+                        "description": "登出成功",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "获取当前登录用户的详细信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取当前用户",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}": {
+            "get": {
+                "description": "重定向到第三方OAuth授权页面",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "OAuth 第三方登录",
+                "parameters": [
+                    {
+                        "enum": [
+                            "google",
+                            "github",
+                            "wechat"
+                        ],
+                        "type": "string",
+                        "description": "OAuth提供商",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "重定向到OAuth授权页面"
+                    },
+                    "501": {
+                        "description": "OAuth登录尚未实现",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}/callback": {
+            "get": {
+                "description": "处理OAuth授权回调,完成登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "OAuth 回调",
+                "parameters": [
+                    {
+                        "enum": [
+                            "google",
+                            "github",
+                            "wechat"
+                        ],
+                        "type": "string",
+                        "description": "OAuth提供商",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "授权码",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "状态码",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "OAuth回调尚未实现",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "使用刷新令牌获取新的访问令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "刷新令牌",
+                "parameters": [
+                    {
+                        "description": "刷新令牌请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "刷新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.TokenPair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "刷新令牌无效或已过期",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "使用邮箱注册新用户，需先获取验证码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "用户注册",
+                "parameters": [
+                    {
+                        "description": "注册请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "注册成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或验证码无效",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "验证码尝试次数过多",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/send-code": {
+            "post": {
+                "description": "发送邮箱验证码，用于注册或重置密码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "发送验证码",
+                "parameters": [
+                    {
+                        "description": "发送验证码请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SendCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "验证码发送成功",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "请求过于频繁",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "执行检索 → 验证 → 提示词 → LLM → 引用一致性校验流程，以 SSE 流式返回。事件类型: warning, citation, verification, token, check, usage, refusal, done, error。仅检索 asOfDate (默认当天) 有效的条文，问题引用的条文在该日已失效或尚未生效时先返回 warning；没有足够相关且通过验证的法条时不调用 LLM，依次返回 refusal (拒答原因与候选条文)、token (标准拒答回复) 与 done；check 事件给出回答实际引用的条文；其 answer 字段非空时为引用校验处理后的完整回答，应替换已流式输出的内容",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "聊天"
+                ],
+                "summary": "智能问答",
+                "parameters": [
+                    {
+                        "description": "聊天请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSE 事件流",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "额度不足或匿名对话次数已用完",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "会话不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/messages/{id}/evidence": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "导出助手回答的签名证据包 (ZIP)：问题、回答、引用原文、ContentHash 与 Merkle 证明、所属版本 Root 与上链交易，以及服务端对全部内容的签名。可使用 verify-evidence 命令离线复核。未配置独立的证据包签名私钥时不提供该接口",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "聊天"
+                ],
+                "summary": "导出回答证据包",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "消息ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "证据包",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "不是助手回答",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "消息不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "按最后消息时间倒序游标分页返回当前用户 (或匿名用户) 的会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天"
+                ],
+                "summary": "获取会话列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "分页游标 (上一页返回的 nextCursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SessionListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "创建空会话，匿名用户最多只能拥有一个会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天"
+                ],
+                "summary": "创建会话",
+                "parameters": [
+                    {
+                        "description": "会话信息",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.CreateSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SessionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "匿名会话数已达上限",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/sessions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "返回会话信息及全部消息，助手消息附带法条引用与链上验证信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天"
+                ],
+                "summary": "获取会话详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SessionDetailResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "会话不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "软删除会话，删除后不再出现在列表中",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天"
+                ],
+                "summary": "删除会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "会话不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "更新当前用户的姓名、手机、头像等信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "更新个人资料",
+                "parameters": [
+                    {
+                        "description": "更新请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "修改当前用户密码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "修改密码",
+                "parameters": [
+                    {
+                        "description": "修改密码请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "密码修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "旧密码错误或请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/quota": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "查看当前用户的Token配额使用情况",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "获取当前用户Token配额",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.UsageStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify/chunk": {
+            "post": {
+                "description": "提交法条原文与 Merkle 证明，验证其是否属于链上存证的知识库版本。未指定版本时按证明计算出的 Root 匹配版本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "公开验证"
+                ],
+                "summary": "验证分块文本",
+                "parameters": [
+                    {
+                        "description": "分块文本与证明",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VerifyChunkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "验证完成 (以 verified 判断是否通过)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChunkProof"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify/citation/{id}": {
+            "get": {
+                "description": "重新验证回答中引用的法条文本，返回文本、内容哈希、叶子哈希、Merkle 证明、链上 Root、交易哈希与区块号。响应附带算法说明，第三方可据此在链上独立复核",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "公开验证"
+                ],
+                "summary": "验证回答引用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "引用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "验证完成 (以 verified 判断是否通过)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.CitationProofResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的引用ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "引用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify/version/{id}": {
+            "get": {
+                "description": "比对知识库版本记录的 Merkle Root 与链上存证，返回交易哈希、区块号与合约信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "公开验证"
+                ],
+                "summary": "验证知识库版本",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "版本ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "验证完成 (以 verified 判断是否通过)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionProofResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的版本ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "版本不存在",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.AdjustQuotaRequest": {
+            "type": "object",
+            "required": [
+                "newQuota",
+                "userId"
+            ],
+            "properties": {
+                "newQuota": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ArticleValidityRequest": {
+            "type": "object",
+            "required": [
+                "articleNumber"
+            ],
+            "properties": {
+                "articleNumber": {
+                    "description": "条号，与分块的 articleNumber 一致，如 \"107\"",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "effectiveDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "expiryDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "supersededBy": {
+                    "description": "替代条文说明，如 \"《民法典》第五百七十七条\"",
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ArticleValidityResponse": {
+            "type": "object",
+            "properties": {
+                "articleNumber": {
+                    "type": "string"
+                },
+                "effectiveDate": {
+                    "type": "string"
+                },
+                "expiryDate": {
+                    "type": "string"
+                },
+                "supersededBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ChainInfo": {
+            "type": "object",
+            "properties": {
+                "chainId": {
+                    "type": "integer"
+                },
+                "contractAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "oldPassword"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "oldPassword": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ChatRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "asOfDate": {
+                    "description": "适用日期 (YYYY-MM-DD)，按该日有效的法律回答，为空时为当天",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "sessionId": {
+                    "description": "为空时自动创建新会话",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ChunkProof": {
+            "type": "object",
+            "properties": {
+                "anchoredRoot": {
+                    "description": "从链上合约读取的 Merkle Root",
+                    "type": "string"
+                },
+                "blockNumber": {
+                    "type": "integer"
+                },
+                "chain": {
+                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChainInfo"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "computedRoot": {
+                    "type": "string"
+                },
+                "contentHash": {
+                    "type": "string"
+                },
+                "failReason": {
+                    "type": "string"
+                },
+                "leafHash": {
+                    "type": "string"
+                },
+                "merkleIndex": {
+                    "type": "integer"
+                },
+                "merkleProof": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ProofMethod"
+                },
+                "txHash": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "versionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.CitationProofResponse": {
+            "type": "object",
+            "properties": {
+                "anchoredRoot": {
+                    "description": "从链上合约读取的 Merkle Root",
+                    "type": "string"
+                },
+                "articleNumber": {
+                    "type": "string"
+                },
+                "blockNumber": {
+                    "type": "integer"
+                },
+                "chain": {
+                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChainInfo"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "chunkId": {
+                    "type": "string"
+                },
+                "citationId": {
+                    "type": "integer"
+                },
+                "computedRoot": {
+                    "type": "string"
+                },
+                "contentHash": {
+                    "type": "string"
+                },
+                "failReason": {
+                    "type": "string"
+                },
+                "lawHierarchy": {
+                    "type": "string"
+                },
+                "leafHash": {
+                    "type": "string"
+                },
+                "merkleIndex": {
+                    "type": "integer"
+                },
+                "merkleProof": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ProofMethod"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "versionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.CitationResponse": {
+            "type": "object",
+            "properties": {
+                "articleNumber": {
+                    "type": "string"
+                },
+                "blockNumber": {
+                    "type": "integer"
+                },
+                "chunkHash": {
+                    "type": "string"
+                },
+                "chunkId": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "可通过 GET /verify/citation/{id} 公开验证",
+                    "type": "integer"
+                },
+                "lawHierarchy": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "verificationId": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "verifiedAt": {
+                    "type": "string"
+                },
+                "versionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ConfirmVersionRequest": {
+            "type": "object",
+            "required": [
+                "txHash"
+            ],
+            "properties": {
+                "blockNumber": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.CreateSessionRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.CreateVersionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentListResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.DocumentResponse": {
+            "type": "object",
+            "properties": {
+                "articles": {
+                    "description": "单独设置时效的条文",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ArticleValidityResponse"
+                    }
+                },
+                "chunkCount": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "effectiveDate": {
+                    "type": "string"
+                },
+                "expiryDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isMinted": {
+                    "type": "boolean"
+                },
+                "lawName": {
+                    "type": "string"
+                },
+                "lawType": {
+                    "type": "string"
+                },
+                "mimeType": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "originalName": {
+                    "type": "string"
+                },
+                "processError": {
+                    "type": "string"
+                },
+                "publishOrg": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "sourceUrl": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supersededBy": {
+                    "description": "替代本法的文档 ID",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "uploadedBy": {
+                    "type": "string"
+                },
+                "versionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityFinding": {
+            "type": "object",
+            "properties": {
+                "chunkId": {
+                    "type": "string"
+                },
+                "documentId": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "tampered": {
+                    "type": "boolean"
+                },
+                "versionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityReportListResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityReportResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityReportResponse": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "failReasons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.IntegrityFinding"
+                    }
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "resumes": {
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tampered": {
+                    "type": "integer"
+                },
+                "totalChunks": {
+                    "type": "integer"
+                },
+                "triggeredBy": {
+                    "type": "string"
+                },
+                "unverified": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "integer"
+                },
+                "versionCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.LoginResponse": {
+            "type": "object",
+            "properties": {
                 "token": {
                     "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.TokenPair"
                 },
                 "user": {}
             }
         },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "citations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.CitationResponse"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "tokensIn": {
+                    "type": "integer"
+                },
+                "tokensOut": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_lexveritas_lex-veritas-backend_internal_dto.PhoneLoginRequest": {
             "type": "object",
             "required": [
@@ -1151,67 +3494,225 @@ const docTemplate = `{
                 "code": {
                     "type": "string"
                 },
-                "phone": {
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.ProofMethod": {
+            "type": "object",
+            "properties": {
+                "contentHash": {
+                    "type": "string"
+                },
+                "leafHash": {
+                    "type": "string"
+                },
+                "nodeHash": {
+                    "type": "string"
+                },
+                "rootLookup": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.RegisterURLRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "effectiveDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "expiryDate": {
+                    "description": "YYYY-MM-DD，导入已失效的历史法律时填写",
+                    "type": "string"
+                },
+                "lawName": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "lawType": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "description": "为空时使用网页标题",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "publishOrg": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.SendCodeRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "purpose"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "register",
+                        "reset_password"
+                    ]
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.SessionDetailResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastMessageAt": {
+                    "type": "string"
+                },
+                "messageCount": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.MessageResponse"
+                    }
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
+                },
+                "tokensUsed": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.RefreshRequest": {
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.SessionListResponse": {
             "type": "object",
-            "required": [
-                "refreshToken"
-            ],
             "properties": {
-                "refreshToken": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.SessionResponse"
+                    }
+                },
+                "nextCursor": {
                     "type": "string"
                 }
             }
         },
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.RegisterRequest": {
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.SessionResponse": {
             "type": "object",
-            "required": [
-                "code",
-                "email",
-                "name",
-                "password"
-            ],
             "properties": {
-                "code": {
+                "createdAt": {
                     "type": "string"
                 },
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2
+                "lastMessageAt": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "messageCount": {
+                    "type": "integer"
                 },
-                "phone": {
+                "summary": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
+                },
+                "tokensUsed": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_lexveritas_lex-veritas-backend_internal_dto.SendCodeRequest": {
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.SystemConfigResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "purpose"
-            ],
             "properties": {
-                "email": {
+                "category": {
                     "type": "string"
                 },
-                "purpose": {
-                    "type": "string",
-                    "enum": [
-                        "register",
-                        "reset_password"
-                    ]
+                "description": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
@@ -1234,18 +3735,45 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateDocumentValidityRequest": {
+            "type": "object",
+            "properties": {
+                "articles": {
+                    "description": "单独设置时效的条文 (如被修正案删除或修改的条文)",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ArticleValidityRequest"
+                    }
+                },
+                "effectiveDate": {
+                    "description": "YYYY-MM-DD，为空表示未知",
+                    "type": "string"
+                },
+                "expiryDate": {
+                    "description": "YYYY-MM-DD，当日起失效，为空表示现行有效",
+                    "type": "string"
+                },
+                "supersededBy": {
+                    "description": "替代本法的文档 ID，如《合同法》由《民法典》替代",
+                    "type": "string",
+                    "maxLength": 36
+                }
+            }
+        },
         "github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateProfileRequest": {
             "type": "object",
             "properties": {
                 "avatar": {
+                    "description": "允许空字符串以支持清空操作",
                     "type": "string"
                 },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2
+                    "description": "允许空字符串以支持清空操作",
+                    "type": "string"
                 },
                 "phone": {
+                    "description": "允许空字符串以支持清空操作",
                     "type": "string"
                 }
             }
@@ -1282,6 +3810,15 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.UpdateSystemConfigRequest": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
         "github_com_lexveritas_lex-veritas-backend_internal_dto.UsageStats": {
             "type": "object",
             "properties": {
@@ -1361,6 +3898,241 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VerifyChunkRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "merkleProof": {
+                    "type": "array",
+                    "maxItems": 64,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 20000
+                },
+                "versionId": {
+                    "description": "为空时按证明计算出的 Root 匹配版本",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionChunkDiff": {
+            "type": "object",
+            "properties": {
+                "articleNumber": {
+                    "type": "string"
+                },
+                "chunkId": {
+                    "type": "string"
+                },
+                "documentId": {
+                    "type": "string"
+                },
+                "newHash": {
+                    "type": "string"
+                },
+                "oldHash": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionDetailResponse": {
+            "type": "object",
+            "properties": {
+                "blockNumber": {
+                    "type": "integer"
+                },
+                "chunkCount": {
+                    "type": "integer"
+                },
+                "confirmedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionDocumentResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "merkleRoot": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionDiffResponse": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionChunkDiff"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "fromRoot": {
+                    "type": "string"
+                },
+                "modified": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionChunkDiff"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionChunkDiff"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "toRoot": {
+                    "type": "string"
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionDocumentResponse": {
+            "type": "object",
+            "properties": {
+                "chunkCount": {
+                    "description": "纳入该版本的分块数",
+                    "type": "integer"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lawName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionListResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.VersionResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionProofResponse": {
+            "type": "object",
+            "properties": {
+                "anchoredRoot": {
+                    "type": "string"
+                },
+                "blockNumber": {
+                    "type": "integer"
+                },
+                "chain": {
+                    "$ref": "#/definitions/github_com_lexveritas_lex-veritas-backend_internal_dto.ChainInfo"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "chunkCount": {
+                    "type": "integer"
+                },
+                "failReason": {
+                    "type": "string"
+                },
+                "merkleRoot": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                },
+                "verified": {
+                    "description": "链上 Root 与版本记录一致",
+                    "type": "boolean"
+                },
+                "versionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lexveritas_lex-veritas-backend_internal_dto.VersionResponse": {
+            "type": "object",
+            "properties": {
+                "blockNumber": {
+                    "type": "integer"
+                },
+                "chunkCount": {
+                    "type": "integer"
+                },
+                "confirmedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "merkleRoot": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_lexveritas_lex-veritas-backend_internal_pkg_response.Response": {
             "type": "object",
             "properties": {
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// anchorContractABI 存证合约 ABI，汇编源码见 contracts/KnowledgeAnchor.easm
+const anchorContractABI = `[
+	{"type":"function","name":"anchorRoot","stateMutability":"nonpayable",
+	 "inputs":[{"name":"versionId","type":"uint256"},{"name":"root","type":"bytes32"}],"outputs":[]},
+	{"type":"function","name":"getRoot","stateMutability":"view",
+	 "inputs":[{"name":"versionId","type":"uint256"}],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"owner","stateMutability":"view",
+	 "inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"event","name":"RootAnchored","anonymous":false,
+	 "inputs":[{"name":"versionId","type":"uint256","indexed":true},{"name":"root","type":"bytes32","indexed":false}]},
+	{"type":"error","name":"NotOwner","inputs":[]},
+	{"type":"error","name":"EmptyRoot","inputs":[]},
+	{"type":"error","name":"AlreadyAnchored","inputs":[]}
+]`
+
+// anchorContractBin 存证合约部署字节码 (17 字节构造函数 + 243 字节运行时代码)
+const anchorContractBin = "0x336000556100f3806100116000396000f3" +
+	"3461003457600436106100345760003560e01c806373a931d4146100395780639b24b3b01461009b5780638da5cb5b14" +
+	"6100bd575b600080fd5b60443610610034576000543314156100c95760243580156100d3576004356000526001602052" +
+	"604060002080546100dd578190556000526004357fcc391fab6dab87e8e17244276fa0a6b263c0e609ede4fae46c0da6" +
+	"bb9f06ce2760206000a2005b6024361061003457600435600052600160205260406000205460005260206000f35b6000" +
+	"5460005260206000f35b6330cd74716100e7565b6353ce4ece6100e7565b63a0094ce36100e7565b60e01b6000526004" +
+	"6000fd"
+
+// anchorABI 解析后的存证合约 ABI
+var anchorABI = mustParseABI(anchorContractABI)
+
+// rootAnchoredTopic RootAnchored 事件签名哈希
+var rootAnchoredTopic = crypto.Keccak256Hash([]byte("RootAnchored(uint256,bytes32)"))
+
+// mustParseABI 解析内置 ABI，失败说明常量本身有误
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(fmt.Sprintf("存证合约 ABI 无效: %v", err))
+	}
+	return parsed
+}
+
+// decodeAnchorError 将合约自定义错误 (eth_call / eth_estimateGas 返回的 revert 数据) 转换为客户端错误
+func decodeAnchorError(err error) error {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err
+	}
+	revert := common.FromHex(data)
+	if len(revert) < 4 {
+		return err
+	}
+
+	var id [4]byte
+	copy(id[:], revert[:4])
+	abiErr, lookupErr := anchorABI.ErrorByID(id)
+	if lookupErr != nil {
+		return err
+	}
+	switch abiErr.Name {
+	case "NotOwner":
+		return ErrNotContractOwner
+	case "EmptyRoot":
+		return ErrEmptyRoot
+	case "AlreadyAnchored":
+		return ErrRootAlreadyAnchored
+	}
+	return err
+}
+
+// DeployAnchorContract 部署存证合约，签名账户成为合约 owner，返回合约地址
+func (c *BlockchainClient) DeployAnchorContract(ctx context.Context) (string, error) {
+	if c.key == nil {
+		return "", ErrNoSigner
+	}
+
+	txHash, err := c.send(ctx, nil, common.FromHex(anchorContractBin), nil)
+	if err != nil {
+		return "", err
+	}
+	receipt, err := c.WaitReceipt(ctx, txHash)
+	if err != nil {
+		return "", err
+	}
+	return receipt.ContractAddress, nil
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+)
+
+// testChain go-ethereum 模拟链
+type testChain struct {
+	sim    *simulated.Backend
+	owner  *ecdsa.PrivateKey
+	other  *ecdsa.PrivateKey
+	client simulated.Client
+}
+
+// autoCommitBackend 发送交易后立即出块的模拟链后端，用于需要等待回执的调用
+type autoCommitBackend struct {
+	simulated.Client
+	sim *simulated.Backend
+}
+
+func (b *autoCommitBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.Client.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.sim.Commit()
+	return nil
+}
+
+// newTestChain 创建为两个账户预置余额的模拟链
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	owner, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(owner.PublicKey): {Balance: balance},
+		crypto.PubkeyToAddress(other.PublicKey): {Balance: balance},
+	})
+	t.Cleanup(func() { _ = sim.Close() })
+	return &testChain{sim: sim, owner: owner, other: other, client: sim.Client()}
+}
+
+// newClient 使用指定私钥与合约地址创建区块链客户端，autoCommit 为 true 时发送交易后立即出块
+func (c *testChain) newClient(t *testing.T, key *ecdsa.PrivateKey, contract string, autoCommit bool) *BlockchainClient {
+	t.Helper()
+	var backend ChainBackend = c.client
+	if autoCommit {
+		backend = &autoCommitBackend{Client: c.client, sim: c.sim}
+	}
+	cfg := &BlockchainConfig{
+		ContractAddress: contract,
+		ReceiptTimeout:  5 * time.Second,
+		PollInterval:    10 * time.Millisecond,
+	}
+	if key != nil {
+		cfg.PrivateKey = common.Bytes2Hex(crypto.FromECDSA(key))
+	}
+	bc, err := NewBlockchainClientWithBackend(context.Background(), backend, cfg)
+	if err != nil {
+		t.Fatalf("NewBlockchainClientWithBackend() error = %v", err)
+	}
+	return bc
+}
+
+// deploy 由 owner 部署存证合约，返回合约地址
+func (c *testChain) deploy(t *testing.T) string {
+	t.Helper()
+	addr, err := c.newClient(t, c.owner, "", true).DeployAnchorContract(context.Background())
+	if err != nil {
+		t.Fatalf("DeployAnchorContract() error = %v", err)
+	}
+	return addr
+}
+
+// callMsg 构造存证合约只读调用
+func callMsg(t *testing.T, contract, method string, args ...interface{}) ethereum.CallMsg {
+	t.Helper()
+	data, err := anchorABI.Pack(method, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress(contract)
+	return ethereum.CallMsg{To: &to, Data: data}
+}
+
+// testRoot 由字符串生成测试用 Merkle Root
+func testRoot(s string) string {
+	return merkle.LeafHash([]byte(s)).Hex()
+}
+
+func TestDeployAnchorContract(t *testing.T) {
+	chain := newTestChain(t)
+	addr := chain.deploy(t)
+
+	code, err := chain.client.CodeAt(context.Background(), common.HexToAddress(addr), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 构造函数 17 字节，其后为运行时代码
+	runtime := common.FromHex(anchorContractBin)[17:]
+	if len(code) != 243 || !bytes.Equal(code, runtime) {
+		t.Errorf("运行时代码 %d 字节，与部署字节码不一致 (want 243)", len(code))
+	}
+
+	// 部署账户成为 owner
+	out, err := chain.client.CallContract(context.Background(), callMsg(t, addr, "owner"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := anchorABI.Unpack("owner", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := values[0].(common.Address), crypto.PubkeyToAddress(chain.owner.PublicKey); got != want {
+		t.Errorf("owner = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestAnchorRootAndGetRoot(t *testing.T) {
+	ctx := context.Background()
+	chain := newTestChain(t)
+	bc := chain.newClient(t, chain.owner, chain.deploy(t), true)
+	root := testRoot("v7")
+
+	if _, err := bc.GetMerkleRoot(ctx, 7); !errors.Is(err, ErrRootNotAnchored) {
+		t.Fatalf("GetMerkleRoot() before anchor error = %v, want ErrRootNotAnchored", err)
+	}
+	if _, err := bc.FindAnchor(ctx, 7); !errors.Is(err, ErrRootNotAnchored) {
+		t.Fatalf("FindAnchor() before anchor error = %v, want ErrRootNotAnchored", err)
+	}
+
+	txHash, err := bc.SubmitRoot(ctx, 7, root)
+	if err != nil {
+		t.Fatalf("SubmitRoot() error = %v", err)
+	}
+	receipt, err := bc.WaitReceipt(ctx, txHash)
+	if err != nil {
+		t.Fatalf("WaitReceipt() error = %v", err)
+	}
+
+	got, err := bc.GetMerkleRoot(ctx, 7)
+	if err != nil || got != root {
+		t.Errorf("GetMerkleRoot() = %s, %v, want %s", got, err, root)
+	}
+	if _, err := bc.GetMerkleRoot(ctx, 8); !errors.Is(err, ErrRootNotAnchored) {
+		t.Errorf("GetMerkleRoot(8) error = %v, want ErrRootNotAnchored", err)
+	}
+
+	// RootAnchored(versionId indexed, root) 事件
+	want := Anchor{VersionID: 7, Root: root, TxHash: txHash, BlockNumber: receipt.BlockNumber}
+	anchor, err := bc.FindAnchor(ctx, 7)
+	if err != nil || *anchor != want {
+		t.Errorf("FindAnchor() = %+v, %v, want %+v", anchor, err, want)
+	}
+	anchor, err = bc.AnchorInTx(ctx, txHash, 7)
+	if err != nil || *anchor != want {
+		t.Errorf("AnchorInTx() = %+v, %v, want %+v", anchor, err, want)
+	}
+	if _, err := bc.AnchorInTx(ctx, txHash, 8); !errors.Is(err, ErrAnchorNotInTx) {
+		t.Errorf("AnchorInTx(other version) error = %v, want ErrAnchorNotInTx", err)
+	}
+
+	// 只读客户端可以查询，不能提交
+	reader := chain.newClient(t, nil, bc.ContractAddress(), false)
+	if got, err := reader.GetMerkleRoot(ctx, 7); err != nil || got != root {
+		t.Errorf("read-only GetMerkleRoot() = %s, %v", got, err)
+	}
+	if _, err := reader.SubmitRoot(ctx, 9, root); !errors.Is(err, ErrNoSigner) {
+		t.Errorf("read-only SubmitRoot() error = %v, want ErrNoSigner", err)
+	}
+}
+
+func TestAnchorContractErrors(t *testing.T) {
+	ctx := context.Background()
+	chain := newTestChain(t)
+	contract := chain.deploy(t)
+	owner := chain.newClient(t, chain.owner, contract, true)
+
+	if _, err := owner.SubmitRoot(ctx, 1, testRoot("v1")); err != nil {
+		t.Fatalf("SubmitRoot() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		client  *BlockchainClient
+		version int64
+		root    string
+		want    error
+	}{
+		{name: "NotOwner", client: chain.newClient(t, chain.other, contract, true), version: 2, root: testRoot("v2"), want: ErrNotContractOwner},
+		{name: "EmptyRoot", client: owner, version: 2, root: merkle.Hash{}.Hex(), want: ErrEmptyRoot},
+		{name: "AlreadyAnchored", client: owner, version: 1, root: testRoot("v1-changed"), want: ErrRootAlreadyAnchored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.client.SubmitRoot(ctx, tt.version, tt.root); !errors.Is(err, tt.want) {
+				t.Errorf("SubmitRoot() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 被拒绝的提交不改变已存证的 Root
+	if got, _ := owner.GetMerkleRoot(ctx, 1); got != testRoot("v1") {
+		t.Errorf("GetMerkleRoot(1) = %s after rejected overwrite", got)
+	}
+}
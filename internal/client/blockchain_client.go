@@ -1,27 +1,560 @@
 package client
 
-// BlockchainClient 区块链客户端（占位实现）
-// TODO: 实现与 Polygon Amoy 测试网的交互逻辑
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+)
+
+const (
+	// defaultReceiptTimeout 默认等待回执超时
+	defaultReceiptTimeout = 2 * time.Minute
+	// defaultChainPollInterval 默认回执轮询间隔
+	defaultChainPollInterval = 2 * time.Second
+	// chainDialTimeout 连接节点与启动检查超时
+	chainDialTimeout = 10 * time.Second
+	// gasEstimateMarginPercent 估算 Gas 上浮比例
+	gasEstimateMarginPercent = 20
+	// feeBumpPercent 替换交易的费用提升比例 (节点要求至少 10%)
+	feeBumpPercent = 25
+)
+
+var (
+	ErrNoSigner               = errors.New("未配置签名私钥，区块链客户端为只读")
+	ErrContractNotConfigured  = errors.New("未配置存证合约地址")
+	ErrContractNotDeployed    = errors.New("存证合约地址上没有合约代码")
+	ErrChainIDMismatch        = errors.New("节点链 ID 与配置不一致")
+	ErrRootNotAnchored        = errors.New("该版本的 Merkle Root 尚未上链")
+	ErrRootAlreadyAnchored    = errors.New("该版本的 Merkle Root 已上链，不可覆盖")
+	ErrNotContractOwner       = errors.New("签名账户不是存证合约的 owner")
+	ErrEmptyRoot              = errors.New("Merkle Root 不能为空")
+	ErrTxReverted             = errors.New("交易执行失败")
+	ErrTxNotFound             = errors.New("交易不存在或已被节点丢弃")
+	ErrTxPending              = errors.New("交易尚未打包或确认数不足")
+	ErrAnchorNotInTx          = errors.New("交易中没有该版本的存证事件")
+	ErrTxAlreadyMined         = errors.New("交易已打包，无需替换")
+	errInvalidContractAddress = errors.New("存证合约地址格式错误")
+)
+
+// BlockchainConfig 区块链配置别名
+type BlockchainConfig = config.BlockchainConfig
+
+// ChainBackend EVM 节点 JSON-RPC 接口
+// *ethclient.Client 与 go-ethereum 的 simulated.Client 均实现该接口，测试时可注入模拟链
+type ChainBackend interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// TxReceipt 已确认交易的回执
+type TxReceipt struct {
+	TxHash          string
+	BlockNumber     int64
+	GasUsed         uint64
+	ContractAddress string // 仅合约部署交易
+}
+
+// Anchor 链上存证记录 (RootAnchored 事件)
+type Anchor struct {
+	VersionID   int64
+	Root        string
+	TxHash      string
+	BlockNumber int64
+}
+
+// BlockchainClient EVM 存证合约客户端
+//
+// nonce 每次发送前从节点读取 pending nonce (含交易池中的交易)，不在本地持久化，
+// 服务重启后可直接续用；发送过程串行化，避免并发请求取到相同 nonce。
+// 费用使用 EIP-1559 (baseFee*2 + tip，受 MaxFeeGwei 限制)，节点不支持时退回 legacy gasPrice。
 type BlockchainClient struct {
-	// contractAddress 智能合约地址
-	contractAddress string
+	backend        ChainBackend
+	contract       common.Address
+	key            *ecdsa.PrivateKey
+	from           common.Address
+	chainID        *big.Int
+	signer         types.Signer
+	gasLimit       uint64
+	maxFee         *big.Int
+	confirmations  uint64
+	receiptTimeout time.Duration
+	pollInterval   time.Duration
+
+	sendMu sync.Mutex
+}
+
+// NewBlockchainClient 连接 JSON-RPC 节点并创建区块链客户端
+func NewBlockchainClient(cfg *BlockchainConfig) (*BlockchainClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), chainDialTimeout)
+	defer cancel()
+
+	backend, err := ethclient.DialContext(ctx, cfg.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("连接区块链节点失败: %w", err)
+	}
+	c, err := NewBlockchainClientWithBackend(ctx, backend, cfg)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewBlockchainClientWithBackend 使用指定节点后端创建区块链客户端 (本地开发链、模拟链)
+func NewBlockchainClientWithBackend(ctx context.Context, backend ChainBackend, cfg *BlockchainConfig) (*BlockchainClient, error) {
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("读取链 ID 失败: %w", err)
+	}
+	if cfg.ChainID != 0 && chainID.Int64() != cfg.ChainID {
+		return nil, fmt.Errorf("%w: 配置 %d，节点 %s", ErrChainIDMismatch, cfg.ChainID, chainID)
+	}
+
+	c := &BlockchainClient{
+		backend:        backend,
+		chainID:        chainID,
+		signer:         types.LatestSignerForChainID(chainID),
+		gasLimit:       cfg.GasLimit,
+		confirmations:  cfg.Confirmations,
+		receiptTimeout: cfg.ReceiptTimeout,
+		pollInterval:   cfg.PollInterval,
+	}
+	if c.confirmations == 0 {
+		c.confirmations = 1
+	}
+	if c.receiptTimeout <= 0 {
+		c.receiptTimeout = defaultReceiptTimeout
+	}
+	if c.pollInterval <= 0 {
+		c.pollInterval = defaultChainPollInterval
+	}
+	if cfg.MaxFeeGwei > 0 {
+		c.maxFee, _ = new(big.Float).Mul(big.NewFloat(cfg.MaxFeeGwei), big.NewFloat(1e9)).Int(nil)
+	}
+
+	if cfg.PrivateKey != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(cfg.PrivateKey), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("解析签名私钥失败: %w", err)
+		}
+		c.key = key
+		c.from = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	if cfg.ContractAddress != "" {
+		if !common.IsHexAddress(cfg.ContractAddress) {
+			return nil, errInvalidContractAddress
+		}
+		c.contract = common.HexToAddress(cfg.ContractAddress)
+		code, err := backend.CodeAt(ctx, c.contract, nil)
+		if err != nil {
+			return nil, fmt.Errorf("读取存证合约代码失败: %w", err)
+		}
+		if len(code) == 0 {
+			return nil, ErrContractNotDeployed
+		}
+	}
+
+	return c, nil
+}
+
+// Account 返回签名账户地址，只读客户端返回空字符串
+func (c *BlockchainClient) Account() string {
+	if c.key == nil {
+		return ""
+	}
+	return c.from.Hex()
+}
+
+// ContractAddress 返回存证合约地址
+func (c *BlockchainClient) ContractAddress() string {
+	return c.contract.Hex()
+}
+
+// ChainID 返回节点链 ID
+func (c *BlockchainClient) ChainID() int64 {
+	return c.chainID.Int64()
+}
+
+// ReceiptTimeout 返回等待回执超时
+func (c *BlockchainClient) ReceiptTimeout() time.Duration {
+	return c.receiptTimeout
+}
+
+// SubmitRoot 提交版本 Merkle Root 上链，返回交易哈希 (不等待打包)
+// 发送前通过 Gas 估算预执行，版本已存证、非 owner 等合约错误会在此直接返回
+func (c *BlockchainClient) SubmitRoot(ctx context.Context, versionID int64, root string) (string, error) {
+	if c.key == nil {
+		return "", ErrNoSigner
+	}
+	if c.contract == (common.Address{}) {
+		return "", ErrContractNotConfigured
+	}
+	hash, err := merkle.ParseHash(root)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := anchorABI.Pack("anchorRoot", big.NewInt(versionID), [32]byte(hash))
+	if err != nil {
+		return "", err
+	}
+	return c.send(ctx, &c.contract, data, nil)
+}
+
+// GetMerkleRoot 读取版本在链上存证的 Merkle Root，未存证时返回 ErrRootNotAnchored
+func (c *BlockchainClient) GetMerkleRoot(ctx context.Context, versionID int64) (string, error) {
+	if c.contract == (common.Address{}) {
+		return "", ErrContractNotConfigured
+	}
+
+	data, err := anchorABI.Pack("getRoot", big.NewInt(versionID))
+	if err != nil {
+		return "", err
+	}
+	out, err := c.backend.CallContract(ctx, ethereum.CallMsg{To: &c.contract, Data: data}, nil)
+	if err != nil {
+		return "", fmt.Errorf("读取链上 Merkle Root 失败: %w", decodeAnchorError(err))
+	}
+	values, err := anchorABI.Unpack("getRoot", out)
+	if err != nil {
+		return "", fmt.Errorf("解析链上 Merkle Root 失败: %w", err)
+	}
+
+	root := merkle.Hash(values[0].([32]byte))
+	if root == (merkle.Hash{}) {
+		return "", ErrRootNotAnchored
+	}
+	return root.Hex(), nil
+}
+
+// FindAnchor 查询版本的存证事件，用于找回交易哈希与区块号
+func (c *BlockchainClient) FindAnchor(ctx context.Context, versionID int64) (*Anchor, error) {
+	if c.contract == (common.Address{}) {
+		return nil, ErrContractNotConfigured
+	}
+
+	logs, err := c.backend.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{c.contract},
+		Topics: [][]common.Hash{
+			{rootAnchoredTopic},
+			{common.BigToHash(big.NewInt(versionID))},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询存证事件失败: %w", err)
+	}
+	// 合约保证每个版本只存证一次，被重组移除的日志跳过
+	for i := range logs {
+		if anchor := c.parseAnchor(&logs[i], versionID); anchor != nil {
+			return anchor, nil
+		}
+	}
+	return nil, ErrRootNotAnchored
+}
+
+// AnchorInTx 查询已确认交易回执中该版本的存证事件，用于核对手动登记的上链交易
+// 交易未打包或确认数不足时返回 ErrTxPending，交易未存证该版本时返回 ErrAnchorNotInTx
+func (c *BlockchainClient) AnchorInTx(ctx context.Context, txHash string, versionID int64) (*Anchor, error) {
+	if c.contract == (common.Address{}) {
+		return nil, ErrContractNotConfigured
+	}
+
+	receipt, err := c.confirmedReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, ErrTxPending
+	}
+	for _, l := range receipt.Logs {
+		if anchor := c.parseAnchor(l, versionID); anchor != nil {
+			return anchor, nil
+		}
+	}
+	return nil, ErrAnchorNotInTx
+}
+
+// parseAnchor 解析存证合约的 RootAnchored 事件，不是该版本的存证事件时返回 nil
+func (c *BlockchainClient) parseAnchor(l *types.Log, versionID int64) *Anchor {
+	if l.Removed || l.Address != c.contract || len(l.Data) != 32 || len(l.Topics) != 2 ||
+		l.Topics[0] != rootAnchoredTopic || l.Topics[1] != common.BigToHash(big.NewInt(versionID)) {
+		return nil
+	}
+	return &Anchor{
+		VersionID:   versionID,
+		Root:        merkle.Hash(l.Data).Hex(),
+		TxHash:      l.TxHash.Hex(),
+		BlockNumber: int64(l.BlockNumber),
+	}
+}
+
+// VerifyChunk 使用 Merkle 证明验证分块是否属于链上存证的版本
+func (c *BlockchainClient) VerifyChunk(ctx context.Context, versionID int64, contentHash string, merkleProof []string) (bool, error) {
+	root, err := c.GetMerkleRoot(ctx, versionID)
+	if err != nil {
+		return false, err
+	}
+	return merkle.VerifyContent(contentHash, merkleProof, root)
+}
+
+// WaitReceipt 等待交易打包并达到确认数
+// 交易执行失败返回 ErrTxReverted；交易既无回执也不在节点中时返回 ErrTxNotFound；
+// 节点临时错误 (网络抖动、交易索引未完成等) 继续轮询，直到 ctx 结束
+func (c *BlockchainClient) WaitReceipt(ctx context.Context, txHash string) (*TxReceipt, error) {
+	hash := common.HexToHash(txHash)
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		receipt, err := c.confirmedReceipt(ctx, hash)
+		switch {
+		case errors.Is(err, ErrTxReverted), errors.Is(err, ErrTxNotFound):
+			return nil, err
+		case err != nil:
+			lastErr = err
+		case receipt != nil:
+			out := &TxReceipt{
+				TxHash:      hash.Hex(),
+				BlockNumber: receipt.BlockNumber.Int64(),
+				GasUsed:     receipt.GasUsed,
+			}
+			if receipt.ContractAddress != (common.Address{}) {
+				out.ContractAddress = receipt.ContractAddress.Hex()
+			}
+			return out, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (最近一次错误: %v)", ctx.Err(), lastErr)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// confirmedReceipt 查询一次回执，未打包或确认数不足时返回 nil
+func (c *BlockchainClient) confirmedReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := c.backend.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		if _, _, err := c.backend.TransactionByHash(ctx, hash); errors.Is(err, ethereum.NotFound) {
+			return nil, ErrTxNotFound
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询交易回执失败: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: %s", ErrTxReverted, hash.Hex())
+	}
+
+	head, err := c.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询最新区块失败: %w", err)
+	}
+	if head+1 < receipt.BlockNumber.Uint64()+c.confirmations {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+// BumpFee 以相同 nonce、更高费用替换仍在交易池中的交易，返回新交易哈希
+// 原交易已打包时返回 ErrTxAlreadyMined
+func (c *BlockchainClient) BumpFee(ctx context.Context, txHash string) (string, error) {
+	if c.key == nil {
+		return "", ErrNoSigner
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	old, pending, err := c.backend.TransactionByHash(ctx, common.HexToHash(txHash))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return "", ErrTxNotFound
+		}
+		return "", fmt.Errorf("查询交易失败: %w", err)
+	}
+	if !pending {
+		return "", ErrTxAlreadyMined
+	}
+
+	tip, feeCap, err := c.suggestFees(ctx)
+	if err != nil {
+		return "", err
+	}
+	var inner types.TxData
+	if feeCap == nil {
+		inner = &types.LegacyTx{
+			Nonce:    old.Nonce(),
+			GasPrice: c.capFee(maxBig(bump(old.GasPrice()), tip)),
+			Gas:      old.Gas(),
+			To:       old.To(),
+			Data:     old.Data(),
+		}
+	} else {
+		inner = &types.DynamicFeeTx{
+			ChainID:   c.chainID,
+			Nonce:     old.Nonce(),
+			GasTipCap: c.capFee(maxBig(bump(old.GasTipCap()), tip)),
+			GasFeeCap: c.capFee(maxBig(bump(old.GasFeeCap()), feeCap)),
+			Gas:       old.Gas(),
+			To:        old.To(),
+			Data:      old.Data(),
+		}
+	}
+
+	tx, err := types.SignNewTx(c.key, c.signer, inner)
+	if err != nil {
+		return "", fmt.Errorf("签名交易失败: %w", err)
+	}
+	if err := c.backend.SendTransaction(ctx, tx); err != nil {
+		if isNonceTooLow(err) {
+			return "", ErrTxAlreadyMined
+		}
+		return "", fmt.Errorf("发送替换交易失败: %w", err)
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// send 签名并发送交易 (to 为 nil 时部署合约)，返回交易哈希
+func (c *BlockchainClient) send(ctx context.Context, to *common.Address, data []byte, value *big.Int) (string, error) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	gas := c.gasLimit
+	if gas == 0 {
+		estimated, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{From: c.from, To: to, Data: data, Value: value})
+		if err != nil {
+			return "", fmt.Errorf("估算 Gas 失败: %w", decodeAnchorError(err))
+		}
+		gas = estimated * (100 + gasEstimateMarginPercent) / 100
+	}
+
+	tip, feeCap, err := c.suggestFees(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 其他进程 (或重启前的本服务) 可能刚用掉 nonce，遇到 nonce 过低时重新读取一次
+	for attempt := 0; ; attempt++ {
+		nonce, err := c.backend.PendingNonceAt(ctx, c.from)
+		if err != nil {
+			return "", fmt.Errorf("读取账户 nonce 失败: %w", err)
+		}
+
+		var inner types.TxData
+		if feeCap == nil {
+			inner = &types.LegacyTx{Nonce: nonce, GasPrice: tip, Gas: gas, To: to, Value: value, Data: data}
+		} else {
+			inner = &types.DynamicFeeTx{
+				ChainID: c.chainID, Nonce: nonce, GasTipCap: tip, GasFeeCap: feeCap,
+				Gas: gas, To: to, Value: value, Data: data,
+			}
+		}
+		tx, err := types.SignNewTx(c.key, c.signer, inner)
+		if err != nil {
+			return "", fmt.Errorf("签名交易失败: %w", err)
+		}
+
+		err = c.backend.SendTransaction(ctx, tx)
+		if err == nil || isAlreadyKnown(err) {
+			return tx.Hash().Hex(), nil
+		}
+		if attempt == 0 && isNonceTooLow(err) {
+			continue
+		}
+		return "", fmt.Errorf("发送交易失败: %w", err)
+	}
+}
+
+// suggestFees 计算交易费用
+// EIP-1559 链返回 (tip, feeCap)；不支持 EIP-1559 的链 feeCap 为 nil，tip 即 legacy gasPrice
+func (c *BlockchainClient) suggestFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询最新区块失败: %w", err)
+	}
+
+	if head.BaseFee == nil {
+		price, err := c.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("查询 Gas 价格失败: %w", err)
+		}
+		return c.capFee(price), nil, nil
+	}
+
+	tip, err := c.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询 Gas 小费失败: %w", err)
+	}
+	// 预留两倍 baseFee，连续多个满块后交易仍可打包
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	feeCap = c.capFee(feeCap)
+	if tip.Cmp(feeCap) > 0 {
+		tip = feeCap
+	}
+	return tip, feeCap, nil
+}
+
+// capFee 将单位 Gas 费用限制在 MaxFeeGwei 以内
+func (c *BlockchainClient) capFee(v *big.Int) *big.Int {
+	if c.maxFee != nil && v.Cmp(c.maxFee) > 0 {
+		return new(big.Int).Set(c.maxFee)
+	}
+	return v
+}
+
+// bump 按 feeBumpPercent 提高费用
+func bump(v *big.Int) *big.Int {
+	out := new(big.Int).Mul(v, big.NewInt(100+feeBumpPercent))
+	return out.Div(out, big.NewInt(100))
 }
 
-// NewBlockchainClient 创建区块链客户端
-func NewBlockchainClient(contractAddress string) *BlockchainClient {
-	return &BlockchainClient{
-		contractAddress: contractAddress,
+// maxBig 返回较大值
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
 	}
+	return b
 }
 
-// GetMerkleRoot 获取链上 Merkle Root
-func (c *BlockchainClient) GetMerkleRoot(versionID int64) (string, error) {
-	// TODO: 实现具体的链上查询逻辑
-	return "", nil
+// isNonceTooLow 判断节点是否因 nonce 已被使用而拒绝交易 (JSON-RPC 错误只能按消息匹配)
+func isNonceTooLow(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
 }
 
-// VerifyChunk 验证 Chunk 的链上完整性
-func (c *BlockchainClient) VerifyChunk(chunkHash string, merkleProof []string) (bool, error) {
-	// TODO: 实现 Merkle Proof 验证逻辑
-	return false, nil
+// isAlreadyKnown 判断相同交易是否已在节点交易池中
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
 }
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNonceAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	chain := newTestChain(t)
+	contract := chain.deploy(t)
+
+	// 交易仍在交易池中时服务重启，新客户端从节点 pending nonce 续用，不会重复使用 nonce
+	first := chain.newClient(t, chain.owner, contract, false)
+	tx1, err := first.SubmitRoot(ctx, 1, testRoot("v1"))
+	if err != nil {
+		t.Fatalf("SubmitRoot(1) error = %v", err)
+	}
+	restarted := chain.newClient(t, chain.owner, contract, false)
+	tx2, err := restarted.SubmitRoot(ctx, 2, testRoot("v2"))
+	if err != nil {
+		t.Fatalf("SubmitRoot(2) after restart error = %v", err)
+	}
+
+	if n1, n2 := txNonce(t, chain, tx1), txNonce(t, chain, tx2); n2 != n1+1 {
+		t.Errorf("nonce after restart = %d, want %d", n2, n1+1)
+	}
+
+	chain.sim.Commit()
+	for version, hash := range map[int64]string{1: tx1, 2: tx2} {
+		if _, err := restarted.WaitReceipt(ctx, hash); err != nil {
+			t.Errorf("WaitReceipt(%d) error = %v", version, err)
+		}
+		if _, err := restarted.GetMerkleRoot(ctx, version); err != nil {
+			t.Errorf("GetMerkleRoot(%d) error = %v", version, err)
+		}
+	}
+
+	// 交易打包后再次重启，nonce 继续递增
+	tx3, err := chain.newClient(t, chain.owner, contract, false).SubmitRoot(ctx, 3, testRoot("v3"))
+	if err != nil {
+		t.Fatalf("SubmitRoot(3) error = %v", err)
+	}
+	if n, want := txNonce(t, chain, tx3), txNonce(t, chain, tx2)+1; n != want {
+		t.Errorf("nonce after mined transactions = %d, want %d", n, want)
+	}
+}
+
+// txNonce 查询交易的 nonce
+func txNonce(t *testing.T, chain *testChain, hash string) uint64 {
+	t.Helper()
+	tx, _, err := chain.client.TransactionByHash(context.Background(), common.HexToHash(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx.Nonce()
+}
+
+func TestWaitReceipt(t *testing.T) {
+	ctx := context.Background()
+	chain := newTestChain(t)
+	contract := chain.deploy(t)
+	bc := chain.newClient(t, chain.owner, contract, false)
+
+	txHash, err := bc.SubmitRoot(ctx, 1, testRoot("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未打包: 交易在池中时继续等待，直到超时
+	if _, err := bc.AnchorInTx(ctx, txHash, 1); !errors.Is(err, ErrTxPending) {
+		t.Errorf("AnchorInTx() before mining error = %v, want ErrTxPending", err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err = bc.WaitReceipt(waitCtx, txHash)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitReceipt() before mining error = %v, want DeadlineExceeded", err)
+	}
+
+	chain.sim.Commit()
+	receipt, err := bc.WaitReceipt(ctx, txHash)
+	if err != nil || receipt.TxHash != txHash || receipt.GasUsed == 0 {
+		t.Errorf("WaitReceipt() = %+v, %v", receipt, err)
+	}
+
+	unknown := common.BytesToHash([]byte("unknown")).Hex()
+	if _, err := bc.WaitReceipt(ctx, unknown); !errors.Is(err, ErrTxNotFound) {
+		t.Errorf("WaitReceipt(unknown) error = %v, want ErrTxNotFound", err)
+	}
+
+	// 固定 Gas 上限时跳过估算，重复存证的交易上链后执行失败
+	fixedGas := chain.newClient(t, chain.owner, contract, true)
+	fixedGas.gasLimit = 100000
+	reverted, err := fixedGas.SubmitRoot(ctx, 1, testRoot("v1-changed"))
+	if err != nil {
+		t.Fatalf("SubmitRoot() with fixed gas error = %v", err)
+	}
+	if _, err := bc.WaitReceipt(ctx, reverted); !errors.Is(err, ErrTxReverted) {
+		t.Errorf("WaitReceipt(reverted) error = %v, want ErrTxReverted", err)
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	ctx := context.Background()
+	chain := newTestChain(t)
+	contract := chain.deploy(t)
+	bc := chain.newClient(t, chain.owner, contract, false)
+
+	oldHash, err := bc.SubmitRoot(ctx, 1, testRoot("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldTx, _, err := chain.client.TransactionByHash(ctx, common.HexToHash(oldHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newHash, err := bc.BumpFee(ctx, oldHash)
+	if err != nil {
+		t.Fatalf("BumpFee() error = %v", err)
+	}
+
+	newTx, _, err := chain.client.TransactionByHash(ctx, common.HexToHash(newHash))
+	if err != nil {
+		t.Fatalf("替换交易不在交易池中: %v", err)
+	}
+	if newTx.Nonce() != oldTx.Nonce() || newTx.GasFeeCap().Cmp(oldTx.GasFeeCap()) <= 0 || newTx.GasTipCap().Cmp(oldTx.GasTipCap()) <= 0 {
+		t.Errorf("替换交易应使用相同 nonce 与更高费用: nonce %d/%d", oldTx.Nonce(), newTx.Nonce())
+	}
+
+	chain.sim.Commit()
+	if _, err := bc.WaitReceipt(ctx, newHash); err != nil {
+		t.Errorf("WaitReceipt(replacement) error = %v", err)
+	}
+	// 被替换的原交易不会上链
+	if _, err := bc.WaitReceipt(ctx, oldHash); !errors.Is(err, ErrTxNotFound) {
+		t.Errorf("WaitReceipt(replaced) error = %v, want ErrTxNotFound", err)
+	}
+	if _, err := bc.BumpFee(ctx, newHash); !errors.Is(err, ErrTxAlreadyMined) {
+		t.Errorf("BumpFee(mined) error = %v, want ErrTxAlreadyMined", err)
+	}
+	anchor, err := bc.FindAnchor(ctx, 1)
+	if err != nil || anchor.TxHash != newHash {
+		t.Errorf("FindAnchor() = %+v, %v, want tx %s", anchor, err, newHash)
+	}
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// EmbedInput 待向量化的分块
+type EmbedInput struct {
+	ContentHash string // DocumentChunk.ContentHash，作为缓存键
+	Text        string
+}
+
+// EmbeddingCache 向量缓存接口 (按模型与内容哈希寻址)
+type EmbeddingCache interface {
+	Get(model, contentHash string) ([]float32, bool)
+	Set(model, contentHash string, vector []float32) error
+}
+
+// NewEmbeddingCache 创建向量缓存，dir 为空时使用内存缓存
+func NewEmbeddingCache(dir string) EmbeddingCache {
+	if dir == "" {
+		return NewMemoryEmbeddingCache()
+	}
+	return NewFileEmbeddingCache(dir)
+}
+
+// CachedEmbedder 带内容哈希缓存的向量化器
+// 重复导入未变化的法条时直接命中缓存，不再调用向量化服务
+type CachedEmbedder struct {
+	embedder Embedder
+	cache    EmbeddingCache
+}
+
+// NewCachedEmbedder 创建带缓存的向量化器
+func NewCachedEmbedder(embedder Embedder, cache EmbeddingCache) *CachedEmbedder {
+	return &CachedEmbedder{
+		embedder: embedder,
+		cache:    cache,
+	}
+}
+
+// Embedder 返回底层向量化实现
+func (c *CachedEmbedder) Embedder() Embedder {
+	return c.embedder
+}
+
+// EmbedChunks 向量化分块，返回向量 (顺序与输入一致) 与缓存命中数
+func (c *CachedEmbedder) EmbedChunks(ctx context.Context, inputs []EmbedInput) ([][]float32, int, error) {
+	model := c.embedder.Model()
+	vectors := make([][]float32, len(inputs))
+
+	var (
+		missTexts   []string
+		missIndexes []int
+	)
+	// 同一批次内相同内容只向量化一次
+	pending := make(map[string][]int)
+	for i, in := range inputs {
+		if v, ok := c.cache.Get(model, in.ContentHash); ok {
+			vectors[i] = v
+			continue
+		}
+		if idx, ok := pending[in.ContentHash]; ok {
+			pending[in.ContentHash] = append(idx, i)
+			continue
+		}
+		pending[in.ContentHash] = []int{i}
+		missTexts = append(missTexts, in.Text)
+		missIndexes = append(missIndexes, i)
+	}
+
+	hits := len(inputs)
+	for _, idx := range pending {
+		hits -= len(idx)
+	}
+	if len(missTexts) == 0 {
+		return vectors, hits, nil
+	}
+
+	embedded, err := c.embedder.Embed(ctx, missTexts)
+	if err != nil {
+		return nil, hits, err
+	}
+	for j, i := range missIndexes {
+		hash := inputs[i].ContentHash
+		for _, k := range pending[hash] {
+			vectors[k] = embedded[j]
+		}
+		if err := c.cache.Set(model, hash, embedded[j]); err != nil {
+			logger.Warn("写入向量缓存失败", zap.String("content_hash", hash), zap.Error(err))
+		}
+	}
+	return vectors, hits, nil
+}
+
+// ============================================================================
+// 内存缓存
+// ============================================================================
+
+// MemoryEmbeddingCache 内存向量缓存
+type MemoryEmbeddingCache struct {
+	mu      sync.RWMutex
+	vectors map[string][]float32
+}
+
+// NewMemoryEmbeddingCache 创建内存向量缓存
+func NewMemoryEmbeddingCache() *MemoryEmbeddingCache {
+	return &MemoryEmbeddingCache{vectors: make(map[string][]float32)}
+}
+
+// Get 读取缓存
+func (m *MemoryEmbeddingCache) Get(model, contentHash string) ([]float32, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.vectors[model+"/"+contentHash]
+	return v, ok
+}
+
+// Set 写入缓存
+func (m *MemoryEmbeddingCache) Set(model, contentHash string, vector []float32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vectors[model+"/"+contentHash] = vector
+	return nil
+}
+
+// ============================================================================
+// 文件缓存
+// ============================================================================
+
+// FileEmbeddingCache 文件向量缓存
+// 目录结构: <dir>/<model>/<hash[:2]>/<hash>.bin，内容为小端序 float32 数组
+type FileEmbeddingCache struct {
+	dir string
+}
+
+// NewFileEmbeddingCache 创建文件向量缓存
+func NewFileEmbeddingCache(dir string) *FileEmbeddingCache {
+	return &FileEmbeddingCache{dir: dir}
+}
+
+// Get 读取缓存
+func (f *FileEmbeddingCache) Get(model, contentHash string) ([]float32, bool) {
+	path, ok := f.path(model, contentHash)
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 || len(data)%4 != 0 {
+		return nil, false
+	}
+
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vector, true
+}
+
+// Set 写入缓存 (先写临时文件再重命名，避免读到半写入的数据)
+func (f *FileEmbeddingCache) Set(model, contentHash string, vector []float32) error {
+	path, ok := f.path(model, contentHash)
+	if !ok {
+		return fmt.Errorf("无效的内容哈希: %q", contentHash)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data := make([]byte, len(vector)*4)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path 计算缓存文件路径
+func (f *FileEmbeddingCache) path(model, contentHash string) (string, bool) {
+	if len(contentHash) < 3 || strings.ContainsAny(contentHash, `/\.`) {
+		return "", false
+	}
+	modelDir := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == '@' {
+			return '_'
+		}
+		return r
+	}, model)
+	return filepath.Join(f.dir, modelDir, contentHash[:2], contentHash+".bin"), true
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// countingEmbedder 记录调用次数与向量化文本的 Embedder
+type countingEmbedder struct {
+	*HashEmbedder
+	mu    sync.Mutex
+	calls int
+	texts []string
+}
+
+func (c *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	c.mu.Lock()
+	c.calls++
+	c.texts = append(c.texts, texts...)
+	c.mu.Unlock()
+	return c.HashEmbedder.Embed(ctx, texts)
+}
+
+func TestCachedEmbedderByContentHash(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingEmbedder{HashEmbedder: NewHashEmbedder(16)}
+	cached := NewCachedEmbedder(inner, NewMemoryEmbeddingCache())
+
+	first := []EmbedInput{
+		{ContentHash: "aaa111", Text: "第一条"},
+		{ContentHash: "bbb222", Text: "第二条"},
+		{ContentHash: "aaa111", Text: "第一条"}, // 同批次重复内容只向量化一次
+	}
+	vectors, hits, err := cached.EmbedChunks(ctx, first)
+	if err != nil {
+		t.Fatalf("EmbedChunks() error = %v", err)
+	}
+	if hits != 0 {
+		t.Errorf("hits = %d, want 0", hits)
+	}
+	if !reflect.DeepEqual(inner.texts, []string{"第一条", "第二条"}) {
+		t.Errorf("embedded texts = %v", inner.texts)
+	}
+	if !reflect.DeepEqual(vectors[0], vectors[2]) {
+		t.Error("相同内容哈希的向量应相同")
+	}
+
+	// 再次导入: 未变化的分块命中缓存，仅新内容调用向量化服务
+	second := []EmbedInput{
+		{ContentHash: "aaa111", Text: "第一条"},
+		{ContentHash: "ccc333", Text: "第三条"},
+		{ContentHash: "bbb222", Text: "第二条"},
+	}
+	inner.texts = nil
+	vectors, hits, err = cached.EmbedChunks(ctx, second)
+	if err != nil {
+		t.Fatalf("EmbedChunks() error = %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("hits = %d, want 2", hits)
+	}
+	if !reflect.DeepEqual(inner.texts, []string{"第三条"}) {
+		t.Errorf("embedded texts = %v, want only 第三条", inner.texts)
+	}
+	want, _ := inner.HashEmbedder.Embed(ctx, []string{"第一条", "第三条", "第二条"})
+	if !reflect.DeepEqual(vectors, want) {
+		t.Error("缓存命中与未命中的向量顺序应与输入一致")
+	}
+
+	// 全部命中时不调用向量化服务
+	calls := inner.calls
+	if _, hits, _ = cached.EmbedChunks(ctx, second); hits != 3 || inner.calls != calls {
+		t.Errorf("hits = %d, calls = %d, want 3 hits without calls", hits, inner.calls-calls)
+	}
+}
+
+func TestCachedEmbedderKeyedByModel(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryEmbeddingCache()
+	input := []EmbedInput{{ContentHash: "aaa111", Text: "第一条"}}
+
+	if _, _, err := NewCachedEmbedder(NewHashEmbedder(16), cache).EmbedChunks(ctx, input); err != nil {
+		t.Fatal(err)
+	}
+	// 不同模型 (维度) 不共用缓存
+	vectors, hits, err := NewCachedEmbedder(NewHashEmbedder(32), cache).EmbedChunks(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits != 0 || len(vectors[0]) != 32 {
+		t.Errorf("hits = %d, dim = %d, want miss with dim 32", hits, len(vectors[0]))
+	}
+}
+
+func TestFileEmbeddingCacheReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	input := []EmbedInput{
+		{ContentHash: "3f2a9c", Text: "第一条"},
+		{ContentHash: "7b41d0", Text: "第二条"},
+	}
+
+	inner := &countingEmbedder{HashEmbedder: NewHashEmbedder(24)}
+	want, _, err := NewCachedEmbedder(inner, NewEmbeddingCache(dir)).EmbedChunks(ctx, input)
+	if err != nil {
+		t.Fatalf("EmbedChunks() error = %v", err)
+	}
+
+	// 重新打开缓存目录 (模拟进程重启)，向量从文件读取且与写入时一致
+	reloaded := &countingEmbedder{HashEmbedder: NewHashEmbedder(24)}
+	got, hits, err := NewCachedEmbedder(reloaded, NewFileEmbeddingCache(dir)).EmbedChunks(ctx, input)
+	if err != nil {
+		t.Fatalf("EmbedChunks() error = %v", err)
+	}
+	if hits != 2 || reloaded.calls != 0 {
+		t.Errorf("hits = %d, calls = %d, want 2 hits without calls", hits, reloaded.calls)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("重新加载的向量与写入时不一致")
+	}
+}
+
+func TestFileEmbeddingCacheInvalidHash(t *testing.T) {
+	cache := NewFileEmbeddingCache(t.TempDir())
+	for _, hash := range []string{"", "ab", "../etc", "a/b/c", "x.y.z"} {
+		if err := cache.Set("m", hash, []float32{1}); err == nil {
+			t.Errorf("Set(%q) 应拒绝无效的内容哈希", hash)
+		}
+		if _, ok := cache.Get("m", hash); ok {
+			t.Errorf("Get(%q) 不应命中", hash)
+		}
+	}
+	if _, ok := cache.Get("m", "missing"); ok {
+		t.Error("未写入的哈希不应命中")
+	}
+}
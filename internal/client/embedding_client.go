@@ -0,0 +1,356 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultEmbeddingTimeout 默认请求超时
+	defaultEmbeddingTimeout = 30 * time.Second
+	// defaultEmbeddingBatchSize 默认单次请求文本数
+	defaultEmbeddingBatchSize = 32
+	// defaultEmbeddingBatchTokens 默认单次请求 Token 上限
+	defaultEmbeddingBatchTokens = 16000
+
+	// EmbeddingProviderOpenAI OpenAI 兼容接口
+	EmbeddingProviderOpenAI = "openai"
+	// EmbeddingProviderHash 确定性哈希向量
+	EmbeddingProviderHash = "hash"
+)
+
+// EmbeddingConfig 向量化配置别名
+type EmbeddingConfig = config.EmbeddingConfig
+
+// Embedder 文本向量化接口
+type Embedder interface {
+	// Embed 批量向量化，返回顺序与输入一致
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model 返回模型标识 (用于缓存隔离)
+	Model() string
+}
+
+// NewEmbedder 根据配置创建向量化实现
+// dimension 为向量库维度，哈希实现直接使用该维度
+func NewEmbedder(cfg *EmbeddingConfig, dimension int) Embedder {
+	if cfg.Provider == EmbeddingProviderHash {
+		return NewHashEmbedder(dimension)
+	}
+	return NewOpenAIEmbedder(cfg)
+}
+
+// EmbedQuery 向量化单条文本
+func EmbedQuery(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	vectors, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("向量化返回 %d 条结果，期望 1 条", len(vectors))
+	}
+	return vectors[0], nil
+}
+
+// CheckEmbeddingDimension 校验向量化输出维度与向量库一致
+func CheckEmbeddingDimension(ctx context.Context, e Embedder, dimension int) error {
+	vector, err := EmbedQuery(ctx, e, "维度探测")
+	if err != nil {
+		return fmt.Errorf("向量化服务不可用: %w", err)
+	}
+	if len(vector) != dimension {
+		return fmt.Errorf("%w: 模型 %s 输出 %d 维，向量库为 %d 维", ErrDimensionMismatch, e.Model(), len(vector), dimension)
+	}
+	return nil
+}
+
+// ============================================================================
+// OpenAI 兼容实现
+// ============================================================================
+
+// OpenAIEmbedder OpenAI Embeddings 兼容接口客户端
+type OpenAIEmbedder struct {
+	baseURL        string
+	apiKey         string
+	model          string
+	dimensions     int
+	batchSize      int
+	maxBatchTokens int
+	maxRetries     int
+	retryBackoff   time.Duration
+	httpClient     *http.Client
+}
+
+// NewOpenAIEmbedder 创建 OpenAI 兼容向量化客户端
+func NewOpenAIEmbedder(cfg *EmbeddingConfig) *OpenAIEmbedder {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultEmbeddingTimeout
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultEmbeddingBatchSize
+	}
+	maxBatchTokens := cfg.MaxBatchTokens
+	if maxBatchTokens <= 0 {
+		maxBatchTokens = defaultEmbeddingBatchTokens
+	}
+	backoff := cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	return &OpenAIEmbedder{
+		baseURL:        strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:         cfg.APIKey,
+		model:          cfg.Model,
+		dimensions:     cfg.Dimensions,
+		batchSize:      batchSize,
+		maxBatchTokens: maxBatchTokens,
+		maxRetries:     cfg.MaxRetries,
+		retryBackoff:   backoff,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				DialContext:         (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+				IdleConnTimeout:     90 * time.Second,
+				MaxIdleConnsPerHost: 16,
+			},
+		},
+	}
+}
+
+// Model 返回模型标识
+func (e *OpenAIEmbedder) Model() string {
+	if e.dimensions > 0 {
+		return fmt.Sprintf("%s@%d", e.model, e.dimensions)
+	}
+	return e.model
+}
+
+// Embed 批量向量化，自动按文本数与 Token 数拆分请求
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, batch := range splitBatches(texts, e.batchSize, e.maxBatchTokens) {
+		result, err := e.embedBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, result...)
+	}
+	return vectors, nil
+}
+
+// embedBatch 发送单次向量化请求
+func (e *OpenAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	payload := embeddingRequest{
+		Model:          e.model,
+		Input:          texts,
+		EncodingFormat: "float",
+		Dimensions:     e.dimensions,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= e.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := retryDelay(e.retryBackoff, attempt, lastErr)
+			logger.Warn("向量化请求失败，准备重试",
+				zap.Int("attempt", attempt),
+				zap.Duration("wait", wait),
+				zap.Error(lastErr),
+			)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		vectors, err := e.post(ctx, body, len(texts))
+		if err == nil {
+			return vectors, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.Retryable() {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// post 执行 HTTP 请求并解析结果
+func (e *OpenAIEmbedder) post(ctx context.Context, body []byte, n int) ([][]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("向量化请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var result embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析向量化响应失败: %w", err)
+	}
+	if len(result.Data) != n {
+		return nil, fmt.Errorf("向量化返回 %d 条结果，期望 %d 条", len(result.Data), n)
+	}
+
+	sort.Slice(result.Data, func(i, j int) bool {
+		return result.Data[i].Index < result.Data[j].Index
+	})
+	vectors := make([][]float32, n)
+	for i, d := range result.Data {
+		vectors[i] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// splitBatches 按文本数与估算 Token 数拆分批次
+// 超过 Token 上限的单条文本独占一个批次，由服务端决定是否截断
+func splitBatches(texts []string, batchSize, maxTokens int) [][]string {
+	var (
+		batches [][]string
+		current []string
+		tokens  int
+	)
+	for _, text := range texts {
+		t := EstimateTokens(text)
+		if len(current) > 0 && (len(current) >= batchSize || tokens+t > maxTokens) {
+			batches = append(batches, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, text)
+		tokens += t
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// embeddingRequest Embeddings 请求体
+type embeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	EncodingFormat string   `json:"encoding_format,omitempty"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+}
+
+// embeddingResponse Embeddings 响应体
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Model string      `json:"model"`
+	Usage *usageBlock `json:"usage,omitempty"`
+}
+
+// ============================================================================
+// 确定性哈希实现
+// ============================================================================
+
+// HashEmbedder 确定性哈希向量化
+// 将字符与字符二元组哈希到固定维度并归一化，相同文本总是得到相同向量，
+// 字面重合度高的文本相似度也更高。仅用于测试与离线开发。
+type HashEmbedder struct {
+	dimension int
+}
+
+// NewHashEmbedder 创建哈希向量化实现
+func NewHashEmbedder(dimension int) *HashEmbedder {
+	return &HashEmbedder{dimension: dimension}
+}
+
+// Model 返回模型标识
+func (h *HashEmbedder) Model() string {
+	return fmt.Sprintf("hash@%d", h.dimension)
+}
+
+// Embed 批量向量化
+func (h *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = h.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed 向量化单条文本
+func (h *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, h.dimension)
+
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	for i := range runes {
+		h.add(vector, string(runes[i]))
+		if i+1 < len(runes) {
+			h.add(vector, string(runes[i:i+2]))
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+	return vector
+}
+
+// add 将特征哈希到向量的某一维 (带符号，减少碰撞偏差)
+func (h *HashEmbedder) add(vector []float32, feature string) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(feature))
+	sum := hasher.Sum64()
+
+	idx := int(sum % uint64(h.dimension))
+	if sum>>63 == 1 {
+		vector[idx]--
+	} else {
+		vector[idx]++
+	}
+}
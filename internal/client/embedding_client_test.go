@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHashEmbedderDeterministic(t *testing.T) {
+	ctx := context.Background()
+	texts := []string{
+		"用人单位与劳动者约定的试用期不得超过六个月",
+		"第一千一百六十五条 行为人因过错侵害他人民事权益造成损害的，应当承担侵权责任。",
+		"",
+	}
+
+	for _, dim := range []int{8, 128, 1536} {
+		a, err := NewHashEmbedder(dim).Embed(ctx, texts)
+		if err != nil {
+			t.Fatalf("Embed() error = %v", err)
+		}
+		b, _ := NewHashEmbedder(dim).Embed(ctx, texts)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("dim %d: 相同输入的向量不一致", dim)
+		}
+		if len(a) != len(texts) {
+			t.Fatalf("dim %d: 返回 %d 条向量，期望 %d", dim, len(a), len(texts))
+		}
+		for i, v := range a {
+			if len(v) != dim {
+				t.Errorf("dim %d: 第 %d 条向量维度为 %d", dim, i, len(v))
+			}
+		}
+
+		// 非空文本归一化为单位向量，空文本为零向量
+		if norm := vectorNorm(a[0]); math.Abs(norm-1) > 1e-5 {
+			t.Errorf("dim %d: 向量模长 = %v，期望 1", dim, norm)
+		}
+		if norm := vectorNorm(a[2]); norm != 0 {
+			t.Errorf("dim %d: 空文本向量模长 = %v，期望 0", dim, norm)
+		}
+	}
+}
+
+func TestHashEmbedderSimilarity(t *testing.T) {
+	e := NewHashEmbedder(256)
+	vectors, _ := e.Embed(context.Background(), []string{
+		"劳动合同试用期",
+		"劳动合同的试用期最长多久",
+		"船舶碰撞损害赔偿",
+	})
+	related := CosineSimilarity(vectors[0], vectors[1])
+	unrelated := CosineSimilarity(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("相关文本相似度 %v 应高于无关文本 %v", related, unrelated)
+	}
+	// 大小写与标点不影响向量
+	a, _ := EmbedQuery(context.Background(), e, "Article 10, Labor Law")
+	b, _ := EmbedQuery(context.Background(), e, "article 10 labor law")
+	if !reflect.DeepEqual(a, b) {
+		t.Error("仅大小写与标点不同的文本向量应相同")
+	}
+}
+
+func TestCheckEmbeddingDimension(t *testing.T) {
+	ctx := context.Background()
+	if err := CheckEmbeddingDimension(ctx, NewHashEmbedder(64), 64); err != nil {
+		t.Errorf("CheckEmbeddingDimension() error = %v", err)
+	}
+	if err := CheckEmbeddingDimension(ctx, NewHashEmbedder(64), 128); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("CheckEmbeddingDimension() error = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestSplitBatches(t *testing.T) {
+	long := strings.Repeat("法", 30)
+	tests := []struct {
+		name      string
+		texts     []string
+		batchSize int
+		maxTokens int
+		want      []int // 各批次文本数
+	}{
+		{name: "按文本数拆分", texts: []string{"a", "b", "c", "d", "e"}, batchSize: 2, maxTokens: 1000, want: []int{2, 2, 1}},
+		{name: "按 Token 数拆分", texts: []string{long, long, long}, batchSize: 10, maxTokens: 60, want: []int{2, 1}},
+		{name: "单条超限仍单独成批", texts: []string{long, "a"}, batchSize: 10, maxTokens: 10, want: []int{1, 1}},
+		{name: "空输入", texts: nil, batchSize: 10, maxTokens: 10, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := splitBatches(tt.texts, tt.batchSize, tt.maxTokens)
+			var sizes []int
+			var joined []string
+			for _, b := range batches {
+				sizes = append(sizes, len(b))
+				joined = append(joined, b...)
+			}
+			if !reflect.DeepEqual(sizes, tt.want) {
+				t.Errorf("batch sizes = %v, want %v", sizes, tt.want)
+			}
+			if len(tt.texts) > 0 && !reflect.DeepEqual(joined, tt.texts) {
+				t.Error("拆分后文本顺序应保持不变")
+			}
+		})
+	}
+}
+
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
@@ -1,45 +1,392 @@
 package client
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"go.uber.org/zap"
 )
 
-// LLMClient LLM 客户端（占位实现）
-// TODO: 实现与 OpenAI GPT-4 的交互逻辑
-type LLMClient struct {
-	apiKey  string
-	model   string
-	baseURL string
-}
+const (
+	// defaultLLMTimeout 默认请求超时
+	defaultLLMTimeout = 60 * time.Second
+	// defaultRetryBackoff 默认重试退避时间
+	defaultRetryBackoff = 500 * time.Millisecond
+	// maxRetryBackoff 单次退避上限
+	maxRetryBackoff = 10 * time.Second
+	// maxSSELineSize SSE 单行最大长度
+	maxSSELineSize = 1024 * 1024
+)
+
+// LLMConfig LLM 配置别名
+type LLMConfig = config.LLMConfig
 
-// LLMConfig LLM 配置
-type LLMConfig struct {
-	APIKey  string
-	Model   string
-	BaseURL string
+// LLMClient OpenAI Chat Completions 兼容客户端
+// 支持 OpenAI、DeepSeek、通义千问、vLLM 等兼容服务
+type LLMClient struct {
+	apiKey       string
+	model        string
+	baseURL      string
+	temperature  float64
+	maxTokens    int
+	timeout      time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
+	httpClient   *http.Client
 }
 
 // NewLLMClient 创建 LLM 客户端
 func NewLLMClient(cfg *LLMConfig) *LLMClient {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultLLMTimeout
+	}
+	backoff := cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
 	return &LLMClient{
-		apiKey:  cfg.APIKey,
-		model:   cfg.Model,
-		baseURL: cfg.BaseURL,
+		apiKey:       cfg.APIKey,
+		model:        cfg.Model,
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		temperature:  cfg.Temperature,
+		maxTokens:    cfg.MaxTokens,
+		timeout:      timeout,
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: backoff,
+		httpClient: &http.Client{
+			// 不设置整体超时，避免截断流式响应；首包超时由 Transport 控制
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				DialContext:           (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+				ResponseHeaderTimeout: timeout,
+				IdleConnTimeout:       90 * time.Second,
+				MaxIdleConnsPerHost:   16,
+			},
+		},
 	}
 }
 
+// Model 返回模型名称
+func (c *LLMClient) Model() string {
+	return c.model
+}
+
 // ChatCompletion 聊天补全
 func (c *LLMClient) ChatCompletion(ctx context.Context, messages []Message) (*ChatResponse, error) {
-	// TODO: 实现 OpenAI API 调用
-	return nil, nil
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.do(ctx, c.newRequest(messages, false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("解析 LLM 响应失败: %w", err)
+	}
+	if len(body.Choices) == 0 {
+		return nil, errors.New("LLM 响应中没有候选结果")
+	}
+
+	choice := body.Choices[0]
+	result := &ChatResponse{
+		Content:      choice.Message.Content,
+		FinishReason: choice.FinishReason,
+	}
+	if body.Usage != nil {
+		result.Usage = body.Usage.toTokenUsage()
+	} else {
+		result.Usage = estimateUsage(messages, result.Content)
+	}
+	return result, nil
 }
 
 // ChatCompletionStream 流式聊天补全
+// 返回的通道按顺序输出增量内容，最后一个块 Done=true 并携带 Usage；
+// 出错时输出带 Error 的块后关闭。ctx 取消时停止读取并关闭通道。
 func (c *LLMClient) ChatCompletionStream(ctx context.Context, messages []Message) (<-chan StreamChunk, error) {
-	// TODO: 实现流式响应
-	return nil, nil
+	resp, err := c.do(ctx, c.newRequest(messages, true))
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk, 16)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		send := func(chunk StreamChunk) bool {
+			select {
+			case ch <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var (
+			content strings.Builder
+			usage   *TokenUsage
+		)
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			// 忽略空行、注释与非 data 字段
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk chatCompletionChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				send(StreamChunk{Error: fmt.Errorf("解析 LLM 流式响应失败: %w", err)})
+				return
+			}
+			if chunk.Error != nil {
+				send(StreamChunk{Error: chunk.Error})
+				return
+			}
+			if chunk.Usage != nil {
+				u := chunk.Usage.toTokenUsage()
+				usage = &u
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Delta.Content == "" {
+					continue
+				}
+				content.WriteString(choice.Delta.Content)
+				if !send(StreamChunk{Content: choice.Delta.Content}) {
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			if ctx.Err() == nil {
+				send(StreamChunk{Error: fmt.Errorf("读取 LLM 流式响应失败: %w", err)})
+			}
+			return
+		}
+
+		// 服务端未返回 usage 时按字符估算
+		if usage == nil {
+			u := estimateUsage(messages, content.String())
+			usage = &u
+		}
+		send(StreamChunk{Done: true, Usage: usage})
+	}()
+
+	return ch, nil
+}
+
+// newRequest 构建请求体
+func (c *LLMClient) newRequest(messages []Message, stream bool) *chatCompletionRequest {
+	req := &chatCompletionRequest{
+		Model:       c.model,
+		Messages:    messages,
+		Temperature: c.temperature,
+		MaxTokens:   c.maxTokens,
+		Stream:      stream,
+	}
+	if stream {
+		req.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+	return req
+}
+
+// do 发送请求，对网络错误、429 与 5xx 响应按指数退避重试
+func (c *LLMClient) do(ctx context.Context, payload *chatCompletionRequest) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := c.backoff(attempt, lastErr)
+			logger.Warn("LLM 请求失败，准备重试",
+				zap.Int("attempt", attempt),
+				zap.Duration("wait", wait),
+				zap.Error(lastErr),
+			)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if payload.Stream {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		if c.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("LLM 请求失败: %w", err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+
+		apiErr := parseAPIError(resp)
+		resp.Body.Close()
+		if !apiErr.Retryable() {
+			return nil, apiErr
+		}
+		lastErr = apiErr
+	}
+
+	return nil, lastErr
 }
 
+// backoff 计算第 attempt 次重试前的等待时间
+// 优先使用服务端返回的 Retry-After，否则指数退避并加入随机抖动
+func (c *LLMClient) backoff(attempt int, lastErr error) time.Duration {
+	return retryDelay(c.retryBackoff, attempt, lastErr)
+}
+
+// retryDelay 计算重试等待时间：优先使用 Retry-After，否则指数退避加抖动
+func retryDelay(base time.Duration, attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, maxRetryBackoff)
+	}
+
+	wait := base << (attempt - 1)
+	if wait <= 0 || wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(wait)/2 + 1))
+	return wait/2 + jitter
+}
+
+// ============================================================================
+// 错误类型
+// ============================================================================
+
+// APIError LLM 服务端错误
+type APIError struct {
+	StatusCode int           `json:"-"`
+	Message    string        `json:"message"`
+	Type       string        `json:"type"`
+	Code       interface{}   `json:"code"`
+	RetryAfter time.Duration `json:"-"`
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("LLM API 错误 (status=%d, type=%s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("LLM API 错误 (type=%s): %s", e.Type, e.Message)
+}
+
+// Retryable 是否可重试 (429 限流或 5xx 服务端错误)
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// parseAPIError 从非 200 响应中解析错误
+func parseAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			apiErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var wrapper struct {
+		Error *APIError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err == nil && wrapper.Error != nil {
+		apiErr.Message = wrapper.Error.Message
+		apiErr.Type = wrapper.Error.Type
+		apiErr.Code = wrapper.Error.Code
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// ============================================================================
+// Token 估算
+// ============================================================================
+
+// EstimateTokens 粗略估算文本 Token 数
+// 中日韩字符按 1 字 1 Token 计算，其余字符按 4 字节 1 Token 计算
+func EstimateTokens(text string) int {
+	var cjk, other int
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other += len(string(r))
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// estimateUsage 服务端未返回用量时估算 Token 用量
+func estimateUsage(messages []Message, completion string) TokenUsage {
+	prompt := 0
+	for _, m := range messages {
+		// 每条消息额外约 4 个格式 Token
+		prompt += EstimateTokens(m.Content) + 4
+	}
+	out := EstimateTokens(completion)
+	return TokenUsage{
+		PromptTokens:     prompt,
+		CompletionTokens: out,
+		TotalTokens:      prompt + out,
+	}
+}
+
+// ============================================================================
+// 数据结构
+// ============================================================================
+
 // Message 消息结构
 type Message struct {
 	Role    string `json:"role"` // system | user | assistant
@@ -64,5 +411,62 @@ type TokenUsage struct {
 type StreamChunk struct {
 	Content string
 	Done    bool
+	Usage   *TokenUsage // 仅在最后一个块中返回
 	Error   error
 }
+
+// chatCompletionRequest 请求体 (OpenAI wire format)
+type chatCompletionRequest struct {
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	Temperature   float64        `json:"temperature"`
+	MaxTokens     int            `json:"max_tokens,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
+}
+
+// streamOptions 流式选项
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// usageBlock 用量块
+type usageBlock struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// toTokenUsage 转换为 TokenUsage
+func (u *usageBlock) toTokenUsage() TokenUsage {
+	total := u.TotalTokens
+	if total == 0 {
+		total = u.PromptTokens + u.CompletionTokens
+	}
+	return TokenUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      total,
+	}
+}
+
+// chatCompletionResponse 非流式响应体
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usageBlock `json:"usage"`
+}
+
+// chatCompletionChunk 流式响应块
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usageBlock `json:"usage"`
+	Error *APIError   `json:"error"`
+}
@@ -0,0 +1,329 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testMessages = []Message{
+	{Role: "system", Content: "你是法律助手"},
+	{Role: "user", Content: "劳动合同的试用期最长多久？"},
+}
+
+// newTestLLM 创建指向测试服务的客户端
+func newTestLLM(t *testing.T, handler http.HandlerFunc, maxRetries int) *LLMClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewLLMClient(&LLMConfig{
+		BaseURL:      srv.URL,
+		Model:        "test-model",
+		Timeout:      5 * time.Second,
+		MaxRetries:   maxRetries,
+		RetryBackoff: time.Millisecond,
+	})
+}
+
+// sseHandler 按片段写出 SSE 响应体，每个片段后刷新，用于模拟跨包拆分的行
+func sseHandler(t *testing.T, parts ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("解析请求失败: %v", err)
+		}
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Errorf("流式请求应携带 stream 与 include_usage: %+v", req)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, p := range parts {
+			_, _ = w.Write([]byte(p))
+			flusher.Flush()
+		}
+	}
+}
+
+// collect 读取流式响应直至通道关闭
+func collect(t *testing.T, ch <-chan StreamChunk) (string, *TokenUsage, error, bool) {
+	t.Helper()
+	var (
+		content strings.Builder
+		usage   *TokenUsage
+		done    bool
+	)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case chunk, ok := <-ch:
+			if !ok {
+				return content.String(), usage, nil, done
+			}
+			if chunk.Error != nil {
+				return content.String(), usage, chunk.Error, done
+			}
+			content.WriteString(chunk.Content)
+			if chunk.Done {
+				done = true
+				usage = chunk.Usage
+			}
+		case <-timeout:
+			t.Fatal("读取流式响应超时")
+		}
+	}
+}
+
+func deltaLine(content string) string {
+	return fmt.Sprintf("data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", content)
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	usageLine := "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":30,\"completion_tokens\":4,\"total_tokens\":34}}\n\n"
+	split := deltaLine("六个月")
+
+	tests := []struct {
+		name      string
+		parts     []string
+		want      string
+		wantUsage *TokenUsage // nil 表示应为估算用量
+		wantErr   string
+	}{
+		{
+			name:      "增量内容与服务端用量",
+			parts:     []string{deltaLine("最长"), deltaLine("六个月"), usageLine, "data: [DONE]\n\n"},
+			want:      "最长六个月",
+			wantUsage: &TokenUsage{PromptTokens: 30, CompletionTokens: 4, TotalTokens: 34},
+		},
+		{
+			name:  "未返回用量时估算",
+			parts: []string{deltaLine("最长"), deltaLine("六个月"), "data: [DONE]\n\n"},
+			want:  "最长六个月",
+		},
+		{
+			name:      "行被拆分到多个包",
+			parts:     []string{deltaLine("最长"), split[:10], split[10:25], split[25:], usageLine, "data: [DO", "NE]\n\n"},
+			want:      "最长六个月",
+			wantUsage: &TokenUsage{PromptTokens: 30, CompletionTokens: 4, TotalTokens: 34},
+		},
+		{
+			name:  "忽略注释、空行、非 data 字段与 [DONE] 之后的内容",
+			parts: []string{": keep-alive\n\n", "event: message\n", "data:{\"choices\":[{\"delta\":{\"content\":\"六个月\"}}]}\n\n", "\n", "data: [DONE]\n\n", deltaLine("多余")},
+			want:  "六个月",
+		},
+		{
+			name:  "未发送 [DONE] 即结束",
+			parts: []string{deltaLine("六个月")},
+			want:  "六个月",
+		},
+		{
+			name:    "流中返回错误",
+			parts:   []string{deltaLine("最长"), "data: {\"error\":{\"message\":\"overloaded\",\"type\":\"server_error\"}}\n\n"},
+			want:    "最长",
+			wantErr: "overloaded",
+		},
+		{
+			name:    "无法解析的数据块",
+			parts:   []string{"data: {not json}\n\n"},
+			wantErr: "解析 LLM 流式响应失败",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestLLM(t, sseHandler(t, tt.parts...), 0)
+			ch, err := c.ChatCompletionStream(context.Background(), testMessages)
+			if err != nil {
+				t.Fatalf("ChatCompletionStream() error = %v", err)
+			}
+
+			content, usage, err, done := collect(t, ch)
+			if content != tt.want {
+				t.Errorf("content = %q, want %q", content, tt.want)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				if done {
+					t.Error("出错时不应输出 Done 块")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !done || usage == nil {
+				t.Fatal("最后一个块应为 Done 并携带 Usage")
+			}
+			want := tt.wantUsage
+			if want == nil {
+				estimated := estimateUsage(testMessages, tt.want)
+				want = &estimated
+			}
+			if *usage != *want {
+				t.Errorf("usage = %+v, want %+v", *usage, *want)
+			}
+		})
+	}
+}
+
+func TestChatCompletionUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantUsage TokenUsage
+	}{
+		{
+			name:      "服务端用量",
+			body:      `{"choices":[{"message":{"role":"assistant","content":"六个月"},"finish_reason":"stop"}],"usage":{"prompt_tokens":30,"completion_tokens":3}}`,
+			wantUsage: TokenUsage{PromptTokens: 30, CompletionTokens: 3, TotalTokens: 33},
+		},
+		{
+			name:      "估算用量",
+			body:      `{"choices":[{"message":{"role":"assistant","content":"六个月"},"finish_reason":"stop"}]}`,
+			wantUsage: estimateUsage(testMessages, "六个月"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestLLM(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}, 0)
+
+			resp, err := c.ChatCompletion(context.Background(), testMessages)
+			if err != nil {
+				t.Fatalf("ChatCompletion() error = %v", err)
+			}
+			if resp.Content != "六个月" || resp.FinishReason != "stop" {
+				t.Errorf("resp = %+v", resp)
+			}
+			if resp.Usage != tt.wantUsage {
+				t.Errorf("usage = %+v, want %+v", resp.Usage, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestChatCompletionRetry(t *testing.T) {
+	const okBody = `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`
+
+	tests := []struct {
+		name         string
+		statuses     []int // 依次返回的状态码，超出后返回最后一个
+		maxRetries   int
+		wantAttempts int32
+		wantStatus   int // 0 表示应成功
+	}{
+		{name: "429 后成功", statuses: []int{429, 200}, maxRetries: 2, wantAttempts: 2},
+		{name: "5xx 后成功", statuses: []int{500, 502, 200}, maxRetries: 3, wantAttempts: 3},
+		{name: "重试次数用尽", statuses: []int{503}, maxRetries: 2, wantAttempts: 3, wantStatus: 503},
+		{name: "4xx 不重试", statuses: []int{400, 200}, maxRetries: 3, wantAttempts: 1, wantStatus: 400},
+		{name: "401 不重试", statuses: []int{401, 200}, maxRetries: 3, wantAttempts: 1, wantStatus: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			c := newTestLLM(t, func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+				status := tt.statuses[min(n, len(tt.statuses)-1)]
+				if status != http.StatusOK {
+					w.WriteHeader(status)
+					_, _ = w.Write([]byte(`{"error":{"message":"fail","type":"test"}}`))
+					return
+				}
+				_, _ = w.Write([]byte(okBody))
+			}, tt.maxRetries)
+
+			_, err := c.ChatCompletion(context.Background(), testMessages)
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Fatalf("error = %v, want APIError status %d", err, tt.wantStatus)
+			}
+			if apiErr.Message != "fail" {
+				t.Errorf("message = %q, want %q", apiErr.Message, "fail")
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	if got := retryDelay(base, 1, &APIError{StatusCode: 429, RetryAfter: 2 * time.Second}); got != 2*time.Second {
+		t.Errorf("Retry-After delay = %v, want 2s", got)
+	}
+	if got := retryDelay(base, 1, &APIError{StatusCode: 429, RetryAfter: time.Hour}); got != maxRetryBackoff {
+		t.Errorf("capped Retry-After delay = %v, want %v", got, maxRetryBackoff)
+	}
+	for attempt := 1; attempt <= 10; attempt++ {
+		wait := min(base<<(attempt-1), maxRetryBackoff)
+		got := retryDelay(base, attempt, errors.New("network"))
+		if got < wait/2 || got > wait {
+			t.Errorf("attempt %d delay = %v, want within [%v, %v]", attempt, got, wait/2, wait)
+		}
+	}
+}
+
+func TestChatCompletionStreamCancel(t *testing.T) {
+	released := make(chan struct{})
+	c := newTestLLM(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(deltaLine("最长")))
+		w.(http.Flusher).Flush()
+		// 保持连接直至客户端取消
+		<-r.Context().Done()
+		close(released)
+	}, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := c.ChatCompletionStream(ctx, testMessages)
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+
+	first := <-ch
+	if first.Content != "最长" {
+		t.Fatalf("first chunk = %+v", first)
+	}
+	cancel()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case chunk, ok := <-ch:
+			if !ok {
+				select {
+				case <-released:
+				case <-timeout:
+					t.Fatal("取消后服务端连接未关闭")
+				}
+				return
+			}
+			if chunk.Done {
+				t.Fatal("取消后不应输出 Done 块")
+			}
+		case <-timeout:
+			t.Fatal("取消后通道未关闭")
+		}
+	}
+}
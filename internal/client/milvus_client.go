@@ -8,6 +8,8 @@ import (
 
 	"github.com/lexveritas/lex-veritas-backend/internal/config"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 	"go.uber.org/zap"
 )
@@ -94,21 +96,341 @@ func CloseMilvus() error {
 	return milvusClient.Close(context.Background())
 }
 
-// SearchVector 向量检索
-func SearchVector(ctx context.Context, collectionName string, vectors [][]float32, topK int) ([]SearchResult, error) {
-	if milvusClient == nil {
-		return nil, fmt.Errorf("milvus not initialized")
+// ============================================================================
+// Milvus 向量存储
+// ============================================================================
+
+const (
+	// milvusUpsertBatchSize 单次 Upsert 的最大行数
+	milvusUpsertBatchSize = 500
+	// milvusHNSWM / milvusHNSWEfConstruction HNSW 索引参数
+	milvusHNSWM              = 16
+	milvusHNSWEfConstruction = 200
+	// milvusSearchEf 检索时的 ef 参数
+	milvusSearchEf = 64
+)
+
+// milvusOutputFields 检索返回的标量字段
+var milvusOutputFields = []string{
+	FieldDocumentID,
+	FieldVersionID,
+	FieldLawType,
+	FieldArticleNumber,
+	FieldEffectiveDate,
+	FieldExpiryDate,
+}
+
+// MilvusStore 基于 Milvus 的向量存储
+type MilvusStore struct {
+	cfg *MilvusConfig
+}
+
+// NewMilvusStore 创建 Milvus 向量存储
+// 使用全局 Milvus 客户端，调用时未连接将返回 ErrVectorStoreNotReady
+func NewMilvusStore(cfg *MilvusConfig) *MilvusStore {
+	return &MilvusStore{cfg: cfg}
+}
+
+// EnsureCollection 幂等地创建集合、索引并加载
+func (m *MilvusStore) EnsureCollection(ctx context.Context) error {
+	cli := GetMilvusClient()
+	if cli == nil {
+		return ErrVectorStoreNotReady
 	}
+	name := m.cfg.CollectionName
 
-	// TODO: 实现具体的向量检索逻辑
-	// 这里只是接口定义，具体实现需要根据 Collection Schema 调整
-	return nil, nil
+	has, err := cli.HasCollection(ctx, milvusclient.NewHasCollectionOption(name))
+	if err != nil {
+		return fmt.Errorf("检查集合失败: %w", err)
+	}
+
+	if !has {
+		if err := cli.CreateCollection(ctx, milvusclient.NewCreateCollectionOption(name, m.schema()).
+			WithIndexOptions(m.indexOptions()...)); err != nil {
+			return fmt.Errorf("创建集合失败: %w", err)
+		}
+		logger.Info("Milvus 集合已创建",
+			zap.String("collection", name),
+			zap.Int("dimension", m.cfg.Dimension),
+		)
+	} else {
+		coll, err := cli.DescribeCollection(ctx, milvusclient.NewDescribeCollectionOption(name))
+		if err != nil {
+			return fmt.Errorf("获取集合信息失败: %w", err)
+		}
+		if err := m.checkDimension(coll.Schema); err != nil {
+			return err
+		}
+		if err := m.ensureFields(ctx, cli, coll.Schema); err != nil {
+			return err
+		}
+		if err := m.ensureIndexes(ctx, cli); err != nil {
+			return err
+		}
+	}
+
+	task, err := cli.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(name))
+	if err != nil {
+		return fmt.Errorf("加载集合失败: %w", err)
+	}
+	return task.Await(ctx)
 }
 
-// SearchResult 检索结果
-type SearchResult struct {
-	ID       int64
-	Score    float32
-	Content  string
-	Metadata map[string]interface{}
+// Upsert 批量写入或更新向量
+func (m *MilvusStore) Upsert(ctx context.Context, records []VectorRecord) error {
+	cli := GetMilvusClient()
+	if cli == nil {
+		return ErrVectorStoreNotReady
+	}
+
+	for start := 0; start < len(records); start += milvusUpsertBatchSize {
+		end := min(start+milvusUpsertBatchSize, len(records))
+		batch := records[start:end]
+
+		n := len(batch)
+		chunkIDs := make([]string, n)
+		documentIDs := make([]string, n)
+		versionIDs := make([]int64, n)
+		lawTypes := make([]string, n)
+		articleNumbers := make([]string, n)
+		effectiveDates := make([]int64, n)
+		expiryDates := make([]int64, n)
+		vectors := make([][]float32, n)
+		for i, r := range batch {
+			if len(r.Vector) != m.cfg.Dimension {
+				return fmt.Errorf("%w: chunk %s 维度 %d，期望 %d", ErrDimensionMismatch, r.ChunkID, len(r.Vector), m.cfg.Dimension)
+			}
+			chunkIDs[i] = r.ChunkID
+			documentIDs[i] = r.DocumentID
+			versionIDs[i] = r.VersionID
+			lawTypes[i] = r.LawType
+			articleNumbers[i] = r.ArticleNumber
+			effectiveDates[i] = r.EffectiveDate
+			expiryDates[i] = r.ExpiryDate
+			vectors[i] = r.Vector
+		}
+
+		opt := milvusclient.NewColumnBasedInsertOption(m.cfg.CollectionName).
+			WithVarcharColumn(FieldChunkID, chunkIDs).
+			WithVarcharColumn(FieldDocumentID, documentIDs).
+			WithInt64Column(FieldVersionID, versionIDs).
+			WithVarcharColumn(FieldLawType, lawTypes).
+			WithVarcharColumn(FieldArticleNumber, articleNumbers).
+			WithInt64Column(FieldEffectiveDate, effectiveDates).
+			WithInt64Column(FieldExpiryDate, expiryDates).
+			WithFloatVectorColumn(FieldVector, m.cfg.Dimension, vectors)
+		if _, err := cli.Upsert(ctx, opt); err != nil {
+			return fmt.Errorf("写入向量失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateDates 以部分更新 (partial upsert) 写入分块的生效与失效日期
+func (m *MilvusStore) UpdateDates(ctx context.Context, dates []ChunkDates) error {
+	cli := GetMilvusClient()
+	if cli == nil {
+		return ErrVectorStoreNotReady
+	}
+
+	for start := 0; start < len(dates); start += milvusUpsertBatchSize {
+		batch := dates[start:min(start+milvusUpsertBatchSize, len(dates))]
+
+		chunkIDs := make([]string, len(batch))
+		effectiveDates := make([]int64, len(batch))
+		expiryDates := make([]int64, len(batch))
+		for i, d := range batch {
+			chunkIDs[i] = d.ChunkID
+			effectiveDates[i] = d.EffectiveDate
+			expiryDates[i] = d.ExpiryDate
+		}
+
+		opt := milvusclient.NewColumnBasedInsertOption(m.cfg.CollectionName).
+			WithVarcharColumn(FieldChunkID, chunkIDs).
+			WithInt64Column(FieldEffectiveDate, effectiveDates).
+			WithInt64Column(FieldExpiryDate, expiryDates).
+			WithPartialUpdate(true)
+		if _, err := cli.Upsert(ctx, opt); err != nil {
+			return fmt.Errorf("更新向量时效失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteByDocument 删除文档的全部向量
+func (m *MilvusStore) DeleteByDocument(ctx context.Context, documentID string) error {
+	cli := GetMilvusClient()
+	if cli == nil {
+		return ErrVectorStoreNotReady
+	}
+
+	_, err := cli.Delete(ctx, milvusclient.NewDeleteOption(m.cfg.CollectionName).
+		WithExpr(FilterEq(FieldDocumentID, documentID)))
+	if err != nil {
+		return fmt.Errorf("删除向量失败: %w", err)
+	}
+	return nil
+}
+
+// Search 向量检索
+func (m *MilvusStore) Search(ctx context.Context, vector []float32, topK int, filter string) ([]SearchResult, error) {
+	cli := GetMilvusClient()
+	if cli == nil {
+		return nil, ErrVectorStoreNotReady
+	}
+	if len(vector) != m.cfg.Dimension {
+		return nil, fmt.Errorf("%w: 查询向量维度 %d，期望 %d", ErrDimensionMismatch, len(vector), m.cfg.Dimension)
+	}
+
+	opt := milvusclient.NewSearchOption(m.cfg.CollectionName, topK, []entity.Vector{entity.FloatVector(vector)}).
+		WithANNSField(FieldVector).
+		WithOutputFields(milvusOutputFields...)
+	if !m.cfg.UseCloud {
+		opt = opt.WithAnnParam(index.NewHNSWAnnParam(milvusSearchEf))
+	}
+	if filter != "" {
+		opt = opt.WithFilter(filter)
+	}
+
+	resultSets, err := cli.Search(ctx, opt)
+	if err != nil {
+		return nil, fmt.Errorf("向量检索失败: %w", err)
+	}
+	if len(resultSets) == 0 {
+		return nil, nil
+	}
+
+	rs := resultSets[0]
+	if rs.Err != nil {
+		return nil, fmt.Errorf("向量检索失败: %w", rs.Err)
+	}
+
+	results := make([]SearchResult, 0, rs.ResultCount)
+	for i := 0; i < rs.ResultCount; i++ {
+		chunkID, err := rs.IDs.GetAsString(i)
+		if err != nil {
+			return nil, fmt.Errorf("解析检索结果失败: %w", err)
+		}
+		metadata := make(map[string]interface{}, len(milvusOutputFields))
+		for _, field := range milvusOutputFields {
+			col := rs.GetColumn(field)
+			if col == nil {
+				continue
+			}
+			if v, err := col.Get(i); err == nil {
+				metadata[field] = v
+			}
+		}
+		results = append(results, SearchResult{
+			ChunkID:  chunkID,
+			Score:    rs.Scores[i],
+			Metadata: metadata,
+		})
+	}
+	return results, nil
+}
+
+// schema 集合结构定义
+func (m *MilvusStore) schema() *entity.Schema {
+	return entity.NewSchema().
+		WithName(m.cfg.CollectionName).
+		WithDescription("法律知识库分块向量").
+		WithField(entity.NewField().WithName(FieldChunkID).WithDataType(entity.FieldTypeVarChar).WithMaxLength(64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName(FieldDocumentID).WithDataType(entity.FieldTypeVarChar).WithMaxLength(36)).
+		WithField(entity.NewField().WithName(FieldVersionID).WithDataType(entity.FieldTypeInt64)).
+		WithField(entity.NewField().WithName(FieldLawType).WithDataType(entity.FieldTypeVarChar).WithMaxLength(50)).
+		WithField(entity.NewField().WithName(FieldArticleNumber).WithDataType(entity.FieldTypeVarChar).WithMaxLength(32)).
+		WithField(entity.NewField().WithName(FieldEffectiveDate).WithDataType(entity.FieldTypeInt64)).
+		WithField(expiryDateField()).
+		WithField(entity.NewField().WithName(FieldVector).WithDataType(entity.FieldTypeFloatVector).WithDim(int64(m.cfg.Dimension)))
+}
+
+// indexOptions 集合索引定义
+// Zilliz Cloud 仅支持 AUTOINDEX
+func (m *MilvusStore) indexOptions() []milvusclient.CreateIndexOption {
+	name := m.cfg.CollectionName
+
+	var vectorIndex index.Index = index.NewHNSWIndex(entity.COSINE, milvusHNSWM, milvusHNSWEfConstruction)
+	if m.cfg.UseCloud {
+		vectorIndex = index.NewAutoIndex(entity.COSINE)
+	}
+
+	return []milvusclient.CreateIndexOption{
+		milvusclient.NewCreateIndexOption(name, FieldVector, vectorIndex).WithIndexName(FieldVector),
+		milvusclient.NewCreateIndexOption(name, FieldDocumentID, index.NewInvertedIndex()).WithIndexName(FieldDocumentID),
+		milvusclient.NewCreateIndexOption(name, FieldVersionID, index.NewInvertedIndex()).WithIndexName(FieldVersionID),
+		milvusclient.NewCreateIndexOption(name, FieldLawType, index.NewInvertedIndex()).WithIndexName(FieldLawType),
+		milvusclient.NewCreateIndexOption(name, FieldEffectiveDate, index.NewSortedIndex()).WithIndexName(FieldEffectiveDate),
+		milvusclient.NewCreateIndexOption(name, FieldExpiryDate, index.NewSortedIndex()).WithIndexName(FieldExpiryDate),
+	}
+}
+
+// expiryDateField 失效日期字段
+// 该字段晚于集合初版加入，已有集合通过 AddCollectionField 补充，Milvus 要求新增字段可为空，
+// 默认值 0 (现行有效) 使存量数据无需回填
+func expiryDateField() *entity.Field {
+	return entity.NewField().WithName(FieldExpiryDate).WithDataType(entity.FieldTypeInt64).
+		WithNullable(true).WithDefaultValueLong(0)
+}
+
+// ensureFields 为已有集合补充后续版本新增的字段
+func (m *MilvusStore) ensureFields(ctx context.Context, cli *milvusclient.Client, schema *entity.Schema) error {
+	for _, field := range schema.Fields {
+		if field.Name == FieldExpiryDate {
+			return nil
+		}
+	}
+	if err := cli.AddCollectionField(ctx, milvusclient.NewAddCollectionFieldOption(m.cfg.CollectionName, expiryDateField())); err != nil {
+		return fmt.Errorf("补充字段 %s 失败: %w", FieldExpiryDate, err)
+	}
+	logger.Info("Milvus 集合已补充字段",
+		zap.String("collection", m.cfg.CollectionName),
+		zap.String("field", FieldExpiryDate),
+	)
+	return nil
+}
+
+// ensureIndexes 补建缺失的索引
+func (m *MilvusStore) ensureIndexes(ctx context.Context, cli *milvusclient.Client) error {
+	existing, err := cli.ListIndexes(ctx, milvusclient.NewListIndexOption(m.cfg.CollectionName))
+	if err != nil {
+		return fmt.Errorf("获取索引列表失败: %w", err)
+	}
+	has := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		has[name] = true
+	}
+
+	for _, opt := range m.indexOptions() {
+		req := opt.Request()
+		if has[req.GetIndexName()] {
+			continue
+		}
+		task, err := cli.CreateIndex(ctx, opt)
+		if err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %w", req.GetIndexName(), err)
+		}
+		if err := task.Await(ctx); err != nil {
+			return fmt.Errorf("等待索引 %s 构建失败: %w", req.GetIndexName(), err)
+		}
+	}
+	return nil
+}
+
+// checkDimension 校验已有集合的向量维度
+func (m *MilvusStore) checkDimension(schema *entity.Schema) error {
+	for _, field := range schema.Fields {
+		if field.Name != FieldVector {
+			continue
+		}
+		dim, err := field.GetDim()
+		if err != nil {
+			return fmt.Errorf("读取向量维度失败: %w", err)
+		}
+		if int(dim) != m.cfg.Dimension {
+			return fmt.Errorf("%w: 集合 %s 为 %d 维，配置为 %d 维", ErrDimensionMismatch, m.cfg.CollectionName, dim, m.cfg.Dimension)
+		}
+		return nil
+	}
+	return fmt.Errorf("集合 %s 缺少向量字段 %s", m.cfg.CollectionName, FieldVector)
 }
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+)
+
+// defaultRerankTimeout 默认请求超时
+const defaultRerankTimeout = 10 * time.Second
+
+// RerankConfig 重排配置别名
+type RerankConfig = config.RerankConfig
+
+// RerankResult 重排结果
+type RerankResult struct {
+	Index int     // 对应输入文档的下标
+	Score float32 // 相关度分数，越大越相关
+}
+
+// RerankClient 重排接口客户端
+// 兼容 Jina/Cohere 风格的 POST /rerank 接口 (vLLM、Xinference、SiliconFlow 等部署的 bge-reranker)
+// 重排位于问答链路上，失败时由调用方降级，因此不做重试
+type RerankClient struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewRerankClient 创建重排客户端
+func NewRerankClient(cfg *RerankConfig) *RerankClient {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultRerankTimeout
+	}
+
+	return &RerankClient{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+		model:   cfg.Model,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				DialContext:         (&net.Dialer{Timeout: 5 * time.Second}).DialContext,
+				IdleConnTimeout:     90 * time.Second,
+				MaxIdleConnsPerHost: 16,
+			},
+		},
+	}
+}
+
+// Model 返回模型名称
+func (c *RerankClient) Model() string {
+	return c.model
+}
+
+// Rerank 计算每个文档与查询的相关度，返回结果按相关度降序排列
+func (c *RerankClient) Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(rerankRequest{
+		Model:     c.model,
+		Query:     query,
+		Documents: documents,
+		TopN:      len(documents),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("重排请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var result rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析重排响应失败: %w", err)
+	}
+
+	out := make([]RerankResult, 0, len(result.Results))
+	for _, r := range result.Results {
+		if r.Index < 0 || r.Index >= len(documents) {
+			return nil, fmt.Errorf("重排返回的文档下标 %d 越界 (共 %d 条)", r.Index, len(documents))
+		}
+		out = append(out, RerankResult{Index: r.Index, Score: r.RelevanceScore})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+	return out, nil
+}
+
+// rerankRequest 重排请求体
+type rerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+// rerankResponse 重排响应体
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float32 `json:"relevance_score"`
+	} `json:"results"`
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 向量集合字段名
+const (
+	FieldChunkID       = "chunk_id"
+	FieldDocumentID    = "document_id"
+	FieldVersionID     = "version_id"
+	FieldLawType       = "law_type"
+	FieldArticleNumber = "article_number"
+	FieldEffectiveDate = "effective_date"
+	FieldExpiryDate    = "expiry_date"
+	FieldVector        = "vector"
+)
+
+var (
+	ErrVectorStoreNotReady = errors.New("向量库未初始化")
+	ErrDimensionMismatch   = errors.New("向量维度与集合定义不一致")
+)
+
+// VectorRecord 向量记录
+type VectorRecord struct {
+	ChunkID       string
+	DocumentID    string
+	VersionID     int64
+	LawType       string
+	ArticleNumber string
+	EffectiveDate int64 // 生效日期 (YYYYMMDD)，0 表示未知
+	ExpiryDate    int64 // 失效日期 (YYYYMMDD)，0 表示现行有效
+	Vector        []float32
+}
+
+// ChunkDates 分块时效 (更新标量字段，不重写向量)
+type ChunkDates struct {
+	ChunkID       string
+	EffectiveDate int64
+	ExpiryDate    int64
+}
+
+// SearchResult 检索结果
+type SearchResult struct {
+	ChunkID  string
+	Score    float32 // 余弦相似度，越大越相似
+	Metadata map[string]interface{}
+}
+
+// VectorStore 向量存储接口
+type VectorStore interface {
+	// EnsureCollection 幂等地创建集合、索引并加载
+	EnsureCollection(ctx context.Context) error
+	// Upsert 批量写入或更新向量 (以 chunk_id 为主键)
+	Upsert(ctx context.Context, records []VectorRecord) error
+	// DeleteByDocument 删除文档的全部向量
+	DeleteByDocument(ctx context.Context, documentID string) error
+	// UpdateDates 更新分块的生效与失效日期，不存在的分块忽略
+	UpdateDates(ctx context.Context, dates []ChunkDates) error
+	// Search 向量检索，filter 为标量过滤表达式 (Milvus 布尔表达式语法)，为空表示不过滤
+	Search(ctx context.Context, vector []float32, topK int, filter string) ([]SearchResult, error)
+}
+
+// DateKey 将日期转换为 effective_date / expiry_date 字段值 (YYYYMMDD)
+func DateKey(t time.Time) int64 {
+	return int64(t.Year()*10000 + int(t.Month())*100 + t.Day())
+}
+
+// OptionalDateKey 同 DateKey，日期为空时返回 0
+func OptionalDateKey(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return DateKey(*t)
+}
+
+// ============================================================================
+// 过滤表达式构造
+// ============================================================================
+
+// FilterEq 构造等值过滤表达式
+func FilterEq(field string, value interface{}) string {
+	return field + " == " + filterLiteral(value)
+}
+
+// FilterCompare 构造比较过滤表达式 (op: <, <=, >, >=, !=)
+func FilterCompare(field, op string, value interface{}) string {
+	return field + " " + op + " " + filterLiteral(value)
+}
+
+// FilterIn 构造集合过滤表达式
+func FilterIn[T any](field string, values []T) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = filterLiteral(v)
+	}
+	return field + " in [" + strings.Join(items, ", ") + "]"
+}
+
+// FilterAnd 以 and 连接多个表达式，忽略空表达式
+func FilterAnd(exprs ...string) string {
+	return joinFilters("and", exprs)
+}
+
+// FilterOr 以 or 连接多个表达式，忽略空表达式
+func FilterOr(exprs ...string) string {
+	return joinFilters("or", exprs)
+}
+
+// joinFilters 以逻辑运算符连接表达式，多个表达式时逐个加括号
+func joinFilters(op string, exprs []string) string {
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		if e = strings.TrimSpace(e); e != "" {
+			parts = append(parts, "("+e+")")
+		}
+	}
+	if len(parts) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(parts[0], "("), ")")
+	}
+	return strings.Join(parts, " "+op+" ")
+}
+
+// FilterInForce 构造 "在 date 当日有效" 的过滤表达式
+// 生效日期未知 (0) 视为已生效，失效日期为 0 表示现行有效
+func FilterInForce(date time.Time) string {
+	key := DateKey(date)
+	return FilterAnd(
+		FilterCompare(FieldEffectiveDate, "<=", key),
+		FilterOr(FilterEq(FieldExpiryDate, 0), FilterCompare(FieldExpiryDate, ">", key)),
+	)
+}
+
+// filterLiteral 格式化表达式字面量
+func filterLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return strconv.Quote(fmt.Sprint(v))
+	}
+}
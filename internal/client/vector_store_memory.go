@@ -0,0 +1,468 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	_ VectorStore = (*MilvusStore)(nil)
+	_ VectorStore = (*MemoryVectorStore)(nil)
+)
+
+// MemoryVectorStore 内存向量存储
+// 用于测试与本地开发，支持与 Milvus 相同的常用过滤表达式子集:
+// ==, !=, <, <=, >, >=, in, not in, and, or, not 与括号
+type MemoryVectorStore struct {
+	mu        sync.RWMutex
+	dimension int
+	records   map[string]VectorRecord
+}
+
+// NewMemoryVectorStore 创建内存向量存储
+func NewMemoryVectorStore(dimension int) *MemoryVectorStore {
+	return &MemoryVectorStore{
+		dimension: dimension,
+		records:   make(map[string]VectorRecord),
+	}
+}
+
+// EnsureCollection 内存存储无需初始化
+func (m *MemoryVectorStore) EnsureCollection(ctx context.Context) error {
+	return nil
+}
+
+// Upsert 批量写入或更新向量
+func (m *MemoryVectorStore) Upsert(ctx context.Context, records []VectorRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range records {
+		if len(r.Vector) != m.dimension {
+			return fmt.Errorf("%w: chunk %s 维度 %d，期望 %d", ErrDimensionMismatch, r.ChunkID, len(r.Vector), m.dimension)
+		}
+	}
+	for _, r := range records {
+		r.Vector = append([]float32(nil), r.Vector...)
+		m.records[r.ChunkID] = r
+	}
+	return nil
+}
+
+// DeleteByDocument 删除文档的全部向量
+func (m *MemoryVectorStore) DeleteByDocument(ctx context.Context, documentID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, r := range m.records {
+		if r.DocumentID == documentID {
+			delete(m.records, id)
+		}
+	}
+	return nil
+}
+
+// UpdateDates 更新分块的生效与失效日期
+func (m *MemoryVectorStore) UpdateDates(ctx context.Context, dates []ChunkDates) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, d := range dates {
+		r, ok := m.records[d.ChunkID]
+		if !ok {
+			continue
+		}
+		r.EffectiveDate = d.EffectiveDate
+		r.ExpiryDate = d.ExpiryDate
+		m.records[d.ChunkID] = r
+	}
+	return nil
+}
+
+// Search 暴力计算余弦相似度检索
+func (m *MemoryVectorStore) Search(ctx context.Context, vector []float32, topK int, filter string) ([]SearchResult, error) {
+	if len(vector) != m.dimension {
+		return nil, fmt.Errorf("%w: 查询向量维度 %d，期望 %d", ErrDimensionMismatch, len(vector), m.dimension)
+	}
+
+	var expr filterNode
+	if strings.TrimSpace(filter) != "" {
+		var err error
+		if expr, err = parseFilter(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := make([]SearchResult, 0, len(m.records))
+	for _, r := range m.records {
+		fields := r.fields()
+		if expr != nil && !expr.eval(fields) {
+			continue
+		}
+		delete(fields, FieldChunkID)
+		results = append(results, SearchResult{
+			ChunkID:  r.ChunkID,
+			Score:    CosineSimilarity(vector, r.Vector),
+			Metadata: fields,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ChunkID < results[j].ChunkID
+	})
+	if topK > 0 && len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// Len 返回向量数量
+func (m *MemoryVectorStore) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.records)
+}
+
+// fields 返回记录的标量字段
+func (r VectorRecord) fields() map[string]interface{} {
+	return map[string]interface{}{
+		FieldChunkID:       r.ChunkID,
+		FieldDocumentID:    r.DocumentID,
+		FieldVersionID:     r.VersionID,
+		FieldLawType:       r.LawType,
+		FieldArticleNumber: r.ArticleNumber,
+		FieldEffectiveDate: r.EffectiveDate,
+		FieldExpiryDate:    r.ExpiryDate,
+	}
+}
+
+// CosineSimilarity 余弦相似度
+func CosineSimilarity(a, b []float32) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+// ============================================================================
+// 过滤表达式解析
+// ============================================================================
+
+// filterNode 过滤表达式节点
+type filterNode interface {
+	eval(fields map[string]interface{}) bool
+}
+
+type andNode struct{ left, right filterNode }
+type orNode struct{ left, right filterNode }
+type notNode struct{ inner filterNode }
+
+type compareNode struct {
+	field string
+	op    string
+	value interface{}
+}
+
+type inNode struct {
+	field  string
+	values []interface{}
+	negate bool
+}
+
+func (n andNode) eval(f map[string]interface{}) bool { return n.left.eval(f) && n.right.eval(f) }
+func (n orNode) eval(f map[string]interface{}) bool  { return n.left.eval(f) || n.right.eval(f) }
+func (n notNode) eval(f map[string]interface{}) bool { return !n.inner.eval(f) }
+
+func (n compareNode) eval(f map[string]interface{}) bool {
+	c, ok := compareValues(f[n.field], n.value)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func (n inNode) eval(f map[string]interface{}) bool {
+	for _, v := range n.values {
+		if c, ok := compareValues(f[n.field], v); ok && c == 0 {
+			return !n.negate
+		}
+	}
+	return n.negate
+}
+
+// compareValues 比较字段值与字面量
+func compareValues(field, literal interface{}) (int, bool) {
+	switch fv := field.(type) {
+	case string:
+		lv, ok := literal.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(fv, lv), true
+	case int64:
+		lv, ok := literal.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case float64(fv) < lv:
+			return -1, true
+		case float64(fv) > lv:
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// filterParser 递归下降解析器
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseFilter 解析过滤表达式
+func parseFilter(expr string) (filterNode, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("过滤表达式解析失败: 多余的 %q", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "or" || t == "||"; t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "and" || t == "&&"; t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	switch p.peek() {
+	case "not", "!":
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	case "(":
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("过滤表达式解析失败: 缺少右括号")
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	field := p.next()
+	if field == "" || !isIdentStart(rune(field[0])) {
+		return nil, fmt.Errorf("过滤表达式解析失败: 无效字段 %q", field)
+	}
+
+	op := p.next()
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{field: field, op: op, value: value}, nil
+	case "in":
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inNode{field: field, values: values}, nil
+	case "not":
+		if p.next() != "in" {
+			return nil, fmt.Errorf("过滤表达式解析失败: not 后应为 in")
+		}
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inNode{field: field, values: values, negate: true}, nil
+	}
+	return nil, fmt.Errorf("过滤表达式解析失败: 不支持的运算符 %q", op)
+}
+
+func (p *filterParser) parseList() ([]interface{}, error) {
+	if p.next() != "[" {
+		return nil, fmt.Errorf("过滤表达式解析失败: in 后应为列表")
+	}
+	var values []interface{}
+	for p.peek() != "]" {
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek() == "," {
+			p.next()
+		}
+	}
+	p.next()
+	return values, nil
+}
+
+func (p *filterParser) parseLiteral() (interface{}, error) {
+	t := p.next()
+	if t == "" {
+		return nil, fmt.Errorf("过滤表达式解析失败: 缺少字面量")
+	}
+	if t[0] == '"' || t[0] == '\'' {
+		if t[0] == '\'' {
+			t = `"` + strings.ReplaceAll(t[1:len(t)-1], `"`, `\"`) + `"`
+		}
+		s, err := strconv.Unquote(t)
+		if err != nil {
+			return nil, fmt.Errorf("过滤表达式解析失败: %w", err)
+		}
+		return s, nil
+	}
+	f, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return nil, fmt.Errorf("过滤表达式解析失败: 无效字面量 %q", t)
+	}
+	return f, nil
+}
+
+// tokenizeFilter 过滤表达式分词
+func tokenizeFilter(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("过滤表达式解析失败: 字符串未闭合")
+			}
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("=!<>&|", r):
+			j := i + 1
+			if j < len(runes) && strings.ContainsRune("=&|", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case isIdentStart(r) || unicode.IsDigit(r) || r == '-' || r == '.':
+			j := i + 1
+			for j < len(runes) && (isIdentStart(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			token := string(runes[i:j])
+			switch lower := strings.ToLower(token); lower {
+			case "and", "or", "not", "in":
+				token = lower
+			}
+			tokens = append(tokens, token)
+			i = j
+		default:
+			return nil, fmt.Errorf("过滤表达式解析失败: 非法字符 %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// newTestStore 创建包含不同法律类别与时效的内存向量库
+func newTestStore(t *testing.T) *MemoryVectorStore {
+	t.Helper()
+	store := NewMemoryVectorStore(2)
+	records := []VectorRecord{
+		{ChunkID: "civil-1", DocumentID: "civil", LawType: "民法", VersionID: 1, EffectiveDate: 20210101},
+		{ChunkID: "civil-2", DocumentID: "civil", LawType: "民法", VersionID: 1, EffectiveDate: 20210101},
+		{ChunkID: "contract-1", DocumentID: "contract", LawType: "民法", VersionID: 1, EffectiveDate: 19991001, ExpiryDate: 20210101},
+		{ChunkID: "labor-1", DocumentID: "labor", LawType: "劳动法", VersionID: 2, EffectiveDate: 20080101},
+		{ChunkID: "unknown-1", DocumentID: "unknown", LawType: `司法"解释"`, VersionID: 2},
+		{ChunkID: "future-1", DocumentID: "future", LawType: "刑法", VersionID: 3, EffectiveDate: 20300101},
+	}
+	for i := range records {
+		records[i].Vector = []float32{1, float32(i)}
+	}
+	if err := store.Upsert(context.Background(), records); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	return store
+}
+
+// searchIDs 以给定过滤表达式检索，返回按 ID 排序的分块 ID
+func searchIDs(t *testing.T, store *MemoryVectorStore, filter string) []string {
+	t.Helper()
+	results, err := store.Search(context.Background(), []float32{1, 0}, 100, filter)
+	if err != nil {
+		t.Fatalf("Search(%q) error = %v", filter, err)
+	}
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ChunkID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestFilterExpressions(t *testing.T) {
+	date := time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		got, want string
+	}{
+		{FilterEq(FieldLawType, "民法"), `law_type == "民法"`},
+		{FilterEq(FieldVersionID, int64(3)), `version_id == 3`},
+		{FilterIn(FieldLawType, []string{"民法", `a"b`}), `law_type in ["民法", "a\"b"]`},
+		{FilterAnd("a == 1", "", " "), `a == 1`},
+		{FilterAnd("a == 1", "b == 2"), `(a == 1) and (b == 2)`},
+		{FilterInForce(date), `(effective_date <= 20200601) and ((expiry_date == 0) or (expiry_date > 20200601))`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("filter = %s, want %s", tt.got, tt.want)
+		}
+	}
+}
+
+func TestMemoryVectorStoreFilter(t *testing.T) {
+	store := newTestStore(t)
+	at := func(y, m, d int) time.Time { return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{
+			name: "不过滤",
+			want: []string{"civil-1", "civil-2", "contract-1", "future-1", "labor-1", "unknown-1"},
+		},
+		{
+			// 生效日期未知视为已生效，失效日期为 0 表示现行有效
+			name:   "现行有效",
+			filter: FilterInForce(at(2024, 1, 1)),
+			want:   []string{"civil-1", "civil-2", "labor-1", "unknown-1"},
+		},
+		{
+			name:   "历史日期",
+			filter: FilterInForce(at(2020, 6, 1)),
+			want:   []string{"contract-1", "labor-1", "unknown-1"},
+		},
+		{
+			// 失效当日已不再有效，生效当日已生效
+			name:   "失效与生效当日",
+			filter: FilterInForce(at(2021, 1, 1)),
+			want:   []string{"civil-1", "civil-2", "labor-1", "unknown-1"},
+		},
+		{
+			name:   "法律类别",
+			filter: FilterIn(FieldLawType, []string{"民法", "劳动法"}),
+			want:   []string{"civil-1", "civil-2", "contract-1", "labor-1"},
+		},
+		{
+			name:   "法律类别含引号",
+			filter: FilterIn(FieldLawType, []string{`司法"解释"`}),
+			want:   []string{"unknown-1"},
+		},
+		{
+			name:   "空类别列表",
+			filter: FilterIn(FieldLawType, []string{}),
+			want:   []string{},
+		},
+		{
+			name:   "法律类别与时效组合",
+			filter: FilterAnd(FilterInForce(at(2024, 1, 1)), FilterIn(FieldLawType, []string{"民法"})),
+			want:   []string{"civil-1", "civil-2"},
+		},
+		{
+			name:   "not in 与数值比较",
+			filter: `law_type not in ["民法"] and version_id >= 2`,
+			want:   []string{"future-1", "labor-1", "unknown-1"},
+		},
+		{
+			name:   "not、or 与括号",
+			filter: `not (document_id == "civil" or document_id == 'labor') && version_id != 3`,
+			want:   []string{"contract-1", "unknown-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchIDs(t, store, tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryVectorStoreInvalidFilter(t *testing.T) {
+	store := newTestStore(t)
+	for _, filter := range []string{
+		`law_type ==`,
+		`(law_type == "民法"`,
+		`law_type ~ "民法"`,
+		`law_type in "民法"`,
+		`law_type == "民法`,
+		`law_type == "民法" law_type`,
+	} {
+		if _, err := store.Search(context.Background(), []float32{1, 0}, 10, filter); err == nil {
+			t.Errorf("Search(%q) 应返回解析错误", filter)
+		}
+	}
+}
+
+func TestMemoryVectorStoreSearch(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryVectorStore(2)
+	err := store.Upsert(ctx, []VectorRecord{
+		{ChunkID: "a", DocumentID: "d1", LawType: "民法", Vector: []float32{1, 0}},
+		{ChunkID: "b", DocumentID: "d1", LawType: "民法", Vector: []float32{1, 1}},
+		{ChunkID: "c", DocumentID: "d2", LawType: "民法", Vector: []float32{0, 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := store.Search(ctx, []float32{1, 0}, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ChunkID != "a" || results[1].ChunkID != "b" {
+		t.Fatalf("results = %+v, want a, b by similarity", results)
+	}
+	if results[0].Score < 0.999 {
+		t.Errorf("score = %v, want 1", results[0].Score)
+	}
+	if results[0].Metadata[FieldDocumentID] != "d1" || results[0].Metadata[FieldLawType] != "民法" {
+		t.Errorf("metadata = %v", results[0].Metadata)
+	}
+	if _, ok := results[0].Metadata[FieldChunkID]; ok {
+		t.Error("metadata 不应包含主键")
+	}
+
+	if err := store.Upsert(ctx, []VectorRecord{{ChunkID: "x", Vector: []float32{1}}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Upsert() error = %v, want ErrDimensionMismatch", err)
+	}
+	if _, err := store.Search(ctx, []float32{1, 0, 0}, 1, ""); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Search() error = %v, want ErrDimensionMismatch", err)
+	}
+
+	// 更新时效后过滤结果随之变化
+	if err := store.UpdateDates(ctx, []ChunkDates{{ChunkID: "a", EffectiveDate: 20200101, ExpiryDate: 20220101}, {ChunkID: "missing"}}); err != nil {
+		t.Fatal(err)
+	}
+	inForce := FilterInForce(time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local))
+	if got := searchIDs(t, store, inForce); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("after UpdateDates = %v, want [b c]", got)
+	}
+
+	if err := store.DeleteByDocument(ctx, "d1"); err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Len() = %d after delete, want 1", store.Len())
+	}
+}
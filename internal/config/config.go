@@ -16,6 +16,16 @@ type Config struct {
 	Database     DatabaseConfig     `mapstructure:"database"`
 	Redis        RedisConfig        `mapstructure:"redis"`
 	Milvus       MilvusConfig       `mapstructure:"milvus"`
+	LLM          LLMConfig          `mapstructure:"llm"`
+	Citation     CitationConfig     `mapstructure:"citation"`
+	Retrieval    RetrievalConfig    `mapstructure:"retrieval"`
+	Rerank       RerankConfig       `mapstructure:"rerank"`
+	Graph        GraphConfig        `mapstructure:"graph"`
+	Embedding    EmbeddingConfig    `mapstructure:"embedding"`
+	Document     DocumentConfig     `mapstructure:"document"`
+	Blockchain   BlockchainConfig   `mapstructure:"blockchain"`
+	Audit        AuditConfig        `mapstructure:"audit"`
+	Evidence     EvidenceConfig     `mapstructure:"evidence"`
 	JWT          JWTConfig          `mapstructure:"jwt"`
 	Auth         AuthConfig         `mapstructure:"auth"`
 	CORS         CORSConfig         `mapstructure:"cors"`
@@ -103,6 +113,126 @@ func (c *MilvusConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// LLMConfig 大模型配置 (OpenAI Chat Completions 兼容接口)
+// 适用于 OpenAI、DeepSeek、通义千问、vLLM、Ollama 等兼容服务
+type LLMConfig struct {
+	BaseURL      string        `mapstructure:"base_url"`      // 接口地址，如 https://api.openai.com/v1
+	APIKey       string        `mapstructure:"api_key"`       // API Key（本地服务可为空）
+	Model        string        `mapstructure:"model"`         // 模型名称
+	Temperature  float64       `mapstructure:"temperature"`   // 采样温度
+	MaxTokens    int           `mapstructure:"max_tokens"`    // 单次回答最大 Token 数
+	Timeout      time.Duration `mapstructure:"timeout"`       // 非流式请求超时 / 流式首包超时
+	MaxRetries   int           `mapstructure:"max_retries"`   // 429/5xx 最大重试次数
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // 重试初始退避时间（指数增长）
+}
+
+// CitationConfig 回答引用一致性校验配置
+type CitationConfig struct {
+	Mode       string `mapstructure:"mode"`       // 引用无法核验时的处理方式: flag 标注 | strip 删除所在句子
+	Regenerate bool   `mapstructure:"regenerate"` // 引用无法核验时是否要求模型重新生成一次
+}
+
+// RetrievalConfig 问答检索配置
+type RetrievalConfig struct {
+	TopK       int  `mapstructure:"top_k"`       // 返回给后续节点的条文数
+	Hybrid     bool `mapstructure:"hybrid"`      // 是否启用混合检索 (向量 + PostgreSQL 全文检索 + 条号精确检索)
+	CandidateK int  `mapstructure:"candidate_k"` // 混合检索时每路检索的候选数
+	RRFK       int  `mapstructure:"rrf_k"`       // Reciprocal Rank Fusion 平滑常数
+	ExpandHops int  `mapstructure:"expand_hops"` // 引用扩展跳数，0 表示不加载检索结果引用的条文
+	ExpandMax  int  `mapstructure:"expand_max"`  // 引用扩展最多纳入的条文数
+}
+
+// RerankConfig 检索结果重排配置 (Jina/Cohere 风格 /rerank 接口)
+type RerankConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`   // 是否在检索后重排
+	BaseURL  string        `mapstructure:"base_url"`  // 接口地址，如 http://localhost:8001/v1
+	APIKey   string        `mapstructure:"api_key"`   // API Key（本地服务可为空）
+	Model    string        `mapstructure:"model"`     // 模型名称，如 BAAI/bge-reranker-v2-m3
+	Timeout  time.Duration `mapstructure:"timeout"`   // 请求超时
+	MinScore float32       `mapstructure:"min_score"` // 重排分数阈值，低于该值的条文被丢弃
+}
+
+// GraphConfig RAG Graph 执行配置
+type GraphConfig struct {
+	QueryAnalysis QueryAnalysisConfig `mapstructure:"query_analysis"` // 查询理解
+	Timeouts      GraphTimeoutConfig  `mapstructure:"timeouts"`       // 节点超时，0 表示不限制
+}
+
+// QueryAnalysisConfig 查询理解配置
+// 检索前由 LLM 识别法律领域、抽取指明的条文，并将问题改写为多个规范法律用语的检索问题
+type QueryAnalysisConfig struct {
+	Enabled       bool                `mapstructure:"enabled"`        // 是否启用 (每次问答增加一次 LLM 调用)
+	MaxQueries    int                 `mapstructure:"max_queries"`    // 改写问题数上限
+	MinConfidence float64             `mapstructure:"min_confidence"` // 领域分类置信度不低于该值时才按领域过滤检索
+	LawTypes      map[string][]string `mapstructure:"law_types"`      // 法律领域 → 文档法律类型 (Document.LawType)，未配置的领域不过滤
+}
+
+// GraphTimeoutConfig RAG Graph 节点超时配置
+type GraphTimeoutConfig struct {
+	Analyze  time.Duration `mapstructure:"analyze"`  // 查询理解
+	Validity time.Duration `mapstructure:"validity"` // 引用条文时效检查
+	Retrieve time.Duration `mapstructure:"retrieve"` // 向量检索 (含问题向量化)
+	Verify   time.Duration `mapstructure:"verify"`   // 链上验证
+	Rerank   time.Duration `mapstructure:"rerank"`   // 检索结果重排
+	Expand   time.Duration `mapstructure:"expand"`   // 引用扩展
+	Prompt   time.Duration `mapstructure:"prompt"`   // 提示词构建
+	Generate time.Duration `mapstructure:"generate"` // LLM 流式生成 (完整回答)
+	Check    time.Duration `mapstructure:"check"`    // 引用一致性校验 (含重新生成)
+}
+
+// EmbeddingConfig 向量化配置 (OpenAI Embeddings 兼容接口)
+type EmbeddingConfig struct {
+	Provider       string        `mapstructure:"provider"`         // openai | hash (确定性哈希，仅用于测试)
+	BaseURL        string        `mapstructure:"base_url"`         // 接口地址
+	APIKey         string        `mapstructure:"api_key"`          // API Key
+	Model          string        `mapstructure:"model"`            // 模型名称
+	Dimensions     int           `mapstructure:"dimensions"`       // 请求的输出维度（0 表示使用模型默认维度）
+	BatchSize      int           `mapstructure:"batch_size"`       // 单次请求最大文本数
+	MaxBatchTokens int           `mapstructure:"max_batch_tokens"` // 单次请求最大 Token 数（估算）
+	Timeout        time.Duration `mapstructure:"timeout"`          // 请求超时
+	MaxRetries     int           `mapstructure:"max_retries"`      // 429/5xx 最大重试次数
+	RetryBackoff   time.Duration `mapstructure:"retry_backoff"`    // 重试初始退避时间
+	CacheDir       string        `mapstructure:"cache_dir"`        // 向量缓存目录（按内容哈希），为空时仅缓存在内存
+}
+
+// DocumentConfig 知识库文档导入配置
+type DocumentConfig struct {
+	UploadDir      string        `mapstructure:"upload_dir"`      // 上传文件存储目录
+	MaxUploadSize  int64         `mapstructure:"max_upload_size"` // 单个文件最大字节数
+	Workers        int           `mapstructure:"workers"`         // 导入 Worker 数量
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // 待处理文档轮询间隔（新文档会立即唤醒 Worker）
+	FetchTimeout   time.Duration `mapstructure:"fetch_timeout"`   // URL 文档抓取超时
+	ProcessTimeout time.Duration `mapstructure:"process_timeout"` // 单个文档处理超时
+	ProcessLease   time.Duration `mapstructure:"process_lease"`   // 处理中文档的租约，Worker 定期续期，超时未续期的文档由任一实例重新导入
+}
+
+// BlockchainConfig 区块链存证配置 (EVM 兼容链，如 anvil、geth --dev、Polygon)
+type BlockchainConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`          // 是否启用上链存证
+	RPCURL          string        `mapstructure:"rpc_url"`          // JSON-RPC 地址
+	ChainID         int64         `mapstructure:"chain_id"`         // 链 ID，0 表示从节点读取；非 0 时与节点不一致将拒绝启动
+	ContractAddress string        `mapstructure:"contract_address"` // 存证合约地址 (contracts/KnowledgeAnchor.easm)
+	PrivateKey      string        `mapstructure:"private_key"`      // 签名账户私钥 (十六进制)，为空时只读
+	GasLimit        uint64        `mapstructure:"gas_limit"`        // 固定 Gas 上限，0 表示估算后上浮 20%
+	MaxFeeGwei      float64       `mapstructure:"max_fee_gwei"`     // 单位 Gas 最高费用 (Gwei)，0 表示不限制
+	Confirmations   uint64        `mapstructure:"confirmations"`    // 交易确认所需区块数
+	ReceiptTimeout  time.Duration `mapstructure:"receipt_timeout"`  // 等待回执超时，超时后提高费用重发
+	PollInterval    time.Duration `mapstructure:"poll_interval"`    // 回执轮询间隔
+}
+
+// AuditConfig 全量完整性审计配置
+type AuditConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`    // 是否启用定时审计 (需同时启用区块链)
+	Schedule  string        `mapstructure:"schedule"`   // cron 表达式 (分 时 日 月 周)，也支持 @daily、@every 6h
+	BatchSize int           `mapstructure:"batch_size"` // 每批验证的分块数，每批结束后保存进度检查点
+	LockTTL   time.Duration `mapstructure:"lock_ttl"`   // 分布式锁有效期，执行期间每 1/3 TTL 续期
+}
+
+// EvidenceConfig 证据包配置
+type EvidenceConfig struct {
+	SigningKey string `mapstructure:"signing_key"` // 证据包签名私钥 (secp256k1 十六进制)，须与 blockchain.private_key 不同，为空时不可导出证据包
+}
+
 // JWTConfig JWT 认证配置
 type JWTConfig struct {
 	Secret        string        `mapstructure:"secret"`
@@ -208,6 +338,33 @@ func Load(configPath string) (*Config, error) {
 		{"milvus.port", "MILVUS_PORT"},
 		{"milvus.api_key", "MILVUS_API_KEY"},
 		{"milvus.use_cloud", "MILVUS_USE_CLOUD"},
+		// LLM
+		{"llm.base_url", "LLM_BASE_URL"},
+		{"llm.api_key", "LLM_API_KEY"},
+		{"llm.model", "LLM_MODEL"},
+		// Embedding
+		{"embedding.provider", "EMBEDDING_PROVIDER"},
+		{"embedding.base_url", "EMBEDDING_BASE_URL"},
+		{"embedding.api_key", "EMBEDDING_API_KEY"},
+		{"embedding.model", "EMBEDDING_MODEL"},
+		// 重排
+		{"rerank.enabled", "RERANK_ENABLED"},
+		{"rerank.base_url", "RERANK_BASE_URL"},
+		{"rerank.api_key", "RERANK_API_KEY"},
+		{"rerank.model", "RERANK_MODEL"},
+		// 文档导入
+		{"document.upload_dir", "DOCUMENT_UPLOAD_DIR"},
+		// 区块链
+		{"blockchain.enabled", "BLOCKCHAIN_ENABLED"},
+		{"blockchain.rpc_url", "BLOCKCHAIN_RPC_URL"},
+		{"blockchain.chain_id", "BLOCKCHAIN_CHAIN_ID"},
+		{"blockchain.contract_address", "BLOCKCHAIN_CONTRACT_ADDRESS"},
+		{"blockchain.private_key", "BLOCKCHAIN_PRIVATE_KEY"},
+		// 完整性审计
+		{"audit.enabled", "AUDIT_ENABLED"},
+		{"audit.schedule", "AUDIT_SCHEDULE"},
+		// 证据包
+		{"evidence.signing_key", "EVIDENCE_SIGNING_KEY"},
 	}
 
 	for _, e := range bindEnvs {
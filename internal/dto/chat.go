@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ============================================================================
+// 聊天相关 DTO
+// ============================================================================
+
+// ChatRequest 聊天请求
+type ChatRequest struct {
+	SessionID string `json:"sessionId,omitempty"` // 为空时自动创建新会话
+	Message   string `json:"message" binding:"required,max=2000"`
+	AsOfDate  string `json:"asOfDate,omitempty" binding:"omitempty,datetime=2006-01-02"` // 适用日期 (YYYY-MM-DD)，按该日有效的法律回答，为空时为当天
+}
+
+// ============================================================================
+// 会话管理 DTO
+// ============================================================================
+
+// SessionListRequest 会话列表查询请求
+type SessionListRequest struct {
+	Cursor string `form:"cursor"` // 上一页返回的 nextCursor，为空表示第一页
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// CreateSessionRequest 创建会话请求
+type CreateSessionRequest struct {
+	Title string `json:"title" binding:"max=200"`
+}
+
+// SessionResponse 会话信息响应
+type SessionResponse struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Summary       string     `json:"summary,omitempty"`
+	MessageCount  int        `json:"messageCount"`
+	TokensUsed    int        `json:"tokensUsed"`
+	LastMessageAt *time.Time `json:"lastMessageAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// SessionListResponse 会话列表响应 (游标分页)
+type SessionListResponse struct {
+	List       []SessionResponse `json:"list"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+	HasMore    bool              `json:"hasMore"`
+	Limit      int               `json:"limit"`
+}
+
+// SessionDetailResponse 会话详情响应
+type SessionDetailResponse struct {
+	SessionResponse
+	Messages []MessageResponse `json:"messages"`
+}
+
+// MessageResponse 消息响应
+type MessageResponse struct {
+	ID        string             `json:"id"`
+	Role      string             `json:"role"`
+	Content   string             `json:"content"`
+	TokensIn  int                `json:"tokensIn,omitempty"`
+	TokensOut int                `json:"tokensOut,omitempty"`
+	Citations []CitationResponse `json:"citations,omitempty"`
+	Metadata  json.RawMessage    `json:"metadata,omitempty" swaggertype:"object"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// CitationResponse 法条引用响应
+type CitationResponse struct {
+	ID             int64      `json:"id"` // 可通过 GET /verify/citation/{id} 公开验证
+	ChunkID        string     `json:"chunkId"`
+	Text           string     `json:"text"`
+	Source         string     `json:"source"`
+	ArticleNumber  string     `json:"articleNumber,omitempty"`
+	LawHierarchy   string     `json:"lawHierarchy,omitempty"`
+	ChunkHash      string     `json:"chunkHash,omitempty"`
+	VersionID      int        `json:"versionId,omitempty"`
+	VerificationID string     `json:"verificationId,omitempty"`
+	BlockNumber    int64      `json:"blockNumber,omitempty"`
+	Verified       bool       `json:"verified"`
+	VerifiedAt     *time.Time `json:"verifiedAt,omitempty"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// 知识库文档管理 DTO
+// ============================================================================
+
+// UploadDocumentRequest 上传文档请求 (multipart/form-data，文件字段为 file)
+type UploadDocumentRequest struct {
+	Name          string `form:"name" binding:"max=255"` // 为空时使用文件名
+	LawName       string `form:"lawName" binding:"max=200"`
+	LawType       string `form:"lawType" binding:"max=50"`
+	EffectiveDate string `form:"effectiveDate"` // YYYY-MM-DD
+	ExpiryDate    string `form:"expiryDate"`    // YYYY-MM-DD，导入已失效的历史法律时填写
+	PublishOrg    string `form:"publishOrg" binding:"max=100"`
+}
+
+// RegisterURLRequest 登记网页文档请求
+type RegisterURLRequest struct {
+	URL           string `json:"url" binding:"required,url,max=2000"`
+	Name          string `json:"name" binding:"max=255"` // 为空时使用网页标题
+	LawName       string `json:"lawName" binding:"max=200"`
+	LawType       string `json:"lawType" binding:"max=50"`
+	EffectiveDate string `json:"effectiveDate"` // YYYY-MM-DD
+	ExpiryDate    string `json:"expiryDate"`    // YYYY-MM-DD，导入已失效的历史法律时填写
+	PublishOrg    string `json:"publishOrg" binding:"max=100"`
+}
+
+// UpdateDocumentValidityRequest 设置文档时效请求
+// 每次请求为全量设置: 未列出的条文沿用文档的时效
+type UpdateDocumentValidityRequest struct {
+	EffectiveDate string                   `json:"effectiveDate"`                    // YYYY-MM-DD，为空表示未知
+	ExpiryDate    string                   `json:"expiryDate"`                       // YYYY-MM-DD，当日起失效，为空表示现行有效
+	SupersededBy  string                   `json:"supersededBy" binding:"max=36"`    // 替代本法的文档 ID，如《合同法》由《民法典》替代
+	Articles      []ArticleValidityRequest `json:"articles" binding:"max=1000,dive"` // 单独设置时效的条文 (如被修正案删除或修改的条文)
+}
+
+// ArticleValidityRequest 条文时效设置，为空的字段沿用文档设置
+type ArticleValidityRequest struct {
+	ArticleNumber string `json:"articleNumber" binding:"required,max=32"` // 条号，与分块的 articleNumber 一致，如 "107"
+	EffectiveDate string `json:"effectiveDate"`                           // YYYY-MM-DD
+	ExpiryDate    string `json:"expiryDate"`                              // YYYY-MM-DD
+	SupersededBy  string `json:"supersededBy" binding:"max=200"`          // 替代条文说明，如 "《民法典》第五百七十七条"
+}
+
+// DocumentListRequest 文档列表查询请求
+type DocumentListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	Status   string `form:"status" binding:"omitempty,oneof=pending processing indexed minted error"`
+	Type     string `form:"type" binding:"omitempty,oneof=pdf docx txt url markdown"`
+	LawType  string `form:"lawType"`
+	Keyword  string `form:"keyword"` // 搜索文档名称或法律名称
+}
+
+// DocumentResponse 文档信息响应
+type DocumentResponse struct {
+	ID            string                    `json:"id"`
+	Name          string                    `json:"name"`
+	OriginalName  string                    `json:"originalName,omitempty"`
+	Type          string                    `json:"type"`
+	MimeType      string                    `json:"mimeType,omitempty"`
+	Size          int64                     `json:"size"`
+	SourceURL     string                    `json:"sourceUrl,omitempty"`
+	LawName       string                    `json:"lawName,omitempty"`
+	LawType       string                    `json:"lawType,omitempty"`
+	EffectiveDate *time.Time                `json:"effectiveDate,omitempty"`
+	PublishOrg    string                    `json:"publishOrg,omitempty"`
+	ExpiryDate    *time.Time                `json:"expiryDate,omitempty"`
+	SupersededBy  string                    `json:"supersededBy,omitempty"` // 替代本法的文档 ID
+	Articles      []ArticleValidityResponse `json:"articles,omitempty"`     // 单独设置时效的条文
+	Status        string                    `json:"status"`
+	ProcessError  string                    `json:"processError,omitempty"`
+	ChunkCount    int                       `json:"chunkCount"`
+	IsMinted      bool                      `json:"isMinted"`
+	VersionID     int                       `json:"versionId,omitempty"`
+	UploadedBy    string                    `json:"uploadedBy"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+	UpdatedAt     time.Time                 `json:"updatedAt"`
+}
+
+// ArticleValidityResponse 条文时效设置
+type ArticleValidityResponse struct {
+	ArticleNumber string     `json:"articleNumber"`
+	EffectiveDate *time.Time `json:"effectiveDate,omitempty"`
+	ExpiryDate    *time.Time `json:"expiryDate,omitempty"`
+	SupersededBy  string     `json:"supersededBy,omitempty"`
+}
+
+// DocumentListResponse 文档列表响应
+type DocumentListResponse struct {
+	List  []DocumentResponse `json:"list"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Size  int                `json:"pageSize"`
+}
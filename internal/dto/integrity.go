@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// 完整性审计 DTO
+// ============================================================================
+
+// IntegrityReportListRequest 审计报告列表查询请求
+type IntegrityReportListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// IntegrityFinding 审计未通过的分块
+type IntegrityFinding struct {
+	ChunkID    string `json:"chunkId"`
+	DocumentID string `json:"documentId"`
+	VersionID  int    `json:"versionId"`
+	Tampered   bool   `json:"tampered"`
+	Reason     string `json:"reason"`
+}
+
+// IntegrityReportResponse 审计报告响应
+type IntegrityReportResponse struct {
+	ID           int64              `json:"id"`
+	Status       string             `json:"status"`
+	TotalChunks  int64              `json:"totalChunks"`
+	VersionCount int                `json:"versionCount"`
+	Checked      int64              `json:"checked"`
+	Verified     int64              `json:"verified"`
+	Tampered     int64              `json:"tampered"`
+	Unverified   int64              `json:"unverified"`
+	FailReasons  map[string]int64   `json:"failReasons,omitempty"`
+	Findings     []IntegrityFinding `json:"findings,omitempty"`
+	TriggeredBy  string             `json:"triggeredBy,omitempty"`
+	Resumes      int                `json:"resumes"`
+	LastError    string             `json:"lastError,omitempty"`
+	StartedAt    time.Time          `json:"startedAt"`
+	UpdatedAt    time.Time          `json:"updatedAt"`
+	FinishedAt   *time.Time         `json:"finishedAt,omitempty"`
+}
+
+// IntegrityReportListResponse 审计报告列表响应
+type IntegrityReportListResponse struct {
+	List  []IntegrityReportResponse `json:"list"`
+	Total int64                     `json:"total"`
+	Page  int                       `json:"page"`
+	Size  int                       `json:"pageSize"`
+}
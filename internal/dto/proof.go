@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// 公开验证 DTO
+// ============================================================================
+
+// VerifyChunkRequest 分块验证请求
+type VerifyChunkRequest struct {
+	Text        string   `json:"text" binding:"required,max=20000"`
+	MerkleProof []string `json:"merkleProof" binding:"max=64,dive,len=66,startswith=0x,hexadecimal"`
+	VersionID   int      `json:"versionId" binding:"omitempty,min=1"` // 为空时按证明计算出的 Root 匹配版本
+}
+
+// ProofMethod 验证算法说明，第三方可按此独立复核，无需信任本服务
+type ProofMethod struct {
+	ContentHash string `json:"contentHash"`
+	LeafHash    string `json:"leafHash"`
+	NodeHash    string `json:"nodeHash"`
+	RootLookup  string `json:"rootLookup"`
+}
+
+// ChainInfo 存证所在的链与合约
+type ChainInfo struct {
+	ChainID         int64  `json:"chainId,omitempty"`
+	ContractAddress string `json:"contractAddress,omitempty"`
+}
+
+// ChunkProof 分块存证证明
+type ChunkProof struct {
+	ContentHash  string   `json:"contentHash"`
+	LeafHash     string   `json:"leafHash"`
+	MerkleIndex  *int     `json:"merkleIndex,omitempty"`
+	MerkleProof  []string `json:"merkleProof"`
+	ComputedRoot string   `json:"computedRoot,omitempty"`
+
+	VersionID    int       `json:"versionId,omitempty"`
+	AnchoredRoot string    `json:"anchoredRoot,omitempty"` // 从链上合约读取的 Merkle Root
+	TxHash       string    `json:"txHash,omitempty"`
+	BlockNumber  int64     `json:"blockNumber,omitempty"`
+	Chain        ChainInfo `json:"chain"`
+
+	Verified   bool        `json:"verified"`
+	FailReason string      `json:"failReason,omitempty"`
+	Method     ProofMethod `json:"method"`
+	CheckedAt  time.Time   `json:"checkedAt"`
+}
+
+// CitationProofResponse 引用验证响应
+type CitationProofResponse struct {
+	CitationID    int64  `json:"citationId"`
+	ChunkID       string `json:"chunkId"`
+	Text          string `json:"text"`
+	Source        string `json:"source"`
+	ArticleNumber string `json:"articleNumber,omitempty"`
+	LawHierarchy  string `json:"lawHierarchy,omitempty"`
+	ChunkProof
+}
+
+// VersionProofResponse 版本存证验证响应
+type VersionProofResponse struct {
+	VersionID    int       `json:"versionId"`
+	MerkleRoot   string    `json:"merkleRoot"`
+	ChunkCount   int       `json:"chunkCount"`
+	Status       string    `json:"status"`
+	AnchoredRoot string    `json:"anchoredRoot,omitempty"`
+	TxHash       string    `json:"txHash,omitempty"`
+	BlockNumber  int64     `json:"blockNumber,omitempty"`
+	Chain        ChainInfo `json:"chain"`
+	Verified     bool      `json:"verified"` // 链上 Root 与版本记录一致
+	FailReason   string    `json:"failReason,omitempty"`
+	Method       string    `json:"method"`
+	CheckedAt    time.Time `json:"checkedAt"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// 系统配置 DTO
+// ============================================================================
+
+// SystemConfigListRequest 系统配置列表查询请求
+type SystemConfigListRequest struct {
+	Category string `form:"category" binding:"omitempty,max=50"`
+}
+
+// UpdateSystemConfigRequest 更新系统配置请求
+type UpdateSystemConfigRequest struct {
+	Value string `json:"value" binding:"max=10000"`
+}
+
+// SystemConfigResponse 系统配置响应
+type SystemConfigResponse struct {
+	Key         string    `json:"key"`
+	Value       string    `json:"value"`
+	Type        string    `json:"type"`
+	Category    string    `json:"category"`
+	Description string    `json:"description,omitempty"`
+	UpdatedBy   string    `json:"updatedBy,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
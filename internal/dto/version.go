@@ -0,0 +1,88 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// 知识库版本 DTO
+// ============================================================================
+
+// CreateVersionRequest 创建版本请求
+type CreateVersionRequest struct {
+	Description string `json:"description" binding:"max=500"`
+}
+
+// ConfirmVersionRequest 确认版本上链请求
+type ConfirmVersionRequest struct {
+	TxHash      string `json:"txHash" binding:"required,len=66,startswith=0x,hexadecimal"`
+	BlockNumber int64  `json:"blockNumber" binding:"min=0"`
+}
+
+// VersionListRequest 版本列表查询请求
+type VersionListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	Status   string `form:"status" binding:"omitempty,oneof=pending confirmed"`
+}
+
+// VersionDiffRequest 版本对比请求
+type VersionDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+// VersionResponse 版本信息响应
+type VersionResponse struct {
+	ID          int        `json:"id"`
+	MerkleRoot  string     `json:"merkleRoot"`
+	ChunkCount  int        `json:"chunkCount"`
+	Description string     `json:"description,omitempty"`
+	TxHash      string     `json:"txHash,omitempty"`
+	BlockNumber int64      `json:"blockNumber,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
+}
+
+// VersionListResponse 版本列表响应
+type VersionListResponse struct {
+	List  []VersionResponse `json:"list"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Size  int               `json:"pageSize"`
+}
+
+// VersionDocumentResponse 版本包含的文档
+type VersionDocumentResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	LawName    string `json:"lawName,omitempty"`
+	ChunkCount int    `json:"chunkCount"` // 纳入该版本的分块数
+	Deleted    bool   `json:"deleted,omitempty"`
+}
+
+// VersionDetailResponse 版本详情响应
+type VersionDetailResponse struct {
+	VersionResponse
+	Documents []VersionDocumentResponse `json:"documents"`
+}
+
+// VersionChunkDiff 分块差异
+type VersionChunkDiff struct {
+	ChunkID       string `json:"chunkId"`
+	DocumentID    string `json:"documentId"`
+	ArticleNumber string `json:"articleNumber,omitempty"`
+	OldHash       string `json:"oldHash,omitempty"`
+	NewHash       string `json:"newHash,omitempty"`
+}
+
+// VersionDiffResponse 版本对比响应 (from → to)
+type VersionDiffResponse struct {
+	From      int                `json:"from"`
+	To        int                `json:"to"`
+	FromRoot  string             `json:"fromRoot"`
+	ToRoot    string             `json:"toRoot"`
+	Added     []VersionChunkDiff `json:"added"`
+	Removed   []VersionChunkDiff `json:"removed"`
+	Modified  []VersionChunkDiff `json:"modified"`
+	Unchanged int                `json:"unchanged"`
+}
@@ -1,34 +1,603 @@
 // Package graph 提供 Eino Graph 编排相关代码
 package graph
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrGraphNotBuilt Graph 未构建
+var ErrGraphNotBuilt = errors.New("graph 尚未构建")
+
+const (
+	// eventBufferSize 事件通道缓冲大小
+	eventBufferSize = 64
+	// dateLayout 事件与执行记录中的日期格式
+	dateLayout = "2006-01-02"
+)
+
+// 节点名称 (写入 NodeTrace.Name)
+const (
+	NodeAnalyze    = "analyze"     // 查询理解: 领域分类、条文抽取与多路改写 (可选)
+	NodeValidity   = "validity"    // 引用条文时效检查 (可选)
+	NodeRetrieve   = "retrieve"    // 检索 (向量 + 全文)
+	NodeRerank     = "rerank"      // 检索结果重排 (可选)
+	NodeScoreGate  = "score_gate"  // 相似度拒答判断
+	NodeExpand     = "expand"      // 引用扩展 (可选)
+	NodeVerify     = "verify"      // 链上验证
+	NodeVerifyGate = "verify_gate" // 验证结果拒答判断
+	NodePrompt     = "prompt"      // 提示词构建
+	NodeRefuse     = "refuse"      // 拒答分支
+	NodeGenerate   = "generate"    // LLM 流式生成
+	NodeCheck      = "check"       // 引用一致性校验
+)
+
+// Nodes Graph 节点
+type Nodes struct {
+	Analyzer  *nodes.QueryAnalyzer   // 可选，为 nil 时以原问题检索
+	Validity  *nodes.ValidityChecker // 可选，为 nil 时不检查引用条文时效
+	Retriever *nodes.Retriever
+	Reranker  *nodes.Reranker          // 可选，为 nil 时不重排
+	Expander  *nodes.ReferenceExpander // 可选，为 nil 时不加载引用条文
+	Verifier  *nodes.Verifier
+	Refusal   *nodes.RefusalPolicy
+	Prompt    *nodes.PromptBuilder
+	LLM       *nodes.LLMNode
+	Checker   *nodes.CitationChecker
+}
+
 // Builder Graph 构建器
-// TODO: 集成 Eino Graph 框架，编排 RAG 流程
+// 检索阶段: 查询理解 → 时效检查 → 检索 → 重排 → 拒答判断 (相似度) → 引用扩展 → 验证 → 拒答判断 (验证结果) → 提示词
+// 生成阶段: 拒答分支 | LLM → 引用一致性校验
 type Builder struct {
-	// retriever 检索节点
-	// verifier  验证节点
-	// prompt    提示词节点
-	// llm       大模型节点
+	nodes    Nodes
+	timeouts config.GraphTimeoutConfig
+
+	prepare *DAG
+	respond *DAG
+	built   bool
 }
 
 // NewBuilder 创建 Graph 构建器
-func NewBuilder() *Builder {
-	return &Builder{}
+func NewBuilder(n Nodes, timeouts config.GraphTimeoutConfig) *Builder {
+	return &Builder{
+		nodes:    n,
+		timeouts: timeouts,
+	}
 }
 
 // Build 构建并编译 Graph
 func (b *Builder) Build() error {
-	// TODO: 实现 Eino Graph 构建逻辑
-	// 1. 创建检索节点
-	// 2. 创建验证节点
-	// 3. 创建提示词节点
-	// 4. 创建 LLM 节点
-	// 5. 连接节点
-	// 6. 编译 Graph
+	switch {
+	case b.nodes.Retriever == nil:
+		return errors.New("graph 缺少检索节点")
+	case b.nodes.Verifier == nil:
+		return errors.New("graph 缺少验证节点")
+	case b.nodes.Refusal == nil:
+		return errors.New("graph 缺少拒答策略节点")
+	case b.nodes.Prompt == nil:
+		return errors.New("graph 缺少提示词节点")
+	case b.nodes.LLM == nil:
+		return errors.New("graph 缺少 LLM 节点")
+	case b.nodes.Checker == nil:
+		return errors.New("graph 缺少引用校验节点")
+	}
+
+	answering := (*State).answering
+	prepare, err := NewDAG(
+		Node{Name: NodeAnalyze, Timeout: b.timeouts.Analyze, Optional: true, When: b.shouldAnalyze, Run: b.analyze},
+		Node{Name: NodeValidity, Deps: []string{NodeAnalyze}, Timeout: b.timeouts.Validity, Optional: true, When: b.shouldCheckValidity, Run: b.checkValidity},
+		Node{Name: NodeRetrieve, Deps: []string{NodeAnalyze}, Timeout: b.timeouts.Retrieve, Run: b.retrieve},
+		Node{Name: NodeRerank, Deps: []string{NodeRetrieve}, Timeout: b.timeouts.Rerank, Optional: true, When: b.shouldRerank, Run: b.rerank},
+		Node{Name: NodeScoreGate, Deps: []string{NodeRerank}, Run: b.screen},
+		Node{Name: NodeExpand, Deps: []string{NodeScoreGate}, Timeout: b.timeouts.Expand, Optional: true, When: b.shouldExpand, Run: b.expand},
+		Node{Name: NodeVerify, Deps: []string{NodeExpand}, Timeout: b.timeouts.Verify, When: answering, Run: b.verify},
+		Node{Name: NodeVerifyGate, Deps: []string{NodeVerify}, When: answering, Run: b.checkVerified},
+		Node{Name: NodePrompt, Deps: []string{NodeVerifyGate}, Timeout: b.timeouts.Prompt, When: answering, Run: b.buildPrompt},
+	)
+	if err != nil {
+		return err
+	}
+	respond, err := NewDAG(
+		Node{Name: NodeRefuse, When: (*State).refused, Run: b.refuse},
+		Node{Name: NodeGenerate, Timeout: b.timeouts.Generate, When: answering, Run: b.generate},
+		Node{Name: NodeCheck, Deps: []string{NodeGenerate}, Timeout: b.timeouts.Check, When: answering, Run: b.check},
+	)
+	if err != nil {
+		return err
+	}
+
+	b.prepare = prepare
+	b.respond = respond
+	b.built = true
+	return nil
+}
+
+// Input Graph 执行输入
+type Input struct {
+	Question string
+	History  []nodes.ChatHistoryItem
+	UserID   string    // 提问用户 ID (记录到验证记录)，匿名用户为空
+	AsOf     time.Time // 适用日期，按该日有效的法律回答；零值表示当天
+}
+
+// Result Graph 非流式执行结果
+type Result struct {
+	Answer    string
+	Citations []CitationData // 回答实际引用的法条 (未完成引用校验时为全部检索结果)
+	Check     *CheckData
+	Refusal   *RefusalData  // 拒答时非空，Answer 为标准拒答回复
+	Warnings  []WarningData // 时效提示
+	Trace     *TraceData
+	Usage     client.TokenUsage
+}
+
+// Stream 流式执行 Graph
+// 检索阶段同步执行，出错时直接返回错误；生成阶段在后台协程中执行，
+// 事件按 warning... → citation → verification → token... → check → trace → usage → done 的顺序发送，
+// warning 为问题引用的条文在适用日期无效时的提示 (可能没有)，
+// citation 为全部检索结果，check 给出回答实际引用的条文与引用校验处理后的回答。
+// 拒答时不调用 LLM，事件为 warning... → refusal → token (标准拒答回复) → trace → done。
+// 生成阶段出错时发送 trace 与 error 事件并关闭通道（不再发送 done）。
+func (b *Builder) Stream(ctx context.Context, in Input) (<-chan Event, error) {
+	if !b.built {
+		return nil, ErrGraphNotBuilt
+	}
+
+	st := NewState(in)
+	if err := b.prepare.Run(ctx, st); err != nil {
+		logger.Warn("RAG Graph 检索阶段执行失败", zap.Any("nodes", st.Trace), zap.Error(err))
+		return nil, err
+	}
+
+	events := make(chan Event, eventBufferSize)
+	st.events = events
+	go func() {
+		defer close(events)
+
+		for _, w := range st.Warnings {
+			if !st.send(ctx, Event{Type: EventWarning, Data: newWarningData(w)}) {
+				return
+			}
+		}
+		err := b.respond.Run(ctx, st)
+		if ctx.Err() != nil {
+			return
+		}
+		if !st.send(ctx, Event{Type: EventTrace, Data: st.traceData()}) {
+			return
+		}
+		if err != nil {
+			st.send(ctx, Event{Type: EventError, Data: ErrorData{Message: err.Error()}})
+			return
+		}
+
+		if st.answering() || st.Usage.TotalTokens > 0 {
+			if !st.send(ctx, Event{Type: EventUsage, Data: UsageData{
+				Model:            b.nodes.LLM.Model(),
+				PromptTokens:     st.Usage.PromptTokens,
+				CompletionTokens: st.Usage.CompletionTokens,
+				TotalTokens:      st.Usage.TotalTokens,
+			}}) {
+				return
+			}
+		}
+		st.send(ctx, Event{Type: EventDone, Data: DoneData{}})
+	}()
+
+	return events, nil
+}
+
+// Execute 执行 Graph（非流式）
+func (b *Builder) Execute(ctx context.Context, in Input) (*Result, error) {
+	events, err := b.Stream(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	var answer strings.Builder
+	for ev := range events {
+		switch data := ev.Data.(type) {
+		case TokenData:
+			answer.WriteString(data.Content)
+		case CitationData:
+			result.Citations = append(result.Citations, data)
+		case CheckData:
+			result.Check = &data
+		case RefusalData:
+			result.Refusal = &data
+		case WarningData:
+			result.Warnings = append(result.Warnings, data)
+		case TraceData:
+			result.Trace = &data
+		case UsageData:
+			result.Usage = client.TokenUsage{
+				PromptTokens:     data.PromptTokens,
+				CompletionTokens: data.CompletionTokens,
+				TotalTokens:      data.TotalTokens,
+			}
+		case ErrorData:
+			return nil, errors.New(data.Message)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result.Answer = answer.String()
+	if result.Check != nil {
+		if result.Check.Answer != "" {
+			result.Answer = result.Check.Answer
+		}
+		result.Citations = CitedOnly(result.Citations, result.Check)
+	}
+	return result, nil
+}
+
+// shouldAnalyze 启用查询理解时分析问题
+func (b *Builder) shouldAnalyze(*State) bool {
+	return b.nodes.Analyzer != nil
+}
+
+// analyze 识别问题的法律领域与指明的条文，并改写为多个检索问题 (可选节点，失败时使用原问题检索)
+func (b *Builder) analyze(ctx context.Context, st *State) error {
+	analysis, usage, err := b.nodes.Analyzer.Analyze(ctx, st.Input.Question, st.Input.History)
+	st.addUsage(usage)
+	if err != nil {
+		return fmt.Errorf("查询理解失败: %w", err)
+	}
+	st.Analysis = analysis
+	st.Query = analysis.Queries[0]
+	st.Queries = analysis.Queries
+	st.LawTypes = analysis.LawTypes
+	return nil
+}
+
+// shouldCheckValidity 启用时效检查时检查问题引用的条文
+func (b *Builder) shouldCheckValidity(*State) bool {
+	return b.nodes.Validity != nil
+}
+
+// checkValidity 检查问题引用的条文在适用日期是否有效 (可选节点，失败时不提示)
+func (b *Builder) checkValidity(ctx context.Context, st *State) error {
+	warnings, err := b.nodes.Validity.Check(ctx, st.Query, st.AsOf)
+	st.Warnings = warnings
+	if err != nil {
+		return fmt.Errorf("时效检查失败: %w", err)
+	}
+	return nil
+}
+
+// retrieve 检索适用日期有效的相关法条
+// 按领域限定法律类型后没有检索结果时 (领域判断有误或知识库未按领域标注)，不限法律类型重新检索
+func (b *Builder) retrieve(ctx context.Context, st *State) error {
+	req := nodes.RetrieveRequest{
+		Queries:  st.Queries,
+		LawTypes: st.LawTypes,
+		AsOf:     st.AsOf,
+	}
+	if st.Analysis != nil {
+		req.Statutes = st.Analysis.Statutes
+	}
+	results, err := b.nodes.Retriever.Retrieve(ctx, req)
+	if err == nil && len(results) == 0 && len(req.LawTypes) > 0 {
+		st.LawTypes, req.LawTypes = nil, nil
+		results, err = b.nodes.Retriever.Retrieve(ctx, req)
+	}
+	if err != nil {
+		return fmt.Errorf("检索失败: %w", err)
+	}
+	st.Results = results
+	return nil
+}
+
+// shouldRerank 启用重排且有检索结果时重排
+func (b *Builder) shouldRerank(st *State) bool {
+	return b.nodes.Reranker != nil && len(st.Results) > 0
+}
+
+// rerank 重排检索结果 (可选节点，失败时沿用检索顺序)
+func (b *Builder) rerank(ctx context.Context, st *State) error {
+	results, err := b.nodes.Reranker.Rerank(ctx, st.Query, st.Results)
+	if err != nil {
+		return fmt.Errorf("重排失败，沿用检索顺序: %w", err)
+	}
+	st.Results = results
+	return nil
+}
+
+// screen 按相似度阈值筛选检索结果，没有足够相关的法条时拒答
+func (b *Builder) screen(ctx context.Context, st *State) error {
+	st.Thresholds = b.nodes.Refusal.Thresholds(ctx)
+	st.Results, st.Refusal = st.Thresholds.Screen(st.Results)
 	return nil
 }
 
-// Execute 执行 Graph
-func (b *Builder) Execute(input string) (string, error) {
-	// TODO: 实现 Graph 执行逻辑
-	return "", nil
+// shouldExpand 启用引用扩展且未拒答时加载引用条文
+func (b *Builder) shouldExpand(st *State) bool {
+	return b.nodes.Expander != nil && st.answering() && len(st.Results) > 0
+}
+
+// expand 加载检索结果引用的条文，追加到检索结果之后 (可选节点，失败时保留已加载的条文)
+// 引用条文与检索结果一同进入验证节点
+func (b *Builder) expand(ctx context.Context, st *State) error {
+	expanded, err := b.nodes.Expander.Expand(ctx, st.Results, st.AsOf)
+	for _, r := range expanded {
+		r.Rank = len(st.Results) + 1
+		st.Results = append(st.Results, r)
+	}
+	if err != nil {
+		return fmt.Errorf("引用扩展失败: %w", err)
+	}
+	return nil
+}
+
+// verify 验证检索结果 (验证记录写入失败不影响本次问答)
+func (b *Builder) verify(ctx context.Context, st *State) error {
+	inputs := make([]nodes.VerifyInput, len(st.Results))
+	for i, r := range st.Results {
+		inputs[i] = nodes.VerifyInput{
+			ChunkID:     r.ChunkID,
+			DocumentID:  r.DocumentID,
+			VersionID:   r.VersionID,
+			Content:     r.Content,
+			MerkleProof: r.MerkleProof,
+		}
+	}
+	verifyResults, err := b.nodes.Verifier.Verify(ctx, nodes.Trigger{Type: model.TriggerQA, By: st.Input.UserID}, inputs)
+	if err != nil {
+		logger.Warn("问答引用验证记录失败", zap.Error(err))
+	}
+	byChunk := make(map[string]nodes.VerifyResult, len(verifyResults))
+	for _, vr := range verifyResults {
+		byChunk[vr.ChunkID] = vr
+	}
+
+	st.Chunks = make([]nodes.ChunkWithVerification, len(st.Results))
+	for i, r := range st.Results {
+		vr := byChunk[r.ChunkID]
+		st.Chunks[i] = nodes.ChunkWithVerification{
+			ChunkID:       r.ChunkID,
+			DocumentID:    r.DocumentID,
+			Content:       r.Content,
+			Source:        r.Source,
+			ArticleNumber: r.ArticleNumber,
+			LawHierarchy:  r.LawHierarchy,
+			Score:         r.Score,
+			RerankScore:   r.RerankScore,
+			Rank:          r.Rank,
+			Reason:        inclusionReason(r),
+			Verified:      vr.Verified,
+			VerifyInfo:    vr,
+		}
+	}
+	return nil
+}
+
+// checkVerified 全部法条未通过链上验证时拒答
+func (b *Builder) checkVerified(_ context.Context, st *State) error {
+	st.Refusal = st.Thresholds.CheckVerified(st.Chunks, b.nodes.Verifier.ChainEnabled())
+	return nil
+}
+
+// buildPrompt 构建提示词
+func (b *Builder) buildPrompt(ctx context.Context, st *State) error {
+	input := nodes.PromptInput{
+		Question:    st.Input.Question,
+		Chunks:      st.Chunks,
+		ChatHistory: st.Input.History,
+	}
+	// 仅在用户指定适用日期时提示 LLM 按历史法律回答
+	if !st.Input.AsOf.IsZero() {
+		input.AsOfDate = st.AsOf.Format(dateLayout)
+	}
+	for _, w := range st.Warnings {
+		input.Notices = append(input.Notices, w.Message)
+	}
+	prompt, err := b.nodes.Prompt.Build(ctx, input)
+	if err != nil {
+		return err
+	}
+	st.Prompt = prompt
+	// 仅装入提示词的法条作为候选引用，序号与提示词中的 [n] 一致
+	st.Chunks = prompt.Chunks
+	return nil
+}
+
+// refuse 发送拒答事件
+func (b *Builder) refuse(ctx context.Context, st *State) error {
+	data := RefusalData{
+		Reason:     st.Refusal.Reason,
+		Message:    st.Refusal.Message,
+		Candidates: make([]CitationData, len(st.Refusal.Candidates)),
+	}
+	for i, chunk := range st.Refusal.Candidates {
+		data.Candidates[i] = newCitationData(i+1, chunk)
+	}
+
+	if !st.send(ctx, Event{Type: EventRefusal, Data: data}) ||
+		!st.send(ctx, Event{Type: EventToken, Data: TokenData{Content: st.Refusal.Message}}) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// generate 发送引用法条并流式生成回答
+func (b *Builder) generate(ctx context.Context, st *State) error {
+	stream, err := b.nodes.LLM.GenerateStream(ctx, st.Prompt.Messages)
+	if err != nil {
+		return fmt.Errorf("调用 LLM 失败: %w", err)
+	}
+
+	verified := 0
+	for i, chunk := range st.Chunks {
+		if chunk.Verified {
+			verified++
+		}
+		if !st.send(ctx, Event{Type: EventCitation, Data: newCitationData(i+1, chunk)}) {
+			return ctx.Err()
+		}
+	}
+	if !st.send(ctx, Event{Type: EventVerification, Data: VerificationData{Total: len(st.Chunks), Verified: verified}}) {
+		return ctx.Err()
+	}
+
+	var (
+		usage  client.TokenUsage
+		answer strings.Builder
+	)
+	for chunk := range stream {
+		if chunk.Error != nil {
+			return chunk.Error
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		if chunk.Content != "" {
+			answer.WriteString(chunk.Content)
+			if !st.send(ctx, Event{Type: EventToken, Data: TokenData{Content: chunk.Content}}) {
+				return ctx.Err()
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	st.addUsage(usage)
+	st.Answer = answer.String()
+	// 流在超时或取消时提前关闭
+	return ctx.Err()
+}
+
+// check 校验回答引用并发送校验结果
+func (b *Builder) check(ctx context.Context, st *State) error {
+	data := b.checkCitations(ctx, st.Prompt.Messages, st.Answer, st.Chunks, &st.Usage)
+	st.Check = &data
+	if !st.send(ctx, Event{Type: EventCheck, Data: data}) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// CitedOnly 按引用校验结果筛选回答实际引用的法条
+func CitedOnly(citations []CitationData, check *CheckData) []CitationData {
+	cited := make(map[int]bool, len(check.Cited))
+	for _, index := range check.Cited {
+		cited[index] = true
+	}
+	out := make([]CitationData, 0, len(check.Cited))
+	for _, c := range citations {
+		if cited[c.Index] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// checkCitations 校验回答引用，按配置在存在无法核验的引用时重新生成一次
+// 重新生成的 Token 用量累加到 usage
+func (b *Builder) checkCitations(ctx context.Context, messages []client.Message, answer string, chunks []nodes.ChunkWithVerification, usage *client.TokenUsage) CheckData {
+	result := b.nodes.Checker.Check(answer, chunks)
+	regenerated := false
+
+	if !result.Passed() && b.nodes.Checker.Regenerate() {
+		retry := make([]client.Message, 0, len(messages)+2)
+		retry = append(retry, messages...)
+		retry = append(retry,
+			client.Message{Role: "assistant", Content: answer},
+			client.Message{Role: "user", Content: b.nodes.Checker.CorrectionPrompt(result.Violations)},
+		)
+		resp, err := b.nodes.LLM.Generate(ctx, retry)
+		switch {
+		case err != nil:
+			logger.Warn("引用校验未通过，重新生成失败，沿用原回答", zap.Error(err))
+		case strings.TrimSpace(resp.Content) == "":
+			logger.Warn("引用校验未通过，重新生成的回答为空，沿用原回答")
+		default:
+			usage.PromptTokens += resp.Usage.PromptTokens
+			usage.CompletionTokens += resp.Usage.CompletionTokens
+			usage.TotalTokens += resp.Usage.TotalTokens
+			result = b.nodes.Checker.Check(resp.Content, chunks)
+			regenerated = true
+		}
+	}
+
+	data := CheckData{
+		Passed:      result.Passed(),
+		Regenerated: regenerated,
+		Cited:       make([]int, len(result.Cited)),
+	}
+	if result.Modified || regenerated {
+		data.Answer = result.Answer
+	}
+	// Chunk 下标与 citation 事件的 index 一一对应 (index = 下标 + 1)
+	for i, idx := range result.Cited {
+		data.Cited[i] = idx + 1
+	}
+	for _, v := range result.Violations {
+		data.Violations = append(data.Violations, ViolationData{
+			Text:    v.Text,
+			Law:     v.Law,
+			Article: v.Article,
+			Reason:  v.Reason,
+		})
+	}
+	return data
+}
+
+// newCitationData 将带验证信息的 Chunk 转换为 citation 事件数据
+func newCitationData(index int, chunk nodes.ChunkWithVerification) CitationData {
+	return CitationData{
+		Index:         index,
+		ChunkID:       chunk.ChunkID,
+		Text:          chunk.Content,
+		Source:        chunk.Source,
+		ArticleNumber: chunk.ArticleNumber,
+		LawHierarchy:  chunk.LawHierarchy,
+		Score:         chunk.Score,
+		RerankScore:   chunk.RerankScore,
+		Reason:        chunk.Reason,
+		ChunkHash:     chunk.VerifyInfo.ChunkHash,
+		VersionID:     chunk.VerifyInfo.VersionID,
+		Verified:      chunk.Verified,
+		BlockNumber:   chunk.VerifyInfo.BlockNumber,
+		TxHash:        chunk.VerifyInfo.TxHash,
+	}
+}
+
+// newWarningData 将时效提示转换为 warning 事件数据
+func newWarningData(w nodes.ValidityWarning) WarningData {
+	data := WarningData{
+		Type:          w.Type,
+		Source:        w.Source,
+		ArticleNumber: w.ArticleNumber,
+		SupersededBy:  w.SupersededBy,
+		Message:       w.Message,
+	}
+	if w.EffectiveDate != nil {
+		data.EffectiveDate = w.EffectiveDate.Format(dateLayout)
+	}
+	if w.ExpiryDate != nil {
+		data.ExpiryDate = w.ExpiryDate.Format(dateLayout)
+	}
+	return data
+}
+
+// inclusionReason 检索结果纳入原因，直接检索命中的条文为空
+func inclusionReason(r nodes.RetrievalResult) string {
+	if r.Expansion == nil {
+		return ""
+	}
+	return r.Expansion.Reason()
 }
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+)
+
+// checkChunks 参考法条: [1] 民法典第1165条 (已验证)、[2] 民法典第1166条 (未验证)
+var checkChunks = []nodes.ChunkWithVerification{
+	{ChunkID: "civil-1165", Source: "中华人民共和国民法典", ArticleNumber: "1165", Verified: true},
+	{ChunkID: "civil-1166", Source: "中华人民共和国民法典", ArticleNumber: "1166"},
+}
+
+// newRegenerateLLM 启动每次返回 answer 的模拟模型，返回 LLM 节点与请求计数
+func newRegenerateLLM(t *testing.T, answer string) (*nodes.LLMNode, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req struct {
+			Messages []client.Message `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if n := len(req.Messages); n < 2 || req.Messages[n-2].Role != "assistant" || !strings.Contains(req.Messages[n-1].Content, "请仅依据") {
+			t.Errorf("重新生成请求应附带原回答与修正提示: %+v", req.Messages)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": answer}}},
+			"usage":   map[string]int{"prompt_tokens": 100, "completion_tokens": 20, "total_tokens": 120},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return nodes.NewLLMNode(client.NewLLMClient(&client.LLMConfig{BaseURL: srv.URL, Model: "test", Timeout: 5 * time.Second})), &calls
+}
+
+func TestCheckCitationsRegenerate(t *testing.T) {
+	messages := []client.Message{{Role: "system", Content: "参考法条"}, {Role: "user", Content: "问题"}}
+	tests := []struct {
+		name        string
+		regenerate  bool
+		answer      string
+		retry       string
+		calls       int32
+		passed      bool
+		regenerated bool
+		want        string // 校验后的回答，为空表示沿用原回答
+	}{
+		{name: "通过时不重新生成", regenerate: true, answer: "依照第1165条 [1]。", retry: "不应调用", calls: 0, passed: true},
+		{name: "未开启重新生成", regenerate: false, answer: "依照第1166条。", retry: "不应调用", calls: 0, want: "依照第1166条〔该条文未通过链上验证〕。"},
+		{name: "重新生成后通过", regenerate: true, answer: "依照第1166条。", retry: "依照第1165条 [1]。", calls: 1, passed: true, regenerated: true, want: "依照第1165条 [1]。"},
+		{
+			name: "重新生成仍未通过时不再重试", regenerate: true, answer: "依照第1166条。", retry: "依照《刑法》第二十条。", calls: 1, regenerated: true,
+			want: "依照《刑法》第二十条〔未在参考法条中，请核实〕。",
+		},
+		{name: "重新生成为空时沿用原回答", regenerate: true, answer: "依照第1166条。", retry: "  ", calls: 1, want: "依照第1166条〔该条文未通过链上验证〕。"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm, calls := newRegenerateLLM(t, tt.retry)
+			b := NewBuilder(Nodes{LLM: llm, Checker: nodes.NewCitationChecker(nodes.CitationModeFlag, tt.regenerate)}, config.GraphTimeoutConfig{})
+
+			usage := client.TokenUsage{TotalTokens: 10}
+			data := b.checkCitations(context.Background(), messages, tt.answer, checkChunks, &usage)
+			if got := calls.Load(); got != tt.calls {
+				t.Fatalf("LLM 调用 %d 次, want %d", got, tt.calls)
+			}
+			if data.Passed != tt.passed || data.Regenerated != tt.regenerated || data.Answer != tt.want {
+				t.Errorf("CheckData = %+v, want passed %v regenerated %v answer %q", data, tt.passed, tt.regenerated, tt.want)
+			}
+			// 仅采用的重新生成结果计入用量
+			wantTokens := 10
+			if tt.regenerated {
+				wantTokens += 120
+			}
+			if usage.TotalTokens != wantTokens {
+				t.Errorf("usage.TotalTokens = %d, want %d", usage.TotalTokens, wantTokens)
+			}
+		})
+	}
+}
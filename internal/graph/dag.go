@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNodeTimeout 节点执行超时
+var ErrNodeTimeout = errors.New("节点执行超时")
+
+// 节点执行状态 (写入 NodeTrace.Status)
+const (
+	NodeStatusOK       = "ok"       // 执行成功
+	NodeStatusSkipped  = "skipped"  // 执行条件不满足，未执行
+	NodeStatusFailed   = "failed"   // 执行出错
+	NodeStatusTimeout  = "timeout"  // 执行超时
+	NodeStatusCanceled = "canceled" // 请求取消 (客户端断开)
+)
+
+// Node 执行图节点
+type Node struct {
+	Name     string
+	Deps     []string                                   // 依赖的节点，全部结束 (成功、跳过或可选节点失败) 后才执行
+	Timeout  time.Duration                              // 单节点超时，<= 0 表示不限制
+	Optional bool                                       // 可选节点出错时记录错误并继续执行后续节点
+	When     func(st *State) bool                       // 执行条件，为 nil 时总是执行，返回 false 时跳过 (用于拒答等分支)
+	Run      func(ctx context.Context, st *State) error // 节点逻辑，结果写入 State
+}
+
+// DAG 有向无环执行图
+// 节点按拓扑顺序依次执行，共享同一个类型化的 State，每个节点的耗时与错误记录到 State.Trace
+type DAG struct {
+	order []*Node
+}
+
+// NewDAG 创建执行图，校验节点名唯一、依赖存在且无环
+// 拓扑顺序相同时保持声明顺序，保证执行顺序确定
+func NewDAG(nodes ...Node) (*DAG, error) {
+	byName := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		if n.Name == "" || n.Run == nil {
+			return nil, fmt.Errorf("graph 节点 #%d 缺少名称或执行逻辑", i)
+		}
+		if _, ok := byName[n.Name]; ok {
+			return nil, fmt.Errorf("graph 节点 %s 重复", n.Name)
+		}
+		byName[n.Name] = i
+	}
+
+	indegree := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, n := range nodes {
+		for _, dep := range n.Deps {
+			j, ok := byName[dep]
+			if !ok {
+				return nil, fmt.Errorf("graph 节点 %s 依赖的节点 %s 不存在", n.Name, dep)
+			}
+			indegree[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	order := make([]*Node, 0, len(nodes))
+	done := make([]bool, len(nodes))
+	for len(order) < len(nodes) {
+		next := -1
+		for i := range nodes {
+			if !done[i] && indegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, errors.New("graph 节点存在循环依赖")
+		}
+		done[next] = true
+		order = append(order, &nodes[next])
+		for _, j := range dependents[next] {
+			indegree[j]--
+		}
+	}
+	return &DAG{order: order}, nil
+}
+
+// Run 执行全部节点
+// 必需节点出错、超时或请求取消时停止执行并返回错误，已执行节点的记录保留在 State.Trace
+func (d *DAG) Run(ctx context.Context, st *State) error {
+	for _, n := range d.order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if n.When != nil && !n.When(st) {
+			st.Trace = append(st.Trace, NodeTrace{Name: n.Name, Status: NodeStatusSkipped})
+			continue
+		}
+
+		trace, err := runNode(ctx, n, st)
+		st.Trace = append(st.Trace, trace)
+		if err != nil && (!n.Optional || trace.Status == NodeStatusCanceled) {
+			return err
+		}
+	}
+	return nil
+}
+
+// runNode 在节点超时内执行单个节点
+func runNode(ctx context.Context, n *Node, st *State) (NodeTrace, error) {
+	nodeCtx := ctx
+	if n.Timeout > 0 {
+		var cancel context.CancelFunc
+		nodeCtx, cancel = context.WithTimeout(ctx, n.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := n.Run(nodeCtx, st)
+	trace := NodeTrace{
+		Name:      n.Name,
+		Status:    NodeStatusOK,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err == nil {
+		return trace, nil
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		trace.Status = NodeStatusCanceled
+		err = ctx.Err()
+	case errors.Is(nodeCtx.Err(), context.DeadlineExceeded):
+		trace.Status = NodeStatusTimeout
+		err = fmt.Errorf("%w: %s (%s)", ErrNodeTimeout, n.Name, n.Timeout)
+	default:
+		trace.Status = NodeStatusFailed
+	}
+	trace.Error = err.Error()
+	return trace, err
+}
@@ -0,0 +1,128 @@
+package graph
+
+// EventType 流式事件类型
+type EventType string
+
+const (
+	EventToken        EventType = "token"        // 回答增量片段
+	EventCitation     EventType = "citation"     // 法条引用
+	EventVerification EventType = "verification" // 链上验证汇总
+	EventCheck        EventType = "check"        // 引用一致性校验结果
+	EventRefusal      EventType = "refusal"      // 拒答 (无可靠法条依据，未调用 LLM)
+	EventWarning      EventType = "warning"      // 时效提示 (问题引用的条文在适用日期已失效或尚未生效)
+	EventTrace        EventType = "trace"        // 执行记录 (仅服务端使用，不转发给客户端)
+	EventUsage        EventType = "usage"        // Token 用量
+	EventDone         EventType = "done"         // 流结束
+	EventError        EventType = "error"        // 执行出错
+)
+
+// Event Graph 流式事件
+type Event struct {
+	Type EventType
+	Data interface{}
+}
+
+// TokenData token 事件数据
+type TokenData struct {
+	Content string `json:"content"`
+}
+
+// CitationData citation 事件数据
+type CitationData struct {
+	Index         int     `json:"index"`
+	ChunkID       string  `json:"chunkId"`
+	Text          string  `json:"text"`
+	Source        string  `json:"source"`
+	ArticleNumber string  `json:"articleNumber,omitempty"`
+	LawHierarchy  string  `json:"lawHierarchy,omitempty"`
+	Score         float32 `json:"score"`
+	RerankScore   float32 `json:"rerankScore,omitempty"`
+	Reason        string  `json:"reason,omitempty"` // 纳入原因，如 "被《民法典》第1165条引用 (...)"，直接检索命中时为空
+	ChunkHash     string  `json:"chunkHash,omitempty"`
+	VersionID     int     `json:"versionId,omitempty"`
+	Verified      bool    `json:"verified"`
+	BlockNumber   int64   `json:"blockNumber,omitempty"`
+	TxHash        string  `json:"txHash,omitempty"`
+}
+
+// VerificationData verification 事件数据
+type VerificationData struct {
+	Total    int `json:"total"`
+	Verified int `json:"verified"`
+}
+
+// CheckData check 事件数据 (引用一致性校验)
+type CheckData struct {
+	Passed      bool            `json:"passed"`                // 回答中的引用是否全部可核验
+	Regenerated bool            `json:"regenerated,omitempty"` // 是否因引用无法核验而重新生成
+	Answer      string          `json:"answer,omitempty"`      // 处理后的完整回答，仅与流式输出不同时返回，客户端应以此替换
+	Cited       []int           `json:"cited"`                 // 回答实际引用的 citation index
+	Violations  []ViolationData `json:"violations,omitempty"`  // 无法核验的引用
+}
+
+// ViolationData 无法核验的引用
+type ViolationData struct {
+	Text    string `json:"text"`
+	Law     string `json:"law,omitempty"`
+	Article string `json:"article,omitempty"`
+	Reason  string `json:"reason"` // unretrieved: 未检索到; unverified: 未通过链上验证
+}
+
+// RefusalData refusal 事件数据
+type RefusalData struct {
+	Reason     string         `json:"reason"`     // low_similarity | unverified | out_of_scope
+	Message    string         `json:"message"`    // 标准拒答回复
+	Candidates []CitationData `json:"candidates"` // 最接近的候选条文
+}
+
+// WarningData warning 事件数据
+type WarningData struct {
+	Type          string `json:"type"` // repealed | not_yet_effective
+	Source        string `json:"source"`
+	ArticleNumber string `json:"articleNumber"`
+	EffectiveDate string `json:"effectiveDate,omitempty"` // YYYY-MM-DD
+	ExpiryDate    string `json:"expiryDate,omitempty"`    // YYYY-MM-DD
+	SupersededBy  string `json:"supersededBy,omitempty"`  // 替代条文说明
+	Message       string `json:"message"`
+}
+
+// TraceData trace 事件数据，记录到消息元数据用于复现回答
+type TraceData struct {
+	Query           string      `json:"query,omitempty"`         // 改写后的检索问题，未改写时为空
+	AsOfDate        string      `json:"asOfDate"`                // 适用日期 (YYYY-MM-DD)
+	Domain          string      `json:"domain,omitempty"`        // 查询理解识别的法律领域
+	Queries         []string    `json:"queries,omitempty"`       // 查询理解给出的全部检索问题
+	LawTypes        []string    `json:"lawTypes,omitempty"`      // 检索限定的文档法律类型 (无结果回退时为空)
+	PromptVersion   string      `json:"promptVersion,omitempty"` // 系统提示词模板版本，拒答时为空
+	PromptTokens    int         `json:"promptTokens"`            // 提示词 Token 数 (估算)
+	DroppedChunks   int         `json:"droppedChunks"`           // 因 Token 预算丢弃的法条数
+	HistoryMessages int         `json:"historyMessages"`         // 携带的历史消息数
+	Nodes           []NodeTrace `json:"nodes"`                   // 各节点执行记录 (按执行顺序)
+}
+
+// NodeTrace 节点执行记录
+type NodeTrace struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"` // ok | skipped | failed | timeout | canceled
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// UsageData usage 事件数据
+type UsageData struct {
+	Model            string `json:"model,omitempty"`
+	PromptTokens     int    `json:"promptTokens"`
+	CompletionTokens int    `json:"completionTokens"`
+	TotalTokens      int    `json:"totalTokens"`
+}
+
+// DoneData done 事件数据
+type DoneData struct {
+	SessionID string `json:"sessionId,omitempty"`
+	MessageID string `json:"messageId,omitempty"`
+}
+
+// ErrorData error 事件数据
+type ErrorData struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,296 @@
+// Package nodes 提供 Eino Graph 节点实现
+package nodes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+)
+
+// CitationMode 回答中存在无法核验的引用时的处理方式
+type CitationMode string
+
+const (
+	CitationModeFlag  CitationMode = "flag"  // 在引用后追加提示
+	CitationModeStrip CitationMode = "strip" // 删除包含该引用的句子
+)
+
+// 引用问题原因
+const (
+	CitationUnretrieved = "unretrieved" // 引用了未检索到的条文
+	CitationUnverified  = "unverified"  // 引用的条文未通过链上验证
+)
+
+// citationFlags flag 模式下追加在引用后的提示
+var citationFlags = map[string]string{
+	CitationUnretrieved: "〔未在参考法条中，请核实〕",
+	CitationUnverified:  "〔该条文未通过链上验证〕",
+}
+
+var (
+	// indexMarkerPattern 按参考法条序号引用，如 "[1]"
+	indexMarkerPattern = regexp.MustCompile(`\[(\d{1,3})\]`)
+	// sentenceEnds strip 模式下的句子边界
+	sentenceEnds = "。！？；!?;\n"
+)
+
+// CitationChecker 引用一致性校验节点
+// 解析 LLM 回答中的法条引用，与检索并验证过的 Chunks 逐一匹配：
+// 命中已验证条文的引用计入回答引用，引用未检索或未验证条文的内容按模式标注或删除
+type CitationChecker struct {
+	mode       CitationMode
+	regenerate bool
+}
+
+// NewCitationChecker 创建引用一致性校验节点
+// regenerate 为 true 时，存在无法核验的引用会触发一次重新生成
+func NewCitationChecker(mode CitationMode, regenerate bool) *CitationChecker {
+	if mode != CitationModeStrip {
+		mode = CitationModeFlag
+	}
+	return &CitationChecker{
+		mode:       mode,
+		regenerate: regenerate,
+	}
+}
+
+// Regenerate 是否在校验未通过时重新生成一次
+func (c *CitationChecker) Regenerate() bool {
+	return c.regenerate
+}
+
+// CitationViolation 无法核验的引用
+type CitationViolation struct {
+	Text    string // 回答中的原文片段，如 "《刑法》第二十条"
+	Law     string // 法律名称，为空表示未指明
+	Article string // 条号 (阿拉伯数字)
+	Reason  string // CitationUnretrieved / CitationUnverified
+
+	start, end int
+}
+
+// CitationCheckResult 引用一致性校验结果
+type CitationCheckResult struct {
+	Answer     string              // 按模式处理后的回答
+	Modified   bool                // Answer 是否与原回答不同
+	Cited      []int               // 回答实际引用的已验证 Chunk 下标 (升序)
+	Violations []CitationViolation // 无法核验的引用
+}
+
+// Passed 回答中的引用是否全部可核验
+func (r *CitationCheckResult) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// Check 校验回答中的引用
+func (c *CitationChecker) Check(answer string, chunks []ChunkWithVerification) *CitationCheckResult {
+	result := &CitationCheckResult{Answer: answer}
+	cited := make(map[int]bool)
+
+	// 1. 法条引用，如 "《民法典》第1165条"、"本法第十条"
+	for _, ref := range chunker.FindReferences(answer) {
+		matched := matchReference(ref.Reference, chunks)
+		if v, ok := classify(matched, chunks, cited); !ok {
+			result.Violations = append(result.Violations, CitationViolation{
+				Text:    ref.Text,
+				Law:     ref.Law,
+				Article: ref.Article,
+				Reason:  v,
+				start:   ref.Start,
+				end:     ref.End,
+			})
+		}
+	}
+
+	// 2. 参考法条序号引用，如 "[1]"
+	for _, m := range indexMarkerPattern.FindAllStringSubmatchIndex(answer, -1) {
+		var matched []int
+		if n, _ := strconv.Atoi(answer[m[2]:m[3]]); n >= 1 && n <= len(chunks) {
+			matched = []int{n - 1}
+		}
+		if v, ok := classify(matched, chunks, cited); !ok {
+			result.Violations = append(result.Violations, CitationViolation{
+				Text:   answer[m[0]:m[1]],
+				Reason: v,
+				start:  m[0],
+				end:    m[1],
+			})
+		}
+	}
+
+	for i := range chunks {
+		if cited[i] {
+			result.Cited = append(result.Cited, i)
+		}
+	}
+	if len(result.Violations) > 0 {
+		sort.Slice(result.Violations, func(i, j int) bool {
+			return result.Violations[i].start < result.Violations[j].start
+		})
+		result.Answer = c.apply(answer, result.Violations)
+		result.Modified = result.Answer != answer
+	}
+	return result
+}
+
+// CorrectionPrompt 生成要求模型修正引用的提示，用于重新生成
+func (c *CitationChecker) CorrectionPrompt(violations []CitationViolation) string {
+	var sb strings.Builder
+	sb.WriteString("你的回答引用了以下不在参考法条中或未通过链上验证的条文：\n")
+	seen := make(map[string]bool)
+	for _, v := range violations {
+		if seen[v.Text] {
+			continue
+		}
+		seen[v.Text] = true
+		fmt.Fprintf(&sb, "- %s\n", v.Text)
+	}
+	sb.WriteString("请仅依据系统提示中标注为已链上验证的参考法条重新回答，引用时注明出处；不得引用其他条文，条文不足以回答时请明确说明。")
+	return sb.String()
+}
+
+// apply 按模式处理无法核验的引用 (violations 按位置升序)
+func (c *CitationChecker) apply(answer string, violations []CitationViolation) string {
+	if c.mode == CitationModeStrip {
+		return stripSentences(answer, violations)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, v := range violations {
+		sb.WriteString(answer[last:v.end])
+		sb.WriteString(citationFlags[v.Reason])
+		last = v.end
+	}
+	sb.WriteString(answer[last:])
+	return sb.String()
+}
+
+// stripSentences 删除包含无法核验引用的句子，并在末尾说明删除数量
+func stripSentences(answer string, violations []CitationViolation) string {
+	type span struct{ start, end int }
+	var spans []span
+	for _, v := range violations {
+		start := 0
+		if i := strings.LastIndexAny(answer[:v.start], sentenceEnds); i >= 0 {
+			_, size := utf8.DecodeRuneInString(answer[i:])
+			start = i + size
+		}
+		end := len(answer)
+		if i := strings.IndexAny(answer[v.end:], sentenceEnds); i >= 0 {
+			// 保留换行，仅删除句末标点
+			end = v.end + i
+			if answer[end] != '\n' {
+				_, size := utf8.DecodeRuneInString(answer[end:])
+				end += size
+			}
+		}
+		if n := len(spans); n > 0 && start < spans[n-1].end {
+			if end > spans[n-1].end {
+				spans[n-1].end = end
+			}
+			continue
+		}
+		spans = append(spans, span{start, end})
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, s := range spans {
+		sb.WriteString(answer[last:s.start])
+		last = s.end
+	}
+	sb.WriteString(answer[last:])
+
+	out := strings.TrimSpace(sb.String())
+	if out != "" {
+		out += "\n\n"
+	}
+	out += fmt.Sprintf("（已删除 %d 处引用了无法核验条文的内容）", len(spans))
+	return out
+}
+
+// classify 根据匹配到的 Chunk 判定引用是否可核验，已验证的 Chunk 计入 cited
+// 返回 (原因, 是否可核验)
+func classify(matched []int, chunks []ChunkWithVerification, cited map[int]bool) (string, bool) {
+	if len(matched) == 0 {
+		return CitationUnretrieved, false
+	}
+	ok := false
+	for _, i := range matched {
+		if chunks[i].Verified {
+			cited[i] = true
+			ok = true
+		}
+	}
+	if !ok {
+		return CitationUnverified, false
+	}
+	return "", true
+}
+
+// matchReference 返回与引用匹配的 Chunk 下标
+// 引用未指明法律 (如 "本法"、省略法律名称) 时仅按条号匹配
+func matchReference(ref chunker.Reference, chunks []ChunkWithVerification) []int {
+	var matched []int
+	for i, chunk := range chunks {
+		if chunk.ArticleNumber == "" || !articleInRange(chunk.ArticleNumber, ref.Article, ref.ToArticle) {
+			continue
+		}
+		if ref.Law != "" && !sameLaw(ref.Law, chunk) {
+			continue
+		}
+		matched = append(matched, i)
+	}
+	return matched
+}
+
+// articleInRange 判断条号是否等于 from，或位于 [from, to] 区间内
+func articleInRange(article, from, to string) bool {
+	if to == "" {
+		return article == from
+	}
+	a, b, c := articleOrdinal(article), articleOrdinal(from), articleOrdinal(to)
+	return a >= b && a <= c
+}
+
+// articleOrdinal 将条号转换为可比较的序数，"10-1" (第十条之一) 位于第十条与第十一条之间
+func articleOrdinal(article string) int {
+	main, sub, _ := strings.Cut(article, "-")
+	n, _ := strconv.Atoi(main)
+	s, _ := strconv.Atoi(sub)
+	return n*100 + s
+}
+
+// sameLaw 判断引用的法律名称是否指向 Chunk 所属法律
+// 兼容全称与简称，如 "中华人民共和国民法典" 与 "民法典"
+func sameLaw(law string, chunk ChunkWithVerification) bool {
+	root, _, _ := strings.Cut(chunk.LawHierarchy, "/")
+	return lawNameMatches(law, chunk.Source, root)
+}
+
+// lawNameMatches 判断法律名称是否与候选名称之一相符 (兼容全称与简称)，law 为空时视为相符
+func lawNameMatches(law string, candidates ...string) bool {
+	name := normalizeLawName(law)
+	if name == "" {
+		return true
+	}
+	for _, c := range candidates {
+		c = normalizeLawName(c)
+		if c != "" && (strings.Contains(c, name) || strings.Contains(name, c)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLawName 去除书名号与 "中华人民共和国" 前缀
+func normalizeLawName(name string) string {
+	name = strings.Trim(strings.TrimSpace(name), "《》")
+	return strings.TrimPrefix(name, "中华人民共和国")
+}
@@ -0,0 +1,156 @@
+package nodes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// citationChunks 参考法条: [1] 民法典第1165条 (已验证)、[2] 民法典第1166条 (未验证)、[3] 劳动合同法第47条 (已验证)
+var citationChunks = []ChunkWithVerification{
+	{ChunkID: "civil-1165", Source: "中华人民共和国民法典", ArticleNumber: "1165", LawHierarchy: "中华人民共和国民法典/第七编 侵权责任", Verified: true},
+	{ChunkID: "civil-1166", Source: "中华人民共和国民法典", ArticleNumber: "1166", LawHierarchy: "中华人民共和国民法典/第七编 侵权责任"},
+	{ChunkID: "labor-47", Source: "中华人民共和国劳动合同法", ArticleNumber: "47", LawHierarchy: "中华人民共和国劳动合同法/第四章", Verified: true},
+}
+
+func TestCitationCheckParse(t *testing.T) {
+	type violation struct{ text, law, article, reason string }
+	tests := []struct {
+		name       string
+		answer     string
+		cited      []int
+		violations []violation
+	}{
+		{name: "书名号与阿拉伯数字", answer: "依照《民法典》第1165条，应当承担侵权责任。", cited: []int{0}},
+		{name: "全称与中文数字", answer: "依照《中华人民共和国民法典》第一千一百六十五条，应当承担侵权责任。", cited: []int{0}},
+		{name: "本法仅按条号匹配", answer: "本法第一千一百六十五条规定了过错责任。", cited: []int{0}},
+		{name: "多处引用", answer: "参照《劳动合同法》第四十七条与《民法典》第1165条。", cited: []int{0, 2}},
+		{name: "区间内有已验证条文", answer: "见《民法典》第一千一百六十五条至第一千一百六十六条。", cited: []int{0}},
+		{
+			name:       "未检索到的条文",
+			answer:     "依照《刑法》第二十条，正当防卫不负刑事责任。",
+			violations: []violation{{"《刑法》第二十条", "刑法", "20", CitationUnretrieved}},
+		},
+		{
+			name:       "法律名称不符",
+			answer:     "依照《劳动合同法》第1165条处理。",
+			violations: []violation{{"《劳动合同法》第1165条", "劳动合同法", "1165", CitationUnretrieved}},
+		},
+		{
+			name:       "未验证的条文",
+			answer:     "依照《民法典》第1166条，无过错也应承担责任。",
+			violations: []violation{{"《民法典》第1166条", "民法典", "1166", CitationUnverified}},
+		},
+		{name: "序号引用", answer: "应当承担侵权责任 [1]，经济补偿按年限计算 [3]。", cited: []int{0, 2}},
+		{
+			name:       "序号为 0",
+			answer:     "应当承担侵权责任 [0]。",
+			violations: []violation{{text: "[0]", reason: CitationUnretrieved}},
+		},
+		{
+			name:       "序号超出参考法条数",
+			answer:     "应当承担侵权责任 [1]，另见 [4]。",
+			cited:      []int{0},
+			violations: []violation{{text: "[4]", reason: CitationUnretrieved}},
+		},
+		{
+			name:       "序号指向未验证条文",
+			answer:     "无过错也应承担责任 [2]。",
+			violations: []violation{{text: "[2]", reason: CitationUnverified}},
+		},
+		{
+			name:   "违规按出现位置排序",
+			answer: "见 [9]，另依照《刑法》第二十条。",
+			violations: []violation{
+				{text: "[9]", reason: CitationUnretrieved},
+				{"《刑法》第二十条", "刑法", "20", CitationUnretrieved},
+			},
+		},
+		{name: "无引用", answer: "该问题需要结合具体情况判断。"},
+	}
+
+	checker := NewCitationChecker(CitationModeFlag, false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := checker.Check(tt.answer, citationChunks)
+			if !reflect.DeepEqual(res.Cited, tt.cited) {
+				t.Errorf("Cited = %v, want %v", res.Cited, tt.cited)
+			}
+			var got []violation
+			for _, v := range res.Violations {
+				got = append(got, violation{v.Text, v.Law, v.Article, v.Reason})
+			}
+			if !reflect.DeepEqual(got, tt.violations) {
+				t.Errorf("Violations = %+v, want %+v", got, tt.violations)
+			}
+			if res.Passed() != (len(tt.violations) == 0) || res.Modified != !res.Passed() {
+				t.Errorf("Passed = %v, Modified = %v", res.Passed(), res.Modified)
+			}
+		})
+	}
+}
+
+func TestCitationCheckModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   CitationMode
+		answer string
+		want   string
+	}{
+		{
+			name:   "flag 未检索",
+			mode:   CitationModeFlag,
+			answer: "侵权责任适用第1165条。另依照《刑法》第二十条处理。",
+			want:   "侵权责任适用第1165条。另依照《刑法》第二十条〔未在参考法条中，请核实〕处理。",
+		},
+		{
+			name:   "flag 未验证",
+			mode:   CitationModeFlag,
+			answer: "无过错责任见《民法典》第1166条 [2]。",
+			want:   "无过错责任见《民法典》第1166条〔该条文未通过链上验证〕 [2]〔该条文未通过链上验证〕。",
+		},
+		{
+			name:   "strip 未检索",
+			mode:   CitationModeStrip,
+			answer: "侵权责任适用第1165条。另依照《刑法》第二十条处理。",
+			want:   "侵权责任适用第1165条。\n\n（已删除 1 处引用了无法核验条文的内容）",
+		},
+		{
+			name:   "strip 未验证，同一句中的多处引用只删除一次",
+			mode:   CitationModeStrip,
+			answer: "过错责任见第1165条 [1]！无过错责任见《民法典》第1166条 [2]。\n经济补偿见 [3]。",
+			want:   "过错责任见第1165条 [1]！\n经济补偿见 [3]。\n\n（已删除 1 处引用了无法核验条文的内容）",
+		},
+		{
+			name:   "strip 全部删除",
+			mode:   CitationModeStrip,
+			answer: "依照《刑法》第二十条处理",
+			want:   "（已删除 1 处引用了无法核验条文的内容）",
+		},
+		{
+			name:   "未知模式按 flag 处理",
+			mode:   CitationMode("unknown"),
+			answer: "见 [5]。",
+			want:   "见 [5]〔未在参考法条中，请核实〕。",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := NewCitationChecker(tt.mode, false).Check(tt.answer, citationChunks)
+			if res.Answer != tt.want {
+				t.Errorf("Answer =\n%q\nwant\n%q", res.Answer, tt.want)
+			}
+			if !res.Modified || res.Passed() {
+				t.Errorf("Modified = %v, Passed = %v", res.Modified, res.Passed())
+			}
+		})
+	}
+}
+
+func TestCorrectionPrompt(t *testing.T) {
+	res := NewCitationChecker(CitationModeFlag, true).Check("见《刑法》第二十条，又见《刑法》第二十条与 [2]。", citationChunks)
+	prompt := NewCitationChecker(CitationModeFlag, true).CorrectionPrompt(res.Violations)
+	if strings.Count(prompt, "- 《刑法》第二十条\n") != 1 || !strings.Contains(prompt, "- [2]\n") {
+		t.Errorf("CorrectionPrompt() 应列出去重后的违规引用:\n%s", prompt)
+	}
+}
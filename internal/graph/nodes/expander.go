@@ -0,0 +1,221 @@
+// Package nodes 提供 Eino Graph 节点实现
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+)
+
+const (
+	// defaultExpandMax 默认最多纳入的引用条文数
+	defaultExpandMax = 5
+	// maxRangeArticles 区间引用 ("第X条至第Y条") 最多展开的条文数
+	maxRangeArticles = 5
+)
+
+// Expansion 引用扩展纳入原因
+type Expansion struct {
+	Hop         int    // 第几跳引用 (由检索结果直接引用为 1)
+	FromChunkID string // 引用该条文的分块
+	FromSource  string // 引用方所属法律
+	FromArticle string // 引用方条号
+	Text        string // 引用原文，如 "依照本法第一千一百七十九条"
+}
+
+// Reason 纳入原因描述，如 "被《民法典》第1165条引用 (依照本法第一千一百七十九条)"
+func (e *Expansion) Reason() string {
+	from := "《" + e.FromSource + "》"
+	if e.FromArticle != "" {
+		from += "第" + e.FromArticle + "条"
+	}
+	if e.Text == "" {
+		return "被" + from + "引用"
+	}
+	return fmt.Sprintf("被%s引用 (%s)", from, e.Text)
+}
+
+// ReferenceExpander 引用扩展节点
+// 沿 DocumentChunk.References 加载检索结果引用的条文，最多 hops 跳，已纳入的条文不重复加载
+type ReferenceExpander struct {
+	docRepo repository.DocumentRepository
+	hops    int
+	max     int
+}
+
+// NewReferenceExpander 创建引用扩展节点
+// max <= 0 时使用默认值
+func NewReferenceExpander(hops, max int) *ReferenceExpander {
+	if max <= 0 {
+		max = defaultExpandMax
+	}
+	return &ReferenceExpander{
+		docRepo: repository.NewDocumentRepository(),
+		hops:    hops,
+		max:     max,
+	}
+}
+
+// refTarget 引用解析后的目标条文
+type refTarget struct {
+	parent     *RetrievalResult
+	ref        chunker.Reference
+	documentID string
+	article    string
+}
+
+// Expand 加载 results 引用的条文，返回新纳入的条文 (按引用出现顺序，不含 results 本身)
+// asOf 非零时仅纳入在该日有效的条文
+func (e *ReferenceExpander) Expand(ctx context.Context, results []RetrievalResult, asOf time.Time) ([]RetrievalResult, error) {
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		seen[r.ChunkID] = true
+	}
+	lawDocs := make(map[string][]string)
+
+	var expanded []RetrievalResult
+	frontier := results
+	for hop := 1; hop <= e.hops && len(frontier) > 0 && len(expanded) < e.max; hop++ {
+		targets, err := e.resolve(ctx, frontier, lawDocs)
+		if err != nil {
+			return expanded, err
+		}
+		found, err := e.load(ctx, targets, asOf)
+		if err != nil {
+			return expanded, err
+		}
+
+		var next []RetrievalResult
+		for i, t := range targets {
+			for _, res := range found[i] {
+				if seen[res.ChunkID] || len(expanded)+len(next) >= e.max {
+					continue
+				}
+				seen[res.ChunkID] = true
+				res.Match = MatchRef
+				res.Expansion = &Expansion{
+					Hop:         hop,
+					FromChunkID: t.parent.ChunkID,
+					FromSource:  t.parent.Source,
+					FromArticle: t.parent.ArticleNumber,
+					Text:        t.ref.Text,
+				}
+				next = append(next, res)
+			}
+		}
+		expanded = append(expanded, next...)
+		frontier = next
+	}
+	return expanded, nil
+}
+
+// resolve 将引用解析为 (文档, 条号) 目标
+// 本法引用指向引用方所在文档；引用其他法律时按法律名称查找文档 (结果缓存在 lawDocs 中)
+func (e *ReferenceExpander) resolve(ctx context.Context, frontier []RetrievalResult, lawDocs map[string][]string) ([]refTarget, error) {
+	var targets []refTarget
+	for i := range frontier {
+		parent := &frontier[i]
+		for _, ref := range parent.References {
+			docIDs := []string{parent.DocumentID}
+			if name := normalizeLawName(ref.Law); name != "" {
+				ids, ok := lawDocs[name]
+				if !ok {
+					docs, err := e.docRepo.FindByLawNames(ctx, []string{name, "中华人民共和国" + name})
+					if err != nil {
+						return nil, err
+					}
+					for _, d := range docs {
+						ids = append(ids, d.ID)
+					}
+					lawDocs[name] = ids
+				}
+				docIDs = ids
+			}
+
+			for _, article := range referencedArticles(ref) {
+				for _, docID := range docIDs {
+					targets = append(targets, refTarget{parent: parent, ref: ref, documentID: docID, article: article})
+				}
+			}
+		}
+	}
+	return targets, nil
+}
+
+// load 批量加载目标条文，返回与 targets 下标对应的检索结果
+func (e *ReferenceExpander) load(ctx context.Context, targets []refTarget, asOf time.Time) ([][]RetrievalResult, error) {
+	found := make([][]RetrievalResult, len(targets))
+	if len(targets) == 0 {
+		return found, nil
+	}
+
+	var docIDs, articles []string
+	seenDoc := make(map[string]bool)
+	seenArticle := make(map[string]bool)
+	for _, t := range targets {
+		if !seenDoc[t.documentID] {
+			seenDoc[t.documentID] = true
+			docIDs = append(docIDs, t.documentID)
+		}
+		if !seenArticle[t.article] {
+			seenArticle[t.article] = true
+			articles = append(articles, t.article)
+		}
+	}
+
+	chunks, err := e.docRepo.FindChunksByArticles(ctx, docIDs, articles)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := e.docRepo.FindByIDs(ctx, docIDs)
+	if err != nil {
+		return nil, err
+	}
+	docByID := make(map[string]*model.Document, len(docs))
+	for i := range docs {
+		docByID[docs[i].ID] = &docs[i]
+	}
+
+	byKey := make(map[string][]*model.DocumentChunk)
+	for i := range chunks {
+		if !inForce(&chunks[i], asOf) {
+			continue
+		}
+		key := chunks[i].DocumentID + "|" + chunks[i].ArticleNumber
+		byKey[key] = append(byKey[key], &chunks[i])
+	}
+	for i, t := range targets {
+		doc, ok := docByID[t.documentID]
+		if !ok {
+			continue
+		}
+		for _, chunk := range byKey[t.documentID+"|"+t.article] {
+			found[i] = append(found[i], newRetrievalResult(chunk, doc))
+		}
+	}
+	return found, nil
+}
+
+// referencedArticles 引用指向的条号，区间引用最多展开 maxRangeArticles 条
+func referencedArticles(ref chunker.Reference) []string {
+	if ref.ToArticle == "" {
+		return []string{ref.Article}
+	}
+	from, errFrom := strconv.Atoi(ref.Article)
+	to, errTo := strconv.Atoi(ref.ToArticle)
+	// "第十条之一至第十一条" 等含 "之X" 的区间只取两端
+	if errFrom != nil || errTo != nil || to < from {
+		return []string{ref.Article, ref.ToArticle}
+	}
+
+	articles := make([]string, 0, maxRangeArticles)
+	for n := from; n <= to && len(articles) < maxRangeArticles; n++ {
+		articles = append(articles, strconv.Itoa(n))
+	}
+	return articles
+}
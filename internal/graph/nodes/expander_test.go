@@ -0,0 +1,258 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+)
+
+// fakeChunkRepo 内存中的文档与分块，仅实现引用扩展用到的查询
+type fakeChunkRepo struct {
+	repository.DocumentRepository
+	docs   []model.Document
+	chunks []model.DocumentChunk
+}
+
+func (f *fakeChunkRepo) FindByIDs(_ context.Context, ids []string) ([]model.Document, error) {
+	var out []model.Document
+	for _, d := range f.docs {
+		for _, id := range ids {
+			if d.ID == id {
+				out = append(out, d)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeChunkRepo) FindByLawNames(_ context.Context, names []string) ([]model.Document, error) {
+	var out []model.Document
+	for _, d := range f.docs {
+		for _, name := range names {
+			if d.LawName == name || d.Name == name {
+				out = append(out, d)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeChunkRepo) FindChunksByArticles(_ context.Context, documentIDs, articles []string) ([]model.DocumentChunk, error) {
+	var out []model.DocumentChunk
+	for _, c := range f.chunks {
+		if contains(documentIDs, c.DocumentID) && contains(articles, c.ArticleNumber) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// contains 切片是否包含 s
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// refChunk 构造引用 refs 的分块
+func refChunk(chunkID, documentID, article string, refs ...chunker.Reference) model.DocumentChunk {
+	c := model.DocumentChunk{ChunkID: chunkID, DocumentID: documentID, ArticleNumber: article, Content: "第" + article + "条"}
+	if len(refs) > 0 {
+		c.References, _ = json.Marshal(refs)
+	}
+	return c
+}
+
+// newExpanderRepo 民法典与劳动合同法的引用关系:
+// 1165 → 1179、1166、《劳动合同法》第47条；1179 → 1165 (循环)、1183；1183 → 1179 (循环)；
+// 1170 → 第1190条至第1200条 (区间)
+func newExpanderRepo() *fakeChunkRepo {
+	expired := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+	repo := &fakeChunkRepo{
+		docs: []model.Document{
+			{ID: "doc-civil", Name: "民法典", LawName: "中华人民共和国民法典"},
+			{ID: "doc-labor", Name: "劳动合同法", LawName: "中华人民共和国劳动合同法"},
+		},
+		chunks: []model.DocumentChunk{
+			refChunk("civil-1165", "doc-civil", "1165",
+				chunker.Reference{Article: "1179", Text: "依照本法第一千一百七十九条"},
+				chunker.Reference{Article: "1166", Text: "依照本法第一千一百六十六条"},
+				chunker.Reference{Law: "劳动合同法", Article: "47", Text: "《劳动合同法》第四十七条"},
+			),
+			refChunk("civil-1166", "doc-civil", "1166"),
+			refChunk("civil-1179", "doc-civil", "1179",
+				chunker.Reference{Article: "1165", Text: "本法第一千一百六十五条"},
+				chunker.Reference{Article: "1183", Text: "本法第一千一百八十三条"},
+			),
+			refChunk("civil-1183", "doc-civil", "1183", chunker.Reference{Article: "1179", Text: "本法第一千一百七十九条"}),
+			refChunk("labor-47", "doc-labor", "47"),
+			refChunk("civil-1170", "doc-civil", "1170", chunker.Reference{Article: "1190", ToArticle: "1200", Text: "本法第一千一百九十条至第一千二百条"}),
+		},
+	}
+	for _, article := range []string{"1190", "1191", "1192", "1193", "1194", "1195", "1196", "1197", "1198", "1199", "1200"} {
+		repo.chunks = append(repo.chunks, refChunk("civil-"+article, "doc-civil", article))
+	}
+	// 2020 年底失效的旧版第 1179 条
+	old := refChunk("civil-1179-old", "doc-civil", "1179")
+	old.ExpiryDate = &expired
+	repo.chunks = append(repo.chunks, old)
+	return repo
+}
+
+// primaryResults 由民法典分块构造检索结果
+func primaryResults(repo *fakeChunkRepo, chunkIDs ...string) []RetrievalResult {
+	var out []RetrievalResult
+	for _, id := range chunkIDs {
+		for i := range repo.chunks {
+			if repo.chunks[i].ChunkID == id {
+				out = append(out, newRetrievalResult(&repo.chunks[i], &repo.docs[0]))
+			}
+		}
+	}
+	return out
+}
+
+func TestExpand(t *testing.T) {
+	type expanded struct {
+		id   string
+		hop  int
+		from string
+	}
+	asOf := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		primary []string
+		hops    int
+		max     int
+		want    []expanded
+	}{
+		{
+			name:    "多跳扩展且循环引用不重复纳入",
+			primary: []string{"civil-1165"},
+			hops:    3,
+			max:     10,
+			want: []expanded{
+				{"civil-1179", 1, "civil-1165"},
+				{"civil-1166", 1, "civil-1165"},
+				{"labor-47", 1, "civil-1165"},
+				{"civil-1183", 2, "civil-1179"},
+			},
+		},
+		{
+			name:    "仅一跳",
+			primary: []string{"civil-1165"},
+			hops:    1,
+			max:     10,
+			want:    []expanded{{"civil-1179", 1, "civil-1165"}, {"civil-1166", 1, "civil-1165"}, {"labor-47", 1, "civil-1165"}},
+		},
+		{
+			name:    "检索结果中已有的条文不重复纳入",
+			primary: []string{"civil-1165", "civil-1166", "civil-1183"},
+			hops:    3,
+			max:     10,
+			want:    []expanded{{"civil-1179", 1, "civil-1165"}, {"labor-47", 1, "civil-1165"}},
+		},
+		{
+			name:    "纳入数量上限",
+			primary: []string{"civil-1165"},
+			hops:    3,
+			max:     2,
+			want:    []expanded{{"civil-1179", 1, "civil-1165"}, {"civil-1166", 1, "civil-1165"}},
+		},
+		{
+			name:    "区间引用最多展开 maxRangeArticles 条",
+			primary: []string{"civil-1170"},
+			hops:    1,
+			max:     10,
+			want: []expanded{
+				{"civil-1190", 1, "civil-1170"},
+				{"civil-1191", 1, "civil-1170"},
+				{"civil-1192", 1, "civil-1170"},
+				{"civil-1193", 1, "civil-1170"},
+				{"civil-1194", 1, "civil-1170"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newExpanderRepo()
+			e := NewReferenceExpander(tt.hops, tt.max)
+			e.docRepo = repo
+
+			results, err := e.Expand(context.Background(), primaryResults(repo, tt.primary...), asOf)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			var got []expanded
+			for _, res := range results {
+				if res.Match != MatchRef || res.Expansion == nil {
+					t.Fatalf("%s Match = %s, Expansion = %v", res.ChunkID, res.Match, res.Expansion)
+				}
+				got = append(got, expanded{res.ChunkID, res.Expansion.Hop, res.Expansion.FromChunkID})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpansionReason(t *testing.T) {
+	repo := newExpanderRepo()
+	e := NewReferenceExpander(2, 10)
+	e.docRepo = repo
+
+	results, err := e.Expand(context.Background(), primaryResults(repo, "civil-1165"), time.Time{})
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	reasons := make(map[string]string)
+	for _, res := range results {
+		reasons[res.ChunkID] = res.Expansion.Reason()
+	}
+	want := map[string]string{
+		"civil-1179":     "被《中华人民共和国民法典》第1165条引用 (依照本法第一千一百七十九条)",
+		"civil-1179-old": "被《中华人民共和国民法典》第1165条引用 (依照本法第一千一百七十九条)", // 未指定适用日期时不按时效筛选
+		"civil-1166":     "被《中华人民共和国民法典》第1165条引用 (依照本法第一千一百六十六条)",
+		"labor-47":       "被《中华人民共和国民法典》第1165条引用 (《劳动合同法》第四十七条)",
+		"civil-1183":     "被《中华人民共和国民法典》第1179条引用 (本法第一千一百八十三条)",
+	}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("Reason() = %v, want %v", reasons, want)
+	}
+
+	if got := (&Expansion{FromSource: "民法典"}).Reason(); got != "被《民法典》引用" {
+		t.Errorf("Reason() = %q", got)
+	}
+}
+
+func TestReferencedArticles(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  chunker.Reference
+		want []string
+	}{
+		{name: "单条", ref: chunker.Reference{Article: "1165"}, want: []string{"1165"}},
+		{name: "区间", ref: chunker.Reference{Article: "10", ToArticle: "12"}, want: []string{"10", "11", "12"}},
+		{name: "区间超出上限", ref: chunker.Reference{Article: "1", ToArticle: "100"}, want: []string{"1", "2", "3", "4", "5"}},
+		{name: "含之X的区间只取两端", ref: chunker.Reference{Article: "10-1", ToArticle: "11"}, want: []string{"10-1", "11"}},
+		{name: "倒置区间只取两端", ref: chunker.Reference{Article: "12", ToArticle: "10"}, want: []string{"12", "10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := referencedArticles(tt.ref); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("referencedArticles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
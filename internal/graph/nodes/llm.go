@@ -1,28 +1,47 @@
 // Package nodes 提供 Eino Graph 节点实现
 package nodes
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+)
+
+// ErrEmptyStream LLM 客户端未返回数据流
+var ErrEmptyStream = errors.New("LLM 客户端未返回数据流")
 
 // LLMNode 大模型节点
 // 负责调用 LLM API 生成回答
 type LLMNode struct {
-	// client LLM 客户端
-	// model  模型名称
+	client *client.LLMClient
 }
 
 // NewLLMNode 创建大模型节点
-func NewLLMNode() *LLMNode {
-	return &LLMNode{}
+func NewLLMNode(llmClient *client.LLMClient) *LLMNode {
+	return &LLMNode{
+		client: llmClient,
+	}
+}
+
+// Model 返回模型名称
+func (l *LLMNode) Model() string {
+	return l.client.Model()
 }
 
 // Generate 生成回答
-func (l *LLMNode) Generate(ctx context.Context, prompt string) (string, error) {
-	// TODO: 实现 LLM 调用逻辑
-	return "", nil
+func (l *LLMNode) Generate(ctx context.Context, messages []client.Message) (*client.ChatResponse, error) {
+	return l.client.ChatCompletion(ctx, messages)
 }
 
 // GenerateStream 流式生成回答
-func (l *LLMNode) GenerateStream(ctx context.Context, prompt string) (<-chan string, error) {
-	// TODO: 实现流式 LLM 调用逻辑
-	return nil, nil
+func (l *LLMNode) GenerateStream(ctx context.Context, messages []client.Message) (<-chan client.StreamChunk, error) {
+	stream, err := l.client.ChatCompletionStream(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		return nil, ErrEmptyStream
+	}
+	return stream, nil
 }
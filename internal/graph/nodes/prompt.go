@@ -1,24 +1,289 @@
 // Package nodes 提供 Eino Graph 节点实现
 package nodes
 
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultPromptVersion 默认系统提示词模板版本
+const DefaultPromptVersion = "legal_qa_v3"
+
+// messageOverheadTokens 每条消息的格式开销 (估算)
+const messageOverheadTokens = 4
+
+// promptFS 系统提示词模板，文件名 (不含扩展名) 即模板版本
+// 已发布的模板不应修改，调整提示词时新增版本，保证历史回答可复现
+//
+//go:embed prompts/*.tmpl
+var promptFS embed.FS
+
+// promptTemplates 已加载的模板
+var promptTemplates = loadPromptTemplates()
+
+// loadPromptTemplates 加载内置模板
+func loadPromptTemplates() map[string]*template.Template {
+	files, err := promptFS.ReadDir("prompts")
+	if err != nil {
+		panic(err)
+	}
+	templates := make(map[string]*template.Template, len(files))
+	for _, f := range files {
+		version := strings.TrimSuffix(f.Name(), path.Ext(f.Name()))
+		templates[version] = template.Must(template.ParseFS(promptFS, "prompts/"+f.Name()))
+	}
+	return templates
+}
+
+// PromptVersions 返回可用的模板版本
+func PromptVersions() []string {
+	versions := make([]string, 0, len(promptTemplates))
+	for v := range promptTemplates {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// HasPromptVersion 模板版本是否存在
+func HasPromptVersion(version string) bool {
+	_, ok := promptTemplates[version]
+	return ok
+}
+
+// PromptSettings 提示词配置 (系统配置 prompt 分类)
+type PromptSettings struct {
+	Version          string // 模板版本
+	TokenBudget      int    // 发送给 LLM 的消息总 Token 预算 (估算)，<= 0 表示不限制
+	HistoryMaxTokens int    // 对话历史最多占用的 Token 数
+}
+
+// DefaultPromptSettings 默认提示词配置，与 model.DefaultSystemConfigs 保持一致
+func DefaultPromptSettings() PromptSettings {
+	return PromptSettings{
+		Version:          DefaultPromptVersion,
+		TokenBudget:      6000,
+		HistoryMaxTokens: 1500,
+	}
+}
+
+// PromptSettingsProvider 提示词配置来源，配置可在运行时调整
+type PromptSettingsProvider interface {
+	PromptSettings(ctx context.Context) PromptSettings
+}
+
 // PromptBuilder 提示词节点
-// 负责构建包含验证通过信息的 Prompt
+// 按版本化模板构建系统提示词，并在 Token 预算内装入参考法条与对话历史
 type PromptBuilder struct {
-	// systemPrompt 系统提示词模板
+	provider PromptSettingsProvider
 }
 
 // NewPromptBuilder 创建提示词节点
-func NewPromptBuilder() *PromptBuilder {
-	return &PromptBuilder{}
+// provider 为 nil 时使用默认配置
+func NewPromptBuilder(provider PromptSettingsProvider) *PromptBuilder {
+	return &PromptBuilder{
+		provider: provider,
+	}
+}
+
+// Prompt 构建结果
+type Prompt struct {
+	Version         string                  // 使用的模板版本
+	Messages        []client.Message        // 发送给 LLM 的消息
+	Chunks          []ChunkWithVerification // 装入提示词的法条 (保持检索顺序，序号与提示词中的 [n] 一致)
+	DroppedChunks   int                     // 因预算不足丢弃的法条数
+	HistoryUsed     int                     // 携带的历史消息数
+	EstimatedTokens int                     // 消息总 Token 数 (估算)
+}
+
+// promptData 模板数据
+type promptData struct {
+	Question string
+	AsOfDate string   // 用户指定的适用日期 (YYYY-MM-DD)，未指定时为空
+	Notices  []string // 时效提示
+	Chunks   []promptChunk
+	Dropped  int
+}
+
+// promptChunk 模板中的参考法条
+type promptChunk struct {
+	Index         int
+	Source        string
+	ArticleNumber string
+	LawHierarchy  string
+	Verified      bool
+	Content       string
 }
 
 // Build 构建提示词
-func (p *PromptBuilder) Build(input PromptInput) string {
-	// TODO: 实现提示词构建逻辑
-	// 1. 组装检索到的法条内容
-	// 2. 添加验证状态信息
-	// 3. 构建完整的 Prompt
-	return ""
+// 1. 对话历史从最近一条起装入，不超过 HistoryMaxTokens
+// 2. 超出预算时按排序名次 (无名次时按相似度) 从低到高丢弃法条
+// 3. 仍超出预算时继续丢弃最早的历史消息
+func (p *PromptBuilder) Build(ctx context.Context, input PromptInput) (*Prompt, error) {
+	settings := p.settings(ctx)
+	tmpl := promptTemplates[settings.Version]
+
+	history := trimHistory(input.ChatHistory, settings.HistoryMaxTokens)
+	kept := make([]bool, len(input.Chunks))
+	for i := range kept {
+		kept[i] = true
+	}
+	// 按相关度从低到高排列的下标，依次作为丢弃候选 (有排序名次时按名次，否则按相似度)
+	dropOrder := make([]int, len(input.Chunks))
+	for i := range dropOrder {
+		dropOrder[i] = i
+	}
+	sort.SliceStable(dropOrder, func(a, b int) bool {
+		ca, cb := input.Chunks[dropOrder[a]], input.Chunks[dropOrder[b]]
+		if ca.Rank > 0 && cb.Rank > 0 {
+			return ca.Rank > cb.Rank
+		}
+		return ca.Score < cb.Score
+	})
+
+	dropped := 0
+	for {
+		chunks := keptChunks(input.Chunks, kept)
+		system, err := renderPrompt(tmpl, input, chunks, dropped)
+		if err != nil {
+			return nil, fmt.Errorf("渲染提示词模板 %s 失败: %w", settings.Version, err)
+		}
+		messages := assembleMessages(system, history, input.Question)
+		tokens := estimateMessages(messages)
+
+		if settings.TokenBudget <= 0 || tokens <= settings.TokenBudget {
+			return &Prompt{
+				Version:         settings.Version,
+				Messages:        messages,
+				Chunks:          chunks,
+				DroppedChunks:   dropped,
+				HistoryUsed:     len(history),
+				EstimatedTokens: tokens,
+			}, nil
+		}
+
+		switch {
+		case dropped < len(dropOrder):
+			kept[dropOrder[dropped]] = false
+			dropped++
+		case len(history) > 0:
+			history = history[1:]
+		default:
+			// 问题本身已超出预算，交由 LLM 接口处理
+			logger.Warn("提示词超出 Token 预算",
+				zap.Int("tokens", tokens),
+				zap.Int("budget", settings.TokenBudget),
+			)
+			return &Prompt{
+				Version:         settings.Version,
+				Messages:        messages,
+				Chunks:          chunks,
+				DroppedChunks:   dropped,
+				HistoryUsed:     len(history),
+				EstimatedTokens: tokens,
+			}, nil
+		}
+	}
+}
+
+// settings 读取提示词配置，模板版本不存在时使用默认版本
+func (p *PromptBuilder) settings(ctx context.Context) PromptSettings {
+	settings := DefaultPromptSettings()
+	if p.provider != nil {
+		settings = p.provider.PromptSettings(ctx)
+	}
+	if !HasPromptVersion(settings.Version) {
+		logger.Warn("提示词模板版本不存在，使用默认版本",
+			zap.String("version", settings.Version),
+			zap.String("default", DefaultPromptVersion),
+		)
+		settings.Version = DefaultPromptVersion
+	}
+	return settings
+}
+
+// renderPrompt 渲染系统提示词
+func renderPrompt(tmpl *template.Template, input PromptInput, chunks []ChunkWithVerification, dropped int) (string, error) {
+	data := promptData{
+		Question: input.Question,
+		AsOfDate: input.AsOfDate,
+		Notices:  input.Notices,
+		Chunks:   make([]promptChunk, len(chunks)),
+		Dropped:  dropped,
+	}
+	for i, c := range chunks {
+		data.Chunks[i] = promptChunk{
+			Index:         i + 1,
+			Source:        c.Source,
+			ArticleNumber: c.ArticleNumber,
+			LawHierarchy:  c.LawHierarchy,
+			Verified:      c.Verified,
+			Content:       c.Content,
+		}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// assembleMessages 组装 LLM 消息: 系统提示词 → 对话历史 → 当前问题
+func assembleMessages(system string, history []ChatHistoryItem, question string) []client.Message {
+	messages := make([]client.Message, 0, len(history)+2)
+	messages = append(messages, client.Message{Role: "system", Content: system})
+	for _, h := range history {
+		messages = append(messages, client.Message{Role: h.Role, Content: h.Content})
+	}
+	return append(messages, client.Message{Role: "user", Content: question})
+}
+
+// trimHistory 从最近一条起保留不超过 maxTokens 的历史消息
+func trimHistory(history []ChatHistoryItem, maxTokens int) []ChatHistoryItem {
+	if maxTokens <= 0 {
+		return nil
+	}
+	used := 0
+	start := len(history)
+	for start > 0 {
+		cost := client.EstimateTokens(history[start-1].Content) + messageOverheadTokens
+		if used+cost > maxTokens {
+			break
+		}
+		used += cost
+		start--
+	}
+	return history[start:]
+}
+
+// keptChunks 按原顺序返回保留的法条
+func keptChunks(chunks []ChunkWithVerification, kept []bool) []ChunkWithVerification {
+	out := make([]ChunkWithVerification, 0, len(chunks))
+	for i, c := range chunks {
+		if kept[i] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// estimateMessages 估算消息总 Token 数
+func estimateMessages(messages []client.Message) int {
+	total := 0
+	for _, m := range messages {
+		total += client.EstimateTokens(m.Content) + messageOverheadTokens
+	}
+	return total
 }
 
 // PromptInput 提示词构建输入
@@ -26,14 +291,24 @@ type PromptInput struct {
 	Question    string
 	Chunks      []ChunkWithVerification
 	ChatHistory []ChatHistoryItem
+	AsOfDate    string   // 用户指定的适用日期 (YYYY-MM-DD)，为空表示按现行法律回答
+	Notices     []string // 时效提示 (问题引用的条文在适用日期已失效或尚未生效)
 }
 
 // ChunkWithVerification 带验证信息的 Chunk
 type ChunkWithVerification struct {
-	Content    string
-	Source     string
-	Verified   bool
-	VerifyInfo VerifyResult
+	ChunkID       string
+	DocumentID    string
+	Content       string
+	Source        string
+	ArticleNumber string
+	LawHierarchy  string
+	Score         float32
+	RerankScore   float32
+	Rank          int
+	Reason        string // 纳入原因 (引用扩展纳入的条文)，直接检索命中时为空
+	Verified      bool
+	VerifyInfo    VerifyResult
 }
 
 // ChatHistoryItem 聊天历史项
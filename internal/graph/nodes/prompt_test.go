@@ -0,0 +1,179 @@
+package nodes
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// staticPromptSettings 固定的提示词配置
+type staticPromptSettings PromptSettings
+
+func (s staticPromptSettings) PromptSettings(context.Context) PromptSettings {
+	return PromptSettings(s)
+}
+
+// promptChunks 4 条参考法条，ranks 与 scores 为各条的排序名次与相似度
+func promptChunks(ranks []int, scores []float32) []ChunkWithVerification {
+	chunks := make([]ChunkWithVerification, 4)
+	for i := range chunks {
+		article := []string{"1165", "1166", "1167", "1168"}[i]
+		chunks[i] = ChunkWithVerification{
+			ChunkID:       "civil-" + article,
+			Source:        "中华人民共和国民法典",
+			ArticleNumber: article,
+			Content:       "第" + article + "条　" + strings.Repeat("行为人因过错侵害他人民事权益造成损害的，应当承担侵权责任。", 3),
+			Rank:          ranks[i],
+			Score:         scores[i],
+			Verified:      true,
+		}
+	}
+	return chunks
+}
+
+// promptHistory 3 条历史消息，按 HistoryMaxTokens = 50 只能携带最近 2 条
+var promptHistory = []ChatHistoryItem{
+	{Role: "user", Content: "第一轮问题：用人单位单方解除劳动合同需要支付经济补偿吗"},
+	{Role: "assistant", Content: "第二轮回答：需要按工作年限支付经济补偿"},
+	{Role: "user", Content: "第三轮问题：侵权责任的归责原则是什么"},
+}
+
+// promptTokens 按默认模板估算装入 chunks 与最近 history 条历史消息时的消息 Token 数
+func promptTokens(t *testing.T, input PromptInput, chunks []ChunkWithVerification, dropped, history int) int {
+	t.Helper()
+	system, err := renderPrompt(promptTemplates[DefaultPromptVersion], input, chunks, dropped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return estimateMessages(assembleMessages(system, input.ChatHistory[len(input.ChatHistory)-history:], input.Question))
+}
+
+func TestPromptBuild(t *testing.T) {
+	byRank := promptChunks([]int{1, 2, 4, 3}, []float32{0.9, 0.5, 0.7, 0.6})
+	byScore := promptChunks([]int{0, 0, 0, 0}, []float32{0.9, 0.5, 0.7, 0.6})
+	pick := func(chunks []ChunkWithVerification, idx ...int) []ChunkWithVerification {
+		out := make([]ChunkWithVerification, 0, len(idx))
+		for _, i := range idx {
+			out = append(out, chunks[i])
+		}
+		return out
+	}
+
+	tests := []struct {
+		name        string
+		version     string
+		chunks      []ChunkWithVerification
+		budget      func(t *testing.T, input PromptInput) int // 为 nil 时不限制
+		wantChunks  []ChunkWithVerification
+		wantDropped int
+		wantHistory int
+		overBudget  bool
+	}{
+		{
+			name:        "不限预算时历史按 HistoryMaxTokens 截取",
+			chunks:      byRank,
+			wantChunks:  byRank,
+			wantHistory: 2,
+		},
+		{
+			name:   "按排序名次丢弃法条",
+			chunks: byRank,
+			budget: func(t *testing.T, input PromptInput) int {
+				return promptTokens(t, input, pick(byRank, 0, 1), 2, 2)
+			},
+			wantChunks:  pick(byRank, 0, 1),
+			wantDropped: 2,
+			wantHistory: 2,
+		},
+		{
+			name:   "无排序名次时按相似度丢弃法条",
+			chunks: byScore,
+			budget: func(t *testing.T, input PromptInput) int {
+				return promptTokens(t, input, pick(byScore, 0, 2), 2, 2)
+			},
+			wantChunks:  pick(byScore, 0, 2),
+			wantDropped: 2,
+			wantHistory: 2,
+		},
+		{
+			name:   "法条全部丢弃后丢弃最早的历史消息",
+			chunks: byRank,
+			budget: func(t *testing.T, input PromptInput) int {
+				return promptTokens(t, input, nil, 4, 1)
+			},
+			wantChunks:  []ChunkWithVerification{},
+			wantDropped: 4,
+			wantHistory: 1,
+		},
+		{
+			name:        "问题本身超出预算",
+			chunks:      byRank,
+			budget:      func(*testing.T, PromptInput) int { return 1 },
+			wantChunks:  []ChunkWithVerification{},
+			wantDropped: 4,
+			overBudget:  true,
+		},
+		{
+			name:        "未知版本使用默认版本",
+			version:     "legal_qa_v99",
+			chunks:      byRank,
+			wantChunks:  byRank,
+			wantHistory: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := PromptInput{Question: "因过错侵害他人民事权益应当承担什么责任", Chunks: tt.chunks, ChatHistory: promptHistory}
+			settings := PromptSettings{Version: DefaultPromptVersion, HistoryMaxTokens: 50}
+			if tt.version != "" {
+				settings.Version = tt.version
+			}
+			if tt.budget != nil {
+				settings.TokenBudget = tt.budget(t, input)
+			}
+
+			p, err := NewPromptBuilder(staticPromptSettings(settings)).Build(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if p.Version != DefaultPromptVersion {
+				t.Errorf("Version = %s, want %s", p.Version, DefaultPromptVersion)
+			}
+			if !reflect.DeepEqual(p.Chunks, tt.wantChunks) {
+				t.Errorf("Chunks = %v, want %v", chunkIDs(p.Chunks), chunkIDs(tt.wantChunks))
+			}
+			if p.DroppedChunks != tt.wantDropped || p.HistoryUsed != tt.wantHistory {
+				t.Errorf("DroppedChunks = %d, HistoryUsed = %d, want %d, %d", p.DroppedChunks, p.HistoryUsed, tt.wantDropped, tt.wantHistory)
+			}
+
+			// 消息: 系统提示词 → 最近的历史消息 → 当前问题
+			if len(p.Messages) != p.HistoryUsed+2 || p.Messages[0].Role != "system" || p.Messages[len(p.Messages)-1].Content != input.Question {
+				t.Fatalf("Messages = %+v", p.Messages)
+			}
+			for i, m := range p.Messages[1 : len(p.Messages)-1] {
+				if h := promptHistory[len(promptHistory)-p.HistoryUsed+i]; m.Content != h.Content {
+					t.Errorf("Messages[%d] = %q, want %q", i+1, m.Content, h.Content)
+				}
+			}
+			if p.EstimatedTokens != estimateMessages(p.Messages) {
+				t.Errorf("EstimatedTokens = %d, want %d", p.EstimatedTokens, estimateMessages(p.Messages))
+			}
+			if settings.TokenBudget > 0 && (p.EstimatedTokens > settings.TokenBudget) != tt.overBudget {
+				t.Errorf("EstimatedTokens = %d, budget %d", p.EstimatedTokens, settings.TokenBudget)
+			}
+			if tt.wantDropped > 0 && !strings.Contains(p.Messages[0].Content, "因篇幅限制未列出") {
+				t.Errorf("系统提示词缺少丢弃说明:\n%s", p.Messages[0].Content)
+			}
+		})
+	}
+}
+
+// chunkIDs 法条 ID 列表
+func chunkIDs(chunks []ChunkWithVerification) []string {
+	ids := make([]string, len(chunks))
+	for i, c := range chunks {
+		ids[i] = c.ChunkID
+	}
+	return ids
+}
@@ -0,0 +1,232 @@
+// Package nodes 提供 Eino Graph 节点实现
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+)
+
+const (
+	// analysisHistoryMessages 查询理解时参考的最近历史消息数
+	analysisHistoryMessages = 6
+	// defaultMaxQueries 默认改写问题数上限
+	defaultMaxQueries = 3
+)
+
+// ErrInvalidAnalysis 模型返回的查询理解结果无法解析
+var ErrInvalidAnalysis = errors.New("查询理解结果不是有效的 JSON")
+
+// legalDomain 法律领域
+type legalDomain struct {
+	Code  string
+	Label string
+}
+
+// legalDomains 查询理解可识别的法律领域 (Code 写入 QueryAnalysis.Domain)
+var legalDomains = []legalDomain{
+	{"labor", "劳动人事：劳动合同、工资报酬、加班、解雇、工伤、社会保险"},
+	{"contract", "合同：买卖、借款、租赁、服务等合同的订立、履行与违约"},
+	{"tort", "侵权责任：人身损害、财产损害、交通事故、医疗损害"},
+	{"property", "物权：所有权、不动产登记、抵押、质押、相邻关系"},
+	{"marriage_family", "婚姻家庭与继承：结婚、离婚、抚养、赡养、遗产继承"},
+	{"company", "公司与商事：公司设立、股东权利、破产、票据、保险"},
+	{"criminal", "刑事：犯罪构成、刑罚、正当防卫、刑事责任"},
+	{"administrative", "行政：行政处罚、行政许可、行政强制、行政复议"},
+	{"procedure", "诉讼与仲裁程序：管辖、起诉、证据、诉讼时效、执行、仲裁"},
+	{"intellectual_property", "知识产权：著作权、商标、专利、不正当竞争"},
+	{"consumer", "消费者权益：产品质量、食品安全、网络购物、退换货"},
+	{"other", "其他或无法判断"},
+}
+
+// QueryAnalyzerOptions 查询理解配置
+type QueryAnalyzerOptions struct {
+	MaxQueries    int                 // 改写问题数上限，<= 0 时使用默认值
+	MinConfidence float64             // 领域分类置信度不低于该值时才按领域过滤检索
+	LawTypes      map[string][]string // 法律领域 → 文档法律类型 (Document.LawType)，未配置的领域不过滤
+}
+
+// QueryAnalysis 查询理解结果
+type QueryAnalysis struct {
+	Domain     string              // 法律领域，如 labor；无法识别时为空
+	Confidence float64             // 领域分类置信度 (0-1)
+	LawTypes   []string            // 按领域限定检索的文档法律类型，为空时不限
+	Statutes   []chunker.Reference // 问题中指明的法律与条号
+	Queries    []string            // 检索问题，第一个为规范化改写后的主问题
+}
+
+// analysisOutput 模型输出的 JSON 结构
+type analysisOutput struct {
+	Domain     string  `json:"domain"`
+	Confidence float64 `json:"confidence"`
+	Statutes   []struct {
+		Law     string `json:"law"`
+		Article string `json:"article"`
+	} `json:"statutes"`
+	Queries []string `json:"queries"`
+}
+
+// QueryAnalyzer 查询理解节点
+// 口语化的问题 (如"老板不给工资怎么办") 与法条用语差异大，直接检索效果差。
+// 由 LLM 识别法律领域、抽取问题中指明的法律与条号，并结合对话历史补全指代，
+// 改写为若干规范法律用语的检索问题，各问题的检索结果融合排序
+type QueryAnalyzer struct {
+	llm  *LLMNode
+	opts QueryAnalyzerOptions
+}
+
+// NewQueryAnalyzer 创建查询理解节点
+func NewQueryAnalyzer(llm *LLMNode, opts QueryAnalyzerOptions) *QueryAnalyzer {
+	if opts.MaxQueries <= 0 {
+		opts.MaxQueries = defaultMaxQueries
+	}
+	return &QueryAnalyzer{
+		llm:  llm,
+		opts: opts,
+	}
+}
+
+// Analyze 分析问题，模型未给出改写问题时以原问题检索
+// 无对话历史时原问题同样参与检索，避免改写偏离原意导致漏检
+func (a *QueryAnalyzer) Analyze(ctx context.Context, question string, history []ChatHistoryItem) (*QueryAnalysis, client.TokenUsage, error) {
+	if len(history) > analysisHistoryMessages {
+		history = history[len(history)-analysisHistoryMessages:]
+	}
+
+	var sb strings.Builder
+	if len(history) > 0 {
+		sb.WriteString("对话历史：\n")
+		for _, h := range history {
+			role := "用户"
+			if h.Role == "assistant" {
+				role = "助手"
+			}
+			sb.WriteString(role + "：" + h.Content + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("最新问题：" + question)
+
+	resp, err := a.llm.Generate(ctx, []client.Message{
+		{Role: "system", Content: a.systemPrompt()},
+		{Role: "user", Content: sb.String()},
+	})
+	if err != nil {
+		return nil, client.TokenUsage{}, err
+	}
+
+	out, err := parseAnalysisOutput(resp.Content)
+	if err != nil {
+		return nil, resp.Usage, err
+	}
+
+	analysis := &QueryAnalysis{
+		Domain:     knownDomain(out.Domain),
+		Confidence: out.Confidence,
+	}
+	if analysis.Domain != "" && out.Confidence >= a.opts.MinConfidence {
+		analysis.LawTypes = a.opts.LawTypes[analysis.Domain]
+	}
+	for _, s := range out.Statutes {
+		analysis.Statutes = append(analysis.Statutes, statuteReferences(s.Law, s.Article)...)
+	}
+
+	seen := make(map[string]bool)
+	add := func(q string) {
+		q = strings.TrimSpace(q)
+		if q == "" || seen[q] {
+			return
+		}
+		seen[q] = true
+		analysis.Queries = append(analysis.Queries, q)
+	}
+	for _, q := range out.Queries {
+		if len(analysis.Queries) == a.opts.MaxQueries {
+			break
+		}
+		add(q)
+	}
+	if len(history) == 0 || len(analysis.Queries) == 0 {
+		add(question)
+	}
+	return analysis, resp.Usage, nil
+}
+
+// systemPrompt 查询理解提示词
+func (a *QueryAnalyzer) systemPrompt() string {
+	var sb strings.Builder
+	sb.WriteString(`你是法律检索助手。请分析用户的最新问题，只输出一个 JSON 对象，不要添加解释：
+{"domain": "领域代码", "confidence": 0.9, "statutes": [{"law": "法律名称", "article": "第X条"}], "queries": ["检索问题"]}
+
+- domain：问题所属的法律领域，从下列代码中选择一个；confidence 为判断的把握 (0-1)。
+`)
+	for _, d := range legalDomains {
+		sb.WriteString("  - " + d.Code + "：" + d.Label + "\n")
+	}
+	sb.WriteString(`- statutes：问题中明确提到的法律与条号，如"劳动合同法第三十条"；没有提到具体条号时为空数组，不要推测。
+- queries：将问题改写为 1 至 `)
+	sb.WriteString(strconv.Itoa(a.opts.MaxQueries))
+	sb.WriteString(` 个语义完整、可独立检索的问题，使用规范的法律用语 (如"老板不给工资"改写为"用人单位拖欠劳动者劳动报酬")，
+  结合对话历史补全代词和省略的主语、法律名称、情形；保留问题中的法律名称、条文序号和关键事实；不要回答问题。`)
+	return sb.String()
+}
+
+// parseAnalysisOutput 解析模型输出，兼容 Markdown 代码块包裹与前后多余文字
+func parseAnalysisOutput(content string) (*analysisOutput, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, ErrInvalidAnalysis
+	}
+	var out analysisOutput
+	if err := json.Unmarshal([]byte(content[start:end+1]), &out); err != nil {
+		return nil, ErrInvalidAnalysis
+	}
+	return &out, nil
+}
+
+// knownDomain 校验领域代码，无法识别或为 other 时返回空
+func knownDomain(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "other" {
+		return ""
+	}
+	for _, d := range legalDomains {
+		if d.Code == code {
+			return code
+		}
+	}
+	return ""
+}
+
+// statuteReferences 将模型抽取的法律与条号规范化为引用 (条号统一为阿拉伯数字)
+// 条号兼容 "第三十条"、"三十"、"30" 等写法
+func statuteReferences(law, article string) []chunker.Reference {
+	article = strings.TrimSpace(article)
+	if article == "" {
+		return nil
+	}
+	if !strings.HasPrefix(article, "第") {
+		article = "第" + article
+	}
+	if !strings.Contains(article, "条") {
+		article += "条"
+	}
+	law = strings.Trim(strings.TrimSpace(law), "《》")
+	text := article
+	if law != "" {
+		text = "《" + law + "》" + article
+	}
+
+	matches := chunker.FindReferences(text)
+	refs := make([]chunker.Reference, 0, len(matches))
+	for _, m := range matches {
+		refs = append(refs, m.Reference)
+	}
+	return refs
+}
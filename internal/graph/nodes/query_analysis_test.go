@@ -0,0 +1,236 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+)
+
+// newAnalysisLLM 启动返回 content 的模拟模型，返回 LLM 节点与最近一次请求的用户消息
+func newAnalysisLLM(t *testing.T, content string) (*LLMNode, *string) {
+	t.Helper()
+	var prompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []client.Message `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if n := len(req.Messages); n > 0 {
+			prompt = req.Messages[n-1].Content
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+			"usage":   map[string]int{"prompt_tokens": 50, "completion_tokens": 10, "total_tokens": 60},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return NewLLMNode(client.NewLLMClient(&client.LLMConfig{BaseURL: srv.URL, Model: "test", Timeout: 5 * time.Second})), &prompt
+}
+
+func TestParseAnalysisOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // 解析出的领域，wantErr 时忽略
+		wantErr bool
+	}{
+		{name: "纯 JSON", content: `{"domain": "labor", "confidence": 0.9}`, want: "labor"},
+		{name: "Markdown 代码块", content: "```json\n{\"domain\": \"tort\", \"confidence\": 0.8, \"queries\": [\"侵权责任\"]}\n```", want: "tort"},
+		{name: "前后多余文字", content: "分析结果如下：\n{\"domain\": \"criminal\"}\n以上。", want: "criminal"},
+		{name: "没有 JSON", content: "无法判断", wantErr: true},
+		{name: "JSON 不完整", content: "```json\n{\"domain\": \"labor\",\n```", wantErr: true},
+		{name: "JSON 格式错误", content: `{"domain": labor}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := parseAnalysisOutput(tt.content)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAnalysis) {
+					t.Errorf("parseAnalysisOutput() error = %v, want ErrInvalidAnalysis", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAnalysisOutput() error = %v", err)
+			}
+			if out.Domain != tt.want {
+				t.Errorf("Domain = %q, want %q", out.Domain, tt.want)
+			}
+		})
+	}
+}
+
+func TestKnownDomain(t *testing.T) {
+	tests := map[string]string{
+		"labor":                 "labor",
+		" Labor ":               "labor",
+		"intellectual_property": "intellectual_property",
+		"other":                 "",
+		"tax":                   "",
+		"劳动":                    "",
+		"":                      "",
+	}
+	for code, want := range tests {
+		if got := knownDomain(code); got != want {
+			t.Errorf("knownDomain(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestStatuteReferences(t *testing.T) {
+	tests := []struct {
+		law, article string
+		want         []string // "法律|条号"
+	}{
+		{"劳动合同法", "第三十条", []string{"劳动合同法|30"}},
+		{"劳动合同法", "三十", []string{"劳动合同法|30"}},
+		{"劳动合同法", "30", []string{"劳动合同法|30"}},
+		{"《劳动合同法》", "第30条", []string{"劳动合同法|30"}},
+		{"民法典", "一千一百六十五", []string{"民法典|1165"}},
+		{"", "三十", []string{"|30"}},
+		{"劳动合同法", "", nil},
+		{"劳动合同法", "第三十款", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, ref := range statuteReferences(tt.law, tt.article) {
+			got = append(got, ref.Law+"|"+ref.Article)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("statuteReferences(%q, %q) = %v, want %v", tt.law, tt.article, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	const question = "老板三个月不发工资怎么办"
+	history := []ChatHistoryItem{
+		{Role: "user", Content: "我在一家公司上班"},
+		{Role: "assistant", Content: "请问有什么可以帮您"},
+	}
+	opts := QueryAnalyzerOptions{
+		MaxQueries:    2,
+		MinConfidence: 0.7,
+		LawTypes:      map[string][]string{"labor": {"labor_law"}},
+	}
+	output := func(domain string, confidence float64, queries ...string) string {
+		b, _ := json.Marshal(map[string]interface{}{
+			"domain":     domain,
+			"confidence": confidence,
+			"statutes":   []map[string]string{{"law": "劳动合同法", "article": "三十"}},
+			"queries":    queries,
+		})
+		return "```json\n" + string(b) + "\n```"
+	}
+
+	tests := []struct {
+		name         string
+		output       string
+		history      []ChatHistoryItem
+		wantDomain   string
+		wantLawTypes []string
+		wantQueries  []string
+	}{
+		{
+			name:         "置信度达到阈值时按领域过滤",
+			output:       output("labor", 0.9, "用人单位拖欠劳动者劳动报酬"),
+			wantDomain:   "labor",
+			wantLawTypes: []string{"labor_law"},
+			wantQueries:  []string{"用人单位拖欠劳动者劳动报酬", question},
+		},
+		{
+			name:        "置信度低于阈值时不过滤",
+			output:      output("labor", 0.5, "用人单位拖欠劳动者劳动报酬"),
+			wantDomain:  "labor",
+			wantQueries: []string{"用人单位拖欠劳动者劳动报酬", question},
+		},
+		{
+			name:        "无法识别的领域不过滤",
+			output:      output("tax", 0.95, "用人单位拖欠劳动者劳动报酬"),
+			wantQueries: []string{"用人单位拖欠劳动者劳动报酬", question},
+		},
+		{
+			name:         "改写问题去重并限制数量",
+			output:       output("labor", 0.9, " 拖欠工资 ", "拖欠工资", "", "解除劳动合同", "经济补偿"),
+			wantDomain:   "labor",
+			wantLawTypes: []string{"labor_law"},
+			// 无对话历史时原问题在改写问题之后参与检索
+			wantQueries: []string{"拖欠工资", "解除劳动合同", question},
+		},
+		{
+			name:         "改写问题与原问题相同",
+			output:       output("labor", 0.9, question),
+			wantDomain:   "labor",
+			wantLawTypes: []string{"labor_law"},
+			wantQueries:  []string{question},
+		},
+		{
+			name:         "有对话历史时不追加原问题",
+			output:       output("labor", 0.9, "公司拖欠劳动者三个月工资", "拖欠工资", "经济补偿"),
+			history:      history,
+			wantDomain:   "labor",
+			wantLawTypes: []string{"labor_law"},
+			wantQueries:  []string{"公司拖欠劳动者三个月工资", "拖欠工资"},
+		},
+		{
+			name:         "未给出改写问题时以原问题检索",
+			output:       output("labor", 0.9),
+			history:      history,
+			wantDomain:   "labor",
+			wantLawTypes: []string{"labor_law"},
+			wantQueries:  []string{question},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm, _ := newAnalysisLLM(t, tt.output)
+			analysis, usage, err := NewQueryAnalyzer(llm, opts).Analyze(context.Background(), question, tt.history)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if analysis.Domain != tt.wantDomain || !reflect.DeepEqual(analysis.LawTypes, tt.wantLawTypes) {
+				t.Errorf("Domain = %q, LawTypes = %v, want %q, %v", analysis.Domain, analysis.LawTypes, tt.wantDomain, tt.wantLawTypes)
+			}
+			if !reflect.DeepEqual(analysis.Queries, tt.wantQueries) {
+				t.Errorf("Queries = %q, want %q", analysis.Queries, tt.wantQueries)
+			}
+			if len(analysis.Statutes) != 1 || analysis.Statutes[0].Article != "30" {
+				t.Errorf("Statutes = %+v", analysis.Statutes)
+			}
+			if usage.TotalTokens != 60 {
+				t.Errorf("usage.TotalTokens = %d, want 60", usage.TotalTokens)
+			}
+		})
+	}
+}
+
+func TestAnalyzeHistory(t *testing.T) {
+	var history []ChatHistoryItem
+	for i := 1; i <= analysisHistoryMessages+2; i++ {
+		history = append(history, ChatHistoryItem{Role: "user", Content: fmt.Sprintf("第%d条历史消息", i)})
+	}
+	llm, prompt := newAnalysisLLM(t, `{"domain": "labor", "confidence": 0.9, "queries": ["拖欠工资"]}`)
+	if _, _, err := NewQueryAnalyzer(llm, QueryAnalyzerOptions{}).Analyze(context.Background(), "怎么办", history); err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	// 仅参考最近 analysisHistoryMessages 条历史消息
+	if strings.Contains(*prompt, "第2条历史消息") || !strings.Contains(*prompt, "第3条历史消息") || !strings.HasSuffix(*prompt, "最新问题：怎么办") {
+		t.Errorf("查询理解请求:\n%s", *prompt)
+	}
+
+	llm, _ = newAnalysisLLM(t, "无法判断")
+	_, usage, err := NewQueryAnalyzer(llm, QueryAnalyzerOptions{}).Analyze(context.Background(), "怎么办", nil)
+	if !errors.Is(err, ErrInvalidAnalysis) || usage.TotalTokens != 60 {
+		t.Errorf("Analyze() error = %v, usage = %+v, want ErrInvalidAnalysis 且返回用量", err, usage)
+	}
+}
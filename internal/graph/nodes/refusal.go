@@ -0,0 +1,156 @@
+// Package nodes 提供 Eino Graph 节点实现
+package nodes
+
+import (
+	"context"
+	"sort"
+)
+
+// 拒答原因
+const (
+	RefusalLowSimilarity = "low_similarity" // 检索结果相似度均低于阈值
+	RefusalUnverified    = "unverified"     // 检索到的条文全部未通过链上验证
+	RefusalOutOfScope    = "out_of_scope"   // 未检索到条文，或问题超出知识库范围
+)
+
+// refusalMessages 拒答时返回给用户的标准回复
+var refusalMessages = map[string]string{
+	RefusalLowSimilarity: "抱歉，知识库中未检索到与您的问题足够相关的法律条文，无法给出有可靠依据的回答。您可以补充具体情形或涉及的法律名称后重新提问。",
+	RefusalUnverified:    "抱歉，检索到的相关法律条文未能通过链上完整性验证。为避免依据可能被篡改的内容作答，本次不予回答，请稍后重试。",
+	RefusalOutOfScope:    "抱歉，您的问题超出了本系统收录的法律法规范围，无法给出有可靠依据的回答。",
+}
+
+// RefusalThresholds 拒答阈值 (系统配置 retrieval 分类)
+type RefusalThresholds struct {
+	Enabled         bool    // 是否启用拒答策略
+	MinScore        float32 // 相似度阈值，低于该值的条文不进入提示词，全部低于时拒答
+	OutOfScopeScore float32 // 最相似条文也低于该值时视为超出知识库范围
+	RequireVerified bool    // 启用链上验证时，全部条文未通过验证则拒答
+	Candidates      int     // 拒答时返回的候选条文数
+}
+
+// DefaultRefusalThresholds 默认拒答阈值，与 model.DefaultSystemConfigs 保持一致
+func DefaultRefusalThresholds() RefusalThresholds {
+	return RefusalThresholds{
+		Enabled:         true,
+		MinScore:        0.5,
+		OutOfScopeScore: 0.3,
+		RequireVerified: true,
+		Candidates:      3,
+	}
+}
+
+// RefusalThresholdProvider 拒答阈值来源，阈值可在运行时调整
+type RefusalThresholdProvider interface {
+	RefusalThresholds(ctx context.Context) RefusalThresholds
+}
+
+// Refusal 拒答结果
+type Refusal struct {
+	Reason     string
+	Message    string
+	Candidates []ChunkWithVerification // 最接近的候选条文 (按相似度降序)
+}
+
+// RefusalPolicy 拒答策略节点 (No Citation, No Answer)
+// 没有足够相关且通过验证的法条时不调用 LLM，直接返回结构化拒答
+type RefusalPolicy struct {
+	provider RefusalThresholdProvider
+}
+
+// NewRefusalPolicy 创建拒答策略节点
+// provider 为 nil 时使用默认阈值
+func NewRefusalPolicy(provider RefusalThresholdProvider) *RefusalPolicy {
+	return &RefusalPolicy{
+		provider: provider,
+	}
+}
+
+// Thresholds 读取当前拒答阈值
+func (p *RefusalPolicy) Thresholds(ctx context.Context) RefusalThresholds {
+	if p.provider == nil {
+		return DefaultRefusalThresholds()
+	}
+	return p.provider.RefusalThresholds(ctx)
+}
+
+// Screen 按相似度筛选检索结果，保持检索排序
+// 丢弃低于阈值的结果；没有结果达到阈值时返回拒答
+func (t RefusalThresholds) Screen(results []RetrievalResult) ([]RetrievalResult, *Refusal) {
+	if !t.Enabled {
+		return results, nil
+	}
+	if len(results) == 0 {
+		return nil, newRefusal(RefusalOutOfScope, nil, 0)
+	}
+
+	kept := make([]RetrievalResult, 0, len(results))
+	for _, r := range results {
+		if r.Score >= t.MinScore {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) > 0 {
+		return kept, nil
+	}
+
+	candidates := make([]ChunkWithVerification, len(results))
+	for i, r := range results {
+		candidates[i] = ChunkWithVerification{
+			ChunkID:       r.ChunkID,
+			DocumentID:    r.DocumentID,
+			Content:       r.Content,
+			Source:        r.Source,
+			ArticleNumber: r.ArticleNumber,
+			LawHierarchy:  r.LawHierarchy,
+			Score:         r.Score,
+		}
+	}
+	reason := RefusalLowSimilarity
+	if maxScore(results) < t.OutOfScopeScore {
+		reason = RefusalOutOfScope
+	}
+	return nil, newRefusal(reason, candidates, t.Candidates)
+}
+
+// CheckVerified 全部条文未通过链上验证时返回拒答
+// 未启用链上验证 (chainEnabled 为 false) 时不做要求
+func (t RefusalThresholds) CheckVerified(chunks []ChunkWithVerification, chainEnabled bool) *Refusal {
+	if !t.Enabled || !t.RequireVerified || !chainEnabled || len(chunks) == 0 {
+		return nil
+	}
+	for _, c := range chunks {
+		if c.Verified {
+			return nil
+		}
+	}
+	return newRefusal(RefusalUnverified, chunks, t.Candidates)
+}
+
+// newRefusal 生成拒答结果，候选条文按相似度降序取前 limit 条
+// 检索结果按融合或重排名次排列，与相似度顺序不一定一致
+func newRefusal(reason string, candidates []ChunkWithVerification, limit int) *Refusal {
+	candidates = append([]ChunkWithVerification(nil), candidates...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if limit >= 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return &Refusal{
+		Reason:     reason,
+		Message:    refusalMessages[reason],
+		Candidates: candidates,
+	}
+}
+
+// maxScore 返回检索结果的最高相似度
+func maxScore(results []RetrievalResult) float32 {
+	var best float32
+	for i, r := range results {
+		if i == 0 || r.Score > best {
+			best = r.Score
+		}
+	}
+	return best
+}
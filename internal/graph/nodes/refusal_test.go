@@ -0,0 +1,155 @@
+package nodes
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// justBelow 与 justAbove 返回与 f 相邻的 float32
+func justBelow(f float32) float32 { return math.Nextafter32(f, 0) }
+func justAbove(f float32) float32 { return math.Nextafter32(f, 1) }
+
+// scoredResults 按给定相似度构造检索结果，ChunkID 为 c0、c1...
+func scoredResults(scores ...float32) []RetrievalResult {
+	results := make([]RetrievalResult, len(scores))
+	for i, s := range scores {
+		results[i] = RetrievalResult{ChunkID: "c" + strconv.Itoa(i), Score: s, Rank: i + 1}
+	}
+	return results
+}
+
+func TestRefusalScreen(t *testing.T) {
+	th := DefaultRefusalThresholds()
+	tests := []struct {
+		name       string
+		scores     []float32
+		wantKept   []string
+		wantReason string
+		wantCands  []string
+	}{
+		{name: "未检索到条文", wantReason: RefusalOutOfScope, wantCands: []string{}},
+		{name: "等于相似度阈值", scores: []float32{th.MinScore}, wantKept: []string{"c0"}},
+		{name: "略高于相似度阈值", scores: []float32{justAbove(th.MinScore), 0.2}, wantKept: []string{"c0"}},
+		{
+			name:     "保持检索排序并丢弃低于阈值的条文",
+			scores:   []float32{0.6, justBelow(th.MinScore), 0.9},
+			wantKept: []string{"c0", "c2"},
+		},
+		{
+			name:       "略低于相似度阈值",
+			scores:     []float32{justBelow(th.MinScore)},
+			wantReason: RefusalLowSimilarity,
+			wantCands:  []string{"c0"},
+		},
+		{
+			name:       "最高相似度等于超出范围阈值",
+			scores:     []float32{0.1, th.OutOfScopeScore},
+			wantReason: RefusalLowSimilarity,
+			wantCands:  []string{"c1", "c0"},
+		},
+		{
+			name:       "最高相似度略低于超出范围阈值",
+			scores:     []float32{justBelow(th.OutOfScopeScore), 0.1},
+			wantReason: RefusalOutOfScope,
+			wantCands:  []string{"c0", "c1"},
+		},
+		{
+			// 检索结果按融合名次排列，候选条文按相似度降序取前 Candidates 条
+			name:       "候选条文按相似度排序",
+			scores:     []float32{0.2, 0.45, 0.1, 0.4, 0.3},
+			wantReason: RefusalLowSimilarity,
+			wantCands:  []string{"c1", "c3", "c4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, refusal := th.Screen(scoredResults(tt.scores...))
+			var keptIDs []string
+			for _, r := range kept {
+				keptIDs = append(keptIDs, r.ChunkID)
+			}
+			if !reflect.DeepEqual(keptIDs, tt.wantKept) {
+				t.Errorf("kept = %v, want %v", keptIDs, tt.wantKept)
+			}
+			if tt.wantReason == "" {
+				if refusal != nil {
+					t.Errorf("refusal = %+v, want nil", refusal)
+				}
+				return
+			}
+			if refusal == nil {
+				t.Fatalf("refusal = nil, want %s", tt.wantReason)
+			}
+			if refusal.Reason != tt.wantReason || refusal.Message != refusalMessages[tt.wantReason] {
+				t.Errorf("refusal = %s (%s), want %s", refusal.Reason, refusal.Message, tt.wantReason)
+			}
+			if got := chunkIDs(refusal.Candidates); !reflect.DeepEqual(got, tt.wantCands) {
+				t.Errorf("Candidates = %v, want %v", got, tt.wantCands)
+			}
+		})
+	}
+
+	// 未启用拒答时原样返回
+	disabled := th
+	disabled.Enabled = false
+	results := scoredResults(0.1, 0.05)
+	if kept, refusal := disabled.Screen(results); refusal != nil || !reflect.DeepEqual(kept, results) {
+		t.Errorf("未启用拒答 Screen() = %v, %+v", kept, refusal)
+	}
+}
+
+func TestRefusalCheckVerified(t *testing.T) {
+	unverified := []ChunkWithVerification{
+		{ChunkID: "c0", Score: 0.6},
+		{ChunkID: "c1", Score: 0.9},
+		{ChunkID: "c2", Score: 0.5},
+		{ChunkID: "c3", Score: 0.7},
+	}
+	oneVerified := append([]ChunkWithVerification(nil), unverified...)
+	oneVerified[2].Verified = true
+
+	th := DefaultRefusalThresholds()
+	notRequired := th
+	notRequired.RequireVerified = false
+	disabled := th
+	disabled.Enabled = false
+
+	tests := []struct {
+		name         string
+		thresholds   RefusalThresholds
+		chunks       []ChunkWithVerification
+		chainEnabled bool
+		wantCands    []string // 为 nil 时不拒答
+	}{
+		{name: "全部未验证", thresholds: th, chunks: unverified, chainEnabled: true, wantCands: []string{"c1", "c3", "c0"}},
+		{name: "至少一条已验证", thresholds: th, chunks: oneVerified, chainEnabled: true},
+		{name: "未启用链上验证", thresholds: th, chunks: unverified},
+		{name: "不要求验证", thresholds: notRequired, chunks: unverified, chainEnabled: true},
+		{name: "未启用拒答", thresholds: disabled, chunks: unverified, chainEnabled: true},
+		{name: "没有条文", thresholds: th, chainEnabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refusal := tt.thresholds.CheckVerified(tt.chunks, tt.chainEnabled)
+			if tt.wantCands == nil {
+				if refusal != nil {
+					t.Errorf("CheckVerified() = %+v, want nil", refusal)
+				}
+				return
+			}
+			if refusal == nil || refusal.Reason != RefusalUnverified || refusal.Message != refusalMessages[RefusalUnverified] {
+				t.Fatalf("CheckVerified() = %+v, want %s", refusal, RefusalUnverified)
+			}
+			if got := chunkIDs(refusal.Candidates); !reflect.DeepEqual(got, tt.wantCands) {
+				t.Errorf("Candidates = %v, want %v", got, tt.wantCands)
+			}
+		})
+	}
+
+	// 候选排序不修改传入的条文
+	if unverified[0].ChunkID != "c0" || unverified[1].ChunkID != "c1" {
+		t.Errorf("CheckVerified() 修改了传入的条文: %v", chunkIDs(unverified))
+	}
+}
@@ -0,0 +1,74 @@
+// Package nodes 提供 Eino Graph 节点实现
+package nodes
+
+import (
+	"context"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+)
+
+// Reranker 重排节点
+// 调用重排模型 (cross-encoder，如 bge-reranker) 对检索结果重新排序，并丢弃低于阈值的条文
+type Reranker struct {
+	client   *client.RerankClient
+	minScore float32
+}
+
+// NewReranker 创建重排节点
+// minScore <= 0 时不按分数筛选
+func NewReranker(rerankClient *client.RerankClient, minScore float32) *Reranker {
+	return &Reranker{
+		client:   rerankClient,
+		minScore: minScore,
+	}
+}
+
+// Rerank 按重排分数重新排序检索结果，更新 Rank 与 RerankScore
+// 问题中直接指明的条文 (MatchExact) 按检索顺序固定在最前，不参与重排排序与分数筛选，仅记录重排分数
+// 出错时返回错误，由调用方沿用检索顺序；重排响应中缺失的其他条文 (部分实现只返回前 N 条) 视为不相关
+func (r *Reranker) Rerank(ctx context.Context, query string, results []RetrievalResult) ([]RetrievalResult, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	documents := make([]string, len(results))
+	for i, res := range results {
+		documents[i] = res.Source + "\n" + res.Content
+	}
+	ranked, err := r.client.Rerank(ctx, query, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]RetrievalResult, 0, len(results))
+	pinned := make(map[int]int) // 精确命中条文在 results 中的下标 -> 在 out 中的位置
+	for i, res := range results {
+		if res.Match == MatchExact {
+			res.RerankScore = 0
+			res.Rank = len(out) + 1
+			pinned[i] = len(out)
+			out = append(out, res)
+		}
+	}
+
+	seen := make(map[int]bool, len(ranked))
+	for _, rr := range ranked {
+		if seen[rr.Index] {
+			continue
+		}
+		seen[rr.Index] = true
+		if pos, ok := pinned[rr.Index]; ok {
+			out[pos].RerankScore = rr.Score
+			continue
+		}
+		if r.minScore > 0 && rr.Score < r.minScore {
+			continue
+		}
+
+		res := results[rr.Index]
+		res.RerankScore = rr.Score
+		res.Rank = len(out) + 1
+		out = append(out, res)
+	}
+	return out, nil
+}
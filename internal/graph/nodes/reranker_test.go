@@ -0,0 +1,100 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+)
+
+// newFakeReranker 启动模拟重排接口，按 scores (文档下标 -> 分数) 返回结果，未列出的文档不返回
+func newFakeReranker(t *testing.T, scores map[int]float32, minScore float32) *Reranker {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Documents []string `json:"documents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("解析重排请求失败: %v", err)
+		}
+		type item struct {
+			Index          int     `json:"index"`
+			RelevanceScore float32 `json:"relevance_score"`
+		}
+		var results []item
+		for i := range req.Documents {
+			if s, ok := scores[i]; ok {
+				results = append(results, item{Index: i, RelevanceScore: s})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}))
+	t.Cleanup(srv.Close)
+	return NewReranker(client.NewRerankClient(&client.RerankConfig{BaseURL: srv.URL}), minScore)
+}
+
+func TestRerank(t *testing.T) {
+	results := []RetrievalResult{
+		{ChunkID: "civil-1165", Match: MatchExact, Score: 1, Rank: 1},
+		{ChunkID: "civil-1166", Match: MatchHybrid, Score: 0.8, Rank: 2},
+		{ChunkID: "civil-1167", Match: MatchVector, Score: 0.7, Rank: 3},
+		{ChunkID: "civil-1168", Match: MatchKeyword, Score: 0.6, Rank: 4},
+		{ChunkID: "civil-1003", Match: MatchExact, Score: 1, Rank: 5},
+	}
+	type ranked struct {
+		id    string
+		score float32
+	}
+	tests := []struct {
+		name     string
+		scores   map[int]float32
+		minScore float32
+		want     []ranked
+	}{
+		{
+			name:   "精确命中置顶，其余按重排分数排序",
+			scores: map[int]float32{0: 0.1, 1: 0.3, 2: 0.9, 3: 0.6, 4: 0.2},
+			want:   []ranked{{"civil-1165", 0.1}, {"civil-1003", 0.2}, {"civil-1167", 0.9}, {"civil-1168", 0.6}, {"civil-1166", 0.3}},
+		},
+		{
+			name:     "精确命中不受分数阈值影响",
+			scores:   map[int]float32{0: 0.1, 1: 0.3, 2: 0.9, 3: 0.6, 4: 0.2},
+			minScore: 0.5,
+			want:     []ranked{{"civil-1165", 0.1}, {"civil-1003", 0.2}, {"civil-1167", 0.9}, {"civil-1168", 0.6}},
+		},
+		{
+			name:     "重排响应缺失的精确命中仍保留",
+			scores:   map[int]float32{1: 0.8, 3: 0.4},
+			minScore: 0.5,
+			want:     []ranked{{"civil-1165", 0}, {"civil-1003", 0}, {"civil-1166", 0.8}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := newFakeReranker(t, tt.scores, tt.minScore).Rerank(context.Background(), "问题", results)
+			if err != nil {
+				t.Fatalf("Rerank() error = %v", err)
+			}
+			var got []ranked
+			for i, res := range out {
+				got = append(got, ranked{res.ChunkID, res.RerankScore})
+				if res.Rank != i+1 {
+					t.Errorf("%s Rank = %d, want %d", res.ChunkID, res.Rank, i+1)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rerank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 检索结果不被修改
+	if results[0].Rank != 1 || results[4].Rank != 5 || results[0].RerankScore != 0 {
+		t.Errorf("Rerank() 修改了输入: %+v", results)
+	}
+}
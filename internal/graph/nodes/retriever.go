@@ -1,34 +1,512 @@
 // Package nodes 提供 Eino Graph 节点实现
 package nodes
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/textsearch"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// 检索命中方式 (写入 RetrievalResult.Match)
+const (
+	MatchExact   = "exact"     // 问题中指明的条文，直接置顶
+	MatchVector  = "vector"    // 仅向量检索命中
+	MatchKeyword = "keyword"   // 仅全文检索 (或无法确定所属法律的条号检索) 命中
+	MatchHybrid  = "hybrid"    // 向量检索与全文检索均命中
+	MatchRef     = "reference" // 被检索到的条文引用，由引用扩展纳入
+)
+
+const (
+	// defaultRetrieveTopK 默认检索数量
+	defaultRetrieveTopK = 5
+	// defaultRRFK RRF 平滑常数 (Cormack et al. 建议值)
+	defaultRRFK = 60
+	// exactLookupLimit 按条号精确检索时每个条号最多查询的分块数 (同一条号可能出现在多部法律中)
+	exactLookupLimit = 10
+)
+
+// RetrieverOptions 检索配置
+type RetrieverOptions struct {
+	TopK       int  // 返回结果数，<= 0 时使用默认值
+	Hybrid     bool // 是否同时进行全文检索与条号精确检索，并与向量检索结果融合排序
+	CandidateK int  // 混合检索时每路检索的候选数，<= 0 时为 2 * TopK
+	RRFK       int  // RRF 平滑常数，<= 0 时使用默认值
+}
 
 // Retriever 检索节点
-// 负责调用 Milvus 检索相关 Chunks
+// 向量检索 (Milvus) 与全文检索 (PostgreSQL) 并行执行，以 Reciprocal Rank Fusion 融合排序；
+// 查询理解给出多个改写问题时，每个问题分别检索后统一融合；
+// 问题中指明条号 (如 "民法典第一千一百六十五条") 时，精确命中的条文直接置顶
 type Retriever struct {
-	// milvusClient Milvus 客户端
-	// topK         检索数量
+	embedder client.Embedder
+	store    client.VectorStore
+	docRepo  repository.DocumentRepository
+	opts     RetrieverOptions
 }
 
 // NewRetriever 创建检索节点
-func NewRetriever() *Retriever {
-	return &Retriever{}
+func NewRetriever(embedder client.Embedder, store client.VectorStore, opts RetrieverOptions) *Retriever {
+	if opts.TopK <= 0 {
+		opts.TopK = defaultRetrieveTopK
+	}
+	if opts.CandidateK <= 0 {
+		opts.CandidateK = 2 * opts.TopK
+	}
+	if opts.RRFK <= 0 {
+		opts.RRFK = defaultRRFK
+	}
+	return &Retriever{
+		embedder: embedder,
+		store:    store,
+		docRepo:  repository.NewDocumentRepository(),
+		opts:     opts,
+	}
+}
+
+// candidate 融合排序后的候选分块
+type candidate struct {
+	chunkID  string
+	match    string
+	score    float32 // 向量相似度，仅全文检索命中时为 0 (水合后补算)
+	metadata map[string]interface{}
+}
+
+// exactLookup 条号精确检索结果
+type exactLookup struct {
+	pinned    []string // 可确定所属法律的条文 (问题指明法律、问题中出现法律名称或知识库中仅一部法律有该条号)
+	ambiguous []string // 无法确定所属法律的同号条文，参与融合排序
+}
+
+// RetrieveRequest 检索请求
+type RetrieveRequest struct {
+	Queries  []string            // 检索问题 (至少一个)，第一个为主问题；各问题的检索结果融合排序
+	Statutes []chunker.Reference // 查询理解抽取的条文，与问题中识别的条号一同精确检索
+	LawTypes []string            // 限定文档法律类型 (不限制条号精确检索)，为空时不限
+	AsOf     time.Time           // 非零时仅返回在该日有效的条文
+}
+
+// Retrieve 执行检索
+// 主问题的向量检索失败时返回错误；其他问题的检索、全文检索与条号检索失败时降级为使用其余结果
+func (r *Retriever) Retrieve(ctx context.Context, req RetrieveRequest) ([]RetrievalResult, error) {
+	if len(req.Queries) == 0 {
+		return nil, nil
+	}
+	vectors, err := r.embedder.Embed(ctx, req.Queries)
+	if err != nil {
+		return nil, fmt.Errorf("问题向量化失败: %w", err)
+	}
+	if len(vectors) != len(req.Queries) {
+		return nil, fmt.Errorf("向量化返回 %d 条结果，期望 %d 条", len(vectors), len(req.Queries))
+	}
+
+	var (
+		wg          sync.WaitGroup
+		vectorHits  = make([][]client.SearchResult, len(req.Queries))
+		vectorErrs  = make([]error, len(req.Queries))
+		keywordHits = make([][]repository.ChunkSearchHit, len(req.Queries))
+		keywordErrs = make([]error, len(req.Queries))
+		exact       exactLookup
+		exactErr    error
+	)
+
+	filter := r.filter(req)
+	for i, query := range req.Queries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			vectorHits[i], vectorErrs[i] = r.store.Search(ctx, vectors[i], r.candidateK(), filter)
+		}(i)
+		if r.opts.Hybrid {
+			wg.Add(1)
+			go func(i int, query string) {
+				defer wg.Done()
+				keywordHits[i], keywordErrs[i] = r.docRepo.SearchChunks(ctx, textsearch.Query(query), r.opts.CandidateK, req.AsOf, req.LawTypes)
+			}(i, query)
+		}
+	}
+	if r.opts.Hybrid {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exact, exactErr = r.findExact(ctx, req)
+		}()
+	}
+	wg.Wait()
+
+	if vectorErrs[0] != nil {
+		return nil, vectorErrs[0]
+	}
+	for i := range req.Queries {
+		if i > 0 && vectorErrs[i] != nil {
+			logger.Warn("改写问题向量检索失败", zap.String("query", req.Queries[i]), zap.Error(vectorErrs[i]))
+		}
+		if keywordErrs[i] != nil {
+			logger.Warn("全文检索失败，仅使用向量检索结果", zap.String("query", req.Queries[i]), zap.Error(keywordErrs[i]))
+		}
+	}
+	if exactErr != nil {
+		logger.Warn("条号精确检索失败", zap.Error(exactErr))
+	}
+
+	candidates := r.fuse(vectorHits, keywordHits, exact)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	results, err := r.hydrate(ctx, candidates, req.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	r.scoreKeywordOnly(ctx, vectors, results)
+	return results, nil
+}
+
+// candidateK 每个问题向量检索的候选数
+func (r *Retriever) candidateK() int {
+	if r.opts.Hybrid {
+		return r.opts.CandidateK
+	}
+	return r.opts.TopK
+}
+
+// filter 向量检索过滤表达式: 适用日期有效且属于限定的法律类型
+func (r *Retriever) filter(req RetrieveRequest) string {
+	var inForce, lawType string
+	if !req.AsOf.IsZero() {
+		inForce = client.FilterInForce(req.AsOf)
+	}
+	if len(req.LawTypes) > 0 {
+		lawType = client.FilterIn(client.FieldLawType, req.LawTypes)
+	}
+	return client.FilterAnd(inForce, lawType)
+}
+
+// findExact 按问题中指明的条号精确检索 (仅保留 asOf 当日有效的条文)
+// 条号取自各检索问题与查询理解抽取的条文，不受法律类型限制
+func (r *Retriever) findExact(ctx context.Context, req RetrieveRequest) (exactLookup, error) {
+	var (
+		out   exactLookup
+		query = strings.Join(req.Queries, "\n")
+		asOf  = req.AsOf
+	)
+	for _, m := range exactReferences(req) {
+		chunks, err := r.docRepo.FindChunksByArticle(ctx, m.Article, exactLookupLimit)
+		if err != nil {
+			return out, err
+		}
+		if len(chunks) == 0 {
+			continue
+		}
+
+		docIDs := make([]string, 0, len(chunks))
+		for _, c := range chunks {
+			docIDs = append(docIDs, c.DocumentID)
+		}
+		docs, err := r.docRepo.FindByIDs(ctx, docIDs)
+		if err != nil {
+			return out, err
+		}
+		docByID := make(map[string]*model.Document, len(docs))
+		for i := range docs {
+			docByID[docs[i].ID] = &docs[i]
+		}
+
+		var named, rest []string
+		for _, c := range chunks {
+			doc, ok := docByID[c.DocumentID]
+			if !ok || !inForce(&c, asOf) {
+				continue
+			}
+			switch {
+			case m.Law != "":
+				// 问题指明了法律 (如《刑法》第二十条)，其他法律的同号条文不计入
+				if lawNameMatches(m.Law, doc.LawName, doc.Name) {
+					named = append(named, c.ChunkID)
+				}
+			case lawMentioned(query, doc):
+				named = append(named, c.ChunkID)
+			default:
+				rest = append(rest, c.ChunkID)
+			}
+		}
+		if len(named) == 0 && len(rest) == 1 {
+			named, rest = rest, nil
+		}
+		out.pinned = append(out.pinned, named...)
+		out.ambiguous = append(out.ambiguous, rest...)
+	}
+	return out, nil
+}
+
+// exactReferences 需要精确检索的条文引用 (按法律与条号去重)
+// 区间引用 ("第X条至第Y条") 不做精确检索
+func exactReferences(req RetrieveRequest) []chunker.Reference {
+	refs := append([]chunker.Reference(nil), req.Statutes...)
+	for _, q := range req.Queries {
+		for _, m := range chunker.FindReferences(q) {
+			refs = append(refs, m.Reference)
+		}
+	}
+
+	out := make([]chunker.Reference, 0, len(refs))
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.ToArticle != "" {
+			continue
+		}
+		key := normalizeLawName(ref.Law) + "|" + ref.Article
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, ref)
+	}
+	return out
+}
+
+// lawMentioned 问题中是否出现文档所属法律的名称 (兼容省略 "中华人民共和国" 前缀)
+func lawMentioned(query string, doc *model.Document) bool {
+	name := normalizeLawName(documentSource(doc))
+	return name != "" && strings.Contains(query, name)
+}
+
+// fuse 融合各问题各路检索结果，返回前 TopK 个候选
+// 精确命中的条文按出现顺序置顶，其余按 RRF 分数 sum(1 / (k + rank)) 降序排列；
+// 同一分块被多个问题的向量检索命中时取最高相似度
+func (r *Retriever) fuse(vectorHits [][]client.SearchResult, keywordHits [][]repository.ChunkSearchHit, exact exactLookup) []candidate {
+	inVector := make(map[string]client.SearchResult)
+	lists := make([][]string, 0, len(vectorHits)+len(keywordHits)+1)
+	for _, hits := range vectorHits {
+		ids := make([]string, len(hits))
+		for i, h := range hits {
+			if prev, ok := inVector[h.ChunkID]; !ok || h.Score > prev.Score {
+				inVector[h.ChunkID] = h
+			}
+			ids[i] = h.ChunkID
+		}
+		lists = append(lists, ids)
+	}
+	lexical := make(map[string]bool)
+	for _, hits := range keywordHits {
+		ids := make([]string, len(hits))
+		for i, h := range hits {
+			lexical[h.ChunkID] = true
+			ids[i] = h.ChunkID
+		}
+		lists = append(lists, ids)
+	}
+	for _, id := range exact.ambiguous {
+		lexical[id] = true
+	}
+	lists = append(lists, exact.ambiguous)
+
+	newCandidate := func(chunkID, match string) candidate {
+		c := candidate{chunkID: chunkID, match: match}
+		if h, ok := inVector[chunkID]; ok {
+			c.score = h.Score
+			c.metadata = h.Metadata
+		}
+		return c
+	}
+
+	out := make([]candidate, 0, r.opts.TopK)
+	seen := make(map[string]bool)
+	for _, id := range exact.pinned {
+		if len(out) == r.opts.TopK {
+			return out
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		c := newCandidate(id, MatchExact)
+		c.score = 1
+		out = append(out, c)
+	}
+
+	for _, id := range fuseRRF(r.opts.RRFK, lists...) {
+		if len(out) == r.opts.TopK {
+			break
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		_, vec := inVector[id]
+		match := MatchVector
+		switch {
+		case vec && lexical[id]:
+			match = MatchHybrid
+		case !vec:
+			match = MatchKeyword
+		}
+		out = append(out, newCandidate(id, match))
+	}
+	return out
+}
+
+// fuseRRF Reciprocal Rank Fusion，返回按融合分数降序排列的 ID (分数相同时按首次出现顺序)
+func fuseRRF(k int, lists ...[]string) []string {
+	scores := make(map[string]float64)
+	var order []string
+	for _, list := range lists {
+		for rank, id := range list {
+			if _, ok := scores[id]; !ok {
+				order = append(order, id)
+			}
+			scores[id] += 1 / float64(k+rank+1)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	return order
+}
+
+// scoreKeywordOnly 为仅全文检索命中的条文补算与问题的向量相似度 (多个问题时取最高值)
+// 拒答阈值与提示词装入顺序均基于相似度，补算失败时相似度为 0 (将被阈值筛除)
+func (r *Retriever) scoreKeywordOnly(ctx context.Context, queryVectors [][]float32, results []RetrievalResult) {
+	var (
+		indexes []int
+		texts   []string
+	)
+	for i, res := range results {
+		if res.Match == MatchKeyword {
+			indexes = append(indexes, i)
+			texts = append(texts, res.Content)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+
+	vectors, err := r.embedder.Embed(ctx, texts)
+	if err != nil || len(vectors) != len(texts) {
+		logger.Warn("全文检索结果相似度计算失败", zap.Error(err))
+		return
+	}
+	for i, idx := range indexes {
+		for _, qv := range queryVectors {
+			if score := client.CosineSimilarity(qv, vectors[i]); score > results[idx].Score {
+				results[idx].Score = score
+			}
+		}
+	}
+}
+
+// hydrate 从数据库补全分块内容与来源
+// 向量库中残留但数据库已删除的分块、以及向量库时效未同步而在 asOf 当日无效的分块会被丢弃
+func (r *Retriever) hydrate(ctx context.Context, candidates []candidate, asOf time.Time) ([]RetrievalResult, error) {
+	chunkIDs := make([]string, len(candidates))
+	for i, c := range candidates {
+		chunkIDs[i] = c.chunkID
+	}
+	chunks, err := r.docRepo.FindChunksByChunkIDs(ctx, chunkIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkByID := make(map[string]*model.DocumentChunk, len(chunks))
+	docIDs := make([]string, 0, len(chunks))
+	seenDoc := make(map[string]bool)
+	for i := range chunks {
+		chunkByID[chunks[i].ChunkID] = &chunks[i]
+		if !seenDoc[chunks[i].DocumentID] {
+			seenDoc[chunks[i].DocumentID] = true
+			docIDs = append(docIDs, chunks[i].DocumentID)
+		}
+	}
+
+	docs, err := r.docRepo.FindByIDs(ctx, docIDs)
+	if err != nil {
+		return nil, err
+	}
+	docByID := make(map[string]*model.Document, len(docs))
+	for i := range docs {
+		docByID[docs[i].ID] = &docs[i]
+	}
+
+	results := make([]RetrievalResult, 0, len(candidates))
+	for _, c := range candidates {
+		chunk, ok := chunkByID[c.chunkID]
+		if !ok || !inForce(chunk, asOf) {
+			continue
+		}
+		doc, ok := docByID[chunk.DocumentID]
+		if !ok {
+			continue
+		}
+
+		result := newRetrievalResult(chunk, doc)
+		result.Score = c.score
+		result.Rank = len(results) + 1
+		result.RetrievalRank = len(results) + 1
+		result.Match = c.match
+		result.Metadata = c.metadata
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// newRetrievalResult 由分块与所属文档构造检索结果 (不含分数与排序)
+func newRetrievalResult(chunk *model.DocumentChunk, doc *model.Document) RetrievalResult {
+	var proof []string
+	if len(chunk.MerkleProof) > 0 {
+		_ = json.Unmarshal(chunk.MerkleProof, &proof)
+	}
+	var refs []chunker.Reference
+	if len(chunk.References) > 0 {
+		_ = json.Unmarshal(chunk.References, &refs)
+	}
+	return RetrievalResult{
+		ChunkID:       chunk.ChunkID,
+		DocumentID:    chunk.DocumentID,
+		Content:       chunk.Content,
+		Source:        documentSource(doc),
+		ArticleNumber: chunk.ArticleNumber,
+		LawHierarchy:  chunk.LawHierarchy,
+		VersionID:     chunk.VersionID,
+		MerkleProof:   proof,
+		References:    refs,
+	}
 }
 
-// Retrieve 执行向量检索
-func (r *Retriever) Retrieve(ctx context.Context, query string) ([]RetrievalResult, error) {
-	// TODO: 实现向量检索逻辑
-	// 1. 将 query 转换为向量
-	// 2. 调用 Milvus 进行检索
-	// 3. 返回检索结果
-	return nil, nil
+// documentSource 文档来源名称
+func documentSource(doc *model.Document) string {
+	if doc.LawName != "" {
+		return doc.LawName
+	}
+	return doc.Name
 }
 
 // RetrievalResult 检索结果
 type RetrievalResult struct {
-	ChunkID     int64
-	Content     string
-	Score       float32
-	MerkleProof []string
-	Metadata    map[string]interface{}
+	ChunkID       string
+	DocumentID    string
+	Content       string
+	Source        string
+	ArticleNumber string
+	LawHierarchy  string
+	Score         float32 // 检索原始分数: 与问题的向量相似度 (精确命中的条文为 1)，拒答阈值基于该分数
+	RerankScore   float32 // 重排分数，未重排时为 0
+	Rank          int     // 最终排序名次 (从 1 开始)，重排后为重排名次
+	RetrievalRank int     // 检索 (融合排序) 名次
+	Match         string  // 命中方式: exact | vector | keyword | hybrid | reference
+	VersionID     int
+	MerkleProof   []string
+	References    []chunker.Reference // 条文中的交叉引用
+	Expansion     *Expansion          // 由引用扩展纳入时非空
+	Metadata      map[string]interface{}
 }
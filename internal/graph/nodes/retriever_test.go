@@ -0,0 +1,210 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// mapEmbedder 按文本返回预设向量的向量化实现，未预设的文本返回错误
+type mapEmbedder map[string][]float32
+
+func (m mapEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v, ok := m[text]
+		if !ok {
+			return nil, fmt.Errorf("未预设向量: %s", text)
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func (m mapEmbedder) Model() string {
+	return "map"
+}
+
+// stubKeywordRepo 全文检索返回固定结果的数据访问实现 (SQLite 不支持 PostgreSQL 全文检索)，其余方法使用数据库
+type stubKeywordRepo struct {
+	repository.DocumentRepository
+	hits []string
+}
+
+func (s stubKeywordRepo) SearchChunks(_ context.Context, _ string, _ int, _ time.Time, _ []string) ([]repository.ChunkSearchHit, error) {
+	hits := make([]repository.ChunkSearchHit, len(s.hits))
+	for i, id := range s.hits {
+		hits[i].ChunkID = id
+	}
+	return hits, nil
+}
+
+// retrieverChunk 测试分块及其向量 (问题向量为 (1, 0, 0)，余弦相似度即第一维)
+type retrieverChunk struct {
+	chunkID, documentID, article string
+	vector                       []float32
+}
+
+var (
+	queryVector     = []float32{1, 0, 0}
+	retrieverChunks = []retrieverChunk{
+		{"civil-1165", "doc-civil", "1165", []float32{0.9, 0.43588989, 0}},
+		{"civil-1166", "doc-civil", "1166", []float32{0.8, 0.6, 0}},
+		{"civil-1167", "doc-civil", "1167", []float32{0.7, 0.71414284, 0}},
+		{"civil-1168", "doc-civil", "1168", []float32{0.1, 0, 0.99498744}},
+		{"criminal-20", "doc-criminal", "20", []float32{0, 1, 0}},
+		{"labor-20", "doc-labor", "20", []float32{0.2, 0, 0.97979590}},
+	}
+)
+
+// newTestRetriever 使用 SQLite 内存数据库、内存向量库与固定的全文检索结果创建检索节点
+// 每路检索取前 3 个候选，返回前 5 个结果
+func newTestRetriever(t *testing.T, keywordHits []string) *Retriever {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:                 gormlogger.Default.LogMode(gormlogger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		t.Fatal(err)
+	}
+	database.Use(db)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	docs := []model.Document{
+		{ID: "doc-civil", Name: "民法典", LawName: "中华人民共和国民法典", Type: model.DocTypeTXT},
+		{ID: "doc-criminal", Name: "刑法", LawName: "中华人民共和国刑法", Type: model.DocTypeTXT},
+		{ID: "doc-labor", Name: "劳动法", LawName: "中华人民共和国劳动法", Type: model.DocTypeTXT},
+	}
+	if err := db.Create(&docs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	store := client.NewMemoryVectorStore(len(queryVector))
+	embedder := mapEmbedder{}
+	for _, c := range retrieverChunks {
+		content := "第" + c.article + "条 " + c.chunkID + " 的正文"
+		chunk := model.DocumentChunk{
+			ChunkID: c.chunkID, DocumentID: c.documentID, Content: content, ContentHash: chunker.ContentHash(content),
+			ArticleNumber: c.article, IsEmbedded: true,
+		}
+		if err := db.Create(&chunk).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Upsert(ctx, []client.VectorRecord{{ChunkID: c.chunkID, DocumentID: c.documentID, Vector: c.vector}}); err != nil {
+			t.Fatal(err)
+		}
+		embedder[content] = c.vector
+	}
+
+	r := NewRetriever(embedder, store, RetrieverOptions{TopK: 5, Hybrid: true, CandidateK: 3})
+	r.docRepo = stubKeywordRepo{DocumentRepository: r.docRepo, hits: keywordHits}
+	return r
+}
+
+func TestRetrieve(t *testing.T) {
+	type hit struct {
+		id    string
+		match string
+		score float32
+	}
+	tests := []struct {
+		name    string
+		query   string
+		keyword []string
+		want    []hit
+	}{
+		{
+			// RRF: 1167 = 1/63 + 1/61 > 1165 = 1/61 > 1166 = 1168 = 1/62 (同分按首次出现顺序)
+			name:    "向量与全文检索按 RRF 融合",
+			query:   "侵权责任如何承担",
+			keyword: []string{"civil-1167", "civil-1168"},
+			want: []hit{
+				{"civil-1167", MatchHybrid, 0.7},
+				{"civil-1165", MatchVector, 0.9},
+				{"civil-1166", MatchVector, 0.8},
+				{"civil-1168", MatchKeyword, 0.1}, // 仅全文检索命中，补算余弦相似度
+			},
+		},
+		{
+			name:    "问题指明的条文置顶且只出现一次",
+			query:   "民法典第一千一百六十八条怎么规定",
+			keyword: []string{"civil-1168", "civil-1167"},
+			want: []hit{
+				{"civil-1168", MatchExact, 1},
+				{"civil-1167", MatchHybrid, 0.7},
+				{"civil-1165", MatchVector, 0.9},
+				{"civil-1166", MatchVector, 0.8},
+			},
+		},
+		{
+			name:  "指明法律时不纳入其他法律的同号条文",
+			query: "《刑法》第二十条怎么规定",
+			want: []hit{
+				{"criminal-20", MatchExact, 1},
+				{"civil-1165", MatchVector, 0.9},
+				{"civil-1166", MatchVector, 0.8},
+				{"civil-1167", MatchVector, 0.7},
+			},
+		},
+		{
+			// 无法确定所属法律的同号条文作为一路检索结果参与融合
+			name:  "多部法律的同号条文参与融合排序",
+			query: "第二十条怎么规定",
+			want: []hit{
+				{"civil-1165", MatchVector, 0.9},
+				{"criminal-20", MatchKeyword, 0},
+				{"civil-1166", MatchVector, 0.8},
+				{"labor-20", MatchKeyword, 0.2},
+				{"civil-1167", MatchVector, 0.7},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRetriever(t, tt.keyword)
+			r.embedder.(mapEmbedder)[tt.query] = queryVector
+
+			results, err := r.Retrieve(context.Background(), RetrieveRequest{Queries: []string{tt.query}})
+			if err != nil {
+				t.Fatalf("Retrieve() error = %v", err)
+			}
+			var got []hit
+			for i, res := range results {
+				// 分数保留两位小数比较
+				got = append(got, hit{res.ChunkID, res.Match, float32(math.Round(float64(res.Score)*100) / 100)})
+				if res.Rank != i+1 || res.RetrievalRank != i+1 {
+					t.Errorf("%s Rank = %d, RetrievalRank = %d, want %d", res.ChunkID, res.Rank, res.RetrievalRank, i+1)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Retrieve() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuseRRF(t *testing.T) {
+	got := fuseRRF(60, []string{"a", "b", "c"}, []string{"c", "d"}, nil)
+	if want := []string{"c", "a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fuseRRF() = %v, want %v", got, want)
+	}
+}
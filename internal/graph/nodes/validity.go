@@ -0,0 +1,165 @@
+// Package nodes 提供 Eino Graph 节点实现
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+)
+
+// dateLayout 时效提示中的日期格式
+const dateLayout = "2006-01-02"
+
+// 时效提示类型 (写入 ValidityWarning.Type)
+const (
+	WarningRepealed        = "repealed"          // 条文已失效 (废止或被替代)
+	WarningNotYetEffective = "not_yet_effective" // 条文尚未生效
+)
+
+// ValidityWarning 用户引用的条文在适用日期不处于有效期时的提示
+type ValidityWarning struct {
+	Type          string
+	Source        string
+	ArticleNumber string
+	EffectiveDate *time.Time
+	ExpiryDate    *time.Time
+	SupersededBy  string // 替代条文说明
+	Message       string
+}
+
+// ValidityChecker 时效检查节点
+// 问题中指明法律与条号的引用 (如 "合同法第一百零七条") 在适用日期已失效或尚未生效时给出提示；
+// 检索本身只返回适用日期有效的条文，该节点仅负责提醒用户
+type ValidityChecker struct {
+	docRepo repository.DocumentRepository
+}
+
+// NewValidityChecker 创建时效检查节点
+func NewValidityChecker() *ValidityChecker {
+	return &ValidityChecker{
+		docRepo: repository.NewDocumentRepository(),
+	}
+}
+
+// Check 检查问题中引用的条文在 asOf 当日是否有效
+// 同名法律的多个版本中任一版本有效即不提示；未指明法律的条号无法确定所指条文，不提示
+func (v *ValidityChecker) Check(ctx context.Context, query string, asOf time.Time) ([]ValidityWarning, error) {
+	var warnings []ValidityWarning
+	seen := make(map[string]bool)
+	for _, m := range chunker.FindReferences(query) {
+		if m.ToArticle != "" {
+			continue
+		}
+		chunks, err := v.docRepo.FindChunksByArticle(ctx, m.Article, exactLookupLimit)
+		if err != nil {
+			return warnings, err
+		}
+		if len(chunks) == 0 {
+			continue
+		}
+
+		docIDs := make([]string, 0, len(chunks))
+		for _, c := range chunks {
+			docIDs = append(docIDs, c.DocumentID)
+		}
+		docs, err := v.docRepo.FindByIDs(ctx, docIDs)
+		if err != nil {
+			return warnings, err
+		}
+		docByID := make(map[string]*model.Document, len(docs))
+		for i := range docs {
+			docByID[docs[i].ID] = &docs[i]
+		}
+
+		var (
+			cited     *model.DocumentChunk
+			citedDoc  *model.Document
+			anyActive bool
+		)
+		for i := range chunks {
+			doc, ok := docByID[chunks[i].DocumentID]
+			if !ok {
+				continue
+			}
+			if m.Law != "" {
+				if !lawNameMatches(m.Law, doc.LawName, doc.Name) {
+					continue
+				}
+			} else if !lawMentioned(query, doc) {
+				continue
+			}
+			if inForce(&chunks[i], asOf) {
+				anyActive = true
+				break
+			}
+			// 多个版本均无效时取最近失效的版本
+			if cited == nil || laterExpiry(&chunks[i], cited) {
+				cited, citedDoc = &chunks[i], doc
+			}
+		}
+		if anyActive || cited == nil {
+			continue
+		}
+
+		key := cited.DocumentID + "|" + cited.ArticleNumber
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		warnings = append(warnings, newValidityWarning(cited, documentSource(citedDoc), asOf))
+	}
+	return warnings, nil
+}
+
+// newValidityWarning 构造时效提示
+func newValidityWarning(chunk *model.DocumentChunk, source string, asOf time.Time) ValidityWarning {
+	w := ValidityWarning{
+		Source:        source,
+		ArticleNumber: chunk.ArticleNumber,
+		EffectiveDate: chunk.EffectiveDate,
+		ExpiryDate:    chunk.ExpiryDate,
+		SupersededBy:  chunk.SupersededBy,
+	}
+	article := "《" + source + "》第" + chunk.ArticleNumber + "条"
+
+	if chunk.EffectiveDate != nil && chunk.EffectiveDate.After(asOf) {
+		w.Type = WarningNotYetEffective
+		w.Message = fmt.Sprintf("%s自 %s 起施行，在 %s 尚未生效",
+			article, chunk.EffectiveDate.Format(dateLayout), asOf.Format(dateLayout))
+		return w
+	}
+
+	w.Type = WarningRepealed
+	w.Message = fmt.Sprintf("%s已于 %s 失效", article, chunk.ExpiryDate.Format(dateLayout))
+	if chunk.SupersededBy != "" {
+		w.Message += "，已被" + chunk.SupersededBy + "替代"
+	}
+	return w
+}
+
+// inForce 条文在 date 当日是否有效，date 为零值时不限
+func inForce(chunk *model.DocumentChunk, date time.Time) bool {
+	if date.IsZero() {
+		return true
+	}
+	if chunk.EffectiveDate != nil && chunk.EffectiveDate.After(date) {
+		return false
+	}
+	return chunk.ExpiryDate == nil || chunk.ExpiryDate.After(date)
+}
+
+// laterExpiry a 是否比 b 更晚失效 (尚未生效的版本视为最晚)
+func laterExpiry(a, b *model.DocumentChunk) bool {
+	switch {
+	case a.ExpiryDate == nil:
+		return b.ExpiryDate != nil
+	case b.ExpiryDate == nil:
+		return false
+	default:
+		return a.ExpiryDate.After(*b.ExpiryDate)
+	}
+}
@@ -1,40 +1,304 @@
 // Package nodes 提供 Eino Graph 节点实现
 package nodes
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// 验证未通过原因 (写入 VerifyResult.FailReason 与 ProofRecord.FailReason)
+const (
+	reasonChainDisabled    = "未启用区块链存证"
+	reasonNotVersioned     = "分块尚未纳入知识库版本"
+	reasonVersionNotFound  = "知识库版本不存在"
+	reasonVersionLookup    = "知识库版本查询失败"
+	reasonNotAnchored      = "版本 Merkle Root 尚未上链"
+	reasonChainUnavailable = "链上 Merkle Root 查询失败"
+	reasonInvalidProof     = "Merkle 证明格式错误"
+	reasonTampered         = "分块内容或证明与链上存证不一致"
+)
+
+// RootProvider 链上 Merkle Root 查询接口，由 client.BlockchainClient 实现
+type RootProvider interface {
+	GetMerkleRoot(ctx context.Context, versionID int64) (string, error)
+}
+
+// Trigger 验证触发来源
+type Trigger struct {
+	Type model.TriggerType
+	By   string // 触发用户 ID，匿名或系统触发时为空
+}
+
+// anchoredRoot 版本的链上存证
+type anchoredRoot struct {
+	root        merkle.Hash
+	txHash      string
+	blockNumber int64
+}
 
 // Verifier 验证节点
 // 负责计算 Chunk 哈希并与链上 Merkle Root 进行比对验证
 type Verifier struct {
-	// blockchainClient 区块链客户端
+	roots       RootProvider
+	versionRepo repository.VersionRepository
+	proofRepo   repository.ProofRepository
+	auditRepo   repository.AuditRepository
+
+	// confirmed 已确认版本的链上存证 (合约不允许覆盖，可永久缓存)
+	mu        sync.RWMutex
+	confirmed map[int]*anchoredRoot
 }
 
 // NewVerifier 创建验证节点
-func NewVerifier() *Verifier {
-	return &Verifier{}
+// roots 为 nil 时 (未启用区块链) 所有分块均为未验证，但仍会记录验证过程
+func NewVerifier(roots RootProvider) *Verifier {
+	return &Verifier{
+		roots:       roots,
+		versionRepo: repository.NewVersionRepository(),
+		proofRepo:   repository.NewProofRepository(),
+		auditRepo:   repository.NewAuditRepository(),
+		confirmed:   make(map[int]*anchoredRoot),
+	}
+}
+
+// ChainEnabled 是否可查询链上 Merkle Root
+func (v *Verifier) ChainEnabled() bool {
+	return v.roots != nil
 }
 
 // Verify 验证 Chunk 链上完整性
-func (v *Verifier) Verify(ctx context.Context, chunks []VerifyInput) ([]VerifyResult, error) {
-	// TODO: 实现链上验证逻辑
-	// 1. 计算每个 Chunk 的哈希
-	// 2. 使用 Merkle Proof 计算 Root
-	// 3. 与链上 Merkle Root 比对
-	return nil, nil
+// 1. 由分块内容重新计算 ContentHash
+// 2. 沿 Merkle Proof 折叠计算 Root
+// 3. 与分块所属版本的链上 Merkle Root 比对
+// 每个分块写入一条 ProofRecord；计算出的 Root 与链上不一致时写入高危篡改审计日志。
+// 返回的错误仅表示验证记录持久化失败，验证结果本身仍然有效。
+func (v *Verifier) Verify(ctx context.Context, trigger Trigger, chunks []VerifyInput) ([]VerifyResult, error) {
+	results := make([]VerifyResult, len(chunks))
+	records := make([]model.ProofRecord, len(chunks))
+	for i := range chunks {
+		results[i] = v.verifyOne(ctx, &chunks[i])
+		records[i] = newProofRecord(&chunks[i], &results[i], trigger)
+	}
+
+	// 请求结束 (如客户端断开) 不应丢失验证记录与告警
+	persistCtx := context.WithoutCancel(ctx)
+	for i := range results {
+		if results[i].Tampered {
+			v.raiseTamper(persistCtx, &chunks[i], &results[i], trigger)
+		}
+	}
+	if err := v.proofRepo.CreateBatch(persistCtx, records); err != nil {
+		return results, fmt.Errorf("写入存证验证记录失败: %w", err)
+	}
+	return results, nil
+}
+
+// Check 验证第三方提交的分块，不写入验证记录与篡改告警
+// 外部提交的内容与证明不可信，验证不通过不代表知识库数据被篡改
+func (v *Verifier) Check(ctx context.Context, in VerifyInput) VerifyResult {
+	return v.verifyOne(ctx, &in)
+}
+
+// verifyOne 验证单个分块
+func (v *Verifier) verifyOne(ctx context.Context, in *VerifyInput) VerifyResult {
+	hash := chunker.ContentHash(in.Content)
+	res := VerifyResult{
+		ChunkID:   in.ChunkID,
+		VersionID: in.VersionID,
+		ChunkHash: hash,
+	}
+	leaf, err := merkle.ContentLeaf(hash)
+	if err != nil {
+		res.FailReason = reasonInvalidProof
+		return res
+	}
+	res.LeafHash = leaf.Hex()
+	if in.VersionID == 0 {
+		res.FailReason = reasonNotVersioned
+		return res
+	}
+
+	anchored, reason := v.anchoredRoot(ctx, in.VersionID)
+	if anchored != nil {
+		res.OnChainRoot = anchored.root.Hex()
+		res.TxHash = anchored.txHash
+		res.BlockNumber = anchored.blockNumber
+	}
+
+	// 已纳入版本的分块证明无法解析同样视为数据被改动
+	proof, err := merkle.ParseProof(in.MerkleProof)
+	if err != nil {
+		res.FailReason = reasonInvalidProof
+		res.Tampered = anchored != nil
+		return res
+	}
+	computed := merkle.ComputeRoot(proof, leaf)
+	res.ComputedRoot = computed.Hex()
+
+	switch {
+	case anchored == nil:
+		res.FailReason = reason
+	case computed == anchored.root:
+		res.Verified = true
+	default:
+		res.FailReason = reasonTampered
+		res.Tampered = true
+	}
+	return res
+}
+
+// anchoredRoot 查询版本的链上存证，查询失败时返回未通过原因
+func (v *Verifier) anchoredRoot(ctx context.Context, versionID int) (*anchoredRoot, string) {
+	v.mu.RLock()
+	cached := v.confirmed[versionID]
+	v.mu.RUnlock()
+	if cached != nil {
+		return cached, ""
+	}
+
+	if v.roots == nil {
+		return nil, reasonChainDisabled
+	}
+
+	version, err := v.versionRepo.FindByID(ctx, versionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionNotFound) {
+			return nil, reasonVersionNotFound
+		}
+		logger.Warn("查询知识库版本失败", zap.Int("version_id", versionID), zap.Error(err))
+		return nil, reasonVersionLookup
+	}
+
+	// Root 以链上合约为准，数据库中的 MerkleRoot 仅用于展示
+	rootHex, err := v.roots.GetMerkleRoot(ctx, int64(versionID))
+	if err != nil {
+		if errors.Is(err, client.ErrRootNotAnchored) {
+			return nil, reasonNotAnchored
+		}
+		logger.Warn("查询链上 Merkle Root 失败", zap.Int("version_id", versionID), zap.Error(err))
+		return nil, reasonChainUnavailable
+	}
+	root, err := merkle.ParseHash(rootHex)
+	if err != nil {
+		return nil, reasonChainUnavailable
+	}
+
+	anchored := &anchoredRoot{
+		root:        root,
+		txHash:      version.TxHash,
+		blockNumber: version.BlockNumber,
+	}
+	// 待确认版本的交易信息可能还会变化，不缓存
+	if version.Status == model.VersionStatusConfirmed {
+		v.mu.Lock()
+		v.confirmed[versionID] = anchored
+		v.mu.Unlock()
+	}
+	return anchored, ""
+}
+
+// tamperDetails 篡改告警详情 (AuditLog.Details)
+type tamperDetails struct {
+	ChunkID      string            `json:"chunkId"`
+	DocumentID   string            `json:"documentId"`
+	VersionID    int               `json:"versionId"`
+	ContentHash  string            `json:"contentHash"`
+	ComputedRoot string            `json:"computedRoot,omitempty"`
+	OnChainRoot  string            `json:"onChainRoot"`
+	Reason       string            `json:"reason"`
+	TriggerType  model.TriggerType `json:"triggerType"`
+}
+
+// raiseTamper 写入篡改审计日志，同一分块已有未处理的告警时不重复写入
+func (v *Verifier) raiseTamper(ctx context.Context, in *VerifyInput, res *VerifyResult, trigger Trigger) {
+	logger.Error("检测到分块与链上存证不一致",
+		zap.String("chunk_id", in.ChunkID),
+		zap.Int("version_id", in.VersionID),
+		zap.String("computed_root", res.ComputedRoot),
+		zap.String("on_chain_root", res.OnChainRoot),
+		zap.String("trigger", string(trigger.Type)),
+	)
+
+	exists, err := v.auditRepo.HasUnresolved(ctx, model.AuditTamper, in.ChunkID)
+	if err != nil {
+		logger.Warn("查询篡改告警失败", zap.String("chunk_id", in.ChunkID), zap.Error(err))
+	}
+	if exists {
+		return
+	}
+
+	details, _ := json.Marshal(tamperDetails{
+		ChunkID:      in.ChunkID,
+		DocumentID:   in.DocumentID,
+		VersionID:    in.VersionID,
+		ContentHash:  res.ChunkHash,
+		ComputedRoot: res.ComputedRoot,
+		OnChainRoot:  res.OnChainRoot,
+		Reason:       res.FailReason,
+		TriggerType:  trigger.Type,
+	})
+	audit := &model.AuditLog{
+		Type:       model.AuditTamper,
+		Severity:   model.SeverityHigh,
+		Message:    fmt.Sprintf("分块 %s 与知识库版本 %d 的链上存证不一致", in.ChunkID, in.VersionID),
+		Details:    details,
+		Source:     in.ChunkID,
+		SourceType: "chunk",
+		UserID:     trigger.By,
+	}
+	if err := v.auditRepo.Create(ctx, audit); err != nil {
+		logger.Error("写入篡改审计日志失败", zap.String("chunk_id", in.ChunkID), zap.Error(err))
+	}
+}
+
+// newProofRecord 由验证结果生成验证记录
+func newProofRecord(in *VerifyInput, res *VerifyResult, trigger Trigger) model.ProofRecord {
+	return model.ProofRecord{
+		ChunkID:      in.ChunkID,
+		DocumentID:   in.DocumentID,
+		ContentHash:  res.ChunkHash,
+		LeafHash:     res.LeafHash,
+		ComputedRoot: res.ComputedRoot,
+		OnChainRoot:  res.OnChainRoot,
+		Verified:     res.Verified,
+		VersionID:    in.VersionID,
+		FailReason:   res.FailReason,
+		BlockNumber:  res.BlockNumber,
+		TxHash:       res.TxHash,
+		TriggerType:  trigger.Type,
+		TriggerBy:    trigger.By,
+	}
 }
 
 // VerifyInput 验证输入
 type VerifyInput struct {
-	ChunkID     int64
+	ChunkID     string
+	DocumentID  string
+	VersionID   int // 分块所属知识库版本，0 表示尚未纳入版本
 	Content     string
 	MerkleProof []string
 }
 
 // VerifyResult 验证结果
 type VerifyResult struct {
-	ChunkID      int64
+	ChunkID      string
+	VersionID    int
 	Verified     bool
-	ChunkHash    string
+	Tampered     bool   // 计算出的 Root 与链上不一致
+	FailReason   string // 未通过原因
+	ChunkHash    string // 由内容重新计算的 ContentHash
+	LeafHash     string // 由 ContentHash 计算的 Merkle 叶子哈希
 	ComputedRoot string
 	OnChainRoot  string
 	BlockNumber  int64
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+)
+
+// State 节点间传递的执行状态
+// 每次问答创建一个 State，节点按拓扑顺序依次读写，不会并发访问
+type State struct {
+	Input Input
+	Query string    // 主检索问题，启用查询理解时为规范化改写后的问题 (重排与时效检查使用)
+	AsOf  time.Time // 适用日期 (当日零点)，仅检索在该日有效的条文
+
+	Analysis *nodes.QueryAnalysis // 查询理解结果，未启用或失败时为 nil
+	Queries  []string             // 检索问题，各问题的检索结果融合排序
+	LawTypes []string             // 检索限定的文档法律类型，为空时不限
+
+	Warnings []nodes.ValidityWarning // 问题引用的条文在适用日期无效时的提示
+
+	Thresholds nodes.RefusalThresholds
+	Results    []nodes.RetrievalResult       // 检索结果 (已按相似度阈值筛选)
+	Chunks     []nodes.ChunkWithVerification // 带验证信息的法条，提示词构建后仅保留装入提示词的法条
+	Refusal    *nodes.Refusal                // 拒答时非空，后续仅执行拒答分支
+	Prompt     *nodes.Prompt
+
+	Answer string     // LLM 流式生成的原始回答
+	Check  *CheckData // 引用一致性校验结果
+	Usage  client.TokenUsage
+	Trace  []NodeTrace
+
+	events chan<- Event // 生成阶段的事件通道，检索阶段为 nil
+}
+
+// NewState 创建执行状态
+func NewState(in Input) *State {
+	asOf := in.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	return &State{
+		Input:   in,
+		Query:   in.Question,
+		Queries: []string{in.Question},
+		AsOf:    time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location()),
+	}
+}
+
+// refused 是否已拒答
+func (s *State) refused() bool {
+	return s.Refusal != nil
+}
+
+// answering 是否进入回答分支
+func (s *State) answering() bool {
+	return s.Refusal == nil
+}
+
+// addUsage 累加 Token 用量
+func (s *State) addUsage(usage client.TokenUsage) {
+	s.Usage.PromptTokens += usage.PromptTokens
+	s.Usage.CompletionTokens += usage.CompletionTokens
+	s.Usage.TotalTokens += usage.TotalTokens
+}
+
+// send 发送事件，请求取消时返回 false
+func (s *State) send(ctx context.Context, ev Event) bool {
+	if s.events == nil {
+		return true
+	}
+	select {
+	case s.events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// traceData 生成 trace 事件数据
+func (s *State) traceData() TraceData {
+	data := TraceData{AsOfDate: s.AsOf.Format(dateLayout), Nodes: s.Trace}
+	if s.Query != s.Input.Question {
+		data.Query = s.Query
+	}
+	if s.Analysis != nil {
+		data.Domain = s.Analysis.Domain
+		data.Queries = s.Queries
+		data.LawTypes = s.LawTypes
+	}
+	if s.Prompt != nil {
+		data.PromptVersion = s.Prompt.Version
+		data.PromptTokens = s.Prompt.EstimatedTokens
+		data.DroppedChunks = s.Prompt.DroppedChunks
+		data.HistoryMessages = s.Prompt.HistoryUsed
+	}
+	return data
+}
@@ -5,21 +5,25 @@ import (
 	"github.com/lexveritas/lex-veritas-backend/internal/dto"
 	"github.com/lexveritas/lex-veritas-backend/internal/middleware"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/auth"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
 	"github.com/lexveritas/lex-veritas-backend/internal/service"
+	"go.uber.org/zap"
 )
 
 // AuthHandler 认证处理器
 type AuthHandler struct {
 	authSvc   service.AuthService
 	verifySvc service.VerificationService
+	chatSvc   service.ChatService
 }
 
 // NewAuthHandler 创建认证处理器
-func NewAuthHandler(authSvc service.AuthService, verifySvc service.VerificationService) *AuthHandler {
+func NewAuthHandler(authSvc service.AuthService, verifySvc service.VerificationService, chatSvc service.ChatService) *AuthHandler {
 	return &AuthHandler{
 		authSvc:   authSvc,
 		verifySvc: verifySvc,
+		chatSvc:   chatSvc,
 	}
 }
 
@@ -57,6 +61,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	h.claimGuestSessions(c, user.ID)
+
 	response.Success(c, dto.LoginResponse{
 		User:  user,
 		Token: tokenPair,
@@ -87,6 +93,8 @@ func (h *AuthHandler) LoginByPhone(c *gin.Context) {
 		return
 	}
 
+	h.claimGuestSessions(c, user.ID)
+
 	response.Success(c, dto.LoginResponse{
 		User:  user,
 		Token: tokenPair,
@@ -168,6 +176,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	h.claimGuestSessions(c, user.ID)
+
 	response.SuccessWithMessage(c, "注册成功", user)
 }
 
@@ -299,8 +309,39 @@ func (h *AuthHandler) OAuthCallback(c *gin.Context) {
 		return
 	}
 
-	// TODO: 验证 state
-	// TODO: 调用 authSvc.OAuthCallback
+	tokenPair, user, err := h.authSvc.OAuthCallback(c.Request.Context(), provider, code, state)
+	if err != nil {
+		if err == service.ErrOAuthNotImplemented {
+			response.NotImplemented(c, "OAuth回调处理尚未实现")
+		} else {
+			response.InternalError(c, err)
+		}
+		return
+	}
 
-	response.NotImplemented(c, "OAuth回调处理尚未实现")
+	h.claimGuestSessions(c, user.ID)
+
+	response.Success(c, dto.LoginResponse{
+		User:  user,
+		Token: tokenPair,
+	})
+}
+
+// claimGuestSessions 将匿名会话转移给刚登录的用户
+// 认领失败不影响登录结果，仅记录日志
+func (h *AuthHandler) claimGuestSessions(c *gin.Context, userID string) {
+	guestID := middleware.PeekGuestID(c)
+	if guestID == "" {
+		return
+	}
+
+	if err := h.chatSvc.ClaimGuestSessions(c.Request.Context(), guestID, userID); err != nil {
+		logger.Warn("认领匿名会话失败",
+			zap.String("guest_id", guestID),
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return
+	}
+	middleware.ClearGuestID(c)
 }
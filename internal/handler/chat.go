@@ -1,48 +1,234 @@
 package handler
 
 import (
+	"io"
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/middleware"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+	"go.uber.org/zap"
 )
 
 // ChatHandler 聊天处理器
 type ChatHandler struct {
-	// TODO: 注入依赖
+	chatSvc service.ChatService
 }
 
 // NewChatHandler 创建聊天处理器
-func NewChatHandler() *ChatHandler {
-	return &ChatHandler{}
+func NewChatHandler(chatSvc service.ChatService) *ChatHandler {
+	return &ChatHandler{
+		chatSvc: chatSvc,
+	}
 }
 
 // Chat 处理聊天请求（SSE 流式响应）
+// @Summary      智能问答
+// @Description  执行检索 → 验证 → 提示词 → LLM → 引用一致性校验流程，以 SSE 流式返回。事件类型: warning, citation, verification, token, check, usage, refusal, done, error。仅检索 asOfDate (默认当天) 有效的条文，问题引用的条文在该日已失效或尚未生效时先返回 warning；没有足够相关且通过验证的法条时不调用 LLM，依次返回 refusal (拒答原因与候选条文)、token (标准拒答回复) 与 done；check 事件给出回答实际引用的条文；其 answer 字段非空时为引用校验处理后的完整回答，应替换已流式输出的内容
+// @Tags         聊天
+// @Accept       json
+// @Produce      text/event-stream
+// @Security     Bearer
+// @Param        request body dto.ChatRequest true "聊天请求"
+// @Success      200 {string} string "SSE 事件流"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Failure      403 {object} response.Response "额度不足或匿名对话次数已用完"
+// @Failure      404 {object} response.Response "会话不存在"
+// @Router       /chat [post]
 func (h *ChatHandler) Chat(c *gin.Context) {
-	// TODO: 实现 SSE 流式聊天响应
-	response.Success(c, gin.H{
-		"message": "聊天接口尚未实现",
+	var req dto.ChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	owner := service.ChatOwner{UserID: middleware.GetUserID(c)}
+	if owner.UserID == "" {
+		owner.GuestID = middleware.GetGuestID(c)
+		// 匿名用户只允许一个会话，未指定时沿用已有会话
+		if req.SessionID == "" {
+			if guestSession := middleware.GetGuestSession(c); guestSession != nil {
+				req.SessionID = guestSession.SessionID
+			}
+		}
+	}
+
+	session, events, err := h.chatSvc.Chat(c.Request.Context(), owner, &req)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidAsOfDate:
+			response.BadRequest(c, err.Error())
+		case service.ErrChatSessionNotFound:
+			response.ErrorWithCode(c, errors.CodeChatSessionNotFound)
+		case service.ErrGuestSessionLimit:
+			response.Forbidden(c, err.Error())
+		default:
+			response.Error(c, errors.Wrap(errors.CodeLLMError, err))
+		}
+		return
+	}
+
+	if owner.GuestID != "" {
+		if err := middleware.IncrementGuestChatCount(c, session.ID); err != nil {
+			logger.Warn("更新匿名对话次数失败", zap.Error(err))
+		}
+	}
+
+	// 流式响应不受服务器写超时限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		ev, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent(string(ev.Type), ev.Data)
+		return true
 	})
 }
 
 // GetSessions 获取会话列表
+// @Summary      获取会话列表
+// @Description  按最后消息时间倒序游标分页返回当前用户 (或匿名用户) 的会话
+// @Tags         聊天
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        cursor query string false "分页游标 (上一页返回的 nextCursor)"
+// @Param        limit query int false "每页数量" default(20)
+// @Success      200 {object} response.Response{data=dto.SessionListResponse} "获取成功"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Router       /chat/sessions [get]
 func (h *ChatHandler) GetSessions(c *gin.Context) {
-	// TODO: 实现获取会话列表
-	response.Success(c, []interface{}{})
+	var req dto.SessionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.chatSvc.ListSessions(c.Request.Context(), chatOwner(c), &req)
+	if err != nil {
+		if err == service.ErrInvalidCursor {
+			response.BadRequest(c, err.Error())
+		} else {
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.SuccessCursorPage(c, resp.List, resp.NextCursor, resp.HasMore, resp.Limit)
 }
 
 // GetSession 获取会话详情
+// @Summary      获取会话详情
+// @Description  返回会话信息及全部消息，助手消息附带法条引用与链上验证信息
+// @Tags         聊天
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path string true "会话ID"
+// @Success      200 {object} response.Response{data=dto.SessionDetailResponse} "获取成功"
+// @Failure      404 {object} response.Response "会话不存在"
+// @Router       /chat/sessions/{id} [get]
 func (h *ChatHandler) GetSession(c *gin.Context) {
-	// TODO: 实现获取会话详情
-	response.Success(c, nil)
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		response.BadRequest(c, "会话ID不能为空")
+		return
+	}
+
+	resp, err := h.chatSvc.GetSession(c.Request.Context(), chatOwner(c), sessionID)
+	if err != nil {
+		if err == service.ErrChatSessionNotFound {
+			response.ErrorWithCode(c, errors.CodeChatSessionNotFound)
+		} else {
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, resp)
 }
 
 // CreateSession 创建新会话
+// @Summary      创建会话
+// @Description  创建空会话，匿名用户最多只能拥有一个会话
+// @Tags         聊天
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body dto.CreateSessionRequest false "会话信息"
+// @Success      200 {object} response.Response{data=dto.SessionResponse} "创建成功"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Failure      403 {object} response.Response "匿名会话数已达上限"
+// @Router       /chat/sessions [post]
 func (h *ChatHandler) CreateSession(c *gin.Context) {
-	// TODO: 实现创建会话
-	response.Success(c, nil)
+	var req dto.CreateSessionRequest
+	// 请求体可选
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "请求参数错误: "+err.Error())
+			return
+		}
+	}
+
+	resp, err := h.chatSvc.CreateSession(c.Request.Context(), chatOwner(c), &req)
+	if err != nil {
+		if err == service.ErrGuestSessionLimit {
+			response.Forbidden(c, err.Error())
+		} else {
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, resp)
 }
 
 // DeleteSession 删除会话
+// @Summary      删除会话
+// @Description  软删除会话，删除后不再出现在列表中
+// @Tags         聊天
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path string true "会话ID"
+// @Success      200 {object} response.Response "删除成功"
+// @Failure      404 {object} response.Response "会话不存在"
+// @Router       /chat/sessions/{id} [delete]
 func (h *ChatHandler) DeleteSession(c *gin.Context) {
-	// TODO: 实现删除会话
-	response.Success(c, nil)
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		response.BadRequest(c, "会话ID不能为空")
+		return
+	}
+
+	if err := h.chatSvc.DeleteSession(c.Request.Context(), chatOwner(c), sessionID); err != nil {
+		if err == service.ErrChatSessionNotFound {
+			response.ErrorWithCode(c, errors.CodeChatSessionNotFound)
+		} else {
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "会话已删除", nil)
+}
+
+// chatOwner 获取当前请求者 (未登录时使用匿名用户 ID)
+func chatOwner(c *gin.Context) service.ChatOwner {
+	if userID := middleware.GetUserID(c); userID != "" {
+		return service.ChatOwner{UserID: userID}
+	}
+	return service.ChatOwner{GuestID: middleware.GetOrCreateGuestID(c)}
 }
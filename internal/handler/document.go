@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/middleware"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+)
+
+// DocumentHandler 知识库文档管理处理器
+type DocumentHandler struct {
+	docSvc service.DocumentService
+}
+
+// NewDocumentHandler 创建知识库文档管理处理器
+func NewDocumentHandler(docSvc service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		docSvc: docSvc,
+	}
+}
+
+// Upload 上传文档
+// @Summary      上传文档
+// @Description  上传 PDF、DOCX、TXT 或 Markdown 文件，登记后由后台异步完成解析、分块、向量化与索引。可通过文档详情查询处理状态 (pending → processing → indexed / error)
+// @Tags         知识库
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     Bearer
+// @Param        file formData file true "文档文件 (.pdf/.docx/.txt/.md)"
+// @Param        name formData string false "文档名称，为空时使用文件名"
+// @Param        lawName formData string false "法律名称，如 民法典"
+// @Param        lawType formData string false "法律类型，如 法律、行政法规、司法解释"
+// @Param        effectiveDate formData string false "生效日期 (YYYY-MM-DD)"
+// @Param        expiryDate formData string false "失效日期 (YYYY-MM-DD)，导入已失效的历史法律时填写"
+// @Param        publishOrg formData string false "发布机关"
+// @Success      200 {object} response.Response{data=dto.DocumentResponse} "上传成功"
+// @Failure      400 {object} response.Response "请求参数错误或文档类型不支持"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/documents [post]
+func (h *DocumentHandler) Upload(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "请选择要上传的文件")
+		return
+	}
+
+	var req dto.UploadDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.docSvc.Upload(c.Request.Context(), middleware.GetUserID(c), file, &req)
+	if err != nil {
+		handleDocumentError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "文档已上传，正在后台处理", resp)
+}
+
+// RegisterURL 登记网页文档
+// @Summary      登记网页文档
+// @Description  登记法规网页 (HTML、纯文本或 PDF)，由后台异步抓取并导入，重试时重新抓取
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body dto.RegisterURLRequest true "网页文档登记请求"
+// @Success      200 {object} response.Response{data=dto.DocumentResponse} "登记成功"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/documents/url [post]
+func (h *DocumentHandler) RegisterURL(c *gin.Context) {
+	var req dto.RegisterURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.docSvc.RegisterURL(c.Request.Context(), middleware.GetUserID(c), &req)
+	if err != nil {
+		handleDocumentError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "文档已登记，正在后台处理", resp)
+}
+
+// ListDocuments 获取文档列表
+// @Summary      获取文档列表
+// @Description  分页查询知识库文档，支持按状态、类型、法律类型筛选
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        page query int false "页码" default(1)
+// @Param        pageSize query int false "每页数量" default(10)
+// @Param        status query string false "状态筛选" Enums(pending,processing,indexed,minted,error)
+// @Param        type query string false "类型筛选" Enums(pdf,docx,txt,url,markdown)
+// @Param        lawType query string false "法律类型筛选"
+// @Param        keyword query string false "关键词搜索(文档名称或法律名称)"
+// @Success      200 {object} response.Response{data=dto.DocumentListResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/documents [get]
+func (h *DocumentHandler) ListDocuments(c *gin.Context) {
+	var req dto.DocumentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.docSvc.ListDocuments(c.Request.Context(), &req)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetDocument 获取文档详情
+// @Summary      获取文档详情
+// @Description  获取文档信息与处理状态，处理失败时 processError 为失败原因
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path string true "文档ID"
+// @Success      200 {object} response.Response{data=dto.DocumentResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "文档不存在"
+// @Router       /admin/documents/{id} [get]
+func (h *DocumentHandler) GetDocument(c *gin.Context) {
+	docID := c.Param("id")
+	if docID == "" {
+		response.BadRequest(c, "文档ID不能为空")
+		return
+	}
+
+	resp, err := h.docSvc.GetDocument(c.Request.Context(), docID)
+	if err != nil {
+		handleDocumentError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RetryDocument 重试导入文档
+// @Summary      重试导入文档
+// @Description  将处理失败 (error) 的文档重新放入待处理队列
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path string true "文档ID"
+// @Success      200 {object} response.Response{data=dto.DocumentResponse} "已重新排队"
+// @Failure      400 {object} response.Response "文档状态不允许重试"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "文档不存在"
+// @Router       /admin/documents/{id}/retry [post]
+func (h *DocumentHandler) RetryDocument(c *gin.Context) {
+	docID := c.Param("id")
+	if docID == "" {
+		response.BadRequest(c, "文档ID不能为空")
+		return
+	}
+
+	resp, err := h.docSvc.RetryDocument(c.Request.Context(), docID)
+	if err != nil {
+		handleDocumentError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "文档已重新排队", resp)
+}
+
+// DeleteDocument 删除文档
+// @Summary      删除文档
+// @Description  删除文档及其分块与向量，删除后不再参与检索
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path string true "文档ID"
+// @Success      200 {object} response.Response "删除成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "文档不存在"
+// @Router       /admin/documents/{id} [delete]
+func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
+	docID := c.Param("id")
+	if docID == "" {
+		response.BadRequest(c, "文档ID不能为空")
+		return
+	}
+
+	if err := h.docSvc.DeleteDocument(c.Request.Context(), docID); err != nil {
+		handleDocumentError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "文档已删除", nil)
+}
+
+// UpdateValidity 设置文档时效
+// @Summary      设置文档时效
+// @Description  设置法律的生效、失效日期与替代文档 (如《合同法》自 2021-01-01 起失效并由《民法典》替代)，可按条号单独设置被修改或删除的条文。每次请求为全量设置，未列出的条文沿用文档的时效。问答仅检索适用日期有效的条文
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path string true "文档ID"
+// @Param        request body dto.UpdateDocumentValidityRequest true "时效设置"
+// @Success      200 {object} response.Response{data=dto.DocumentResponse} "设置成功"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "文档不存在"
+// @Router       /admin/documents/{id}/validity [put]
+func (h *DocumentHandler) UpdateValidity(c *gin.Context) {
+	docID := c.Param("id")
+	if docID == "" {
+		response.BadRequest(c, "文档ID不能为空")
+		return
+	}
+
+	var req dto.UpdateDocumentValidityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.docSvc.UpdateValidity(c.Request.Context(), docID, &req)
+	if err != nil {
+		handleDocumentError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "文档时效已更新", resp)
+}
+
+// handleDocumentError 文档服务错误响应
+func handleDocumentError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrDocumentNotFound:
+		response.ErrorWithCode(c, errors.CodeDocumentNotFound)
+	case service.ErrUnsupportedDocumentType,
+		service.ErrDocumentTooLarge,
+		service.ErrInvalidEffectiveDate,
+		service.ErrInvalidExpiryDate,
+		service.ErrInvalidValidityRange,
+		service.ErrSupersedingDocument,
+		service.ErrDocumentNotRetryable:
+		response.BadRequest(c, err.Error())
+	case service.ErrDocumentStoreFailed:
+		response.ErrorWithCode(c, errors.CodeDocumentUploadFail)
+	default:
+		response.InternalError(c, err)
+	}
+}
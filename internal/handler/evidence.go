@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+)
+
+// EvidenceHandler 问答证据包处理器
+type EvidenceHandler struct {
+	evidenceSvc service.EvidenceService
+}
+
+// NewEvidenceHandler 创建问答证据包处理器
+func NewEvidenceHandler(evidenceSvc service.EvidenceService) *EvidenceHandler {
+	return &EvidenceHandler{
+		evidenceSvc: evidenceSvc,
+	}
+}
+
+// ExportEvidence 导出回答证据包
+// @Summary      导出回答证据包
+// @Description  导出助手回答的签名证据包 (ZIP)：问题、回答、引用原文、ContentHash 与 Merkle 证明、所属版本 Root 与上链交易，以及服务端对全部内容的签名。可使用 verify-evidence 命令离线复核。未配置独立的证据包签名私钥时不提供该接口
+// @Tags         聊天
+// @Produce      application/zip
+// @Security     Bearer
+// @Param        id path string true "消息ID"
+// @Success      200 {file} file "证据包"
+// @Failure      400 {object} response.Response "不是助手回答"
+// @Failure      404 {object} response.Response "消息不存在"
+// @Router       /chat/messages/{id}/evidence [get]
+func (h *EvidenceHandler) ExportEvidence(c *gin.Context) {
+	messageID := c.Param("id")
+	if messageID == "" {
+		response.BadRequest(c, "消息ID不能为空")
+		return
+	}
+
+	file, err := h.evidenceSvc.Export(c.Request.Context(), chatOwner(c), messageID)
+	if err != nil {
+		switch err {
+		case service.ErrMessageNotFound:
+			response.ErrorWithMessage(c, errors.CodeNotFound, err.Error())
+		case service.ErrEvidenceNotAnswer:
+			response.BadRequest(c, err.Error())
+		case service.ErrEvidenceSigningDisabled:
+			response.NotImplemented(c, err.Error())
+		default:
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	c.Data(http.StatusOK, "application/zip", file.Data)
+}
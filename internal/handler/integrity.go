@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/middleware"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+)
+
+// IntegrityHandler 完整性审计处理器
+type IntegrityHandler struct {
+	integritySvc service.IntegrityService
+}
+
+// NewIntegrityHandler 创建完整性审计处理器
+func NewIntegrityHandler(integritySvc service.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{
+		integritySvc: integritySvc,
+	}
+}
+
+// RunAudit 立即执行完整性审计
+// @Summary      立即执行完整性审计
+// @Description  在后台重新验证已确认版本中的全部分块 (内容哈希 + Merkle 证明 → 链上 Root)，结果写入验证记录与审计报告。有未完成的审计时从其检查点继续
+// @Tags         完整性审计
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Success      200 {object} response.Response{data=dto.IntegrityReportResponse} "审计已开始"
+// @Failure      400 {object} response.Response "审计正在执行中"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      501 {object} response.Response "未启用区块链存证"
+// @Router       /admin/integrity/audits [post]
+func (h *IntegrityHandler) RunAudit(c *gin.Context) {
+	resp, err := h.integritySvc.RunNow(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		handleIntegrityError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "审计已开始", resp)
+}
+
+// ListReports 获取审计报告列表
+// @Summary      获取审计报告列表
+// @Description  分页查询完整性审计报告，新报告在前
+// @Tags         完整性审计
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        page query int false "页码" default(1)
+// @Param        pageSize query int false "每页数量" default(10)
+// @Success      200 {object} response.Response{data=dto.IntegrityReportListResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/integrity/audits [get]
+func (h *IntegrityHandler) ListReports(c *gin.Context) {
+	var req dto.IntegrityReportListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.integritySvc.ListReports(c.Request.Context(), &req)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetReport 获取审计报告
+// @Summary      获取审计报告
+// @Description  获取审计进度、统计与未通过的分块
+// @Tags         完整性审计
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path int true "报告ID"
+// @Success      200 {object} response.Response{data=dto.IntegrityReportResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "报告不存在"
+// @Router       /admin/integrity/audits/{id} [get]
+func (h *IntegrityHandler) GetReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	resp, err := h.integritySvc.GetReport(c.Request.Context(), id)
+	if err != nil {
+		handleIntegrityError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleIntegrityError 完整性审计服务错误响应
+func handleIntegrityError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrIntegrityReportNotFound:
+		response.ErrorWithMessage(c, errors.CodeNotFound, err.Error())
+	case service.ErrIntegrityAuditRunning:
+		response.BadRequest(c, err.Error())
+	case service.ErrIntegrityAuditDisabled:
+		response.NotImplemented(c, err.Error())
+	default:
+		response.InternalError(c, err)
+	}
+}
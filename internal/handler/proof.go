@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+)
+
+// ProofHandler 公开验证处理器 (无需登录)
+type ProofHandler struct {
+	proofSvc service.ProofService
+}
+
+// NewProofHandler 创建公开验证处理器
+func NewProofHandler(proofSvc service.ProofService) *ProofHandler {
+	return &ProofHandler{
+		proofSvc: proofSvc,
+	}
+}
+
+// VerifyCitation 验证回答引用
+// @Summary      验证回答引用
+// @Description  重新验证回答中引用的法条文本，返回文本、内容哈希、叶子哈希、Merkle 证明、链上 Root、交易哈希与区块号。响应附带算法说明，第三方可据此在链上独立复核
+// @Tags         公开验证
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "引用ID"
+// @Success      200 {object} response.Response{data=dto.CitationProofResponse} "验证完成 (以 verified 判断是否通过)"
+// @Failure      400 {object} response.Response "无效的引用ID"
+// @Failure      404 {object} response.Response "引用不存在"
+// @Router       /verify/citation/{id} [get]
+func (h *ProofHandler) VerifyCitation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "无效的引用ID")
+		return
+	}
+
+	resp, err := h.proofSvc.VerifyCitation(c.Request.Context(), id)
+	if err != nil {
+		handleProofError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// VerifyChunk 验证分块文本
+// @Summary      验证分块文本
+// @Description  提交法条原文与 Merkle 证明，验证其是否属于链上存证的知识库版本。未指定版本时按证明计算出的 Root 匹配版本
+// @Tags         公开验证
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.VerifyChunkRequest true "分块文本与证明"
+// @Success      200 {object} response.Response{data=dto.ChunkProof} "验证完成 (以 verified 判断是否通过)"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Router       /verify/chunk [post]
+func (h *ProofHandler) VerifyChunk(c *gin.Context) {
+	var req dto.VerifyChunkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.proofSvc.VerifyChunk(c.Request.Context(), &req)
+	if err != nil {
+		handleProofError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// VerifyVersion 验证知识库版本
+// @Summary      验证知识库版本
+// @Description  比对知识库版本记录的 Merkle Root 与链上存证，返回交易哈希、区块号与合约信息
+// @Tags         公开验证
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "版本ID"
+// @Success      200 {object} response.Response{data=dto.VersionProofResponse} "验证完成 (以 verified 判断是否通过)"
+// @Failure      400 {object} response.Response "无效的版本ID"
+// @Failure      404 {object} response.Response "版本不存在"
+// @Router       /verify/version/{id} [get]
+func (h *ProofHandler) VerifyVersion(c *gin.Context) {
+	id, ok := versionID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.proofSvc.VerifyVersion(c.Request.Context(), id)
+	if err != nil {
+		handleProofError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleProofError 公开验证服务错误响应
+func handleProofError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrCitationNotFound,
+		service.ErrVersionNotFound:
+		response.ErrorWithMessage(c, errors.CodeNotFound, err.Error())
+	case service.ErrInvalidProof:
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err)
+	}
+}
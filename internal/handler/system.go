@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/middleware"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+)
+
+// SystemConfigHandler 系统配置处理器
+type SystemConfigHandler struct {
+	configSvc service.SystemConfigService
+}
+
+// NewSystemConfigHandler 创建系统配置处理器
+func NewSystemConfigHandler(configSvc service.SystemConfigService) *SystemConfigHandler {
+	return &SystemConfigHandler{
+		configSvc: configSvc,
+	}
+}
+
+// ListConfigs 获取系统配置列表
+// @Summary      获取系统配置列表
+// @Description  按分类列出运行时系统配置，尚未修改过的配置项返回默认值
+// @Tags         系统配置
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        category query string false "配置分类" Enums(retrieval,prompt)
+// @Success      200 {object} response.Response{data=[]dto.SystemConfigResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/configs [get]
+func (h *SystemConfigHandler) ListConfigs(c *gin.Context) {
+	var req dto.SystemConfigListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	list, err := h.configSvc.List(c.Request.Context(), req.Category)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// UpdateConfig 更新系统配置
+// @Summary      更新系统配置
+// @Description  更新配置值，值需符合配置类型 (int/float/bool/json)。多实例部署时其他实例在 30 秒内生效
+// @Tags         系统配置
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        key path string true "配置键" example(retrieval.min_score)
+// @Param        request body dto.UpdateSystemConfigRequest true "配置值"
+// @Success      200 {object} response.Response{data=dto.SystemConfigResponse} "更新成功"
+// @Failure      400 {object} response.Response "配置值与类型不匹配"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "配置项不存在"
+// @Router       /admin/configs/{key} [put]
+func (h *SystemConfigHandler) UpdateConfig(c *gin.Context) {
+	key := c.Param("key")
+	if key == "" {
+		response.BadRequest(c, "配置键不能为空")
+		return
+	}
+
+	var req dto.UpdateSystemConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.configSvc.Update(c.Request.Context(), key, &req, middleware.GetUserID(c))
+	if err != nil {
+		switch err {
+		case service.ErrSystemConfigNotFound:
+			response.ErrorWithMessage(c, errors.CodeNotFound, err.Error())
+		case service.ErrInvalidConfigValue, service.ErrUnknownPromptVersion:
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", resp)
+}
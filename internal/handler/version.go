@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/errors"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
+	"github.com/lexveritas/lex-veritas-backend/internal/service"
+)
+
+// VersionHandler 知识库版本处理器
+type VersionHandler struct {
+	versionSvc service.VersionService
+}
+
+// NewVersionHandler 创建知识库版本处理器
+func NewVersionHandler(versionSvc service.VersionService) *VersionHandler {
+	return &VersionHandler{
+		versionSvc: versionSvc,
+	}
+}
+
+// CreateVersion 创建知识库版本
+// @Summary      创建知识库版本
+// @Description  冻结当前所有已索引文档的分块集合，计算 Merkle Root 与每个分块的 Merkle 证明。新版本为 pending 状态，上链确认后分块证明才对外生效
+// @Tags         知识库版本
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body dto.CreateVersionRequest false "版本描述"
+// @Success      200 {object} response.Response{data=dto.VersionResponse} "创建成功"
+// @Failure      400 {object} response.Response "没有已索引的文档或内容未变化"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/versions [post]
+func (h *VersionHandler) CreateVersion(c *gin.Context) {
+	var req dto.CreateVersionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "请求参数错误: "+err.Error())
+			return
+		}
+	}
+
+	resp, err := h.versionSvc.CreateVersion(c.Request.Context(), &req)
+	if err != nil {
+		handleVersionError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "版本已创建", resp)
+}
+
+// ListVersions 获取版本列表
+// @Summary      获取版本列表
+// @Description  分页查询知识库版本，新版本在前
+// @Tags         知识库版本
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        page query int false "页码" default(1)
+// @Param        pageSize query int false "每页数量" default(10)
+// @Param        status query string false "状态筛选" Enums(pending,confirmed)
+// @Success      200 {object} response.Response{data=dto.VersionListResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Router       /admin/versions [get]
+func (h *VersionHandler) ListVersions(c *gin.Context) {
+	var req dto.VersionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.versionSvc.ListVersions(c.Request.Context(), &req)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetVersion 获取版本详情
+// @Summary      获取版本详情
+// @Description  获取版本信息及其包含的文档
+// @Tags         知识库版本
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path int true "版本ID"
+// @Success      200 {object} response.Response{data=dto.VersionDetailResponse} "获取成功"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "版本不存在"
+// @Router       /admin/versions/{id} [get]
+func (h *VersionHandler) GetVersion(c *gin.Context) {
+	id, ok := versionID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.versionSvc.GetVersion(c.Request.Context(), id)
+	if err != nil {
+		handleVersionError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ConfirmVersion 确认版本上链
+// @Summary      确认版本上链
+// @Description  记录版本 Merkle Root 的上链交易，版本变为 confirmed，其文档标记为已存证 (minted)。启用区块链时核对交易回执中的存证事件与合约 getRoot，二者均须等于版本 Merkle Root，区块号以回执为准；未启用区块链时仅登记交易信息
+// @Tags         知识库版本
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path int true "版本ID"
+// @Param        request body dto.ConfirmVersionRequest true "上链交易信息"
+// @Success      200 {object} response.Response{data=dto.VersionResponse} "确认成功"
+// @Failure      400 {object} response.Response "请求参数错误、版本不是待确认状态或上链交易无效"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "版本不存在"
+// @Failure      502 {object} response.Response "链上 Merkle Root 与版本不一致或链上查询失败"
+// @Router       /admin/versions/{id}/confirm [post]
+func (h *VersionHandler) ConfirmVersion(c *gin.Context) {
+	id, ok := versionID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ConfirmVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.versionSvc.ConfirmVersion(c.Request.Context(), id, &req)
+	if err != nil {
+		handleVersionError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "版本已确认", resp)
+}
+
+// AnchorVersion 提交版本上链
+// @Summary      提交版本上链
+// @Description  将版本 Merkle Root 写入链上存证合约。交易发出后立即返回 (版本仍为 pending 并带 txHash)，后台等待回执达到确认数后版本自动变为 confirmed；链上已存证该版本时直接确认
+// @Tags         知识库版本
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id path int true "版本ID"
+// @Success      200 {object} response.Response{data=dto.VersionResponse} "交易已提交"
+// @Failure      400 {object} response.Response "版本不是待确认状态"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "版本不存在"
+// @Failure      501 {object} response.Response "未启用区块链存证"
+// @Failure      502 {object} response.Response "区块链节点错误或链上存证不一致"
+// @Router       /admin/versions/{id}/anchor [post]
+func (h *VersionHandler) AnchorVersion(c *gin.Context) {
+	id, ok := versionID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.versionSvc.AnchorVersion(c.Request.Context(), id)
+	if err != nil {
+		handleVersionError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "上链交易已提交", resp)
+}
+
+// DiffVersions 对比版本
+// @Summary      对比版本
+// @Description  按分块对比两个版本，返回新增、删除、修改的分块与未变化的分块数
+// @Tags         知识库版本
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        from query int true "基准版本ID"
+// @Param        to query int true "目标版本ID"
+// @Success      200 {object} response.Response{data=dto.VersionDiffResponse} "对比成功"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Failure      401 {object} response.Response "未授权"
+// @Failure      403 {object} response.Response "权限不足"
+// @Failure      404 {object} response.Response "版本不存在"
+// @Router       /admin/versions/diff [get]
+func (h *VersionHandler) DiffVersions(c *gin.Context) {
+	var req dto.VersionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	resp, err := h.versionSvc.DiffVersions(c.Request.Context(), &req)
+	if err != nil {
+		handleVersionError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// versionID 解析路径中的版本 ID，无效时直接响应错误
+func versionID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "无效的版本ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// handleVersionError 版本服务错误响应
+func handleVersionError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrVersionNotFound:
+		response.ErrorWithMessage(c, errors.CodeNotFound, err.Error())
+	case service.ErrVersionNotPending,
+		service.ErrVersionUnchanged,
+		service.ErrNoIndexedChunks,
+		service.ErrAnchorTxInvalid:
+		response.BadRequest(c, err.Error())
+	case service.ErrBlockchainDisabled:
+		response.NotImplemented(c, err.Error())
+	case service.ErrAnchorRootMismatch,
+		service.ErrAnchorFailed,
+		service.ErrChainQueryFailed:
+		response.ErrorWithMessage(c, errors.CodeChainError, err.Error())
+	default:
+		response.InternalError(c, err)
+	}
+}
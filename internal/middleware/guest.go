@@ -80,6 +80,20 @@ func GetOrCreateGuestID(c *gin.Context) string {
 	return guestID
 }
 
+// PeekGuestID 读取匿名用户 Cookie (不存在时不创建)
+func PeekGuestID(c *gin.Context) string {
+	guestID, err := c.Cookie(guestCookieName)
+	if err != nil {
+		return ""
+	}
+	return guestID
+}
+
+// ClearGuestID 清除匿名用户 Cookie
+func ClearGuestID(c *gin.Context) {
+	c.SetCookie(guestCookieName, "", -1, "/", "", false, true)
+}
+
 // GetGuestID 从上下文获取匿名用户 ID
 func GetGuestID(c *gin.Context) string {
 	if id, exists := c.Get("guestId"); exists {
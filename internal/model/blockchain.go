@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // KnowledgeVersion 知识库版本
 type KnowledgeVersion struct {
@@ -18,7 +22,7 @@ type KnowledgeVersion struct {
 	BlockNumber int64  `json:"blockNumber,omitempty"`
 
 	// 状态
-	Status string `json:"status" gorm:"type:varchar(20);default:'pending'"`
+	Status VersionStatus `json:"status" gorm:"type:varchar(20);default:'pending'"`
 
 	// 关联的文档
 	Documents []Document `json:"-" gorm:"many2many:version_documents;"`
@@ -27,6 +31,24 @@ type KnowledgeVersion struct {
 	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
 }
 
+// VersionChunk 版本分块快照
+// 冻结版本创建时的分块集合、叶子顺序与证明，分块后续重新导入也不影响历史版本的验证与对比
+type VersionChunk struct {
+	ID        int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	VersionID int    `json:"versionId" gorm:"uniqueIndex:idx_version_chunk;not null"`
+	ChunkID   string `json:"chunkId" gorm:"type:varchar(64);uniqueIndex:idx_version_chunk;not null"`
+
+	DocumentID    string `json:"documentId" gorm:"type:varchar(36);index;not null"`
+	ArticleNumber string `json:"articleNumber,omitempty" gorm:"type:varchar(32)"`
+	ContentHash   string `json:"contentHash" gorm:"type:varchar(64);not null"`
+
+	// Merkle 验证数据
+	MerkleIndex int            `json:"merkleIndex"`
+	MerkleProof datatypes.JSON `json:"merkleProof,omitempty" gorm:"type:jsonb"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
 // ProofRecord 存证验证记录
 type ProofRecord struct {
 	ID int64 `json:"id" gorm:"primaryKey;autoIncrement"`
@@ -36,21 +58,53 @@ type ProofRecord struct {
 	DocumentID string `json:"documentId,omitempty" gorm:"type:varchar(36);index"`
 
 	// 验证数据
-	LeafHash     string `json:"leafHash" gorm:"type:varchar(64);not null"`
+	ContentHash  string `json:"contentHash" gorm:"type:varchar(64)"`
+	LeafHash     string `json:"leafHash" gorm:"type:varchar(66);not null"` // Merkle 叶子 (keccak256)，与 /verify 返回一致
 	ComputedRoot string `json:"computedRoot" gorm:"type:varchar(66)"`
 	OnChainRoot  string `json:"onChainRoot" gorm:"type:varchar(66)"`
 
 	// 验证结果
-	Verified  bool `json:"verified"`
-	VersionID int  `json:"versionId" gorm:"index"`
+	Verified   bool   `json:"verified"`
+	VersionID  int    `json:"versionId" gorm:"index"`
+	FailReason string `json:"failReason,omitempty" gorm:"type:varchar(100)"` // 未通过原因 (内容被篡改、版本未上链等)
 
 	// 链上信息
 	BlockNumber int64  `json:"blockNumber,omitempty"`
 	TxHash      string `json:"txHash,omitempty" gorm:"type:varchar(66)"`
 
 	// 触发来源
-	TriggerType string `json:"triggerType" gorm:"type:varchar(20)"`
-	TriggerBy   string `json:"triggerBy,omitempty" gorm:"type:varchar(36)"`
+	TriggerType TriggerType `json:"triggerType" gorm:"type:varchar(20);index"`
+	TriggerBy   string      `json:"triggerBy,omitempty" gorm:"type:varchar(36)"`
 
 	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
 }
+
+// IntegrityReport 全量完整性审计报告
+// 同时作为审计进度检查点：未完成的报告在下次执行时从 Cursor 之后继续
+type IntegrityReport struct {
+	ID     int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Status IntegrityStatus `json:"status" gorm:"type:varchar(20);index;default:'running'"`
+
+	// 审计范围 (开始时统计)
+	TotalChunks  int64 `json:"totalChunks"`
+	VersionCount int   `json:"versionCount"`
+
+	// 进度检查点: 已验证到的 DocumentChunk.ID
+	Cursor int64 `json:"cursor"`
+
+	// 验证统计
+	Checked     int64          `json:"checked"`
+	Verified    int64          `json:"verified"`
+	Tampered    int64          `json:"tampered"`
+	Unverified  int64          `json:"unverified"`                              // 无法验证 (版本未上链、链上查询失败等)
+	FailReasons datatypes.JSON `json:"failReasons,omitempty" gorm:"type:jsonb"` // 未通过原因 → 分块数
+	Findings    datatypes.JSON `json:"findings,omitempty" gorm:"type:jsonb"`    // 未通过的分块 (数量有上限)
+
+	// 执行信息
+	TriggeredBy string     `json:"triggeredBy,omitempty" gorm:"type:varchar(36)"` // 手动触发的管理员，定时触发为空
+	Resumes     int        `json:"resumes"`                                       // 中断后继续执行的次数
+	LastError   string     `json:"lastError,omitempty" gorm:"type:text"`
+	StartedAt   time.Time  `json:"startedAt" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
@@ -63,6 +63,7 @@ type MessageCitation struct {
 
 	// 区块链验证信息
 	ChunkHash      string     `json:"chunkHash" gorm:"type:varchar(64)"`
+	VersionID      int        `json:"versionId,omitempty" gorm:"index"` // 验证时分块所属的知识库版本 (定位冻结的 Merkle 证明)
 	VerificationID string     `json:"verificationId,omitempty" gorm:"type:varchar(66)"`
 	BlockNumber    int64      `json:"blockNumber,omitempty"`
 	Verified       bool       `json:"verified" gorm:"default:false"`
@@ -25,9 +25,14 @@ type Document struct {
 	EffectiveDate *time.Time `json:"effectiveDate,omitempty"`
 	PublishOrg    string     `json:"publishOrg,omitempty" gorm:"type:varchar(100)"`
 
+	// 时效性: 失效日期当日起不再有效，为空表示现行有效
+	ExpiryDate   *time.Time `json:"expiryDate,omitempty"`
+	SupersededBy string     `json:"supersededBy,omitempty" gorm:"type:varchar(36);index"` // 替代本法的文档 ID
+
 	// 处理状态
 	Status       DocumentStatus `json:"status" gorm:"type:varchar(20);default:'pending'"`
 	ProcessError string         `json:"processError,omitempty" gorm:"type:text"`
+	HeartbeatAt  *time.Time     `json:"-"` // 导入 Worker 最近一次续期处理中状态的时间，超过租约未续期视为中断
 
 	// 分块统计
 	ChunkCount int `json:"chunkCount" gorm:"default:0"`
@@ -58,11 +63,19 @@ type DocumentChunk struct {
 	Content     string `json:"content" gorm:"type:text;not null"`
 	ContentHash string `json:"contentHash" gorm:"type:varchar(64);index;not null"`
 
+	// 全文检索分词 (textsearch.Document)，GIN 索引由迁移工具创建
+	SearchText string `json:"-" gorm:"type:text"`
+
 	// 法律结构
 	ChunkOrder    int    `json:"chunkOrder"`
 	LawHierarchy  string `json:"lawHierarchy,omitempty" gorm:"type:varchar(500)"`
 	ArticleNumber string `json:"articleNumber,omitempty" gorm:"type:varchar(32)"`
 
+	// 条文时效 (导入时取自文档，可按条号单独设置)，为空表示不限
+	EffectiveDate *time.Time `json:"effectiveDate,omitempty" gorm:"index"`
+	ExpiryDate    *time.Time `json:"expiryDate,omitempty" gorm:"index"`
+	SupersededBy  string     `json:"supersededBy,omitempty" gorm:"type:varchar(200)"` // 替代条文说明，如 "《民法典》第五百七十七条"
+
 	// 引用关系
 	References datatypes.JSON `json:"references,omitempty" gorm:"type:jsonb"`
 
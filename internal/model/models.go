@@ -13,7 +13,9 @@ func AllModels() []any {
 		&Document{},
 		&DocumentChunk{},
 		&KnowledgeVersion{},
+		&VersionChunk{},
 		&ProofRecord{},
+		&IntegrityReport{},
 		&AuditLog{},
 		&SystemConfig{},
 	}
@@ -47,3 +47,55 @@ type SystemConfig struct {
 	UpdatedBy   string    `json:"updatedBy,omitempty" gorm:"type:varchar(36)"`
 	UpdatedAt   time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
 }
+
+// 检索分类配置项
+const (
+	ConfigRefusalEnabled    = "retrieval.refusal_enabled"
+	ConfigMinScore          = "retrieval.min_score"
+	ConfigOutOfScopeScore   = "retrieval.out_of_scope_score"
+	ConfigRequireVerified   = "retrieval.require_verified"
+	ConfigRefusalCandidates = "retrieval.refusal_candidates"
+)
+
+// 提示词分类配置项
+const (
+	ConfigPromptVersion     = "prompt.version"
+	ConfigPromptTokenBudget = "prompt.token_budget"
+	ConfigPromptHistory     = "prompt.history_max_tokens"
+)
+
+// DefaultSystemConfigs 返回系统配置默认值，迁移时写入尚不存在的配置项
+func DefaultSystemConfigs() []SystemConfig {
+	return []SystemConfig{
+		{Key: ConfigRefusalEnabled, Value: "true", Type: ConfigTypeBool, Category: ConfigCategoryRetrieval,
+			Description: "是否启用拒答策略 (No Citation, No Answer)：没有足够相关且通过验证的法条时不调用 LLM"},
+		{Key: ConfigMinScore, Value: "0.5", Type: ConfigTypeFloat, Category: ConfigCategoryRetrieval,
+			Description: "相似度阈值 (余弦相似度)，低于该值的条文不进入提示词；全部低于该值时拒答 (low_similarity)"},
+		{Key: ConfigOutOfScopeScore, Value: "0.3", Type: ConfigTypeFloat, Category: ConfigCategoryRetrieval,
+			Description: "超出范围阈值，最相似条文也低于该值时视为问题超出知识库范围 (out_of_scope)"},
+		{Key: ConfigRequireVerified, Value: "true", Type: ConfigTypeBool, Category: ConfigCategoryRetrieval,
+			Description: "启用链上验证时，检索到的条文全部未通过验证则拒答 (unverified)"},
+		{Key: ConfigRefusalCandidates, Value: "3", Type: ConfigTypeInt, Category: ConfigCategoryRetrieval,
+			Description: "拒答时返回的最接近候选条文数"},
+		{Key: ConfigPromptVersion, Value: "legal_qa_v3", Type: ConfigTypeString, Category: ConfigCategoryPrompt,
+			Description: "系统提示词模板版本 (internal/graph/nodes/prompts 下的文件名)，记录在每条回答的元数据中"},
+		{Key: ConfigPromptTokenBudget, Value: "6000", Type: ConfigTypeInt, Category: ConfigCategoryPrompt,
+			Description: "发送给 LLM 的消息总 Token 预算 (估算)，超出时按相似度从低到高丢弃参考法条；0 表示不限制"},
+		{Key: ConfigPromptHistory, Value: "1500", Type: ConfigTypeInt, Category: ConfigCategoryPrompt,
+			Description: "对话历史最多占用的 Token 数，从最近一条起保留"},
+	}
+}
+
+// SupersededDefault 已被替换的系统配置默认值
+type SupersededDefault struct {
+	Key   string
+	Value string // 旧默认值
+}
+
+// SupersededDefaults 返回已被替换的默认值，迁移时仍为旧默认值且未经管理员修改的配置项更新为当前默认值
+func SupersededDefaults() []SupersededDefault {
+	return []SupersededDefault{
+		// legal_qa_v3 起提示词包含适用日期与条文时效提示
+		{Key: ConfigPromptVersion, Value: "legal_qa_v2"},
+	}
+}
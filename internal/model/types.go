@@ -53,6 +53,31 @@ const (
 	DocStatusError      DocumentStatus = "error"
 )
 
+// VersionStatus 知识库版本状态
+type VersionStatus string
+
+const (
+	VersionStatusPending   VersionStatus = "pending"   // 已生成快照，等待上链
+	VersionStatusConfirmed VersionStatus = "confirmed" // Merkle Root 已上链确认
+)
+
+// TriggerType 存证验证触发来源
+type TriggerType string
+
+const (
+	TriggerQA        TriggerType = "qa"        // 问答检索时自动验证引用分块
+	TriggerManual    TriggerType = "manual"    // 用户或第三方手动验证
+	TriggerScheduled TriggerType = "scheduled" // 定时全量完整性审计
+)
+
+// IntegrityStatus 完整性审计执行状态
+type IntegrityStatus string
+
+const (
+	IntegrityRunning   IntegrityStatus = "running"   // 执行中或被中断，下次执行从检查点继续
+	IntegrityCompleted IntegrityStatus = "completed" // 已遍历全部分块
+)
+
 // AuditType 审计类型
 type AuditType string
 
@@ -83,3 +108,18 @@ const (
 	AuditInvestigating AuditStatus = "investigating"
 	AuditResolved      AuditStatus = "resolved"
 )
+
+// 系统配置分类 (SystemConfig.Category)
+const (
+	ConfigCategoryRetrieval = "retrieval" // 检索与拒答策略
+	ConfigCategoryPrompt    = "prompt"    // 提示词模板与 Token 预算
+)
+
+// 系统配置值类型 (SystemConfig.Type)
+const (
+	ConfigTypeString = "string"
+	ConfigTypeInt    = "int"
+	ConfigTypeFloat  = "float"
+	ConfigTypeBool   = "bool"
+	ConfigTypeJSON   = "json"
+)
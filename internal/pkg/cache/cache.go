@@ -56,6 +56,12 @@ func connect(cfg *config.RedisConfig) error {
 	return nil
 }
 
+// Use 使用已创建的客户端代替 Init (如测试使用的内存 Redis)
+func Use(c *redis.Client) {
+	once.Do(func() {})
+	client = c
+}
+
 // Client 获取 Redis 客户端
 func Client() *redis.Client {
 	return client
@@ -64,6 +64,14 @@ const (
 	// 值: 尝试次数
 	// TTL: 5 minutes
 	KeyVerificationAttempts = "verify:attempts:"
+
+	// ========== 后台任务 ==========
+
+	// KeyLockIntegrityAudit 全量完整性审计分布式锁 (多实例部署时仅一个实例执行)
+	// 格式: lock:audit:integrity
+	// 值: 持有者随机令牌
+	// TTL: audit.lock_ttl，执行期间定期续期
+	KeyLockIntegrityAudit = "lock:audit:integrity"
 )
 
 // ============================================================================
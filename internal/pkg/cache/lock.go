@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrLockNotAcquired = errors.New("锁已被其他实例持有")
+	ErrLockLost        = errors.New("锁已过期或被其他实例获取")
+	errNotInitialized  = errors.New("Redis 未初始化")
+)
+
+// refreshScript 仅当锁仍由当前持有者持有时续期
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// unlockScript 仅当锁仍由当前持有者持有时删除
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Lock Redis 分布式锁 (SET NX PX + 随机令牌)
+// 持有者需在 TTL 内调用 Refresh 续期，进程崩溃时锁在 TTL 后自动释放
+type Lock struct {
+	key   string
+	token string
+	ttl   time.Duration
+}
+
+// TryLock 尝试获取锁，已被持有时返回 ErrLockNotAcquired
+func TryLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if client == nil {
+		return nil, errNotInitialized
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	l := &Lock{key: key, token: hex.EncodeToString(buf), ttl: ttl}
+
+	ok, err := client.SetNX(ctx, key, l.token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLockNotAcquired
+	}
+	return l, nil
+}
+
+// TTL 返回锁的有效期
+func (l *Lock) TTL() time.Duration {
+	return l.ttl
+}
+
+// Refresh 续期锁，锁已丢失时返回 ErrLockLost
+func (l *Lock) Refresh(ctx context.Context) error {
+	n, err := refreshScript.Run(ctx, client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Unlock 释放锁，锁已不属于当前持有者时忽略
+func (l *Lock) Unlock(ctx context.Context) error {
+	return unlockScript.Run(ctx, client, []string{l.key}, l.token).Err()
+}
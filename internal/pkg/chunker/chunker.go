@@ -0,0 +1,299 @@
+// Package chunker 提供中文法律条文的结构化分块
+//
+// 以 "条" 为原子分块单位，识别 编/分编/章/节 层级、款与项，
+// 并提取条文中的交叉引用。非法规文本按段落合并分块。
+package chunker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/textsearch"
+	"gorm.io/datatypes"
+)
+
+// defaultMaxRunes 非法规文本单个分块的最大字符数
+const defaultMaxRunes = 800
+
+// 层级标题级别
+const (
+	levelPart    = iota // 编
+	levelSubpart        // 分编
+	levelChapter        // 章
+	levelSection        // 节
+	levelCount
+)
+
+var (
+	// headingPattern 层级标题，如 "第一编　总则"、"第三章 民事法律行为"
+	headingPattern = regexp.MustCompile(`^第([` + numeralChars + `]+)(分编|编|章|节)(?:[\s　]+(.*))?$`)
+	// articlePattern 条文开头，如 "第一千一百六十五条　行为人因过错..."
+	articlePattern = regexp.MustCompile(`^第([` + numeralChars + `]+)条(?:之([一二三四五六七八九十]+))?[\s　]*(.*)$`)
+	// itemPattern 项，如 "（一）..."、"(二)..."
+	itemPattern = regexp.MustCompile(`^[（(]([` + numeralChars + `]+)[）)][\s　]*(.*)$`)
+)
+
+// headingLevels 标题关键字与层级
+var headingLevels = map[string]int{
+	"编":  levelPart,
+	"分编": levelSubpart,
+	"章":  levelChapter,
+	"节":  levelSection,
+}
+
+// Options 分块选项
+type Options struct {
+	DocumentID string // 文档 ID，用于生成 ChunkID
+	LawName    string // 法律简称，作为层级路径根节点，如 "民法典"
+	VersionID  int    // 知识库版本
+	MaxRunes   int    // 非法规文本分块最大字符数，<= 0 使用默认值
+}
+
+// Item 项
+type Item struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// Paragraph 款
+type Paragraph struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+	Items  []Item `json:"items,omitempty"`
+}
+
+// Chunk 结构化分块
+type Chunk struct {
+	Order         int
+	ArticleNumber string   // 条号 (阿拉伯数字)，非法规文本为空
+	ArticleLabel  string   // 原文条号，如 "第一千一百六十五条"
+	Hierarchy     []string // 层级路径，如 ["民法典", "第七编", "第一章"]
+	Content       string
+	Paragraphs    []Paragraph
+	References    []Reference
+}
+
+// LawHierarchy 层级路径字符串，如 "民法典/第一编/第三章/第二节"
+func (c *Chunk) LawHierarchy() string {
+	return strings.Join(c.Hierarchy, "/")
+}
+
+// Split 解析文本并生成 DocumentChunk 记录
+func Split(text string, opts Options) []model.DocumentChunk {
+	chunks := Parse(text, opts)
+	records := make([]model.DocumentChunk, len(chunks))
+	for i := range chunks {
+		records[i] = chunks[i].toModel(opts)
+	}
+	return records
+}
+
+// Parse 解析文本为结构化分块
+// 识别到条文时按条分块，否则按段落合并分块
+func Parse(text string, opts Options) []Chunk {
+	lines := normalizeLines(text)
+
+	var (
+		chunks   []Chunk
+		current  *Chunk
+		body     []string
+		headings [levelCount]string
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Content = strings.Join(body, "\n")
+		current.Paragraphs = parseParagraphs(body)
+		current.References = ExtractReferences(current.Content, current.ArticleNumber)
+		chunks = append(chunks, *current)
+		current, body = nil, nil
+	}
+
+	for _, line := range lines {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			level := headingLevels[m[2]]
+			headings[level] = "第" + m[1] + m[2]
+			for l := level + 1; l < levelCount; l++ {
+				headings[l] = ""
+			}
+			continue
+		}
+
+		if m := articlePattern.FindStringSubmatch(line); m != nil {
+			if number, ok := articleNumber(m[1], m[2]); ok {
+				flush()
+				label := "第" + m[1] + "条"
+				if m[2] != "" {
+					label += "之" + m[2]
+				}
+				current = &Chunk{
+					Order:         len(chunks),
+					ArticleNumber: number,
+					ArticleLabel:  label,
+					Hierarchy:     hierarchyPath(opts.LawName, headings),
+				}
+				body = []string{line}
+				continue
+			}
+		}
+
+		// 条文续行 (款、项)；首条之前的序言、目录等忽略
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+
+	if len(chunks) == 0 {
+		return splitPlain(lines, opts)
+	}
+	return chunks
+}
+
+// parseParagraphs 将条文拆分为款与项
+// 条文首行 (去掉条号) 为第一款，此后每个非 "项" 行开始新的一款
+func parseParagraphs(body []string) []Paragraph {
+	var paragraphs []Paragraph
+	for i, line := range body {
+		if i == 0 {
+			if m := articlePattern.FindStringSubmatch(line); m != nil {
+				line = m[3]
+			}
+			if line == "" {
+				continue
+			}
+		}
+
+		if m := itemPattern.FindStringSubmatch(line); m != nil && len(paragraphs) > 0 {
+			n, _ := ParseNumber(m[1])
+			last := &paragraphs[len(paragraphs)-1]
+			last.Items = append(last.Items, Item{Number: n, Text: m[2]})
+			continue
+		}
+
+		paragraphs = append(paragraphs, Paragraph{
+			Number: len(paragraphs) + 1,
+			Text:   line,
+		})
+	}
+	return paragraphs
+}
+
+// splitPlain 非法规文本按段落合并为不超过 MaxRunes 的分块
+func splitPlain(lines []string, opts Options) []Chunk {
+	maxRunes := opts.MaxRunes
+	if maxRunes <= 0 {
+		maxRunes = defaultMaxRunes
+	}
+
+	var (
+		chunks []Chunk
+		buf    []string
+		size   int
+	)
+	flush := func() {
+		if len(buf) == 0 {
+			return
+		}
+		chunks = append(chunks, Chunk{
+			Order:     len(chunks),
+			Hierarchy: hierarchyPath(opts.LawName, [levelCount]string{}),
+			Content:   strings.Join(buf, "\n"),
+		})
+		buf, size = nil, 0
+	}
+
+	for _, line := range lines {
+		n := len([]rune(line))
+		if size > 0 && size+n > maxRunes {
+			flush()
+		}
+		// 超长段落按字符切分
+		for runes := []rune(line); len(runes) > maxRunes; runes = runes[maxRunes:] {
+			buf = append(buf, string(runes[:maxRunes]))
+			flush()
+			line = string(runes[maxRunes:])
+			n = len([]rune(line))
+		}
+		if line != "" {
+			buf = append(buf, line)
+			size += n
+		}
+	}
+	flush()
+	return chunks
+}
+
+// hierarchyPath 组装层级路径
+func hierarchyPath(lawName string, headings [levelCount]string) []string {
+	path := make([]string, 0, levelCount+1)
+	if lawName != "" {
+		path = append(path, lawName)
+	}
+	for _, h := range headings {
+		if h != "" {
+			path = append(path, h)
+		}
+	}
+	return path
+}
+
+// normalizeLines 规范化换行与空白，去除空行
+func normalizeLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	raw := strings.Split(text, "\n")
+	lines := make([]string, 0, len(raw))
+	for _, line := range raw {
+		line = strings.TrimSpace(strings.Trim(line, "　 \uFEFF"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// toModel 转换为 DocumentChunk 记录
+func (c *Chunk) toModel(opts Options) model.DocumentChunk {
+	record := model.DocumentChunk{
+		ChunkID:       ChunkID(opts.DocumentID, c.Order),
+		DocumentID:    opts.DocumentID,
+		Content:       c.Content,
+		ContentHash:   ContentHash(c.Content),
+		SearchText:    SearchText(c.LawHierarchy(), c.Content),
+		ChunkOrder:    c.Order,
+		LawHierarchy:  c.LawHierarchy(),
+		ArticleNumber: c.ArticleNumber,
+		VersionID:     opts.VersionID,
+	}
+	if len(c.References) > 0 {
+		if data, err := json.Marshal(c.References); err == nil {
+			record.References = datatypes.JSON(data)
+		}
+	}
+	return record
+}
+
+// SearchText 生成分块的全文检索分词，层级路径中的法律名称与章节标题一并参与检索
+func SearchText(lawHierarchy, content string) string {
+	return textsearch.Document(lawHierarchy + " " + content)
+}
+
+// ChunkID 生成分块 ID: <文档 ID>-<序号>
+func ChunkID(documentID string, order int) string {
+	return fmt.Sprintf("%s-%05d", documentID, order)
+}
+
+// ContentHash 计算分块内容哈希 (SHA-256 十六进制)
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+package chunker
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "重新生成 testdata 中的 golden 文件")
+
+// goldenChunk golden 文件中的分块
+type goldenChunk struct {
+	ChunkID       string      `json:"chunkId"`
+	Order         int         `json:"order"`
+	ArticleNumber string      `json:"articleNumber,omitempty"`
+	ArticleLabel  string      `json:"articleLabel,omitempty"`
+	LawHierarchy  string      `json:"lawHierarchy"`
+	ContentHash   string      `json:"contentHash"`
+	Paragraphs    []Paragraph `json:"paragraphs,omitempty"`
+	References    []Reference `json:"references,omitempty"`
+	Content       string      `json:"content"`
+}
+
+func TestParseGolden(t *testing.T) {
+	fixtures := []struct {
+		file string
+		opts Options
+	}{
+		{file: "civil_code.txt", opts: Options{DocumentID: "civil", LawName: "民法典"}},
+		{file: "labor_contract_law.txt", opts: Options{DocumentID: "labor", LawName: "劳动合同法"}},
+		{file: "criminal_law.txt", opts: Options{DocumentID: "criminal", LawName: "刑法"}},
+		{file: "plain_notice.txt", opts: Options{DocumentID: "notice", LawName: "劳动合同管理通知", MaxRunes: 60}},
+	}
+
+	for _, f := range fixtures {
+		t.Run(f.file, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join("testdata", f.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunks := Parse(string(text), f.opts)
+			records := Split(string(text), f.opts)
+			if len(records) != len(chunks) {
+				t.Fatalf("Split() = %d records, Parse() = %d chunks", len(records), len(chunks))
+			}
+			got := make([]goldenChunk, len(chunks))
+			for i, c := range chunks {
+				r := records[i]
+				if r.ChunkOrder != c.Order || r.ArticleNumber != c.ArticleNumber || r.LawHierarchy != c.LawHierarchy() || r.Content != c.Content {
+					t.Errorf("Split()[%d] 与 Parse() 不一致: %+v", i, r)
+				}
+				got[i] = goldenChunk{
+					ChunkID:       r.ChunkID,
+					Order:         c.Order,
+					ArticleNumber: c.ArticleNumber,
+					ArticleLabel:  c.ArticleLabel,
+					LawHierarchy:  c.LawHierarchy(),
+					ContentHash:   r.ContentHash,
+					Paragraphs:    c.Paragraphs,
+					References:    c.References,
+					Content:       c.Content,
+				}
+			}
+
+			data, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, '\n')
+
+			golden := filepath.Join("testdata", strings.TrimSuffix(f.file, ".txt")+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("读取 golden 文件失败 (使用 -update 生成): %v", err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("%s 与 golden 文件不一致，确认改动符合预期后使用 go test ./internal/pkg/chunker -update 更新\n%s", f.file, firstDiff(string(want), string(data)))
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"一", 1, true},
+		{"十", 10, true},
+		{"十五", 15, true},
+		{"二十", 20, true},
+		{"一百零二", 102, true},
+		{"一百一十", 110, true},
+		{"三百零八", 308, true},
+		{"一千零一", 1001, true},
+		{"一千一百六十五", 1165, true},
+		{"一千二百六十", 1260, true},
+		{"两百", 200, true},
+		{"一万零五", 10005, true},
+		{"〇", 0, true},
+		{"壹佰贰拾叁", 123, true},
+		{"1165", 1165, true},
+		{"１２", 12, true},
+		{"", 0, false},
+		{"第十", 0, false},
+		{"十条", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseNumber(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseNumber(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseArticleNumbers(t *testing.T) {
+	text := "第十条　第十条正文。\n第十条之一　之一正文。\n第一百零二条　正文。\n第１２条　全角数字。\n第零条　无效条号，作为上一条的续行。"
+	chunks := Parse(text, Options{})
+
+	var numbers, labels []string
+	for _, c := range chunks {
+		numbers = append(numbers, c.ArticleNumber)
+		labels = append(labels, c.ArticleLabel)
+	}
+	if want := []string{"10", "10-1", "102", "12"}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("article numbers = %v, want %v", numbers, want)
+	}
+	if want := []string{"第十条", "第十条之一", "第一百零二条", "第１２条"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("article labels = %v, want %v", labels, want)
+	}
+	if last := chunks[len(chunks)-1]; len(last.Paragraphs) != 2 {
+		t.Errorf("无效条号应并入上一条: %+v", last.Paragraphs)
+	}
+}
+
+func TestExtractReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		self string
+		want []Reference
+	}{
+		{
+			name: "本法条款项",
+			text: "因本法第二十六条第一款第一项规定的情形致使劳动合同无效的",
+			want: []Reference{{Article: "26", Paragraph: 1, Item: 1, Text: "本法第二十六条第一款第一项"}},
+		},
+		{
+			name: "外部法律并列引用沿用法律名称",
+			text: "依照《中华人民共和国刑法》第二十条、第二十一条的规定",
+			want: []Reference{
+				{Law: "中华人民共和国刑法", Article: "20", Text: "《中华人民共和国刑法》第二十条"},
+				{Law: "中华人民共和国刑法", Article: "21", Text: "第二十一条"},
+			},
+		},
+		{
+			name: "非并列的条号不沿用法律名称",
+			text: "依照《中华人民共和国刑法》第二十条处罚；不符合第二十一条规定的除外",
+			want: []Reference{
+				{Law: "中华人民共和国刑法", Article: "20", Text: "《中华人民共和国刑法》第二十条"},
+				{Article: "21", Text: "第二十一条"},
+			},
+		},
+		{
+			name: "区间与之X",
+			text: "适用本法第一百三十三条之一至第一百三十三条之二的规定",
+			want: []Reference{{Article: "133-1", ToArticle: "133-2", Text: "本法第一百三十三条之一至第一百三十三条之二"}},
+		},
+		{
+			name: "忽略自身条号与重复引用",
+			text: "第四十七条　依照本法第四十七条第二款、本法第八十七条以及本法第八十七条的规定",
+			self: "47",
+			want: []Reference{
+				{Article: "47", Paragraph: 2, Text: "本法第四十七条第二款"},
+				{Article: "87", Text: "本法第八十七条"},
+			},
+		},
+		{
+			name: "无条号的款项不计为引用",
+			text: "机动车所有人、管理人对前款第三项、第四项行为负有直接责任的",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractReferences(tt.text, tt.self)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractReferences() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// firstDiff 返回两段文本首个不同行及其上下文，便于定位 golden 差异
+func firstDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("第 %d 行:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
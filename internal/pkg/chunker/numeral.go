@@ -0,0 +1,82 @@
+package chunker
+
+import "strconv"
+
+// chineseDigits 中文数字
+var chineseDigits = map[rune]int{
+	'零': 0, '〇': 0,
+	'一': 1, '壹': 1,
+	'二': 2, '两': 2, '贰': 2,
+	'三': 3, '叁': 3,
+	'四': 4, '肆': 4,
+	'五': 5, '伍': 5,
+	'六': 6, '陆': 6,
+	'七': 7, '柒': 7,
+	'八': 8, '捌': 8,
+	'九': 9, '玖': 9,
+}
+
+// chineseUnits 中文数位
+var chineseUnits = map[rune]int{
+	'十': 10, '拾': 10,
+	'百': 100, '佰': 100,
+	'千': 1000, '仟': 1000,
+}
+
+// numeralChars 条款编号中可能出现的数字字符 (用于正则)
+const numeralChars = `零〇一二两三四五六七八九十百千万壹贰叁肆伍陆柒捌玖拾佰仟0-9０-９`
+
+// ParseNumber 解析中文或阿拉伯数字，如 "一千一百六十五"、"十"、"1165"
+func ParseNumber(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if n, err := strconv.Atoi(toHalfWidthDigits(s)); err == nil {
+		return n, true
+	}
+
+	var (
+		total   int // 万以上部分
+		section int // 万以下部分
+		digit   = -1
+	)
+	for _, r := range s {
+		if d, ok := chineseDigits[r]; ok {
+			digit = d
+			continue
+		}
+		if u, ok := chineseUnits[r]; ok {
+			// "十五" 省略了前导的 "一"
+			if digit < 0 {
+				digit = 1
+			}
+			section += digit * u
+			digit = -1
+			continue
+		}
+		if r == '万' {
+			if digit > 0 {
+				section += digit
+			}
+			total += section * 10000
+			section, digit = 0, -1
+			continue
+		}
+		return 0, false
+	}
+	if digit > 0 {
+		section += digit
+	}
+	return total + section, true
+}
+
+// toHalfWidthDigits 全角数字转半角
+func toHalfWidthDigits(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if r >= '０' && r <= '９' {
+			runes[i] = r - '０' + '0'
+		}
+	}
+	return string(runes)
+}
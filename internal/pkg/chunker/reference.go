@@ -0,0 +1,147 @@
+package chunker
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Reference 条文中的交叉引用，如 "依照本法第一千一百六十五条第二款"
+type Reference struct {
+	Law       string `json:"law,omitempty"`       // 被引用法律名称，为空表示本法
+	Article   string `json:"article"`             // 条号 (阿拉伯数字，如 "1165"、"10-1")
+	ToArticle string `json:"toArticle,omitempty"` // 区间引用的终止条号，如 "第X条至第Y条"
+	Paragraph int    `json:"paragraph,omitempty"` // 款
+	Item      int    `json:"item,omitempty"`      // 项
+	Text      string `json:"text"`                // 原文片段
+}
+
+// referencePattern 引用匹配
+// 分组: 1 法律名称 (本法/本条例/《...》)，2 条号，3 之X，4 区间终止条号，5 终止之X，6 款，7 项
+var referencePattern = regexp.MustCompile(
+	`(本法|本条例|本规定|本办法|《[^》]{1,50}》)?` +
+		`第([` + numeralChars + `]+)条(?:之([一二三四五六七八九十]+))?` +
+		`(?:至第([` + numeralChars + `]+)条(?:之([一二三四五六七八九十]+))?)?` +
+		`(?:第([` + numeralChars + `]+)款)?` +
+		`(?:第([` + numeralChars + `]+)项)?`,
+)
+
+// listSeparators 并列引用之间的连接词
+var listSeparators = map[string]bool{
+	"、": true, "和": true, "及": true, "以及": true, "或者": true, "，": true,
+}
+
+// ReferenceMatch 文本中的一处引用及其字节位置
+type ReferenceMatch struct {
+	Reference
+	Start int
+	End   int
+}
+
+// ExtractReferences 提取条文中的交叉引用
+// selfArticle 为当前条号，条文开头的自身编号不计为引用
+func ExtractReferences(text, selfArticle string) []Reference {
+	var (
+		refs []Reference
+		seen = make(map[Reference]bool)
+	)
+	for _, m := range FindReferences(text) {
+		// 条文开头的 "第X条" 是条文自身编号
+		if m.Start == 0 && strings.HasPrefix(m.Text, "第") {
+			continue
+		}
+
+		ref := m.Reference
+		// 同一法律内引用自身条号无意义
+		if ref.Law == "" && ref.Article == selfArticle && ref.Paragraph == 0 && ref.ToArticle == "" {
+			continue
+		}
+
+		key := ref
+		key.Text = ""
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// FindReferences 按出现顺序查找文本中的全部引用 (不去重)，返回各处引用的位置
+func FindReferences(text string) []ReferenceMatch {
+	matches := referencePattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var (
+		refs    []ReferenceMatch
+		prevEnd = -1
+		prevLaw string
+	)
+	for _, m := range matches {
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return text[m[2*i]:m[2*i+1]]
+		}
+
+		article, ok := articleNumber(group(2), group(3))
+		if !ok {
+			continue
+		}
+
+		ref := Reference{Article: article, Text: text[m[0]:m[1]]}
+		// 本法/本条例等指向当前法律，《xxx》 指向外部法律
+		// "《刑法》第二十条、第二十一条" 中并列的条号沿用前一个法律名称
+		switch law := group(1); {
+		case strings.HasPrefix(law, "《"):
+			ref.Law = trimBookMarks(law)
+		case law == "" && prevEnd >= 0 && listSeparators[text[prevEnd:m[0]]]:
+			ref.Law = prevLaw
+		}
+		prevEnd, prevLaw = m[1], ref.Law
+		if to := group(4); to != "" {
+			if n, ok := articleNumber(to, group(5)); ok {
+				ref.ToArticle = n
+			}
+		}
+		if p := group(6); p != "" {
+			ref.Paragraph, _ = ParseNumber(p)
+		}
+		if it := group(7); it != "" {
+			ref.Item, _ = ParseNumber(it)
+		}
+
+		refs = append(refs, ReferenceMatch{Reference: ref, Start: m[0], End: m[1]})
+	}
+	return refs
+}
+
+// articleNumber 规范化条号: "一千一百六十五" → "1165"，"十" + "一" (之一) → "10-1"
+func articleNumber(num, sub string) (string, bool) {
+	n, ok := ParseNumber(num)
+	if !ok || n <= 0 {
+		return "", false
+	}
+	result := strconv.Itoa(n)
+	if sub != "" {
+		s, ok := ParseNumber(sub)
+		if !ok {
+			return "", false
+		}
+		result += "-" + strconv.Itoa(s)
+	}
+	return result, true
+}
+
+// trimBookMarks 去除书名号
+func trimBookMarks(s string) string {
+	runes := []rune(s)
+	if len(runes) >= 2 && runes[0] == '《' && runes[len(runes)-1] == '》' {
+		return string(runes[1 : len(runes)-1])
+	}
+	return s
+}
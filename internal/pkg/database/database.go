@@ -70,6 +70,12 @@ func connect(cfg *config.DatabaseConfig) error {
 	return nil
 }
 
+// Use 使用已建立的连接代替 Init (如离线评测工具使用的 SQLite 内存数据库)
+func Use(conn *gorm.DB) {
+	once.Do(func() {})
+	db = conn
+}
+
 // DB 获取数据库实例
 func DB() *gorm.DB {
 	return db
@@ -0,0 +1,77 @@
+// Package docparse 提供知识库文档的纯文本提取
+//
+// 支持 PDF、DOCX、TXT、Markdown 文件与网页 (HTML)，
+// 输出按行组织的纯文本，供 chunker 分块使用。
+package docparse
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+)
+
+var (
+	ErrUnsupportedType  = errors.New("不支持的文档类型")
+	ErrEmptyText        = errors.New("文档中未提取到文本内容")
+	ErrResponseTooLarge = errors.New("网页内容超出大小限制")
+)
+
+// extensionTypes 文件扩展名与文档类型
+var extensionTypes = map[string]model.DocumentType{
+	".pdf":      model.DocTypePDF,
+	".docx":     model.DocTypeDOCX,
+	".txt":      model.DocTypeTXT,
+	".md":       model.DocTypeMarkdown,
+	".markdown": model.DocTypeMarkdown,
+}
+
+// DetectType 根据文件名推断文档类型
+func DetectType(filename string) (model.DocumentType, bool) {
+	t, ok := extensionTypes[strings.ToLower(filepath.Ext(filename))]
+	return t, ok
+}
+
+// ExtractFile 提取本地文件的纯文本
+func ExtractFile(path string, docType model.DocumentType) (string, error) {
+	var (
+		text string
+		err  error
+	)
+	switch docType {
+	case model.DocTypePDF:
+		text, err = extractPDF(path)
+	case model.DocTypeDOCX:
+		text, err = extractDOCX(path)
+	case model.DocTypeTXT, model.DocTypeMarkdown:
+		var data []byte
+		if data, err = os.ReadFile(path); err == nil {
+			text, err = decodePlain(data)
+			if err == nil && docType == model.DocTypeMarkdown {
+				text = stripMarkdown(text)
+			}
+		}
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, docType)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return "", ErrEmptyText
+	}
+	return text, nil
+}
+
+// decodePlain 解码纯文本文件，去除 UTF-8 BOM
+func decodePlain(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", errors.New("文本文件不是有效的 UTF-8 编码")
+	}
+	return strings.TrimPrefix(string(data), "\uFEFF"), nil
+}
@@ -0,0 +1,78 @@
+package docparse
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// docxBodyPath DOCX 正文所在的 XML 文件
+const docxBodyPath = "word/document.xml"
+
+// extractDOCX 提取 DOCX 正文文本，每个段落 (w:p) 一行
+func extractDOCX(path string) (string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("打开 DOCX 失败: %w", err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name != docxBodyPath {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("读取 DOCX 正文失败: %w", err)
+		}
+		defer rc.Close()
+		return parseDocumentXML(rc)
+	}
+	return "", errors.New("DOCX 中缺少 " + docxBodyPath)
+}
+
+// parseDocumentXML 解析 WordprocessingML 正文
+// 只关心 w:t (文本)、w:tab、w:br 与段落结束，忽略样式等其他元素
+func parseDocumentXML(r io.Reader) (string, error) {
+	var (
+		sb     strings.Builder
+		inText bool
+	)
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("解析 DOCX 正文失败: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteByte('\t')
+			case "br", "cr":
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
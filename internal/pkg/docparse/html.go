@@ -0,0 +1,196 @@
+package docparse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// maxFetchBytes 网页抓取的最大响应体积
+const maxFetchBytes = 50 << 20
+
+// WebPage 抓取的网页
+type WebPage struct {
+	Title string
+	Text  string
+}
+
+// skippedElements 不包含正文的元素
+var skippedElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Nav:      true,
+	atom.Footer:   true,
+	atom.Form:     true,
+}
+
+// blockElements 块级元素，前后换行
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Section: true, atom.Article: true, atom.Table: true, atom.Ul: true, atom.Ol: true,
+	atom.Pre: true, atom.Blockquote: true, atom.Dd: true, atom.Dt: true, atom.Hr: true,
+}
+
+// FetchURL 抓取网页并提取正文
+// 支持 HTML (按声明的字符集解码，兼容 GBK 等编码)、纯文本与 PDF
+func FetchURL(ctx context.Context, client *http.Client, url string) (*WebPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "LexVeritas-Ingest/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("抓取网页失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("抓取网页失败: HTTP %d", resp.StatusCode)
+	}
+
+	body := io.LimitReader(resp.Body, maxFetchBytes+1)
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var page *WebPage
+	switch {
+	case mediaType == "application/pdf":
+		page, err = fetchPDF(body)
+	case strings.HasPrefix(mediaType, "text/plain"):
+		page, err = readPlain(body, contentType)
+	default:
+		page, err = parseHTML(body, contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(page.Text) == "" {
+		return nil, ErrEmptyText
+	}
+	return page, nil
+}
+
+// parseHTML 解析 HTML 并提取标题与正文
+func parseHTML(body io.Reader, contentType string) (*WebPage, error) {
+	data, err := readLimited(body)
+	if err != nil {
+		return nil, err
+	}
+	r, err := charset.NewReader(bytes.NewReader(data), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("网页字符集解码失败: %w", err)
+	}
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("解析网页失败: %w", err)
+	}
+
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+			return
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(strings.Join(strings.Fields(n.Data), " "))
+		}
+
+		block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+		if block {
+			sb.WriteByte('\n')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if block {
+			sb.WriteByte('\n')
+		} else if n.Type == html.ElementNode && (n.DataAtom == atom.Td || n.DataAtom == atom.Th) {
+			sb.WriteByte('\t')
+		}
+	}
+	walk(doc)
+
+	// <title> 位于 <head> 中，遍历正文时被跳过，单独查找
+	return &WebPage{Title: findTitle(doc), Text: sb.String()}, nil
+}
+
+// findTitle 查找 <title> 文本
+func findTitle(n *html.Node) string {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Title && n.FirstChild != nil {
+		return strings.TrimSpace(n.FirstChild.Data)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if t := findTitle(c); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// readPlain 读取纯文本响应
+func readPlain(body io.Reader, contentType string) (*WebPage, error) {
+	r, err := charset.NewReader(body, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("字符集解码失败: %w", err)
+	}
+	data, err := readLimited(r)
+	if err != nil {
+		return nil, err
+	}
+	return &WebPage{Text: strings.TrimPrefix(string(data), "\uFEFF")}, nil
+}
+
+// fetchPDF 将 PDF 响应写入临时文件后提取文本
+func fetchPDF(body io.Reader) (*WebPage, error) {
+	tmp, err := os.CreateTemp("", "lexveritas-*.pdf")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("下载 PDF 失败: %w", err)
+	}
+	if n > maxFetchBytes {
+		return nil, ErrResponseTooLarge
+	}
+
+	text, err := extractPDF(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &WebPage{Text: text}, nil
+}
+
+// readLimited 读取响应体，超出 maxFetchBytes 时报错
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取网页失败: %w", err)
+	}
+	if len(data) > maxFetchBytes {
+		return nil, ErrResponseTooLarge
+	}
+	return data, nil
+}
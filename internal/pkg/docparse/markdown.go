@@ -0,0 +1,44 @@
+package docparse
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// mdHeadingPattern 标题标记，如 "## 第一章 总则"
+	mdHeadingPattern = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)
+	// mdPrefixPattern 引用与列表标记，如 "> "、"- "、"* "、"1. "
+	mdPrefixPattern = regexp.MustCompile(`^\s*(?:>\s*)+|^\s*[-*+]\s+|^\s*\d+\.\s+`)
+	// mdLinkPattern 链接与图片，保留链接文字
+	mdLinkPattern = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	// mdEmphasisPattern 加粗、斜体、删除线、行内代码
+	mdEmphasisPattern = regexp.MustCompile("\\*\\*|__|~~|`")
+	// mdRulePattern 分隔线
+	mdRulePattern = regexp.MustCompile(`^\s*(?:-{3,}|\*{3,}|_{3,})\s*$`)
+)
+
+// stripMarkdown 去除 Markdown 标记，保留正文与行结构
+// 法规类 Markdown 通常只用到标题、列表与强调，无需完整解析
+func stripMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inFence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			if mdRulePattern.MatchString(line) {
+				continue
+			}
+			line = mdHeadingPattern.ReplaceAllString(line, "")
+			line = mdPrefixPattern.ReplaceAllString(line, "")
+			line = mdLinkPattern.ReplaceAllString(line, "$1")
+			line = mdEmphasisPattern.ReplaceAllString(line, "")
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
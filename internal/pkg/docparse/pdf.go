@@ -0,0 +1,45 @@
+package docparse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// extractPDF 提取 PDF 文本，按页面行坐标还原换行
+// 扫描件 (无文本层) 会返回 ErrEmptyText
+func extractPDF(path string) (text string, err error) {
+	// 解析器遇到损坏的文件可能 panic
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("PDF 解析失败: %v", r)
+		}
+	}()
+
+	f, reader, err := pdf.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("打开 PDF 失败: %w", err)
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		rows, err := page.GetTextByRow()
+		if err != nil {
+			return "", fmt.Errorf("读取 PDF 第 %d 页失败: %w", i, err)
+		}
+		for _, row := range rows {
+			for _, word := range row.Content {
+				sb.WriteString(word.S)
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String(), nil
+}
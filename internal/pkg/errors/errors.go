@@ -37,6 +37,7 @@ const (
 	CodeRedisError    Code = 5002
 	CodeMilvusError   Code = 5003
 	CodeLLMError      Code = 5004
+	CodeChainError    Code = 5005
 )
 
 // 错误码信息映射
@@ -64,6 +65,7 @@ var codeMessages = map[Code]string{
 	CodeRedisError:    "缓存错误",
 	CodeMilvusError:   "向量数据库错误",
 	CodeLLMError:      "大模型服务错误",
+	CodeChainError:    "区块链服务错误",
 }
 
 // HTTP 状态码映射
@@ -91,6 +93,7 @@ var codeHTTPStatus = map[Code]int{
 	CodeRedisError:    http.StatusInternalServerError,
 	CodeMilvusError:   http.StatusInternalServerError,
 	CodeLLMError:      http.StatusInternalServerError,
+	CodeChainError:    http.StatusBadGateway,
 }
 
 // AppError 应用错误
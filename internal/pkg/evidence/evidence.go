@@ -0,0 +1,216 @@
+// Package evidence 提供问答证据包的生成与离线验证
+//
+// 证据包为 ZIP 文件:
+//   - evidence.json: 问题、回答、引用原文及其 ContentHash、Merkle 证明、所属知识库版本的 Root 与上链交易
+//   - signature.json: 服务端对 evidence.json 的签名 (secp256k1，EIP-191 personal_sign)
+//   - README.txt: 人工复核步骤说明 (不参与签名)
+//
+// 证据包只能且必须包含以上三个文件，增删文件均视为被篡改。
+//
+// 验证无需访问服务端数据库: 校验签名、由原文重新计算哈希并沿证明折叠出 Root，
+// 再到链上存证合约读取该版本的 Root 比对即可。
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FormatVersion 证据包格式版本
+const FormatVersion = 1
+
+// 证据包文件名
+const (
+	ManifestFile  = "evidence.json"
+	SignatureFile = "signature.json"
+	ReadmeFile    = "README.txt"
+)
+
+// maxEntrySize 单个文件解压后的最大字节数
+const maxEntrySize = 32 << 20
+
+var (
+	ErrMissingEntry    = errors.New("证据包缺少必要文件")
+	ErrUnexpectedEntry = errors.New("证据包包含多余文件")
+	ErrEntryTooLarge   = errors.New("证据包文件过大")
+	ErrUnsupported     = errors.New("不支持的证据包格式版本")
+)
+
+// Manifest 证据内容 (evidence.json)
+type Manifest struct {
+	Format      int        `json:"format"`
+	MessageID   string     `json:"messageId"`
+	SessionID   string     `json:"sessionId"`
+	Question    string     `json:"question"`
+	Answer      string     `json:"answer"`
+	AnsweredAt  time.Time  `json:"answeredAt"`
+	Citations   []Citation `json:"citations"`
+	Versions    []Version  `json:"versions"`
+	Chain       Chain      `json:"chain"`
+	GeneratedAt time.Time  `json:"generatedAt"`
+}
+
+// Citation 回答引用的法条
+type Citation struct {
+	Index         int      `json:"index"`
+	CitationID    int64    `json:"citationId"`
+	ChunkID       string   `json:"chunkId"`
+	Source        string   `json:"source"`
+	ArticleNumber string   `json:"articleNumber,omitempty"`
+	LawHierarchy  string   `json:"lawHierarchy,omitempty"`
+	Text          string   `json:"text"`
+	ContentHash   string   `json:"contentHash"` // SHA-256(text)
+	VersionID     int      `json:"versionId,omitempty"`
+	MerkleIndex   *int     `json:"merkleIndex,omitempty"`
+	MerkleProof   []string `json:"merkleProof"`
+	Verified      bool     `json:"verified"` // 回答生成时的链上验证结果
+}
+
+// Version 引用所属的知识库版本及其上链交易
+type Version struct {
+	ID          int        `json:"id"`
+	MerkleRoot  string     `json:"merkleRoot"`
+	ChunkCount  int        `json:"chunkCount"`
+	Status      string     `json:"status"`
+	TxHash      string     `json:"txHash,omitempty"`
+	BlockNumber int64      `json:"blockNumber,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
+}
+
+// Chain 存证所在的链与合约
+type Chain struct {
+	ChainID         int64  `json:"chainId,omitempty"`
+	ContractAddress string `json:"contractAddress,omitempty"`
+}
+
+// Bundle 解析后的证据包
+type Bundle struct {
+	Manifest  Manifest
+	Signature Signature
+	// raw evidence.json 原始字节，签名针对该字节序列
+	raw []byte
+}
+
+// Write 序列化证据内容并签名，以 ZIP 写入 w
+func Write(w io.Writer, m *Manifest, signer *Signer) error {
+	m.Format = FormatVersion
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	sig, err := signer.Sign(raw)
+	if err != nil {
+		return err
+	}
+	sigJSON, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{ManifestFile, raw},
+		{SignatureFile, sigJSON},
+		{ReadmeFile, []byte(readme(m, sig))},
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: m.GeneratedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Open 解析 ZIP 证据包并检查文件清单 (不校验签名与证明，见 Verify)
+func Open(data []byte) (*Bundle, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("读取证据包失败: %w", err)
+	}
+
+	entries := make(map[string][]byte)
+	for _, f := range zr.File {
+		_, seen := entries[f.Name]
+		if seen || (f.Name != ManifestFile && f.Name != SignatureFile && f.Name != ReadmeFile) {
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedEntry, f.Name)
+		}
+		b, err := readEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		entries[f.Name] = b
+	}
+	for _, name := range []string{ManifestFile, SignatureFile, ReadmeFile} {
+		if _, ok := entries[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingEntry, name)
+		}
+	}
+
+	b := &Bundle{raw: entries[ManifestFile]}
+	if err := json.Unmarshal(b.raw, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", ManifestFile, err)
+	}
+	if b.Manifest.Format != FormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupported, b.Manifest.Format)
+	}
+	if err := json.Unmarshal(entries[SignatureFile], &b.Signature); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", SignatureFile, err)
+	}
+	return b, nil
+}
+
+// readEntry 读取 ZIP 文件内容，限制解压后大小
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEntrySize {
+		return nil, ErrEntryTooLarge
+	}
+	return data, nil
+}
+
+// readme 生成人工复核说明
+func readme(m *Manifest, sig *Signature) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "LexVeritas 问答证据包\n\n")
+	fmt.Fprintf(&b, "消息 ID: %s\n生成时间: %s\n签名账户: %s\n", m.MessageID, m.GeneratedAt.Format(time.RFC3339), sig.Signer)
+	if m.Chain.ContractAddress != "" {
+		fmt.Fprintf(&b, "存证合约: %s (chainId %d)\n", m.Chain.ContractAddress, m.Chain.ChainID)
+	}
+	b.WriteString(`
+复核步骤 (也可使用 verify-evidence 命令自动完成):
+1. 签名: digest = SHA-256(evidence.json 原始字节)，应与 signature.json 的 digest 一致；
+   以 EIP-191 personal_sign 规则对 digest 的 32 字节恢复签名地址 (如 ethers.verifyMessage)，
+   应与 signer 一致，并与已公布的服务端地址或存证交易的发送方一致。
+2. 引用原文: contentHash = SHA-256(text 的 UTF-8 字节)。
+3. Merkle 证明: leaf = keccak256(keccak256(contentHash 的 32 字节))，
+   依次与 merkleProof 各项计算 keccak256(min(a, b) ‖ max(a, b))，结果应等于所属版本的 merkleRoot。
+4. 链上存证: 调用存证合约 getRoot(uint256 versionId) 读取 bytes32，应等于 merkleRoot；
+   txHash 交易中的 RootAnchored(versionId, root) 事件为上链记录。
+`)
+	return b.String()
+}
@@ -0,0 +1,341 @@
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+)
+
+// testTexts 知识库版本中的条文，前两条被回答引用
+var testTexts = []string{
+	"第一千一百六十五条　行为人因过错侵害他人民事权益造成损害的，应当承担侵权责任。",
+	"第一千一百六十六条　行为人造成他人民事权益损害，不论行为人有无过错，法律规定应当承担侵权责任的，依照其规定。",
+	"第四百六十四条　合同是民事主体之间设立、变更、终止民事法律关系的协议。",
+}
+
+// newTestSigner 生成随机签名器
+func newTestSigner(t *testing.T) *Signer {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSigner(common.Bytes2Hex(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// testManifest 构造引用已确认版本中两条条文的证据内容
+func testManifest(t *testing.T) *Manifest {
+	t.Helper()
+	hashes := make([]string, len(testTexts))
+	for i, text := range testTexts {
+		hashes[i] = chunker.ContentHash(text)
+	}
+	tree, err := merkle.FromContentHashes(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirmedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := &Manifest{
+		MessageID:  "msg-1",
+		SessionID:  "session-1",
+		Question:   "因过错侵害他人民事权益应当承担什么责任",
+		Answer:     "依照《民法典》第1165条 [1]，行为人因过错侵害他人民事权益造成损害的，应当承担侵权责任 [2]。",
+		AnsweredAt: confirmedAt.Add(time.Hour),
+		Versions: []Version{{
+			ID:          3,
+			MerkleRoot:  tree.Root().Hex(),
+			ChunkCount:  len(testTexts),
+			Status:      string(model.VersionStatusConfirmed),
+			TxHash:      "0x" + strings.Repeat("ab", 32),
+			BlockNumber: 42,
+			ConfirmedAt: &confirmedAt,
+		}},
+		Chain:       Chain{ChainID: 31337, ContractAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3"},
+		GeneratedAt: confirmedAt.Add(2 * time.Hour),
+	}
+	for i := 0; i < 2; i++ {
+		proof, err := tree.ProofHex(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		index := i
+		m.Citations = append(m.Citations, Citation{
+			Index:         i + 1,
+			CitationID:    int64(i + 10),
+			ChunkID:       []string{"civil-1165", "civil-1166"}[i],
+			Source:        "中华人民共和国民法典",
+			ArticleNumber: []string{"1165", "1166"}[i],
+			Text:          testTexts[i],
+			ContentHash:   hashes[i],
+			VersionID:     3,
+			MerkleIndex:   &index,
+			MerkleProof:   proof,
+			Verified:      true,
+		})
+	}
+	return m
+}
+
+// writeBundle 生成签名证据包
+func writeBundle(t *testing.T, m *Manifest, signer *Signer) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, m, signer); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// zipEntry ZIP 中的文件
+type zipEntry struct {
+	name string
+	data []byte
+}
+
+// readEntries 按顺序读取 ZIP 中的全部文件
+func readEntries(t *testing.T, data []byte) []zipEntry {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make([]zipEntry, 0, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, zipEntry{name: f.Name, data: b})
+	}
+	return entries
+}
+
+// rezip 修改证据包中的文件后重新打包，模拟对 ZIP 的篡改
+func rezip(t *testing.T, data []byte, edit func([]zipEntry) []zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range edit(readEntries(t, data)) {
+		fw, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// replaceEntry 替换指定文件的内容
+func replaceEntry(name string, replace func([]byte) []byte) func([]zipEntry) []zipEntry {
+	return func(entries []zipEntry) []zipEntry {
+		for i := range entries {
+			if entries[i].name == name {
+				entries[i].data = replace(entries[i].data)
+			}
+		}
+		return entries
+	}
+}
+
+// replaceOnce 替换原始字节中的首个 old (不存在时证据包未被改动，断言随之失败)
+func replaceOnce(old, new string) func([]byte) []byte {
+	return func(data []byte) []byte {
+		return bytes.Replace(data, []byte(old), []byte(new), 1)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	signer := newTestSigner(t)
+	m := testManifest(t)
+	data := writeBundle(t, m, signer)
+
+	if names := readEntries(t, data); len(names) != 3 || names[0].name != ManifestFile || names[1].name != SignatureFile || names[2].name != ReadmeFile {
+		t.Fatalf("证据包文件 = %v", names)
+	}
+
+	b, err := Open(data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if b.Manifest.Format != FormatVersion || b.Manifest.Answer != m.Answer || len(b.Manifest.Citations) != 2 || b.Signature.Signer != signer.Address() {
+		t.Errorf("Open() manifest = %+v, signature = %+v", b.Manifest, b.Signature)
+	}
+
+	res := b.Verify(strings.ToLower(signer.Address()))
+	if !res.OK() {
+		t.Fatalf("Verify() = %+v", res)
+	}
+	for _, c := range res.Citations {
+		if c.ComputedRoot != m.Versions[0].MerkleRoot {
+			t.Errorf("引用 %d computedRoot = %s, want %s", c.Index, c.ComputedRoot, m.Versions[0].MerkleRoot)
+		}
+	}
+}
+
+func TestTamperedBundle(t *testing.T) {
+	signer := newTestSigner(t)
+	m := testManifest(t)
+	data := writeBundle(t, m, signer)
+	proofEntry := m.Citations[0].MerkleProof[0]
+	forgedProof := "0x" + strings.Repeat("0", 64)
+	otherSig, err := newTestSigner(t).Sign([]byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 直接修改 ZIP 中的 evidence.json，签名覆盖的摘要不再一致
+	tests := []struct {
+		name string
+		edit func([]zipEntry) []zipEntry
+		want error
+	}{
+		{name: "回答改动一个字节", edit: replaceEntry(ManifestFile, replaceOnce("第1165条", "第1166条")), want: ErrDigestMismatch},
+		// "任" (E4 BB BB) 改为 "仺" (E4 BB BA)
+		{name: "引用原文改动一个字节", edit: replaceEntry(ManifestFile, replaceOnce("侵权责任。\"", "侵权责\u4efa。\"")), want: ErrDigestMismatch},
+		{name: "Merkle 证明改动", edit: replaceEntry(ManifestFile, replaceOnce(proofEntry, forgedProof)), want: ErrDigestMismatch},
+		{name: "签名值被替换", edit: replaceEntry(SignatureFile, func(data []byte) []byte {
+			var sig Signature
+			_ = json.Unmarshal(data, &sig)
+			sig.Signature = otherSig.Signature
+			out, _ := json.Marshal(sig)
+			return out
+		}), want: ErrSignerMismatch},
+		{name: "签名格式错误", edit: replaceEntry(SignatureFile, replaceOnce(`"signature": "0x`, `"signature": "0xzz`)), want: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Open(rezip(t, data, tt.edit))
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			res := b.Verify(signer.Address())
+			if res.OK() || !errors.Is(res.SignatureError, tt.want) {
+				t.Errorf("Verify() signatureError = %v, want %v", res.SignatureError, tt.want)
+			}
+		})
+	}
+
+	// 以其他私钥重新签名整个证据包: 签名本身有效，但不是已公布的服务端地址
+	t.Run("签名文件被替换", func(t *testing.T) {
+		forged := writeBundle(t, testManifest(t), newTestSigner(t))
+		b, err := Open(rezip(t, data, replaceEntry(SignatureFile, func([]byte) []byte {
+			return readEntries(t, forged)[1].data
+		})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := b.Verify(signer.Address()); res.OK() {
+			t.Errorf("替换签名文件后 Verify() 通过")
+		}
+
+		b, err = Open(forged)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := b.Verify(signer.Address()); res.OK() || !errors.Is(res.SignatureError, ErrUntrustedSigner) {
+			t.Errorf("Verify() signatureError = %v, want ErrUntrustedSigner", res.SignatureError)
+		}
+	})
+}
+
+func TestBundleEntries(t *testing.T) {
+	data := writeBundle(t, testManifest(t), newTestSigner(t))
+
+	tests := []struct {
+		name string
+		edit func([]zipEntry) []zipEntry
+		want error
+	}{
+		{name: "新增文件", edit: func(e []zipEntry) []zipEntry {
+			return append(e, zipEntry{name: "note.txt", data: []byte("补充说明")})
+		}, want: ErrUnexpectedEntry},
+		{name: "重复的 evidence.json", edit: func(e []zipEntry) []zipEntry {
+			return append(e, zipEntry{name: ManifestFile, data: e[0].data})
+		}, want: ErrUnexpectedEntry},
+		{name: "删除 README.txt", edit: func(e []zipEntry) []zipEntry { return e[:2] }, want: ErrMissingEntry},
+		{name: "删除 signature.json", edit: func(e []zipEntry) []zipEntry { return []zipEntry{e[0], e[2]} }, want: ErrMissingEntry},
+		{name: "删除 evidence.json", edit: func(e []zipEntry) []zipEntry { return e[1:] }, want: ErrMissingEntry},
+		{name: "格式版本", edit: replaceEntry(ManifestFile, replaceOnce(`"format": 1`, `"format": 2`)), want: ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Open(rezip(t, data, tt.edit)); !errors.Is(err, tt.want) {
+				t.Errorf("Open() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Open([]byte("not a zip")); err == nil {
+		t.Error("Open(非 ZIP) 应返回错误")
+	}
+}
+
+func TestVerifyCitations(t *testing.T) {
+	forgedProof := "0x" + strings.Repeat("0", 64)
+
+	// 服务端签名的证据内容本身有误时，签名有效但引用验证不通过
+	tests := []struct {
+		name   string
+		mutate func(m *Manifest)
+		want   string
+	}{
+		{name: "原文与哈希不一致", mutate: func(m *Manifest) { m.Citations[0].Text += "。" }, want: reasonHashMismatch},
+		{name: "原文与哈希同时替换", mutate: func(m *Manifest) {
+			m.Citations[0].Text = testTexts[2] + "（修订）"
+			m.Citations[0].ContentHash = chunker.ContentHash(m.Citations[0].Text)
+		}, want: reasonRootMismatch},
+		{name: "证明项被替换", mutate: func(m *Manifest) { m.Citations[0].MerkleProof[0] = forgedProof }, want: reasonRootMismatch},
+		{name: "证明项缺失", mutate: func(m *Manifest) { m.Citations[0].MerkleProof = m.Citations[0].MerkleProof[1:] }, want: reasonRootMismatch},
+		{name: "证明格式错误", mutate: func(m *Manifest) { m.Citations[0].MerkleProof[0] = "0x1234" }, want: reasonInvalidProof},
+		{name: "未纳入版本", mutate: func(m *Manifest) { m.Citations[0].VersionID = 0 }, want: reasonNotVersioned},
+		{name: "缺少所属版本", mutate: func(m *Manifest) { m.Citations[0].VersionID = 4 }, want: reasonVersionMissing},
+		{name: "版本未上链确认", mutate: func(m *Manifest) { m.Versions[0].Status = string(model.VersionStatusPending) }, want: reasonNotAnchored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newTestSigner(t)
+			m := testManifest(t)
+			tt.mutate(m)
+			b, err := Open(writeBundle(t, m, signer))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := b.Verify(signer.Address())
+			if res.SignatureError != nil {
+				t.Fatalf("Verify() signatureError = %v", res.SignatureError)
+			}
+			if res.OK() || res.Citations[0].Verified || res.Citations[0].Reason != tt.want {
+				t.Errorf("引用 1 = %+v, want %s", res.Citations[0], tt.want)
+			}
+			if tt.want != reasonNotAnchored && !res.Citations[1].Verified {
+				t.Errorf("未改动的引用 2 验证失败: %s", res.Citations[1].Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package evidence
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignatureAlgorithm 签名算法标识
+const SignatureAlgorithm = "secp256k1-eip191-sha256"
+
+var (
+	ErrDigestMismatch   = errors.New("evidence.json 摘要与签名记录不一致")
+	ErrSignerMismatch   = errors.New("签名恢复出的地址与签名账户不一致")
+	ErrInvalidSignature = errors.New("签名格式错误")
+	ErrUnknownAlgorithm = errors.New("不支持的签名算法")
+	ErrUntrustedSigner  = errors.New("签名账户不是期望的服务端地址")
+)
+
+// Signature 服务端签名 (signature.json)
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	Signer    string `json:"signer"`    // 签名账户地址
+	Digest    string `json:"digest"`    // SHA-256(evidence.json) 十六进制
+	Signature string `json:"signature"` // 65 字节 r ‖ s ‖ v (v 为 27/28)
+}
+
+// Signer 证据包签名器
+type Signer struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewSigner 由十六进制 secp256k1 私钥创建签名器
+func NewSigner(hexKey string) (*Signer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("解析签名私钥失败: %w", err)
+	}
+	return &Signer{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// Address 返回签名账户地址
+func (s *Signer) Address() string {
+	return s.address.Hex()
+}
+
+// Sign 对数据的 SHA-256 摘要做 EIP-191 personal_sign 签名
+func (s *Signer) Sign(data []byte) (*Signature, error) {
+	digest := sha256.Sum256(data)
+	sig, err := crypto.Sign(accounts.TextHash(digest[:]), s.key)
+	if err != nil {
+		return nil, err
+	}
+	// 与 eth_sign / ethers 保持一致，v 使用 27/28
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return &Signature{
+		Algorithm: SignatureAlgorithm,
+		Signer:    s.address.Hex(),
+		Digest:    hex.EncodeToString(digest[:]),
+		Signature: hexutil.Encode(sig),
+	}, nil
+}
+
+// verifySignature 校验签名覆盖 data 且由 sig.Signer 签出
+func verifySignature(data []byte, sig *Signature) error {
+	if sig.Algorithm != SignatureAlgorithm {
+		return fmt.Errorf("%w: %s", ErrUnknownAlgorithm, sig.Algorithm)
+	}
+
+	digest := sha256.Sum256(data)
+	if !strings.EqualFold(sig.Digest, hex.EncodeToString(digest[:])) {
+		return ErrDigestMismatch
+	}
+
+	raw, err := hexutil.Decode(sig.Signature)
+	if err != nil || len(raw) != crypto.SignatureLength {
+		return ErrInvalidSignature
+	}
+	if raw[crypto.RecoveryIDOffset] >= 27 {
+		raw[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(digest[:]), raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !common.IsHexAddress(sig.Signer) || crypto.PubkeyToAddress(*pub) != common.HexToAddress(sig.Signer) {
+		return ErrSignerMismatch
+	}
+	return nil
+}
+
+// sameAddress 比较两个以太坊地址 (忽略大小写校验和)
+func sameAddress(a, b string) bool {
+	return common.IsHexAddress(a) && common.HexToAddress(a) == common.HexToAddress(b)
+}
@@ -0,0 +1,116 @@
+package evidence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+)
+
+// 引用验证未通过原因
+const (
+	reasonHashMismatch   = "原文哈希与 contentHash 不一致"
+	reasonNotVersioned   = "引用未纳入知识库版本，无 Merkle 证明"
+	reasonVersionMissing = "证据包中缺少所属版本"
+	reasonNotAnchored    = "所属版本尚未上链确认"
+	reasonInvalidProof   = "Merkle 证明格式错误"
+	reasonRootMismatch   = "由证明计算出的 Root 与版本 merkleRoot 不一致"
+)
+
+// Result 证据包离线验证结果
+type Result struct {
+	Signer         string
+	SignatureError error // 为 nil 表示签名有效
+	Citations      []CitationResult
+}
+
+// CitationResult 单条引用的验证结果
+type CitationResult struct {
+	Index        int
+	ChunkID      string
+	VersionID    int
+	ComputedRoot string
+	Verified     bool
+	Reason       string // 未通过原因
+}
+
+// OK 签名有效且全部引用均可证明属于已上链版本
+func (r *Result) OK() bool {
+	if r.SignatureError != nil {
+		return false
+	}
+	for _, c := range r.Citations {
+		if !c.Verified {
+			return false
+		}
+	}
+	return true
+}
+
+// Verify 离线验证证据包: 签名、引用原文哈希与 Merkle 证明
+// trustedSigner 为已公布的服务端签名地址，非空时签名账户必须与之一致；
+// 版本 Root 是否与链上一致需另行查询存证合约 (见 cmd/verify-evidence -rpc)
+func (b *Bundle) Verify(trustedSigner string) *Result {
+	res := &Result{
+		Signer:         b.Signature.Signer,
+		SignatureError: verifySignature(b.raw, &b.Signature),
+		Citations:      make([]CitationResult, len(b.Manifest.Citations)),
+	}
+	if res.SignatureError == nil && trustedSigner != "" && !sameAddress(trustedSigner, res.Signer) {
+		res.SignatureError = fmt.Errorf("%w: %s，期望 %s", ErrUntrustedSigner, res.Signer, trustedSigner)
+	}
+
+	versions := make(map[int]*Version, len(b.Manifest.Versions))
+	for i := range b.Manifest.Versions {
+		versions[b.Manifest.Versions[i].ID] = &b.Manifest.Versions[i]
+	}
+	for i := range b.Manifest.Citations {
+		res.Citations[i] = verifyCitation(&b.Manifest.Citations[i], versions)
+	}
+	return res
+}
+
+// verifyCitation 验证单条引用
+func verifyCitation(c *Citation, versions map[int]*Version) CitationResult {
+	out := CitationResult{Index: c.Index, ChunkID: c.ChunkID, VersionID: c.VersionID}
+
+	hash := chunker.ContentHash(c.Text)
+	if !strings.EqualFold(hash, c.ContentHash) {
+		out.Reason = reasonHashMismatch
+		return out
+	}
+	if c.VersionID == 0 {
+		out.Reason = reasonNotVersioned
+		return out
+	}
+	version, ok := versions[c.VersionID]
+	if !ok {
+		out.Reason = reasonVersionMissing
+		return out
+	}
+
+	proof, err := merkle.ParseProof(c.MerkleProof)
+	if err != nil {
+		out.Reason = reasonInvalidProof
+		return out
+	}
+	leaf, err := merkle.ContentLeaf(hash)
+	if err != nil {
+		out.Reason = reasonInvalidProof
+		return out
+	}
+	computed := merkle.ComputeRoot(proof, leaf)
+	out.ComputedRoot = computed.Hex()
+
+	switch {
+	case !strings.EqualFold(out.ComputedRoot, version.MerkleRoot):
+		out.Reason = reasonRootMismatch
+	case version.Status != string(model.VersionStatusConfirmed):
+		out.Reason = reasonNotAnchored
+	default:
+		out.Verified = true
+	}
+	return out
+}
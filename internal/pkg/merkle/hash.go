@@ -0,0 +1,80 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// HashSize 哈希字节长度
+const HashSize = 32
+
+var ErrInvalidHash = errors.New("无效的哈希值")
+
+// Hash 32 字节 Keccak-256 哈希 (对应 Solidity bytes32)
+type Hash [HashSize]byte
+
+// Hex 返回 0x 前缀的 66 字符十六进制表示
+func (h Hash) Hex() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// String 实现 fmt.Stringer
+func (h Hash) String() string {
+	return h.Hex()
+}
+
+// ParseHash 解析 64 位十六进制哈希，0x 前缀可选
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s) != HashSize*2 {
+		return h, fmt.Errorf("%w: 长度应为 %d 位十六进制", ErrInvalidHash, HashSize*2)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	return h, nil
+}
+
+// keccak256 计算 Keccak-256 (以太坊使用的原始 Keccak，非 NIST SHA3-256)
+func keccak256(data ...[]byte) Hash {
+	var h Hash
+	d := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		d.Write(b)
+	}
+	d.Sum(h[:0])
+	return h
+}
+
+// LeafHash 计算叶子哈希: keccak256(keccak256(value))
+// value 为 32 字节时与 OpenZeppelin StandardMerkleTree 的 bytes32 叶子编码一致；
+// 双重哈希使叶子 (内层 32 字节输入) 与内部节点 (64 字节输入) 处于不同的域，
+// 避免将内部节点伪造为叶子的第二原像攻击
+func LeafHash(value []byte) Hash {
+	inner := keccak256(value)
+	return keccak256(inner[:])
+}
+
+// ContentLeaf 由分块 ContentHash (SHA-256 十六进制) 计算叶子哈希
+func ContentLeaf(contentHash string) (Hash, error) {
+	raw, err := ParseHash(contentHash)
+	if err != nil {
+		return Hash{}, err
+	}
+	return LeafHash(raw[:]), nil
+}
+
+// NodeHash 计算内部节点哈希: keccak256(min(a, b) ‖ max(a, b))
+// 与 OpenZeppelin Hashes.commutativeKeccak256 一致，证明无需记录左右位置
+func NodeHash(a, b Hash) Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return keccak256(a[:], b[:])
+}
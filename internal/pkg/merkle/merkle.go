@@ -0,0 +1,163 @@
+// Package merkle 提供与 OpenZeppelin MerkleProof 兼容的 Merkle 树
+//
+// 构建规则:
+//   - 叶子: LeafHash(ContentHash 原始 32 字节) = keccak256(keccak256(bytes32))，
+//     顺序即输入顺序 (分块的 MerkleIndex)，不排序、不去重
+//   - 节点: NodeHash(a, b) = keccak256(sorted(a, b))，兄弟节点按字节序排序后拼接
+//   - 奇数节点: 每层末尾落单的节点原样提升到上一层，不复制、不与自身配对，
+//     该层不产生证明元素
+//   - 单叶子树的根即叶子哈希，证明为空
+//
+// 生成的证明可直接用于 Solidity 的 MerkleProof.verify(proof, root, leaf)。
+// 叶子与节点哈希与 @openzeppelin/merkle-tree 的 StandardMerkleTree (bytes32, sortLeaves: false) 一致，
+// 但后者为数组形式的完全二叉树，叶子数为 5、7 等时树形与根不同；两者生成的证明均可由 Verify 验证。
+package merkle
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEmptyTree    = errors.New("Merkle 树至少需要一个叶子")
+	ErrLeafNotFound = errors.New("叶子序号超出范围")
+)
+
+// Tree Merkle 树 (逐层保存节点，levels[0] 为叶子层，最后一层为根)
+type Tree struct {
+	levels [][]Hash
+}
+
+// New 由叶子哈希构建 Merkle 树
+func New(leaves []Hash) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	level := make([]Hash, len(leaves))
+	copy(level, leaves)
+	levels := [][]Hash{level}
+	for len(level) > 1 {
+		next := make([]Hash, 0, (len(level)+1)/2)
+		for i := 0; i+1 < len(level); i += 2 {
+			next = append(next, NodeHash(level[i], level[i+1]))
+		}
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &Tree{levels: levels}, nil
+}
+
+// FromContentHashes 由分块 ContentHash 列表构建 Merkle 树
+func FromContentHashes(contentHashes []string) (*Tree, error) {
+	leaves := make([]Hash, len(contentHashes))
+	for i, h := range contentHashes {
+		leaf, err := ContentLeaf(h)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个叶子: %w", i, err)
+		}
+		leaves[i] = leaf
+	}
+	return New(leaves)
+}
+
+// Root 返回根哈希
+func (t *Tree) Root() Hash {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Len 返回叶子数量
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Leaf 返回指定序号的叶子哈希
+func (t *Tree) Leaf(index int) (Hash, error) {
+	if index < 0 || index >= t.Len() {
+		return Hash{}, ErrLeafNotFound
+	}
+	return t.levels[0][index], nil
+}
+
+// Proof 生成指定叶子的证明 (自底向上的兄弟节点哈希)
+func (t *Tree) Proof(index int) ([]Hash, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, ErrLeafNotFound
+	}
+
+	proof := make([]Hash, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		// 落单节点直接提升，无兄弟节点
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// ProofHex 生成 0x 十六进制格式的证明，可直接存入 DocumentChunk.MerkleProof
+func (t *Tree) ProofHex(index int) ([]string, error) {
+	proof, err := t.Proof(index)
+	if err != nil {
+		return nil, err
+	}
+	return HexProof(proof), nil
+}
+
+// Verify 验证叶子证明，与 OpenZeppelin MerkleProof.verify 等价
+func Verify(proof []Hash, root, leaf Hash) bool {
+	return ComputeRoot(proof, leaf) == root
+}
+
+// ComputeRoot 由叶子与证明计算根哈希 (OpenZeppelin MerkleProof.processProof)
+func ComputeRoot(proof []Hash, leaf Hash) Hash {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = NodeHash(computed, sibling)
+	}
+	return computed
+}
+
+// VerifyContent 验证分块 ContentHash 与十六进制证明是否能还原出 root
+func VerifyContent(contentHash string, proof []string, root string) (bool, error) {
+	leaf, err := ContentLeaf(contentHash)
+	if err != nil {
+		return false, err
+	}
+	expected, err := ParseHash(root)
+	if err != nil {
+		return false, err
+	}
+	hashes, err := ParseProof(proof)
+	if err != nil {
+		return false, err
+	}
+	return Verify(hashes, expected, leaf), nil
+}
+
+// HexProof 将证明转换为 0x 十六进制字符串列表
+func HexProof(proof []Hash) []string {
+	out := make([]string, len(proof))
+	for i, h := range proof {
+		out[i] = h.Hex()
+	}
+	return out
+}
+
+// ParseProof 解析十六进制证明
+func ParseProof(proof []string) ([]Hash, error) {
+	hashes := make([]Hash, len(proof))
+	for i, s := range proof {
+		h, err := ParseHash(s)
+		if err != nil {
+			return nil, fmt.Errorf("证明第 %d 项: %w", i, err)
+		}
+		hashes[i] = h
+	}
+	return hashes, nil
+}
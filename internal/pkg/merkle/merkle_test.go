@@ -0,0 +1,336 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ozVector OpenZeppelin StandardMerkleTree 生成的测试向量 (testdata/gen_vectors.mjs)
+type ozVector struct {
+	Values []string   `json:"values"`
+	Leaves []string   `json:"leaves"`
+	Root   string     `json:"root"`
+	Proofs [][]string `json:"proofs"`
+}
+
+// testLeaves 生成 n 个叶子 (第N条 的 SHA-256 作为 ContentHash)
+func testLeaves(t *testing.T, n int) []Hash {
+	t.Helper()
+	leaves := make([]Hash, n)
+	for i := range leaves {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("第%d条", i+1)))
+		leaf, err := ContentLeaf(hex.EncodeToString(sum[:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves[i] = leaf
+	}
+	return leaves
+}
+
+func mustParse(t *testing.T, s string) Hash {
+	t.Helper()
+	h, err := ParseHash(s)
+	if err != nil {
+		t.Fatalf("ParseHash(%q) error = %v", s, err)
+	}
+	return h
+}
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "0x4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	}
+	for _, tt := range tests {
+		if got := keccak256([]byte(tt.in)).Hex(); got != tt.want {
+			t.Errorf("keccak256(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNodeHashSorted(t *testing.T) {
+	leaves := testLeaves(t, 2)
+	a, b := leaves[0], leaves[1]
+	if NodeHash(a, b) != NodeHash(b, a) {
+		t.Fatal("NodeHash 应与参数顺序无关")
+	}
+
+	lo, hi := a, b
+	if bytes.Compare(lo[:], hi[:]) > 0 {
+		lo, hi = hi, lo
+	}
+	if got, want := NodeHash(a, b), keccak256(lo[:], hi[:]); got != want {
+		t.Errorf("NodeHash = %s, want keccak256(min ‖ max) = %s", got, want)
+	}
+	if NodeHash(a, b) == keccak256(hi[:], lo[:]) {
+		t.Error("兄弟节点应按字节序升序拼接")
+	}
+}
+
+func TestLeafHashDomain(t *testing.T) {
+	leaves := testLeaves(t, 2)
+	node := NodeHash(leaves[0], leaves[1])
+
+	// 叶子为双重哈希，内部节点不能被当作叶子通过验证
+	var pair []byte
+	pair = append(pair, leaves[0][:]...)
+	pair = append(pair, leaves[1][:]...)
+	if LeafHash(pair) == node {
+		t.Error("64 字节输入的叶子哈希不应等于内部节点哈希")
+	}
+	if LeafHash(leaves[0][:]) == keccak256(leaves[0][:]) {
+		t.Error("叶子应为双重 keccak256")
+	}
+}
+
+func TestEmptyTree(t *testing.T) {
+	if _, err := New(nil); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("New(nil) error = %v, want ErrEmptyTree", err)
+	}
+	if _, err := FromContentHashes([]string{}); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("FromContentHashes([]) error = %v, want ErrEmptyTree", err)
+	}
+	if _, err := FromContentHashes([]string{"abc"}); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("FromContentHashes(abc) error = %v, want ErrInvalidHash", err)
+	}
+}
+
+func TestSingleLeaf(t *testing.T) {
+	leaf := testLeaves(t, 1)[0]
+	tree, err := New([]Hash{leaf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root() != leaf {
+		t.Errorf("Root() = %s, want leaf %s", tree.Root(), leaf)
+	}
+	proof, err := tree.Proof(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof) != 0 {
+		t.Errorf("Proof(0) = %v, want empty", proof)
+	}
+	if !Verify(proof, tree.Root(), leaf) {
+		t.Error("单叶子树的空证明应验证通过")
+	}
+}
+
+func TestOddLeafPromotion(t *testing.T) {
+	l := testLeaves(t, 5)
+
+	// 3 个叶子: 第 3 个叶子落单提升，与 (l0,l1) 在上一层配对
+	three, _ := New(l[:3])
+	if got, want := three.Root(), NodeHash(NodeHash(l[0], l[1]), l[2]); got != want {
+		t.Errorf("3 leaves root = %s, want %s", got, want)
+	}
+	if proof, _ := three.Proof(2); !reflect.DeepEqual(proof, []Hash{NodeHash(l[0], l[1])}) {
+		t.Errorf("3 leaves Proof(2) = %v", proof)
+	}
+	if proof, _ := three.Proof(0); !reflect.DeepEqual(proof, []Hash{l[1], l[2]}) {
+		t.Errorf("3 leaves Proof(0) = %v", proof)
+	}
+
+	// 5 个叶子: 第 5 个叶子连续两层提升，直到根节点前才配对 (不复制、不与自身配对)
+	five, _ := New(l)
+	inner := NodeHash(NodeHash(l[0], l[1]), NodeHash(l[2], l[3]))
+	if got, want := five.Root(), NodeHash(inner, l[4]); got != want {
+		t.Errorf("5 leaves root = %s, want %s", got, want)
+	}
+	if proof, _ := five.Proof(4); !reflect.DeepEqual(proof, []Hash{inner}) {
+		t.Errorf("5 leaves Proof(4) = %v, want [%s]", proof, inner)
+	}
+	if proof, _ := five.Proof(0); !reflect.DeepEqual(proof, []Hash{l[1], NodeHash(l[2], l[3]), l[4]}) {
+		t.Errorf("5 leaves Proof(0) = %v", proof)
+	}
+}
+
+func TestProofRoundTrip(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := testLeaves(t, n)
+		tree, err := New(leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.Len() != n {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), n)
+		}
+		root := tree.Root()
+		other := testLeaves(t, n+1)[n]
+
+		for i := 0; i < n; i++ {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatalf("n=%d Proof(%d) error = %v", n, i, err)
+			}
+			if !Verify(proof, root, leaves[i]) {
+				t.Errorf("n=%d Proof(%d) 验证失败", n, i)
+			}
+			if Verify(proof, root, other) {
+				t.Errorf("n=%d Proof(%d) 对其他叶子验证通过", n, i)
+			}
+			if len(proof) > 0 {
+				tampered := append([]Hash(nil), proof...)
+				tampered[len(tampered)-1][0] ^= 1
+				if Verify(tampered, root, leaves[i]) {
+					t.Errorf("n=%d Proof(%d) 篡改后仍验证通过", n, i)
+				}
+			}
+
+			// 十六进制证明经 JSON 存取后仍可验证
+			hexProof, _ := tree.ProofHex(i)
+			data, _ := json.Marshal(hexProof)
+			var stored []string
+			if err := json.Unmarshal(data, &stored); err != nil {
+				t.Fatal(err)
+			}
+			sum := sha256.Sum256([]byte(fmt.Sprintf("第%d条", i+1)))
+			ok, err := VerifyContent(hex.EncodeToString(sum[:]), stored, root.Hex())
+			if err != nil || !ok {
+				t.Errorf("n=%d VerifyContent(%d) = %v, %v", n, i, ok, err)
+			}
+		}
+
+		for _, i := range []int{-1, n} {
+			if _, err := tree.Proof(i); !errors.Is(err, ErrLeafNotFound) {
+				t.Errorf("n=%d Proof(%d) error = %v, want ErrLeafNotFound", n, i, err)
+			}
+			if _, err := tree.Leaf(i); !errors.Is(err, ErrLeafNotFound) {
+				t.Errorf("n=%d Leaf(%d) error = %v, want ErrLeafNotFound", n, i, err)
+			}
+		}
+	}
+}
+
+func TestVerifyContentInvalid(t *testing.T) {
+	valid := "0x" + hex.EncodeToString(make([]byte, HashSize))
+	tests := []struct {
+		name        string
+		contentHash string
+		proof       []string
+		root        string
+	}{
+		{name: "ContentHash 长度错误", contentHash: "abcd", root: valid},
+		{name: "Root 非十六进制", contentHash: valid, root: "0x" + strings.Repeat("g", 64)},
+		{name: "证明元素错误", contentHash: valid, proof: []string{"0x12"}, root: valid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyContent(tt.contentHash, tt.proof, tt.root); !errors.Is(err, ErrInvalidHash) {
+				t.Errorf("VerifyContent() error = %v, want ErrInvalidHash", err)
+			}
+		})
+	}
+}
+
+func TestParseHash(t *testing.T) {
+	raw := "8192d4a55bfdfd0c0e20e2bf6ad5f3643d9e512ee0733857e722b0e9eab02c35"
+	for _, s := range []string{raw, "0x" + raw, "0X" + raw} {
+		if h := mustParse(t, s); h.Hex() != "0x"+raw {
+			t.Errorf("ParseHash(%q).Hex() = %s", s, h.Hex())
+		}
+	}
+	for _, s := range []string{"", "0x", raw[:62], raw + "00", "zz" + raw[2:]} {
+		if _, err := ParseHash(s); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("ParseHash(%q) error = %v, want ErrInvalidHash", s, err)
+		}
+	}
+}
+
+// TestOpenZeppelinVectors 与 @openzeppelin/merkle-tree 生成的向量比对，哈希规则的任何改动都会导致失败
+//
+// StandardMerkleTree 使用数组形式的完全二叉树，与本包的落单提升规则在部分叶子数下树形不同 (如 5、7)，
+// 此时根不同，但 OpenZeppelin 的证明仍须能通过 Verify (与链上 MerkleProof.verify 等价)
+func TestOpenZeppelinVectors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "openzeppelin_vectors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []ozVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 8 {
+		t.Fatalf("vectors = %d, want 8", len(vectors))
+	}
+
+	// 树形与 StandardMerkleTree 相同的叶子数
+	sameShape := map[int]bool{1: true, 2: true, 3: true, 4: true, 6: true, 8: true}
+	// 树形不同时本包的根 (固定值，防止构建规则被无意修改)
+	ownRoots := map[int]string{
+		5: "0x3cb580f3703e65204a9592f8678a297c0cc17c0e7b6f852e800d62ae382cea79",
+		7: "0x99a5137d1b2af4970a618e95de5933be0a48dca0d8fe851e9b126cbe808464fc",
+	}
+
+	for _, v := range vectors {
+		n := len(v.Values)
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			tree, err := FromContentHashes(v.Values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, value := range v.Values {
+				leaf, _ := tree.Leaf(i)
+				if leaf.Hex() != v.Leaves[i] {
+					t.Errorf("Leaf(%d) = %s, want %s", i, leaf.Hex(), v.Leaves[i])
+				}
+				ok, err := VerifyContent(value, v.Proofs[i], v.Root)
+				if err != nil || !ok {
+					t.Errorf("OpenZeppelin 证明 %d 验证失败: %v, %v", i, ok, err)
+				}
+			}
+
+			if !sameShape[n] {
+				if got := tree.Root().Hex(); got != ownRoots[n] {
+					t.Errorf("Root() = %s, want %s", got, ownRoots[n])
+				}
+				return
+			}
+			if got := tree.Root().Hex(); got != v.Root {
+				t.Errorf("Root() = %s, want %s", got, v.Root)
+			}
+			for i := range v.Values {
+				proof, _ := tree.ProofHex(i)
+				if !reflect.DeepEqual(proof, v.Proofs[i]) {
+					t.Errorf("ProofHex(%d) = %v, want %v", i, proof, v.Proofs[i])
+				}
+			}
+		})
+	}
+}
+
+// TestOpenZeppelinReadme @openzeppelin/merkle-tree README 中 ["address", "uint256"] 示例的根
+func TestOpenZeppelinReadme(t *testing.T) {
+	encode := func(addr, amount string) []byte {
+		out := make([]byte, 64)
+		a, _ := hex.DecodeString(addr)
+		copy(out[32-len(a):32], a)
+		v, _ := new(big.Int).SetString(amount, 10)
+		v.FillBytes(out[32:])
+		return out
+	}
+	tree, err := New([]Hash{
+		LeafHash(encode("1111111111111111111111111111111111111111", "5000000000000000000")),
+		LeafHash(encode("2222222222222222222222222222222222222222", "2500000000000000000")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tree.Root().Hex(), "0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77"; got != want {
+		t.Errorf("Root() = %s, want %s", got, want)
+	}
+}
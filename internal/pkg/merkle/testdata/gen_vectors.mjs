@@ -0,0 +1,24 @@
+// 生成 openzeppelin_vectors.json，用于核对 merkle 包与 OpenZeppelin 的兼容性
+//
+//   npm install @openzeppelin/merkle-tree@1
+//   node gen_vectors.mjs > openzeppelin_vectors.json
+//
+// 叶子为 bytes32 ContentHash (第N条 的 SHA-256)，保持输入顺序 (sortLeaves: false)
+import { StandardMerkleTree } from '@openzeppelin/merkle-tree';
+import { createHash } from 'node:crypto';
+
+const vectors = [];
+for (let n = 1; n <= 8; n++) {
+  const values = [];
+  for (let i = 1; i <= n; i++) {
+    values.push(['0x' + createHash('sha256').update(`第${i}条`).digest('hex')]);
+  }
+  const tree = StandardMerkleTree.of(values, ['bytes32'], { sortLeaves: false });
+  vectors.push({
+    values: values.map((v) => v[0]),
+    leaves: values.map((v) => tree.leafHash(v)),
+    root: tree.root,
+    proofs: values.map((_, i) => tree.getProof(i)),
+  });
+}
+console.log(JSON.stringify(vectors, null, 2));
@@ -0,0 +1,84 @@
+// Package textsearch 提供中文全文检索的分词
+//
+// 不依赖 zhparser、pg_jieba 等数据库扩展: 在 Go 侧将文本切分为
+// 汉字二元组 (bigram) 与小写的字母数字词，以空格拼接后存入 search_text 列，
+// 由 PostgreSQL 的 simple 配置 (不做词干化) 建立 GIN 索引并检索。
+//
+//	"民法典第1165条" → "民法 法典 典第 1165 条"
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxQueryTerms 查询最多使用的词项数，避免长问题生成过大的 tsquery
+const maxQueryTerms = 64
+
+// Tokenize 将文本切分为词项 (保留重复词项，用于词频排序)
+// 连续汉字切分为二元组，单个汉字保留为一元词；连续字母数字转为小写整词；其余字符作为分隔符
+func Tokenize(text string) []string {
+	var (
+		tokens []string
+		han    []rune
+		word   []rune
+	)
+	flushHan := func() {
+		switch len(han) {
+		case 0:
+		case 1:
+			tokens = append(tokens, string(han))
+		default:
+			for i := 0; i+1 < len(han); i++ {
+				tokens = append(tokens, string(han[i:i+2]))
+			}
+		}
+		han = han[:0]
+	}
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushHan()
+			flushWord()
+		}
+	}
+	flushHan()
+	flushWord()
+	return tokens
+}
+
+// Document 生成写入 search_text 列的索引文本
+func Document(text string) string {
+	return strings.Join(Tokenize(text), " ")
+}
+
+// Query 生成 to_tsquery('simple', ...) 查询表达式 (词项之间为 OR)
+// 文本中没有可检索的词项时返回空字符串
+func Query(text string) string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0, maxQueryTerms)
+	for _, t := range Tokenize(text) {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		terms = append(terms, t)
+		if len(terms) == maxQueryTerms {
+			break
+		}
+	}
+	return strings.Join(terms, " | ")
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+)
+
+// AuditRepository 审计日志数据访问接口
+type AuditRepository interface {
+	Create(ctx context.Context, log *model.AuditLog) error
+	// HasUnresolved 同一来源是否已有未处理完毕的同类审计事件 (用于告警去重)
+	HasUnresolved(ctx context.Context, auditType model.AuditType, source string) (bool, error)
+}
+
+// auditRepository 审计日志数据访问实现
+type auditRepository struct{}
+
+// NewAuditRepository 创建审计日志数据访问实例
+func NewAuditRepository() AuditRepository {
+	return &auditRepository{}
+}
+
+// Create 写入审计日志
+func (r *auditRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	return database.DB().WithContext(ctx).Create(log).Error
+}
+
+// HasUnresolved 查询未处理完毕的同类审计事件
+func (r *auditRepository) HasUnresolved(ctx context.Context, auditType model.AuditType, source string) (bool, error) {
+	var count int64
+	err := database.DB().WithContext(ctx).Model(&model.AuditLog{}).
+		Where("type = ? AND source = ? AND status <> ?", auditType, source, model.AuditResolved).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrChatSessionNotFound = errors.New("会话不存在")
+	ErrCitationNotFound    = errors.New("引用不存在")
+	ErrMessageNotFound     = errors.New("消息不存在")
+)
+
+// ChatExchange 一轮问答的持久化数据
+type ChatExchange struct {
+	UserMessage      *model.ChatMessage
+	AssistantMessage *model.ChatMessage
+	Title            string            // 会话标题为空时写入
+	TokensUsed       int               // 本轮消耗的 Token
+	Usage            *model.TokenUsage // 登录用户的用量记录 (可选)
+}
+
+// SessionOwnerFilter 会话归属筛选条件 (登录用户优先)
+type SessionOwnerFilter struct {
+	UserID  string
+	GuestID string
+}
+
+// SessionCursor 会话列表游标
+// 按 COALESCE(last_message_at, created_at) 倒序、ID 倒序排列
+type SessionCursor struct {
+	SortAt time.Time
+	ID     string
+}
+
+// ChatRepository 聊天数据访问接口
+type ChatRepository interface {
+	// 会话
+	FindSessionByID(ctx context.Context, id string) (*model.ChatSession, error)
+	CreateSession(ctx context.Context, session *model.ChatSession) error
+	ListSessions(ctx context.Context, owner SessionOwnerFilter, cursor *SessionCursor, limit int) ([]model.ChatSession, error)
+	CountSessions(ctx context.Context, owner SessionOwnerFilter) (int64, error)
+	DeleteSession(ctx context.Context, id string) error
+
+	// ClaimGuestSessions 在同一事务中将匿名会话转移给登录用户，返回转移的会话数
+	ClaimGuestSessions(ctx context.Context, guestID, userID string) (int64, error)
+
+	// 消息
+	ListRecentMessages(ctx context.Context, sessionID string, limit int) ([]model.ChatMessage, error)
+	ListMessagesWithCitations(ctx context.Context, sessionID string) ([]model.ChatMessage, error)
+	FindMessageWithCitations(ctx context.Context, id string) (*model.ChatMessage, error)
+	// FindPrecedingUserMessage 查询会话中 before 之前的最后一条用户消息 (回答对应的问题)
+	FindPrecedingUserMessage(ctx context.Context, sessionID string, before time.Time) (*model.ChatMessage, error)
+
+	// FindCitationByID 按 ID 查询消息引用
+	FindCitationByID(ctx context.Context, id int64) (*model.MessageCitation, error)
+
+	// SaveExchange 在同一事务中保存问答消息、引用并更新会话与用户统计
+	SaveExchange(ctx context.Context, sessionID string, exchange *ChatExchange) error
+}
+
+// chatRepository 聊天数据访问实现
+type chatRepository struct{}
+
+// NewChatRepository 创建聊天数据访问实例
+func NewChatRepository() ChatRepository {
+	return &chatRepository{}
+}
+
+// FindSessionByID 按 ID 查询会话
+func (r *chatRepository) FindSessionByID(ctx context.Context, id string) (*model.ChatSession, error) {
+	var session model.ChatSession
+	if err := database.DB().WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChatSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// CreateSession 创建会话
+func (r *chatRepository) CreateSession(ctx context.Context, session *model.ChatSession) error {
+	return database.DB().WithContext(ctx).Create(session).Error
+}
+
+// sessionSortExpr 会话列表排序字段
+const sessionSortExpr = "COALESCE(last_message_at, created_at)"
+
+// ListSessions 游标分页查询会话列表
+func (r *chatRepository) ListSessions(ctx context.Context, owner SessionOwnerFilter, cursor *SessionCursor, limit int) ([]model.ChatSession, error) {
+	var sessions []model.ChatSession
+
+	db := applySessionOwner(database.DB().WithContext(ctx).Model(&model.ChatSession{}), owner)
+	if cursor != nil {
+		db = db.Where("("+sessionSortExpr+" < ?) OR ("+sessionSortExpr+" = ? AND id < ?)",
+			cursor.SortAt, cursor.SortAt, cursor.ID)
+	}
+
+	if err := db.Order(sessionSortExpr + " DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CountSessions 统计会话数量
+func (r *chatRepository) CountSessions(ctx context.Context, owner SessionOwnerFilter) (int64, error) {
+	var count int64
+	db := applySessionOwner(database.DB().WithContext(ctx).Model(&model.ChatSession{}), owner)
+	if err := db.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteSession 软删除会话
+func (r *chatRepository) DeleteSession(ctx context.Context, id string) error {
+	result := database.DB().WithContext(ctx).Delete(&model.ChatSession{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrChatSessionNotFound
+	}
+	return nil
+}
+
+// ClaimGuestSessions 认领匿名会话
+// 消息与引用通过 session_id 关联，随会话一并转移；会话消耗的 Token 计入用户已用额度
+func (r *chatRepository) ClaimGuestSessions(ctx context.Context, guestID, userID string) (int64, error) {
+	var claimed int64
+	err := database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sessions []model.ChatSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "tokens_used").
+			Where("guest_session_id = ? AND (user_id = '' OR user_id IS NULL)", guestID).
+			Find(&sessions).Error; err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(sessions))
+		var tokens int64
+		for i, s := range sessions {
+			ids[i] = s.ID
+			tokens += int64(s.TokensUsed)
+		}
+
+		result := tx.Model(&model.ChatSession{}).Where("id IN ?", ids).Update("user_id", userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		claimed = result.RowsAffected
+
+		if tokens > 0 {
+			if err := tx.Model(&model.User{}).
+				Where("id = ?", userID).
+				Update("token_used", gorm.Expr("token_used + ?", tokens)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return claimed, nil
+}
+
+// ListMessagesWithCitations 查询会话全部消息及其引用 (按时间正序)
+func (r *chatRepository) ListMessagesWithCitations(ctx context.Context, sessionID string) ([]model.ChatMessage, error) {
+	var messages []model.ChatMessage
+	if err := database.DB().WithContext(ctx).
+		Preload("Citations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Where("session_id = ?", sessionID).
+		Order("created_at ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindMessageWithCitations 按 ID 查询消息及其引用
+func (r *chatRepository) FindMessageWithCitations(ctx context.Context, id string) (*model.ChatMessage, error) {
+	var msg model.ChatMessage
+	if err := database.DB().WithContext(ctx).
+		Preload("Citations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&msg, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// FindPrecedingUserMessage 查询回答对应的问题
+func (r *chatRepository) FindPrecedingUserMessage(ctx context.Context, sessionID string, before time.Time) (*model.ChatMessage, error) {
+	var msg model.ChatMessage
+	if err := database.DB().WithContext(ctx).
+		Where("session_id = ? AND role = ? AND created_at < ?", sessionID, model.RoleUserMsg, before).
+		Order("created_at DESC").
+		First(&msg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// FindCitationByID 按 ID 查询消息引用
+func (r *chatRepository) FindCitationByID(ctx context.Context, id int64) (*model.MessageCitation, error) {
+	var citation model.MessageCitation
+	if err := database.DB().WithContext(ctx).First(&citation, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCitationNotFound
+		}
+		return nil, err
+	}
+	return &citation, nil
+}
+
+// ListRecentMessages 查询会话最近的消息 (按时间正序返回)
+func (r *chatRepository) ListRecentMessages(ctx context.Context, sessionID string, limit int) ([]model.ChatMessage, error) {
+	var messages []model.ChatMessage
+	if err := database.DB().WithContext(ctx).
+		Where("session_id = ?", sessionID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	// 反转为正序
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// SaveExchange 保存一轮问答
+func (r *chatRepository) SaveExchange(ctx context.Context, sessionID string, exchange *ChatExchange) error {
+	return database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 用户消息先于助手消息写入，保证时间顺序
+		if err := tx.Create(exchange.UserMessage).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(exchange.AssistantMessage).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"message_count":   gorm.Expr("message_count + ?", 2),
+			"tokens_used":     gorm.Expr("tokens_used + ?", exchange.TokensUsed),
+			"last_message_at": now,
+		}
+		if exchange.Title != "" {
+			updates["title"] = gorm.Expr("CASE WHEN title = '' OR title IS NULL THEN ? ELSE title END", exchange.Title)
+		}
+		result := tx.Model(&model.ChatSession{}).Where("id = ?", sessionID).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrChatSessionNotFound
+		}
+
+		// 登录用户扣减额度并记录用量
+		if exchange.Usage != nil {
+			if err := tx.Model(&model.User{}).
+				Where("id = ?", exchange.Usage.UserID).
+				Update("token_used", gorm.Expr("token_used + ?", exchange.Usage.TotalTokens)).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(exchange.Usage).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// applySessionOwner 应用会话归属筛选
+// 匿名会话被认领后 user_id 不再为空，不再对匿名用户可见
+func applySessionOwner(db *gorm.DB, owner SessionOwnerFilter) *gorm.DB {
+	if owner.UserID != "" {
+		return db.Where("user_id = ?", owner.UserID)
+	}
+	return db.Where("guest_session_id = ? AND (user_id = '' OR user_id IS NULL)", owner.GuestID)
+}
@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDocumentNotFound = errors.New("文档不存在")
+)
+
+// chunkInsertBatchSize 分块批量写入大小
+const chunkInsertBatchSize = 200
+
+// DocumentRepository 文档与分块数据访问接口
+type DocumentRepository interface {
+	// 文档
+	FindByID(ctx context.Context, id string) (*model.Document, error)
+	FindByIDs(ctx context.Context, ids []string) ([]model.Document, error)
+	// FindByLawNames 按法律名称或文档名称查询文档
+	FindByLawNames(ctx context.Context, names []string) ([]model.Document, error)
+	Create(ctx context.Context, doc *model.Document) error
+	UpdateFields(ctx context.Context, id string, fields map[string]interface{}) error
+	ListWithFilters(ctx context.Context, page, pageSize int, filters *dto.DocumentListRequest) ([]model.Document, int64, error)
+	Delete(ctx context.Context, id string) error
+
+	// 状态流转
+	// TransitStatus 仅当文档处于 from 状态之一时更新为 to，返回是否更新成功
+	TransitStatus(ctx context.Context, id string, from []model.DocumentStatus, to model.DocumentStatus) (bool, error)
+	// ClaimPending 领取最早的待处理文档并标记为处理中，无待处理文档时返回 nil
+	ClaimPending(ctx context.Context) (*model.Document, error)
+	// Heartbeat 续期处理中文档的租约，文档已不在处理中时返回 ErrDocumentNotFound
+	Heartbeat(ctx context.Context, id string) error
+	// ReclaimStale 将租约在 before 之前过期的处理中文档重置为待处理 (恢复实例崩溃或关闭时中断的任务)
+	ReclaimStale(ctx context.Context, before time.Time) (int64, error)
+
+	// 分块
+	FindChunksByChunkIDs(ctx context.Context, chunkIDs []string) ([]model.DocumentChunk, error)
+	// ReplaceChunks 在同一事务中替换文档的全部分块，并将文档标记为已索引
+	// 内容哈希未变化的分块沿用原有的知识库版本与 Merkle 证明
+	ReplaceChunks(ctx context.Context, documentID string, chunks []model.DocumentChunk) error
+	// UpdateValidity 在同一事务中更新文档字段与全部分块的时效，返回更新后的分块
+	// 分块时效按条号取 articles 中的设置，未设置的条文使用 base
+	UpdateValidity(ctx context.Context, documentID string, fields map[string]interface{}, base ChunkValidity, articles map[string]ChunkValidity) ([]model.DocumentChunk, error)
+	// BackfillChunkValidity 为未设置时效的分块填入所属文档的生效与失效日期，返回更新的分块数
+	BackfillChunkValidity(ctx context.Context) (int64, error)
+
+	// 分块检索
+	// SearchChunks 全文检索已向量化的分块，query 为 to_tsquery('simple') 表达式，按相关度降序
+	// asOf 非零时仅返回在该日有效的条文，lawTypes 非空时仅返回所属文档法律类型在其中的条文
+	SearchChunks(ctx context.Context, query string, limit int, asOf time.Time, lawTypes []string) ([]ChunkSearchHit, error)
+	// FindChunksByArticle 按条号查询已向量化的分块
+	FindChunksByArticle(ctx context.Context, article string, limit int) ([]model.DocumentChunk, error)
+	// FindChunksByArticles 查询指定文档中条号属于 articles 的已向量化分块
+	FindChunksByArticles(ctx context.Context, documentIDs, articles []string) ([]model.DocumentChunk, error)
+	// EnsureChunkSearchIndex 幂等地创建全文检索 GIN 索引
+	EnsureChunkSearchIndex(ctx context.Context) error
+	// ListChunksWithoutSearchText 列出 afterID 之后缺少全文检索分词的分块 (按 ID 游标分页，用于回填历史数据)
+	ListChunksWithoutSearchText(ctx context.Context, afterID int64, limit int) ([]model.DocumentChunk, error)
+	// UpdateChunkSearchText 更新分块的全文检索分词
+	UpdateChunkSearchText(ctx context.Context, id int64, searchText string) error
+}
+
+// ChunkSearchHit 全文检索命中的分块
+type ChunkSearchHit struct {
+	model.DocumentChunk
+	Rank float64 // ts_rank_cd 相关度
+}
+
+// ChunkValidity 分块时效
+type ChunkValidity struct {
+	EffectiveDate *time.Time
+	ExpiryDate    *time.Time
+	SupersededBy  string
+}
+
+// columns 转换为分块更新字段 (日期为空时写入 NULL)
+func (v ChunkValidity) columns() map[string]interface{} {
+	return map[string]interface{}{
+		"effective_date": v.EffectiveDate,
+		"expiry_date":    v.ExpiryDate,
+		"superseded_by":  v.SupersededBy,
+	}
+}
+
+// chunkSearchVector 全文检索向量表达式，需与 GIN 索引表达式一致才能命中索引
+const chunkSearchVector = "to_tsvector('simple', document_chunks.search_text)"
+
+// documentRepository 文档数据访问实现
+type documentRepository struct{}
+
+// NewDocumentRepository 创建文档数据访问实例
+func NewDocumentRepository() DocumentRepository {
+	return &documentRepository{}
+}
+
+// FindByID 按 ID 查询文档
+func (r *documentRepository) FindByID(ctx context.Context, id string) (*model.Document, error) {
+	var doc model.Document
+	if err := database.DB().WithContext(ctx).First(&doc, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// FindByIDs 批量查询文档 (已删除的文档不返回)
+func (r *documentRepository) FindByIDs(ctx context.Context, ids []string) ([]model.Document, error) {
+	var docs []model.Document
+	if len(ids) == 0 {
+		return docs, nil
+	}
+	if err := database.DB().WithContext(ctx).Where("id IN ?", ids).Find(&docs).Error; err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// FindByLawNames 按法律名称或文档名称查询文档
+func (r *documentRepository) FindByLawNames(ctx context.Context, names []string) ([]model.Document, error) {
+	var docs []model.Document
+	if len(names) == 0 {
+		return docs, nil
+	}
+	if err := database.DB().WithContext(ctx).Where("law_name IN ? OR name IN ?", names, names).Find(&docs).Error; err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Create 创建文档
+func (r *documentRepository) Create(ctx context.Context, doc *model.Document) error {
+	return database.DB().WithContext(ctx).Create(doc).Error
+}
+
+// UpdateFields 更新指定字段
+func (r *documentRepository) UpdateFields(ctx context.Context, id string, fields map[string]interface{}) error {
+	result := database.DB().WithContext(ctx).Model(&model.Document{}).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDocumentNotFound
+	}
+	return nil
+}
+
+// ListWithFilters 带筛选条件的分页查询
+func (r *documentRepository) ListWithFilters(ctx context.Context, page, pageSize int, filters *dto.DocumentListRequest) ([]model.Document, int64, error) {
+	var docs []model.Document
+	var total int64
+
+	db := database.DB().WithContext(ctx).Model(&model.Document{})
+
+	// 应用筛选条件
+	if filters.Status != "" {
+		db = db.Where("status = ?", filters.Status)
+	}
+	if filters.Type != "" {
+		db = db.Where("type = ?", filters.Type)
+	}
+	if filters.LawType != "" {
+		db = db.Where("law_type = ?", filters.LawType)
+	}
+	if filters.Keyword != "" {
+		// 模糊搜索文档名称或法律名称
+		db = db.Where("name LIKE ? OR law_name LIKE ?", "%"+filters.Keyword+"%", "%"+filters.Keyword+"%")
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := db.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&docs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return docs, total, nil
+}
+
+// Delete 软删除文档并删除其分块
+func (r *documentRepository) Delete(ctx context.Context, id string) error {
+	return database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Document{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDocumentNotFound
+		}
+		return tx.Where("document_id = ?", id).Delete(&model.DocumentChunk{}).Error
+	})
+}
+
+// TransitStatus 条件更新文档状态
+func (r *documentRepository) TransitStatus(ctx context.Context, id string, from []model.DocumentStatus, to model.DocumentStatus) (bool, error) {
+	result := database.DB().WithContext(ctx).
+		Model(&model.Document{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(map[string]interface{}{
+			"status":        to,
+			"process_error": "",
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimPending 领取待处理文档
+// 使用 SKIP LOCKED，多个 Worker 并发领取时不会拿到同一文档
+func (r *documentRepository) ClaimPending(ctx context.Context) (*model.Document, error) {
+	var claimed *model.Document
+	err := database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var doc model.Document
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.DocStatusPending).
+			Order("created_at ASC").
+			First(&doc).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&doc).Updates(map[string]interface{}{
+			"status":        model.DocStatusProcessing,
+			"process_error": "",
+			"heartbeat_at":  time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		claimed = &doc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// Heartbeat 续期处理中文档的租约
+func (r *documentRepository) Heartbeat(ctx context.Context, id string) error {
+	result := database.DB().WithContext(ctx).
+		Model(&model.Document{}).
+		Where("id = ? AND status = ?", id, model.DocStatusProcessing).
+		Update("heartbeat_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDocumentNotFound
+	}
+	return nil
+}
+
+// ReclaimStale 重置租约过期的处理中文档
+// 升级前领取的文档没有心跳时间，按最后更新时间判断
+func (r *documentRepository) ReclaimStale(ctx context.Context, before time.Time) (int64, error) {
+	result := database.DB().WithContext(ctx).
+		Model(&model.Document{}).
+		Where("status = ? AND COALESCE(heartbeat_at, updated_at) < ?", model.DocStatusProcessing, before).
+		Updates(map[string]interface{}{
+			"status":       model.DocStatusPending,
+			"heartbeat_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindChunksByChunkIDs 按 ChunkID 批量查询分块
+func (r *documentRepository) FindChunksByChunkIDs(ctx context.Context, chunkIDs []string) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	if len(chunkIDs) == 0 {
+		return chunks, nil
+	}
+	if err := database.DB().WithContext(ctx).Where("chunk_id IN ?", chunkIDs).Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// ReplaceChunks 替换文档分块并更新文档状态为已索引
+func (r *documentRepository) ReplaceChunks(ctx context.Context, documentID string, chunks []model.DocumentChunk) error {
+	return database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := keepChunkProofs(tx, documentID, chunks); err != nil {
+			return err
+		}
+		if err := tx.Where("document_id = ?", documentID).Delete(&model.DocumentChunk{}).Error; err != nil {
+			return err
+		}
+		if len(chunks) > 0 {
+			if err := tx.CreateInBatches(chunks, chunkInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&model.Document{}).Where("id = ?", documentID).Updates(map[string]interface{}{
+			"status":        model.DocStatusIndexed,
+			"process_error": "",
+			"chunk_count":   len(chunks),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDocumentNotFound
+		}
+		return nil
+	})
+}
+
+// keepChunkProofs 为内容哈希未变化的新分块沿用旧分块的版本与 Merkle 证明
+// Merkle 叶子仅由内容哈希决定，条文顺序变化 (ChunkID 改变) 时证明仍然有效
+func keepChunkProofs(tx *gorm.DB, documentID string, chunks []model.DocumentChunk) error {
+	var existing []model.DocumentChunk
+	err := tx.Select("content_hash", "version_id", "merkle_index", "merkle_proof").
+		Where("document_id = ? AND version_id > 0", documentID).
+		Find(&existing).Error
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+
+	proofs := make(map[string]*model.DocumentChunk, len(existing))
+	for i := range existing {
+		proofs[existing[i].ContentHash] = &existing[i]
+	}
+	for i := range chunks {
+		old, ok := proofs[chunks[i].ContentHash]
+		if !ok || chunks[i].VersionID != 0 {
+			continue
+		}
+		chunks[i].VersionID = old.VersionID
+		chunks[i].MerkleIndex = old.MerkleIndex
+		chunks[i].MerkleProof = old.MerkleProof
+	}
+	return nil
+}
+
+// UpdateValidity 更新文档与分块时效
+func (r *documentRepository) UpdateValidity(ctx context.Context, documentID string, fields map[string]interface{}, base ChunkValidity, articles map[string]ChunkValidity) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	err := database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Document{}).Where("id = ?", documentID).Updates(fields)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDocumentNotFound
+		}
+
+		if err := tx.Model(&model.DocumentChunk{}).Where("document_id = ?", documentID).Updates(base.columns()).Error; err != nil {
+			return err
+		}
+		for article, v := range articles {
+			if err := tx.Model(&model.DocumentChunk{}).
+				Where("document_id = ? AND article_number = ?", documentID, article).
+				Updates(v.columns()).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("document_id = ?", documentID).Order("chunk_order").Find(&chunks).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// BackfillChunkValidity 回填分块时效
+func (r *documentRepository) BackfillChunkValidity(ctx context.Context) (int64, error) {
+	result := database.DB().WithContext(ctx).Exec(`UPDATE document_chunks
+		SET effective_date = documents.effective_date, expiry_date = documents.expiry_date
+		FROM documents
+		WHERE document_chunks.document_id = documents.id
+			AND document_chunks.effective_date IS NULL AND document_chunks.expiry_date IS NULL
+			AND (documents.effective_date IS NOT NULL OR documents.expiry_date IS NOT NULL)`)
+	return result.RowsAffected, result.Error
+}
+
+// SearchChunks 全文检索分块
+func (r *documentRepository) SearchChunks(ctx context.Context, query string, limit int, asOf time.Time, lawTypes []string) ([]ChunkSearchHit, error) {
+	var hits []ChunkSearchHit
+	if query == "" {
+		return hits, nil
+	}
+	db := database.DB().WithContext(ctx).Model(&model.DocumentChunk{}).
+		Select("document_chunks.*, ts_rank_cd("+chunkSearchVector+", to_tsquery('simple', ?)) AS rank", query).
+		Where("document_chunks.is_embedded = ?", true).
+		Where(chunkSearchVector+" @@ to_tsquery('simple', ?)", query)
+	if !asOf.IsZero() {
+		db = db.Where("(document_chunks.effective_date IS NULL OR document_chunks.effective_date <= ?)", asOf).
+			Where("(document_chunks.expiry_date IS NULL OR document_chunks.expiry_date > ?)", asOf)
+	}
+	if len(lawTypes) > 0 {
+		db = db.Where("document_chunks.document_id IN (?)",
+			database.DB().Model(&model.Document{}).Select("id").Where("law_type IN ?", lawTypes))
+	}
+	err := db.Order("rank DESC").
+		Limit(limit).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// FindChunksByArticle 按条号查询分块
+func (r *documentRepository) FindChunksByArticle(ctx context.Context, article string, limit int) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	err := database.DB().WithContext(ctx).
+		Where("article_number = ? AND is_embedded = ?", article, true).
+		Order("id").
+		Limit(limit).
+		Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// FindChunksByArticles 按文档与条号批量查询分块
+func (r *documentRepository) FindChunksByArticles(ctx context.Context, documentIDs, articles []string) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	if len(documentIDs) == 0 || len(articles) == 0 {
+		return chunks, nil
+	}
+	err := database.DB().WithContext(ctx).
+		Where("document_id IN ? AND article_number IN ? AND is_embedded = ?", documentIDs, articles, true).
+		Order("id").
+		Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// EnsureChunkSearchIndex 创建全文检索索引
+func (r *documentRepository) EnsureChunkSearchIndex(ctx context.Context) error {
+	return database.DB().WithContext(ctx).Exec(
+		"CREATE INDEX IF NOT EXISTS idx_document_chunks_search ON document_chunks USING GIN (" + chunkSearchVector + ")",
+	).Error
+}
+
+// ListChunksWithoutSearchText 列出缺少全文检索分词的分块
+func (r *documentRepository) ListChunksWithoutSearchText(ctx context.Context, afterID int64, limit int) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	err := database.DB().WithContext(ctx).
+		Where("id > ? AND (search_text IS NULL OR search_text = '')", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// UpdateChunkSearchText 更新分块的全文检索分词
+func (r *documentRepository) UpdateChunkSearchText(ctx context.Context, id int64, searchText string) error {
+	return database.DB().WithContext(ctx).Model(&model.DocumentChunk{}).
+		Where("id = ?", id).
+		UpdateColumn("search_text", searchText).Error
+}
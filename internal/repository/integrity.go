@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"gorm.io/gorm"
+)
+
+var ErrIntegrityReportNotFound = errors.New("审计报告不存在")
+
+// IntegrityRepository 完整性审计数据访问接口
+type IntegrityRepository interface {
+	// 审计报告
+	Create(ctx context.Context, report *model.IntegrityReport) error
+	FindByID(ctx context.Context, id int64) (*model.IntegrityReport, error)
+	// FindRunning 查询未完成的审计报告，不存在时返回 nil
+	FindRunning(ctx context.Context) (*model.IntegrityReport, error)
+	// Save 保存审计进度与统计
+	Save(ctx context.Context, report *model.IntegrityReport) error
+	List(ctx context.Context, page, pageSize int) ([]model.IntegrityReport, int64, error)
+
+	// 审计范围: 已确认版本中的分块
+	CountScope(ctx context.Context) (chunks int64, versions int, err error)
+	// ListScopeChunks 按 ID 顺序列出 afterID 之后的分块
+	ListScopeChunks(ctx context.Context, afterID int64, limit int) ([]model.DocumentChunk, error)
+}
+
+// integrityRepository 完整性审计数据访问实现
+type integrityRepository struct{}
+
+// NewIntegrityRepository 创建完整性审计数据访问实例
+func NewIntegrityRepository() IntegrityRepository {
+	return &integrityRepository{}
+}
+
+// Create 创建审计报告
+func (r *integrityRepository) Create(ctx context.Context, report *model.IntegrityReport) error {
+	return database.DB().WithContext(ctx).Create(report).Error
+}
+
+// FindByID 根据 ID 查询审计报告
+func (r *integrityRepository) FindByID(ctx context.Context, id int64) (*model.IntegrityReport, error) {
+	var report model.IntegrityReport
+	if err := database.DB().WithContext(ctx).First(&report, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIntegrityReportNotFound
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindRunning 查询最早的未完成审计报告
+func (r *integrityRepository) FindRunning(ctx context.Context) (*model.IntegrityReport, error) {
+	var reports []model.IntegrityReport
+	if err := database.DB().WithContext(ctx).
+		Where("status = ?", model.IntegrityRunning).
+		Order("id").
+		Limit(1).
+		Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	return &reports[0], nil
+}
+
+// Save 保存审计报告
+func (r *integrityRepository) Save(ctx context.Context, report *model.IntegrityReport) error {
+	return database.DB().WithContext(ctx).Save(report).Error
+}
+
+// List 分页查询审计报告 (新报告在前)
+func (r *integrityRepository) List(ctx context.Context, page, pageSize int) ([]model.IntegrityReport, int64, error) {
+	var reports []model.IntegrityReport
+	var total int64
+
+	db := database.DB().WithContext(ctx).Model(&model.IntegrityReport{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := db.Offset(offset).Limit(pageSize).Order("id DESC").Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// CountScope 统计审计范围内的分块数与版本数
+func (r *integrityRepository) CountScope(ctx context.Context) (int64, int, error) {
+	var row struct {
+		Chunks   int64
+		Versions int
+	}
+	err := r.scope(ctx).
+		Select("COUNT(*) AS chunks, COUNT(DISTINCT document_chunks.version_id) AS versions").
+		Scan(&row).Error
+	return row.Chunks, row.Versions, err
+}
+
+// ListScopeChunks 列出审计范围内 afterID 之后的分块 (按 ID 游标分页，可从检查点继续)
+func (r *integrityRepository) ListScopeChunks(ctx context.Context, afterID int64, limit int) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	if err := r.scope(ctx).
+		Select("document_chunks.*").
+		Where("document_chunks.id > ?", afterID).
+		Order("document_chunks.id").
+		Limit(limit).
+		Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// scope 审计范围: 所属版本已确认上链的分块
+func (r *integrityRepository) scope(ctx context.Context) *gorm.DB {
+	return database.DB().WithContext(ctx).Model(&model.DocumentChunk{}).
+		Joins("JOIN knowledge_versions ON knowledge_versions.id = document_chunks.version_id").
+		Where("knowledge_versions.status = ?", model.VersionStatusConfirmed)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+)
+
+// proofRecordBatchSize 验证记录批量写入大小
+const proofRecordBatchSize = 500
+
+// ProofRepository 存证验证记录数据访问接口
+type ProofRepository interface {
+	CreateBatch(ctx context.Context, records []model.ProofRecord) error
+	// ListLegacyLeafHash 列出 afterID 之后 LeafHash 仍为 ContentHash 的历史记录 (按 ID 游标分页，用于回填)
+	ListLegacyLeafHash(ctx context.Context, afterID int64, limit int) ([]model.ProofRecord, error)
+	// UpdateHashes 更新验证记录的 ContentHash 与 Merkle 叶子哈希
+	UpdateHashes(ctx context.Context, id int64, contentHash, leafHash string) error
+}
+
+// proofRepository 存证验证记录数据访问实现
+type proofRepository struct{}
+
+// NewProofRepository 创建存证验证记录数据访问实例
+func NewProofRepository() ProofRepository {
+	return &proofRepository{}
+}
+
+// CreateBatch 批量写入验证记录
+func (r *proofRepository) CreateBatch(ctx context.Context, records []model.ProofRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return database.DB().WithContext(ctx).CreateInBatches(records, proofRecordBatchSize).Error
+}
+
+// ListLegacyLeafHash 列出 LeafHash 为 64 位 ContentHash 的历史验证记录
+func (r *proofRepository) ListLegacyLeafHash(ctx context.Context, afterID int64, limit int) ([]model.ProofRecord, error) {
+	var records []model.ProofRecord
+	err := database.DB().WithContext(ctx).
+		Where("id > ? AND (content_hash IS NULL OR content_hash = '') AND length(leaf_hash) = 64", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// UpdateHashes 更新验证记录的内容哈希与叶子哈希
+func (r *proofRepository) UpdateHashes(ctx context.Context, id int64, contentHash, leafHash string) error {
+	return database.DB().WithContext(ctx).Model(&model.ProofRecord{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"content_hash": contentHash, "leaf_hash": leafHash}).Error
+}
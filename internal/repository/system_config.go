@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrSystemConfigNotFound = errors.New("配置项不存在")
+
+// SystemConfigRepository 系统配置数据访问接口
+type SystemConfigRepository interface {
+	FindByKey(ctx context.Context, key string) (*model.SystemConfig, error)
+	// List 按分类列出配置项，category 为空时列出全部
+	List(ctx context.Context, category string) ([]model.SystemConfig, error)
+	// UpdateValue 更新配置值与更新人
+	UpdateValue(ctx context.Context, key, value, updatedBy string) error
+	// CreateMissing 写入尚不存在的配置项，已存在的配置项保持不变
+	CreateMissing(ctx context.Context, configs []model.SystemConfig) error
+	// ReplaceDefault 将仍为旧默认值且未经管理员修改的配置项更新为新默认值，返回是否更新
+	ReplaceDefault(ctx context.Context, key, from, to string) (bool, error)
+}
+
+// systemConfigRepository 系统配置数据访问实现
+type systemConfigRepository struct{}
+
+// NewSystemConfigRepository 创建系统配置数据访问实例
+func NewSystemConfigRepository() SystemConfigRepository {
+	return &systemConfigRepository{}
+}
+
+// FindByKey 根据键查询配置项
+func (r *systemConfigRepository) FindByKey(ctx context.Context, key string) (*model.SystemConfig, error) {
+	var cfg model.SystemConfig
+	err := database.DB().WithContext(ctx).Where("key = ?", key).First(&cfg).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSystemConfigNotFound
+		}
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// List 按分类列出配置项
+func (r *systemConfigRepository) List(ctx context.Context, category string) ([]model.SystemConfig, error) {
+	query := database.DB().WithContext(ctx).Model(&model.SystemConfig{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var configs []model.SystemConfig
+	if err := query.Order("category ASC, key ASC").Find(&configs).Error; err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// UpdateValue 更新配置值
+func (r *systemConfigRepository) UpdateValue(ctx context.Context, key, value, updatedBy string) error {
+	result := database.DB().WithContext(ctx).Model(&model.SystemConfig{}).
+		Where("key = ?", key).
+		Updates(map[string]interface{}{
+			"value":      value,
+			"updated_by": updatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSystemConfigNotFound
+	}
+	return nil
+}
+
+// CreateMissing 写入尚不存在的配置项
+func (r *systemConfigRepository) CreateMissing(ctx context.Context, configs []model.SystemConfig) error {
+	if len(configs) == 0 {
+		return nil
+	}
+	return database.DB().WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).
+		Create(&configs).Error
+}
+
+// ReplaceDefault 替换未经管理员修改的旧默认值
+func (r *systemConfigRepository) ReplaceDefault(ctx context.Context, key, from, to string) (bool, error) {
+	result := database.DB().WithContext(ctx).Model(&model.SystemConfig{}).
+		Where("key = ? AND value = ?", key, from).
+		Where("updated_by = '' OR updated_by IS NULL").
+		Update("value", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
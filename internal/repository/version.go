@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrVersionNotFound      = errors.New("知识库版本不存在")
+	ErrVersionChunkNotFound = errors.New("分块不在该知识库版本中")
+	ErrVersionNotPending    = errors.New("知识库版本不是待确认状态")
+	ErrVersionRootConflict  = errors.New("相同 Merkle Root 的知识库版本已存在")
+)
+
+// versionChunkBatchSize 版本分块快照批量写入大小
+const versionChunkBatchSize = 500
+
+// VersionRepository 知识库版本数据访问接口
+type VersionRepository interface {
+	// 版本
+	FindByID(ctx context.Context, id int) (*model.KnowledgeVersion, error)
+	FindByRoot(ctx context.Context, merkleRoot string) (*model.KnowledgeVersion, error)
+	List(ctx context.Context, page, pageSize int, status string) ([]model.KnowledgeVersion, int64, error)
+
+	// ListSnapshotSource 列出可纳入版本的分块 (已索引或已上链文档)，按文档 ID、分块序号排序
+	ListSnapshotSource(ctx context.Context) ([]model.DocumentChunk, error)
+	// CreateSnapshot 在同一事务中创建版本、写入分块快照并更新文档的 VersionID
+	CreateSnapshot(ctx context.Context, version *model.KnowledgeVersion, chunks []model.VersionChunk) error
+	// SetTxHash 记录待确认版本已提交的上链交易 (交易被替换或重发时覆盖)
+	SetTxHash(ctx context.Context, id int, txHash string) error
+	// ListAnchoring 列出已提交上链交易但尚未确认的版本
+	ListAnchoring(ctx context.Context) ([]model.KnowledgeVersion, error)
+	// Confirm 将待确认版本标记为已上链，在同一事务中回填 DocumentChunk 的 Merkle 数据，
+	// 并将其文档标记为已存证
+	Confirm(ctx context.Context, id int, txHash string, blockNumber int64) error
+
+	// 版本内容
+	ListChunks(ctx context.Context, versionID int) ([]model.VersionChunk, error)
+	// FindChunk 查询分块在版本中的快照 (叶子序号与证明)
+	FindChunk(ctx context.Context, versionID int, chunkID string) (*model.VersionChunk, error)
+	// FindLatestChunkByHash 查询包含该分块内容的最新已确认版本快照
+	FindLatestChunkByHash(ctx context.Context, chunkID, contentHash string) (*model.VersionChunk, error)
+	ListDocuments(ctx context.Context, versionID int) ([]model.Document, error)
+	CountChunksByDocument(ctx context.Context, versionID int) (map[string]int, error)
+}
+
+// versionRepository 知识库版本数据访问实现
+type versionRepository struct{}
+
+// NewVersionRepository 创建知识库版本数据访问实例
+func NewVersionRepository() VersionRepository {
+	return &versionRepository{}
+}
+
+// FindByID 按 ID 查询版本
+func (r *versionRepository) FindByID(ctx context.Context, id int) (*model.KnowledgeVersion, error) {
+	var version model.KnowledgeVersion
+	if err := database.DB().WithContext(ctx).First(&version, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// FindByRoot 按 Merkle Root 查询版本
+func (r *versionRepository) FindByRoot(ctx context.Context, merkleRoot string) (*model.KnowledgeVersion, error) {
+	var version model.KnowledgeVersion
+	if err := database.DB().WithContext(ctx).First(&version, "merkle_root = ?", merkleRoot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// List 分页查询版本 (新版本在前)
+func (r *versionRepository) List(ctx context.Context, page, pageSize int, status string) ([]model.KnowledgeVersion, int64, error) {
+	var versions []model.KnowledgeVersion
+	var total int64
+
+	db := database.DB().WithContext(ctx).Model(&model.KnowledgeVersion{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := db.Offset(offset).Limit(pageSize).Order("id DESC").Find(&versions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return versions, total, nil
+}
+
+// ListSnapshotSource 列出可纳入版本的分块
+func (r *versionRepository) ListSnapshotSource(ctx context.Context) ([]model.DocumentChunk, error) {
+	var chunks []model.DocumentChunk
+	err := database.DB().WithContext(ctx).
+		Select("document_chunks.chunk_id", "document_chunks.document_id", "document_chunks.article_number",
+			"document_chunks.content_hash", "document_chunks.chunk_order").
+		Joins("JOIN documents ON documents.id = document_chunks.document_id AND documents.deleted_at IS NULL").
+		Where("documents.status IN ?", []model.DocumentStatus{model.DocStatusIndexed, model.DocStatusMinted}).
+		Order("document_chunks.document_id ASC, document_chunks.chunk_order ASC").
+		Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// CreateSnapshot 创建版本快照
+func (r *versionRepository) CreateSnapshot(ctx context.Context, version *model.KnowledgeVersion, chunks []model.VersionChunk) error {
+	return database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 唯一索引冲突时返回明确错误 (并发创建相同内容的版本)
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(version)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionRootConflict
+		}
+
+		docIDs := make([]string, 0)
+		seen := make(map[string]bool)
+		for i := range chunks {
+			chunks[i].VersionID = version.ID
+			if !seen[chunks[i].DocumentID] {
+				seen[chunks[i].DocumentID] = true
+				docIDs = append(docIDs, chunks[i].DocumentID)
+			}
+		}
+		if err := tx.CreateInBatches(chunks, versionChunkBatchSize).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Document{}).Where("id IN ?", docIDs).Update("version_id", version.ID).Error; err != nil {
+			return err
+		}
+
+		rows := make([]map[string]interface{}, len(docIDs))
+		for i, id := range docIDs {
+			rows[i] = map[string]interface{}{"knowledge_version_id": version.ID, "document_id": id}
+		}
+		return tx.Table("version_documents").CreateInBatches(rows, versionChunkBatchSize).Error
+	})
+}
+
+// SetTxHash 记录上链交易哈希
+func (r *versionRepository) SetTxHash(ctx context.Context, id int, txHash string) error {
+	db := database.DB().WithContext(ctx)
+	result := db.Model(&model.KnowledgeVersion{}).
+		Where("id = ? AND status = ?", id, model.VersionStatusPending).
+		Update("tx_hash", txHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notPendingError(db, id)
+	}
+	return nil
+}
+
+// ListAnchoring 列出等待上链确认的版本
+func (r *versionRepository) ListAnchoring(ctx context.Context) ([]model.KnowledgeVersion, error) {
+	var versions []model.KnowledgeVersion
+	if err := database.DB().WithContext(ctx).
+		Where("status = ? AND tx_hash <> ''", model.VersionStatusPending).
+		Order("id ASC").
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Confirm 确认版本上链
+func (r *versionRepository) Confirm(ctx context.Context, id int, txHash string, blockNumber int64) error {
+	return database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.KnowledgeVersion{}).
+			Where("id = ? AND status = ?", id, model.VersionStatusPending).
+			Updates(map[string]interface{}{
+				"status":       model.VersionStatusConfirmed,
+				"tx_hash":      txHash,
+				"block_number": blockNumber,
+				"confirmed_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return notPendingError(tx, id)
+		}
+
+		// 版本确认后才回填分块的当前版本与证明，待确认版本的证明尚无链上 Root 可验证。
+		// 按内容哈希匹配: 快照后内容已变化的分块不回填，已沿用更新版本证明的分块不回退
+		if err := tx.Exec(`UPDATE document_chunks AS dc
+			SET merkle_index = vc.merkle_index, merkle_proof = vc.merkle_proof, version_id = vc.version_id
+			FROM version_chunks AS vc
+			WHERE vc.version_id = ? AND dc.document_id = vc.document_id
+				AND dc.content_hash = vc.content_hash AND dc.version_id < vc.version_id`, id).Error; err != nil {
+			return err
+		}
+
+		// 仅更新仍属于该版本且已索引的文档 (重新导入中的文档不受影响)
+		return tx.Model(&model.Document{}).
+			Where("version_id = ? AND status IN ?", id, []model.DocumentStatus{model.DocStatusIndexed, model.DocStatusMinted}).
+			Updates(map[string]interface{}{
+				"status":    model.DocStatusMinted,
+				"is_minted": true,
+				"minted_at": now,
+			}).Error
+	})
+}
+
+// notPendingError 条件更新未命中时区分版本不存在与状态不符
+func notPendingError(db *gorm.DB, id int) error {
+	var count int64
+	if err := db.Model(&model.KnowledgeVersion{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrVersionNotFound
+	}
+	return ErrVersionNotPending
+}
+
+// ListChunks 查询版本分块快照 (按 Merkle 叶子序号排序)
+func (r *versionRepository) ListChunks(ctx context.Context, versionID int) ([]model.VersionChunk, error) {
+	var chunks []model.VersionChunk
+	if err := database.DB().WithContext(ctx).
+		Where("version_id = ?", versionID).
+		Order("merkle_index ASC").
+		Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// FindChunk 查询分块在版本中的快照
+func (r *versionRepository) FindChunk(ctx context.Context, versionID int, chunkID string) (*model.VersionChunk, error) {
+	var chunk model.VersionChunk
+	if err := database.DB().WithContext(ctx).
+		First(&chunk, "version_id = ? AND chunk_id = ?", versionID, chunkID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionChunkNotFound
+		}
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+// FindLatestChunkByHash 查询内容哈希一致的最新已确认版本快照
+func (r *versionRepository) FindLatestChunkByHash(ctx context.Context, chunkID, contentHash string) (*model.VersionChunk, error) {
+	var chunk model.VersionChunk
+	if err := database.DB().WithContext(ctx).
+		Joins("JOIN knowledge_versions ON knowledge_versions.id = version_chunks.version_id").
+		Where("version_chunks.chunk_id = ? AND version_chunks.content_hash = ?", chunkID, contentHash).
+		Where("knowledge_versions.status = ?", model.VersionStatusConfirmed).
+		Order("version_chunks.version_id DESC").
+		First(&chunk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionChunkNotFound
+		}
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+// ListDocuments 查询版本包含的文档 (含已删除的文档)
+func (r *versionRepository) ListDocuments(ctx context.Context, versionID int) ([]model.Document, error) {
+	var docs []model.Document
+	if err := database.DB().WithContext(ctx).Unscoped().
+		Joins("JOIN version_documents ON version_documents.document_id = documents.id").
+		Where("version_documents.knowledge_version_id = ?", versionID).
+		Order("documents.created_at ASC").
+		Find(&docs).Error; err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// CountChunksByDocument 统计版本中各文档的分块数
+func (r *versionRepository) CountChunksByDocument(ctx context.Context, versionID int) (map[string]int, error) {
+	var rows []struct {
+		DocumentID string
+		Count      int
+	}
+	if err := database.DB().WithContext(ctx).
+		Model(&model.VersionChunk{}).
+		Select("document_id, COUNT(*) AS count").
+		Where("version_id = ?", versionID).
+		Group("document_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.DocumentID] = row.Count
+	}
+	return counts, nil
+}
@@ -1,16 +1,21 @@
 package router
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
 	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
 	"github.com/lexveritas/lex-veritas-backend/internal/handler"
 	"github.com/lexveritas/lex-veritas-backend/internal/middleware"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/auth"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/cache"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/email"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
 	"github.com/lexveritas/lex-veritas-backend/internal/pkg/response"
 	"github.com/lexveritas/lex-veritas-backend/internal/service"
 
@@ -20,10 +25,15 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/swag"
+	"go.uber.org/zap"
 )
 
-// Setup 配置并返回 Gin 引擎
-func Setup(cfg *config.Config) *gin.Engine {
+// StartWorkers 启动后台任务 (文档导入 Worker、上链确认恢复、定时完整性审计)，ctx 取消时停止
+type StartWorkers func(ctx context.Context)
+
+// Setup 配置并返回 Gin 引擎与后台任务启动函数
+// 后台任务由服务入口以收到关闭信号时取消的 ctx 启动，便于优雅关闭
+func Setup(cfg *config.Config) (*gin.Engine, StartWorkers) {
 	// 设置运行模式
 	gin.SetMode(cfg.Server.Mode)
 
@@ -58,10 +68,106 @@ func Setup(cfg *config.Config) *gin.Engine {
 	// 初始化用户服务
 	userSvc := service.NewUserService()
 
+	// 初始化区块链客户端 (引用验证与版本上链共用)
+	var chainClient *client.BlockchainClient
+	var rootProvider nodes.RootProvider
+	if cfg.Blockchain.Enabled {
+		bc, err := client.NewBlockchainClient(&cfg.Blockchain)
+		if err != nil {
+			logger.Warn("区块链客户端初始化失败（链上验证与版本自动上链不可用）", zap.Error(err))
+		} else {
+			chainClient = bc
+			rootProvider = bc
+			logger.Info("区块链客户端已连接",
+				zap.String("contract", bc.ContractAddress()),
+				zap.String("account", bc.Account()),
+			)
+		}
+	}
+
+	// 验证节点 (问答引用验证与定时完整性审计共用链上 Root 缓存)
+	verifier := nodes.NewVerifier(rootProvider)
+
+	// 初始化系统配置服务 (运行时可调的检索、拒答阈值与提示词配置)
+	systemConfigSvc := service.NewSystemConfigService()
+
+	// 初始化 RAG Graph 与聊天服务
+	embedder := client.NewEmbedder(&cfg.Embedding, cfg.Milvus.Dimension)
+	vectorStore := client.NewMilvusStore(&cfg.Milvus)
+	llmNode := nodes.NewLLMNode(client.NewLLMClient(&cfg.LLM))
+	graphNodes := graph.Nodes{
+		Retriever: nodes.NewRetriever(embedder, vectorStore, nodes.RetrieverOptions{
+			TopK:       cfg.Retrieval.TopK,
+			Hybrid:     cfg.Retrieval.Hybrid,
+			CandidateK: cfg.Retrieval.CandidateK,
+			RRFK:       cfg.Retrieval.RRFK,
+		}),
+		Validity: nodes.NewValidityChecker(),
+		Verifier: verifier,
+		Refusal:  nodes.NewRefusalPolicy(systemConfigSvc),
+		Prompt:   nodes.NewPromptBuilder(systemConfigSvc),
+		LLM:      llmNode,
+		Checker:  nodes.NewCitationChecker(nodes.CitationMode(cfg.Citation.Mode), cfg.Citation.Regenerate),
+	}
+	if cfg.Retrieval.ExpandHops > 0 {
+		graphNodes.Expander = nodes.NewReferenceExpander(cfg.Retrieval.ExpandHops, cfg.Retrieval.ExpandMax)
+	}
+	if cfg.Rerank.Enabled {
+		graphNodes.Reranker = nodes.NewReranker(client.NewRerankClient(&cfg.Rerank), cfg.Rerank.MinScore)
+	}
+	if qa := cfg.Graph.QueryAnalysis; qa.Enabled {
+		graphNodes.Analyzer = nodes.NewQueryAnalyzer(llmNode, nodes.QueryAnalyzerOptions{
+			MaxQueries:    qa.MaxQueries,
+			MinConfidence: qa.MinConfidence,
+			LawTypes:      qa.LawTypes,
+		})
+	}
+	chatGraph := graph.NewBuilder(graphNodes, cfg.Graph.Timeouts)
+	if err := chatGraph.Build(); err != nil {
+		logger.Warn("RAG Graph 构建失败（聊天接口不可用）", zap.Error(err))
+	}
+	chatSvc := service.NewChatService(chatGraph)
+
+	// 初始化知识库文档服务与后台导入 Worker
+	docSvc := service.NewDocumentService(
+		&cfg.Document,
+		client.NewCachedEmbedder(embedder, client.NewEmbeddingCache(cfg.Embedding.CacheDir)),
+		vectorStore,
+	)
+
+	// 初始化知识库版本服务
+	versionSvc := service.NewVersionService(chainClient)
+
+	// 初始化完整性审计服务
+	integritySvc := service.NewIntegrityService(&cfg.Audit, verifier)
+
+	// 初始化公开验证服务
+	proofSvc := service.NewProofService(verifier, chainClient)
+
+	// 初始化问答证据包服务 (须配置独立的签名私钥，否则不注册导出接口)
+	evidenceSvc := service.NewEvidenceService(&cfg.Evidence, &cfg.Blockchain, chainClient)
+
+	startWorkers := func(ctx context.Context) {
+		if err := database.Health(); err != nil {
+			logger.Warn("数据库不可用，文档导入 Worker、上链确认恢复与定时完整性审计未启动", zap.Error(err))
+			return
+		}
+		docSvc.Start(ctx)
+		versionSvc.Start(ctx)
+		integritySvc.Start(ctx)
+	}
+
 	// 初始化 Handler
-	authHandler := handler.NewAuthHandler(authSvc, verifySvc)
+	authHandler := handler.NewAuthHandler(authSvc, verifySvc, chatSvc)
 	userHandler := handler.NewUserHandler(userSvc)
 	adminHandler := handler.NewAdminHandler(userSvc)
+	chatHandler := handler.NewChatHandler(chatSvc)
+	documentHandler := handler.NewDocumentHandler(docSvc)
+	versionHandler := handler.NewVersionHandler(versionSvc)
+	integrityHandler := handler.NewIntegrityHandler(integritySvc)
+	proofHandler := handler.NewProofHandler(proofSvc)
+	evidenceHandler := handler.NewEvidenceHandler(evidenceSvc)
+	systemConfigHandler := handler.NewSystemConfigHandler(systemConfigSvc)
 
 	// ======== API 文档端点 ========
 	// Scalar UI (推荐 - 更美观)
@@ -106,6 +212,28 @@ func Setup(cfg *config.Config) *gin.Engine {
 			usersRoutes.PUT("/me/password", userHandler.ChangePassword)
 		}
 
+		// ======== 聊天路由 (可选登录，匿名用户受限) ========
+		chatRoutes := v1.Group("/chat")
+		chatRoutes.Use(middleware.OptionalAuth(authSvc))
+		{
+			chatRoutes.POST("", middleware.GuestLimit(), middleware.QuotaCheck(), chatHandler.Chat)
+			chatRoutes.GET("/sessions", chatHandler.GetSessions)
+			chatRoutes.POST("/sessions", chatHandler.CreateSession)
+			chatRoutes.GET("/sessions/:id", chatHandler.GetSession)
+			chatRoutes.DELETE("/sessions/:id", chatHandler.DeleteSession)
+			if evidenceSvc.Enabled() {
+				chatRoutes.GET("/messages/:id/evidence", evidenceHandler.ExportEvidence)
+			}
+		}
+
+		// ======== 公开验证路由 (无需登录，供第三方复核存证) ========
+		verifyRoutes := v1.Group("/verify")
+		{
+			verifyRoutes.GET("/citation/:id", proofHandler.VerifyCitation)
+			verifyRoutes.POST("/chunk", proofHandler.VerifyChunk)
+			verifyRoutes.GET("/version/:id", proofHandler.VerifyVersion)
+		}
+
 		// ======== 管理员路由 (需管理员权限) ========
 		adminRoutes := v1.Group("/admin")
 		adminRoutes.Use(middleware.JWTAuth(authSvc))
@@ -117,10 +245,36 @@ func Setup(cfg *config.Config) *gin.Engine {
 			adminRoutes.PUT("/users/:id/role", adminHandler.UpdateRole)
 			adminRoutes.PUT("/users/:id/quota", adminHandler.AdjustQuota)
 			adminRoutes.DELETE("/users/:id", adminHandler.DeleteUser)
+
+			// 知识库文档
+			adminRoutes.POST("/documents", documentHandler.Upload)
+			adminRoutes.POST("/documents/url", documentHandler.RegisterURL)
+			adminRoutes.GET("/documents", documentHandler.ListDocuments)
+			adminRoutes.GET("/documents/:id", documentHandler.GetDocument)
+			adminRoutes.POST("/documents/:id/retry", documentHandler.RetryDocument)
+			adminRoutes.PUT("/documents/:id/validity", documentHandler.UpdateValidity)
+			adminRoutes.DELETE("/documents/:id", documentHandler.DeleteDocument)
+
+			// 知识库版本
+			adminRoutes.POST("/versions", versionHandler.CreateVersion)
+			adminRoutes.GET("/versions", versionHandler.ListVersions)
+			adminRoutes.GET("/versions/diff", versionHandler.DiffVersions)
+			adminRoutes.GET("/versions/:id", versionHandler.GetVersion)
+			adminRoutes.POST("/versions/:id/confirm", versionHandler.ConfirmVersion)
+			adminRoutes.POST("/versions/:id/anchor", versionHandler.AnchorVersion)
+
+			// 完整性审计
+			adminRoutes.POST("/integrity/audits", integrityHandler.RunAudit)
+			adminRoutes.GET("/integrity/audits", integrityHandler.ListReports)
+			adminRoutes.GET("/integrity/audits/:id", integrityHandler.GetReport)
+
+			// 系统配置
+			adminRoutes.GET("/configs", systemConfigHandler.ListConfigs)
+			adminRoutes.PUT("/configs/:key", systemConfigHandler.UpdateConfig)
 		}
 	}
 
-	return r
+	return r, startWorkers
 }
 
 // scalarHandler 返回 Scalar API 文档页面
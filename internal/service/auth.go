@@ -23,6 +23,7 @@ var (
 	ErrAccountLocked       = errors.New("由于尝试次数过多，账户已被锁定")
 	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
 	ErrEmailAlreadyExists  = errors.New("邮箱已被注册")
+	ErrOAuthNotImplemented = errors.New("OAuth 回调处理尚未实现")
 )
 
 // refreshTokenData 刷新令牌数据 (存储在 Redis)
@@ -312,5 +313,5 @@ func (s *authService) OAuthCallback(ctx context.Context, provider, code, state s
 	// 4. 查询或创建本地用户
 	// 5. 创建或更新 OAuthAccount
 	// 6. 生成 JWT token
-	return nil, nil, errors.New("OAuth 回调处理尚未实现")
+	return nil, nil, ErrOAuthNotImplemented
 }
@@ -0,0 +1,636 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/cache"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrChatSessionNotFound = errors.New("会话不存在")
+	ErrGuestSessionLimit   = errors.New("匿名用户会话数已达上限，请登录后创建更多会话")
+	ErrInvalidCursor       = errors.New("无效的分页游标")
+	ErrInvalidAsOfDate     = errors.New("适用日期格式错误，应为 YYYY-MM-DD")
+)
+
+const (
+	// chatHistoryLimit 多轮对话携带的历史消息数
+	chatHistoryLimit = 10
+	// chatTitleMaxRunes 自动生成的会话标题最大长度
+	chatTitleMaxRunes = 30
+	// chatEventBufferSize 事件通道缓冲大小
+	chatEventBufferSize = 64
+	// sessionListDefaultLimit 会话列表默认每页数量
+	sessionListDefaultLimit = 20
+)
+
+// 消息结束原因 (写入 ChatMessage.Metadata)
+const (
+	finishReasonStop        = "stop"
+	finishReasonError       = "error"
+	finishReasonInterrupted = "interrupted"
+)
+
+// ChatOwner 会话归属 (登录用户与匿名用户二选一)
+type ChatOwner struct {
+	UserID  string
+	GuestID string
+}
+
+// owns 判断会话是否属于当前请求者
+func (o ChatOwner) owns(session *model.ChatSession) bool {
+	if o.UserID != "" {
+		return session.UserID == o.UserID
+	}
+	return o.GuestID != "" && session.UserID == "" && session.GuestSessionID == o.GuestID
+}
+
+// filter 转换为数据访问层的归属筛选条件
+func (o ChatOwner) filter() repository.SessionOwnerFilter {
+	return repository.SessionOwnerFilter{UserID: o.UserID, GuestID: o.GuestID}
+}
+
+// ChatService 聊天服务接口
+type ChatService interface {
+	// Chat 执行问答，返回所属会话与 SSE 事件流
+	// 事件流结束时问答消息已持久化，最后一个 done 事件携带会话与消息 ID
+	Chat(ctx context.Context, owner ChatOwner, req *dto.ChatRequest) (*model.ChatSession, <-chan graph.Event, error)
+
+	// 会话管理
+	ListSessions(ctx context.Context, owner ChatOwner, req *dto.SessionListRequest) (*dto.SessionListResponse, error)
+	GetSession(ctx context.Context, owner ChatOwner, sessionID string) (*dto.SessionDetailResponse, error)
+	CreateSession(ctx context.Context, owner ChatOwner, req *dto.CreateSessionRequest) (*dto.SessionResponse, error)
+	DeleteSession(ctx context.Context, owner ChatOwner, sessionID string) error
+
+	// ClaimGuestSessions 匿名用户注册或登录后认领其会话，并清除匿名会话缓存
+	ClaimGuestSessions(ctx context.Context, guestID, userID string) error
+}
+
+// chatService 聊天服务实现
+type chatService struct {
+	graph    *graph.Builder
+	chatRepo repository.ChatRepository
+}
+
+// NewChatService 创建聊天服务
+func NewChatService(g *graph.Builder) ChatService {
+	return &chatService{
+		graph:    g,
+		chatRepo: repository.NewChatRepository(),
+	}
+}
+
+// Chat 执行问答
+func (s *chatService) Chat(ctx context.Context, owner ChatOwner, req *dto.ChatRequest) (*model.ChatSession, <-chan graph.Event, error) {
+	var asOf time.Time
+	if req.AsOfDate != "" {
+		t, err := time.ParseInLocation(dateLayout, req.AsOfDate, time.Local)
+		if err != nil {
+			return nil, nil, ErrInvalidAsOfDate
+		}
+		asOf = t
+	}
+
+	// 新会话在事件流开始后才创建，避免参数或检索阶段出错时遗留空会话并占用匿名会话配额
+	var (
+		session *model.ChatSession
+		history []nodes.ChatHistoryItem
+	)
+	if req.SessionID != "" {
+		found, err := s.findOwnedSession(ctx, owner, req.SessionID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if history, err = s.loadHistory(ctx, found.ID); err != nil {
+			return nil, nil, err
+		}
+		session = found
+	} else if err := s.checkGuestLimit(ctx, owner); err != nil {
+		return nil, nil, err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	start := time.Now()
+	events, err := s.graph.Stream(streamCtx, graph.Input{
+		Question: req.Message,
+		History:  history,
+		UserID:   owner.UserID,
+		AsOf:     asOf,
+	})
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	if session == nil {
+		created, err := s.createSession(ctx, owner, "")
+		if err != nil {
+			// 取消已开始的生成
+			cancel()
+			return nil, nil, err
+		}
+		session = created
+	}
+
+	out := make(chan graph.Event, chatEventBufferSize)
+	go func() {
+		defer cancel()
+		s.relay(streamCtx, owner, session, req.Message, start, events, out)
+	}()
+
+	return session, out, nil
+}
+
+// chatOutcome 一次问答的执行结果 (由 Graph 事件汇总)
+type chatOutcome struct {
+	answer    string
+	citations []graph.CitationData
+	check     *graph.CheckData
+	refusal   *graph.RefusalData
+	warnings  []graph.WarningData
+	trace     *graph.TraceData
+	usage     graph.UsageData
+	finish    string
+	latency   time.Duration
+}
+
+// metadata 生成助手消息元数据
+func (o *chatOutcome) metadata() datatypes.JSON {
+	meta := map[string]interface{}{
+		"finishReason": o.finish,
+		"latencyMs":    o.latency.Milliseconds(),
+	}
+	if o.trace != nil {
+		if o.trace.Query != "" {
+			meta["rewrittenQuery"] = o.trace.Query
+		}
+		meta["asOfDate"] = o.trace.AsOfDate
+		if o.trace.Domain != "" || len(o.trace.Queries) > 0 {
+			meta["queryAnalysis"] = map[string]interface{}{
+				"domain":   o.trace.Domain,
+				"queries":  o.trace.Queries,
+				"lawTypes": o.trace.LawTypes,
+			}
+		}
+		if o.trace.PromptVersion != "" {
+			meta["prompt"] = map[string]interface{}{
+				"version":         o.trace.PromptVersion,
+				"estimatedTokens": o.trace.PromptTokens,
+				"droppedChunks":   o.trace.DroppedChunks,
+				"historyMessages": o.trace.HistoryMessages,
+			}
+		}
+		// 各节点耗时与错误，用于排查慢请求与失败原因
+		meta["nodes"] = o.trace.Nodes
+	}
+	if o.check != nil {
+		meta["citationCheck"] = map[string]interface{}{
+			"passed":      o.check.Passed,
+			"regenerated": o.check.Regenerated,
+			"modified":    o.check.Answer != "",
+			"violations":  o.check.Violations,
+		}
+	}
+	if len(o.warnings) > 0 {
+		meta["validityWarnings"] = o.warnings
+	}
+	if o.refusal != nil {
+		candidates := make([]map[string]interface{}, len(o.refusal.Candidates))
+		for i, c := range o.refusal.Candidates {
+			candidates[i] = map[string]interface{}{
+				"chunkId":       c.ChunkID,
+				"source":        c.Source,
+				"articleNumber": c.ArticleNumber,
+				"score":         c.Score,
+				"verified":      c.Verified,
+			}
+		}
+		meta["refusal"] = map[string]interface{}{
+			"reason":     o.refusal.Reason,
+			"candidates": candidates,
+		}
+	}
+	metadata, _ := json.Marshal(meta)
+	return datatypes.JSON(metadata)
+}
+
+// relay 转发 Graph 事件，并在流结束后持久化问答
+func (s *chatService) relay(ctx context.Context, owner ChatOwner, session *model.ChatSession, question string, start time.Time, events <-chan graph.Event, out chan<- graph.Event) {
+	defer close(out)
+
+	var answer strings.Builder
+	outcome := &chatOutcome{finish: finishReasonInterrupted}
+
+	for ev := range events {
+		switch data := ev.Data.(type) {
+		case graph.TokenData:
+			answer.WriteString(data.Content)
+		case graph.CitationData:
+			outcome.citations = append(outcome.citations, data)
+		case graph.CheckData:
+			outcome.check = &data
+		case graph.RefusalData:
+			outcome.refusal = &data
+		case graph.WarningData:
+			outcome.warnings = append(outcome.warnings, data)
+		case graph.TraceData:
+			// 执行记录仅写入消息元数据
+			outcome.trace = &data
+			continue
+		case graph.UsageData:
+			outcome.usage = data
+		case graph.ErrorData:
+			outcome.finish = finishReasonError
+		case graph.DoneData:
+			// done 事件在持久化完成后由本服务重新发送
+			outcome.finish = finishReasonStop
+			continue
+		}
+
+		select {
+		case out <- ev:
+		case <-ctx.Done():
+		}
+	}
+	outcome.latency = time.Since(start)
+
+	// 引用校验完成时保存处理后的回答，且仅保存回答实际引用的法条
+	outcome.answer = answer.String()
+	if outcome.check != nil {
+		if outcome.check.Answer != "" {
+			outcome.answer = outcome.check.Answer
+		}
+		outcome.citations = graph.CitedOnly(outcome.citations, outcome.check)
+	}
+
+	// 客户端断开时仍需保存已生成的内容
+	saveCtx := context.WithoutCancel(ctx)
+	assistantMsg, err := s.saveExchange(saveCtx, owner, session, question, outcome)
+	if err != nil {
+		logger.Error("保存聊天消息失败",
+			zap.String("session_id", session.ID),
+			zap.Error(err),
+		)
+		select {
+		case out <- graph.Event{Type: graph.EventError, Data: graph.ErrorData{Message: "保存聊天消息失败"}}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	if outcome.finish != finishReasonStop {
+		return
+	}
+	select {
+	case out <- graph.Event{Type: graph.EventDone, Data: graph.DoneData{SessionID: session.ID, MessageID: assistantMsg.ID}}:
+	case <-ctx.Done():
+	}
+}
+
+// saveExchange 持久化问答消息与引用
+func (s *chatService) saveExchange(ctx context.Context, owner ChatOwner, session *model.ChatSession, question string, outcome *chatOutcome) (*model.ChatMessage, error) {
+	now := time.Now()
+	usage := outcome.usage
+
+	userMsg := &model.ChatMessage{
+		ID:        uuid.New().String(),
+		SessionID: session.ID,
+		Role:      model.RoleUserMsg,
+		Content:   question,
+		CreatedAt: now,
+	}
+
+	// 提示词包含问题与检索条文，Token 用量只记在助手消息上，避免按会话汇总时重复计算
+	assistantMsg := &model.ChatMessage{
+		ID:        uuid.New().String(),
+		SessionID: session.ID,
+		Role:      model.RoleAssistant,
+		Content:   outcome.answer,
+		TokensIn:  usage.PromptTokens,
+		TokensOut: usage.CompletionTokens,
+		Metadata:  outcome.metadata(),
+		CreatedAt: now.Add(time.Millisecond),
+	}
+	for _, c := range outcome.citations {
+		citation := model.MessageCitation{
+			ChunkID:        c.ChunkID,
+			Text:           c.Text,
+			Source:         c.Source,
+			ArticleNumber:  c.ArticleNumber,
+			LawHierarchy:   c.LawHierarchy,
+			ChunkHash:      c.ChunkHash,
+			VersionID:      c.VersionID,
+			VerificationID: c.TxHash,
+			BlockNumber:    c.BlockNumber,
+			Verified:       c.Verified,
+		}
+		if c.Verified {
+			citation.VerifiedAt = &now
+		}
+		assistantMsg.Citations = append(assistantMsg.Citations, citation)
+	}
+
+	exchange := &repository.ChatExchange{
+		UserMessage:      userMsg,
+		AssistantMessage: assistantMsg,
+		Title:            truncateRunes(question, chatTitleMaxRunes),
+		TokensUsed:       usage.TotalTokens,
+	}
+	if owner.UserID != "" {
+		exchange.Usage = &model.TokenUsage{
+			UserID:      owner.UserID,
+			SessionID:   session.ID,
+			APIEndpoint: "/api/v1/chat",
+			APIMethod:   "POST",
+			TokensIn:    usage.PromptTokens,
+			TokensOut:   usage.CompletionTokens,
+			TotalTokens: usage.TotalTokens,
+			Model:       usage.Model,
+			ModelType:   "chat",
+			Latency:     int(outcome.latency.Milliseconds()),
+			Success:     outcome.finish == finishReasonStop,
+		}
+	}
+
+	if err := s.chatRepo.SaveExchange(ctx, session.ID, exchange); err != nil {
+		return nil, err
+	}
+	return assistantMsg, nil
+}
+
+// ListSessions 游标分页获取会话列表
+func (s *chatService) ListSessions(ctx context.Context, owner ChatOwner, req *dto.SessionListRequest) (*dto.SessionListResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = sessionListDefaultLimit
+	}
+
+	var cursor *repository.SessionCursor
+	if req.Cursor != "" {
+		c, err := decodeSessionCursor(req.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor = c
+	}
+
+	// 多取一条用于判断是否还有下一页
+	sessions, err := s.chatRepo.ListSessions(ctx, owner.filter(), cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SessionListResponse{Limit: limit}
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+		resp.HasMore = true
+		resp.NextCursor = encodeSessionCursor(&sessions[limit-1])
+	}
+
+	resp.List = make([]dto.SessionResponse, len(sessions))
+	for i := range sessions {
+		resp.List[i] = toSessionResponse(&sessions[i])
+	}
+	return resp, nil
+}
+
+// GetSession 获取会话详情 (含消息与引用)
+func (s *chatService) GetSession(ctx context.Context, owner ChatOwner, sessionID string) (*dto.SessionDetailResponse, error) {
+	session, err := s.findOwnedSession(ctx, owner, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.chatRepo.ListMessagesWithCitations(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SessionDetailResponse{
+		SessionResponse: toSessionResponse(session),
+		Messages:        make([]dto.MessageResponse, len(messages)),
+	}
+	for i := range messages {
+		resp.Messages[i] = toMessageResponse(&messages[i])
+	}
+	return resp, nil
+}
+
+// CreateSession 创建空会话
+func (s *chatService) CreateSession(ctx context.Context, owner ChatOwner, req *dto.CreateSessionRequest) (*dto.SessionResponse, error) {
+	session, err := s.createSession(ctx, owner, strings.TrimSpace(req.Title))
+	if err != nil {
+		return nil, err
+	}
+	resp := toSessionResponse(session)
+	return &resp, nil
+}
+
+// DeleteSession 软删除会话
+func (s *chatService) DeleteSession(ctx context.Context, owner ChatOwner, sessionID string) error {
+	session, err := s.findOwnedSession(ctx, owner, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.chatRepo.DeleteSession(ctx, session.ID); err != nil {
+		if errors.Is(err, repository.ErrChatSessionNotFound) {
+			return ErrChatSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ClaimGuestSessions 认领匿名会话
+func (s *chatService) ClaimGuestSessions(ctx context.Context, guestID, userID string) error {
+	if guestID == "" || userID == "" {
+		return nil
+	}
+
+	claimed, err := s.chatRepo.ClaimGuestSessions(ctx, guestID, userID)
+	if err != nil {
+		return err
+	}
+
+	// 会话已归属用户，匿名限制不再适用
+	if err := cache.Delete(ctx, cache.GuestSessionKey(guestID)); err != nil {
+		logger.Warn("清除匿名会话缓存失败", zap.String("guest_id", guestID), zap.Error(err))
+	}
+
+	if claimed > 0 {
+		logger.Info("匿名会话已转移",
+			zap.String("guest_id", guestID),
+			zap.String("user_id", userID),
+			zap.Int64("sessions", claimed),
+		)
+	}
+	return nil
+}
+
+// findOwnedSession 查询会话并校验归属
+func (s *chatService) findOwnedSession(ctx context.Context, owner ChatOwner, sessionID string) (*model.ChatSession, error) {
+	session, err := s.chatRepo.FindSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrChatSessionNotFound) {
+			return nil, ErrChatSessionNotFound
+		}
+		return nil, err
+	}
+	// 不属于当前请求者的会话按不存在处理，避免泄露会话 ID
+	if !owner.owns(session) {
+		return nil, ErrChatSessionNotFound
+	}
+	return session, nil
+}
+
+// createSession 为请求者创建会话
+func (s *chatService) createSession(ctx context.Context, owner ChatOwner, title string) (*model.ChatSession, error) {
+	session := &model.ChatSession{
+		ID:     uuid.New().String(),
+		UserID: owner.UserID,
+		Title:  title,
+	}
+	if owner.UserID == "" {
+		if err := s.checkGuestLimit(ctx, owner); err != nil {
+			return nil, err
+		}
+		session.GuestSessionID = owner.GuestID
+	}
+	if err := s.chatRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// checkGuestLimit 校验匿名用户会话数上限，登录用户不受限
+func (s *chatService) checkGuestLimit(ctx context.Context, owner ChatOwner) error {
+	if owner.UserID != "" {
+		return nil
+	}
+	count, err := s.chatRepo.CountSessions(ctx, owner.filter())
+	if err != nil {
+		return err
+	}
+	if count >= cache.GuestMaxSessions {
+		return ErrGuestSessionLimit
+	}
+	return nil
+}
+
+// loadHistory 加载会话历史消息
+func (s *chatService) loadHistory(ctx context.Context, sessionID string) ([]nodes.ChatHistoryItem, error) {
+	messages, err := s.chatRepo.ListRecentMessages(ctx, sessionID, chatHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]nodes.ChatHistoryItem, 0, len(messages))
+	for _, m := range messages {
+		if m.Content == "" {
+			continue
+		}
+		history = append(history, nodes.ChatHistoryItem{
+			Role:    string(m.Role),
+			Content: m.Content,
+		})
+	}
+	return history, nil
+}
+
+// encodeSessionCursor 编码会话列表游标
+func encodeSessionCursor(session *model.ChatSession) string {
+	sortAt := session.CreatedAt
+	if session.LastMessageAt != nil {
+		sortAt = *session.LastMessageAt
+	}
+	raw := strconv.FormatInt(sortAt.UnixMicro(), 10) + "|" + session.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeSessionCursor 解码会话列表游标
+func decodeSessionCursor(cursor string) (*repository.SessionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	micros, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.SessionCursor{SortAt: time.UnixMicro(micros), ID: id}, nil
+}
+
+// toSessionResponse 转换会话响应
+func toSessionResponse(session *model.ChatSession) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:            session.ID,
+		Title:         session.Title,
+		Summary:       session.Summary,
+		MessageCount:  session.MessageCount,
+		TokensUsed:    session.TokensUsed,
+		LastMessageAt: session.LastMessageAt,
+		CreatedAt:     session.CreatedAt,
+	}
+}
+
+// toMessageResponse 转换消息响应
+func toMessageResponse(msg *model.ChatMessage) dto.MessageResponse {
+	resp := dto.MessageResponse{
+		ID:        msg.ID,
+		Role:      string(msg.Role),
+		Content:   msg.Content,
+		TokensIn:  msg.TokensIn,
+		TokensOut: msg.TokensOut,
+		CreatedAt: msg.CreatedAt,
+	}
+	if len(msg.Metadata) > 0 {
+		resp.Metadata = json.RawMessage(msg.Metadata)
+	}
+	for _, c := range msg.Citations {
+		resp.Citations = append(resp.Citations, dto.CitationResponse{
+			ID:             c.ID,
+			ChunkID:        c.ChunkID,
+			Text:           c.Text,
+			Source:         c.Source,
+			ArticleNumber:  c.ArticleNumber,
+			LawHierarchy:   c.LawHierarchy,
+			ChunkHash:      c.ChunkHash,
+			VersionID:      c.VersionID,
+			VerificationID: c.VerificationID,
+			BlockNumber:    c.BlockNumber,
+			Verified:       c.Verified,
+			VerifiedAt:     c.VerifiedAt,
+		})
+	}
+	return resp
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/docparse"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrDocumentNotFound        = errors.New("文档不存在")
+	ErrUnsupportedDocumentType = errors.New("不支持的文档类型，仅支持 PDF、DOCX、TXT、Markdown")
+	ErrDocumentTooLarge        = errors.New("文档大小超出限制")
+	ErrInvalidEffectiveDate    = errors.New("生效日期格式错误，应为 YYYY-MM-DD")
+	ErrInvalidExpiryDate       = errors.New("失效日期格式错误，应为 YYYY-MM-DD")
+	ErrInvalidValidityRange    = errors.New("失效日期应晚于生效日期")
+	ErrSupersedingDocument     = errors.New("替代文档不存在或为文档本身")
+	ErrDocumentNotRetryable    = errors.New("仅处理失败的文档可以重试")
+	ErrDocumentStoreFailed     = errors.New("文档保存失败")
+)
+
+const (
+	// documentListDefaultPageSize 文档列表默认每页数量
+	documentListDefaultPageSize = 10
+	// dateLayout 生效与失效日期格式
+	dateLayout = "2006-01-02"
+)
+
+// documentMetadata 文档扩展元数据 (写入 Document.Metadata)
+type documentMetadata struct {
+	SourceURL       string            `json:"sourceUrl,omitempty"`       // URL 文档的源地址
+	ArticleValidity []articleValidity `json:"articleValidity,omitempty"` // 单独设置时效的条文 (重新导入时沿用)
+}
+
+// articleValidity 条文时效设置，为空的字段沿用文档设置
+type articleValidity struct {
+	ArticleNumber string     `json:"articleNumber"`
+	EffectiveDate *time.Time `json:"effectiveDate,omitempty"`
+	ExpiryDate    *time.Time `json:"expiryDate,omitempty"`
+	SupersededBy  string     `json:"supersededBy,omitempty"`
+}
+
+// DocumentService 知识库文档服务接口
+type DocumentService interface {
+	// 文档登记 (登记后进入待处理队列，由后台 Worker 异步导入)
+	Upload(ctx context.Context, uploaderID string, file *multipart.FileHeader, req *dto.UploadDocumentRequest) (*dto.DocumentResponse, error)
+	RegisterURL(ctx context.Context, uploaderID string, req *dto.RegisterURLRequest) (*dto.DocumentResponse, error)
+
+	// 文档管理
+	ListDocuments(ctx context.Context, req *dto.DocumentListRequest) (*dto.DocumentListResponse, error)
+	GetDocument(ctx context.Context, id string) (*dto.DocumentResponse, error)
+	RetryDocument(ctx context.Context, id string) (*dto.DocumentResponse, error)
+	DeleteDocument(ctx context.Context, id string) error
+	// UpdateValidity 设置文档与条文的生效、失效日期，同步到分块与向量库
+	UpdateValidity(ctx context.Context, id string, req *dto.UpdateDocumentValidityRequest) (*dto.DocumentResponse, error)
+
+	// Start 启动后台导入 Worker，ctx 结束时 Worker 退出
+	Start(ctx context.Context)
+	// ProcessPending 同步导入全部待处理文档，返回处理的文档数
+	ProcessPending(ctx context.Context) (int, error)
+}
+
+// documentService 知识库文档服务实现
+type documentService struct {
+	docRepo    repository.DocumentRepository
+	embedder   *client.CachedEmbedder
+	store      client.VectorStore
+	cfg        *config.DocumentConfig
+	httpClient *http.Client
+
+	// wake 唤醒空闲 Worker (新文档登记或重试时)，容量即 Worker 数量
+	wake chan struct{}
+}
+
+// NewDocumentService 创建知识库文档服务
+func NewDocumentService(cfg *config.DocumentConfig, embedder *client.CachedEmbedder, store client.VectorStore) DocumentService {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	return &documentService{
+		docRepo:    repository.NewDocumentRepository(),
+		embedder:   embedder,
+		store:      store,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.FetchTimeout},
+		wake:       make(chan struct{}, workers),
+	}
+}
+
+// Upload 保存上传文件并登记文档
+func (s *documentService) Upload(ctx context.Context, uploaderID string, file *multipart.FileHeader, req *dto.UploadDocumentRequest) (*dto.DocumentResponse, error) {
+	docType, ok := docparse.DetectType(file.Filename)
+	if !ok {
+		return nil, ErrUnsupportedDocumentType
+	}
+	if s.cfg.MaxUploadSize > 0 && file.Size > s.cfg.MaxUploadSize {
+		return nil, ErrDocumentTooLarge
+	}
+	effectiveDate, expiryDate, err := parseValidity(req.EffectiveDate, req.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	path := filepath.Join(s.cfg.UploadDir, id+strings.ToLower(filepath.Ext(file.Filename)))
+	if err := saveUploadedFile(file, path); err != nil {
+		logger.Error("保存上传文件失败", zap.String("path", path), zap.Error(err))
+		return nil, ErrDocumentStoreFailed
+	}
+
+	name := req.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(file.Filename), filepath.Ext(file.Filename))
+	}
+	doc := &model.Document{
+		ID:            id,
+		Name:          name,
+		OriginalName:  file.Filename,
+		Type:          docType,
+		MimeType:      file.Header.Get("Content-Type"),
+		Size:          file.Size,
+		FilePath:      path,
+		LawName:       req.LawName,
+		LawType:       req.LawType,
+		EffectiveDate: effectiveDate,
+		ExpiryDate:    expiryDate,
+		PublishOrg:    req.PublishOrg,
+		Status:        model.DocStatusPending,
+		UploadedBy:    uploaderID,
+	}
+	if err := s.docRepo.Create(ctx, doc); err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	s.notify()
+	return toDocumentResponse(doc), nil
+}
+
+// RegisterURL 登记网页文档，内容在导入时抓取 (重试时重新抓取)
+func (s *documentService) RegisterURL(ctx context.Context, uploaderID string, req *dto.RegisterURLRequest) (*dto.DocumentResponse, error) {
+	if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+		return nil, ErrUnsupportedDocumentType
+	}
+	effectiveDate, expiryDate, err := parseValidity(req.EffectiveDate, req.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := json.Marshal(documentMetadata{SourceURL: req.URL})
+	if err != nil {
+		return nil, err
+	}
+
+	// 未指定名称时先使用 URL，导入时替换为网页标题
+	name := req.Name
+	if name == "" {
+		name = req.URL
+	}
+	doc := &model.Document{
+		ID:            uuid.New().String(),
+		Name:          name,
+		Type:          model.DocTypeURL,
+		LawName:       req.LawName,
+		LawType:       req.LawType,
+		EffectiveDate: effectiveDate,
+		ExpiryDate:    expiryDate,
+		PublishOrg:    req.PublishOrg,
+		Status:        model.DocStatusPending,
+		UploadedBy:    uploaderID,
+		Metadata:      datatypes.JSON(metadata),
+	}
+	if err := s.docRepo.Create(ctx, doc); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return toDocumentResponse(doc), nil
+}
+
+// ListDocuments 分页查询文档
+func (s *documentService) ListDocuments(ctx context.Context, req *dto.DocumentListRequest) (*dto.DocumentListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = documentListDefaultPageSize
+	}
+
+	docs, total, err := s.docRepo.ListWithFilters(ctx, req.Page, req.PageSize, req)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.DocumentResponse, len(docs))
+	for i := range docs {
+		list[i] = *toDocumentResponse(&docs[i])
+	}
+	return &dto.DocumentListResponse{
+		List:  list,
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+	}, nil
+}
+
+// GetDocument 获取文档详情
+func (s *documentService) GetDocument(ctx context.Context, id string) (*dto.DocumentResponse, error) {
+	doc, err := s.findDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toDocumentResponse(doc), nil
+}
+
+// RetryDocument 将处理失败的文档重新放入待处理队列
+func (s *documentService) RetryDocument(ctx context.Context, id string) (*dto.DocumentResponse, error) {
+	ok, err := s.docRepo.TransitStatus(ctx, id, []model.DocumentStatus{model.DocStatusError}, model.DocStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.findDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDocumentNotRetryable
+	}
+
+	s.notify()
+	return toDocumentResponse(doc), nil
+}
+
+// DeleteDocument 删除文档、分块、向量与上传文件
+func (s *documentService) DeleteDocument(ctx context.Context, id string) error {
+	doc, err := s.findDocument(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.docRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			return ErrDocumentNotFound
+		}
+		return err
+	}
+
+	// 残留向量在检索时会因找不到分块而被丢弃，清理失败不影响删除结果
+	if err := s.store.DeleteByDocument(ctx, id); err != nil {
+		logger.Warn("删除文档向量失败", zap.String("document_id", id), zap.Error(err))
+	}
+	if doc.FilePath != "" {
+		if err := os.Remove(doc.FilePath); err != nil && !os.IsNotExist(err) {
+			logger.Warn("删除上传文件失败", zap.String("path", doc.FilePath), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// findDocument 查询文档并转换数据访问层错误
+func (s *documentService) findDocument(ctx context.Context, id string) (*model.Document, error) {
+	doc, err := s.docRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseValidity 解析生效与失效日期并校验先后顺序
+func parseValidity(effective, expiry string) (*time.Time, *time.Time, error) {
+	effectiveDate, err := parseDate(effective, ErrInvalidEffectiveDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	expiryDate, err := parseDate(expiry, ErrInvalidExpiryDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if effectiveDate != nil && expiryDate != nil && !expiryDate.After(*effectiveDate) {
+		return nil, nil, ErrInvalidValidityRange
+	}
+	return effectiveDate, expiryDate, nil
+}
+
+// parseDate 解析 YYYY-MM-DD 日期，空字符串返回 nil，格式错误时返回 invalid
+func parseDate(value string, invalid error) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return nil, invalid
+	}
+	return &t, nil
+}
+
+// saveUploadedFile 保存上传文件
+func saveUploadedFile(file *multipart.FileHeader, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return err
+	}
+	return dst.Close()
+}
+
+// readMetadata 读取文档扩展元数据，解析失败时返回空值
+func readMetadata(doc *model.Document) documentMetadata {
+	var meta documentMetadata
+	if len(doc.Metadata) > 0 {
+		_ = json.Unmarshal(doc.Metadata, &meta)
+	}
+	return meta
+}
+
+// sourceURL 读取 URL 文档的源地址
+func sourceURL(doc *model.Document) string {
+	return readMetadata(doc).SourceURL
+}
+
+// toDocumentResponse 转换为文档响应
+func toDocumentResponse(doc *model.Document) *dto.DocumentResponse {
+	meta := readMetadata(doc)
+	var articles []dto.ArticleValidityResponse
+	for _, a := range meta.ArticleValidity {
+		articles = append(articles, dto.ArticleValidityResponse{
+			ArticleNumber: a.ArticleNumber,
+			EffectiveDate: a.EffectiveDate,
+			ExpiryDate:    a.ExpiryDate,
+			SupersededBy:  a.SupersededBy,
+		})
+	}
+	return &dto.DocumentResponse{
+		ID:            doc.ID,
+		Name:          doc.Name,
+		OriginalName:  doc.OriginalName,
+		Type:          string(doc.Type),
+		MimeType:      doc.MimeType,
+		Size:          doc.Size,
+		SourceURL:     meta.SourceURL,
+		LawName:       doc.LawName,
+		LawType:       doc.LawType,
+		EffectiveDate: doc.EffectiveDate,
+		PublishOrg:    doc.PublishOrg,
+		ExpiryDate:    doc.ExpiryDate,
+		SupersededBy:  doc.SupersededBy,
+		Articles:      articles,
+		Status:        string(doc.Status),
+		ProcessError:  doc.ProcessError,
+		ChunkCount:    doc.ChunkCount,
+		IsMinted:      doc.IsMinted,
+		VersionID:     doc.VersionID,
+		UploadedBy:    doc.UploadedBy,
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/docparse"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultIngestPollInterval 默认待处理文档轮询间隔
+	defaultIngestPollInterval = 30 * time.Second
+	// defaultIngestTimeout 默认单个文档处理超时
+	defaultIngestTimeout = 30 * time.Minute
+	// defaultIngestLease 默认处理中文档租约
+	defaultIngestLease = 2 * time.Minute
+)
+
+// Start 启动导入 Worker
+// 租约过期的处理中文档 (实例崩溃或关闭时中断) 会被重置为待处理并重新导入；
+// 其他实例正在导入的文档持续续期，不会被重复处理
+func (s *documentService) Start(ctx context.Context) {
+	s.reclaimStale(ctx)
+
+	workers := cap(s.wake)
+	for i := 0; i < workers; i++ {
+		go s.worker(ctx)
+	}
+	logger.Info("文档导入 Worker 已启动", zap.Int("workers", workers))
+
+	// 启动时可能已有待处理文档
+	s.notify()
+}
+
+// notify 唤醒空闲 Worker (不阻塞)
+func (s *documentService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// worker 循环领取并处理待处理文档，空闲时等待唤醒或定时轮询
+func (s *documentService) worker(ctx context.Context) {
+	interval := s.cfg.PollInterval
+	if interval <= 0 {
+		interval = defaultIngestPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+			s.reclaimStale(ctx)
+		}
+
+		for ctx.Err() == nil {
+			doc, err := s.docRepo.ClaimPending(ctx)
+			if err != nil {
+				logger.Warn("领取待处理文档失败", zap.Error(err))
+				break
+			}
+			if doc == nil {
+				break
+			}
+			s.process(ctx, doc)
+		}
+	}
+}
+
+// lease 返回处理中文档的租约
+func (s *documentService) lease() time.Duration {
+	if s.cfg.ProcessLease > 0 {
+		return s.cfg.ProcessLease
+	}
+	return defaultIngestLease
+}
+
+// reclaimStale 重置租约过期的处理中文档
+func (s *documentService) reclaimStale(ctx context.Context) {
+	n, err := s.docRepo.ReclaimStale(ctx, time.Now().Add(-s.lease()))
+	if err != nil {
+		logger.Warn("恢复中断的文档导入任务失败", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		logger.Info("恢复中断的文档导入任务", zap.Int64("count", n))
+		s.notify()
+	}
+}
+
+// heartbeat 在文档处理期间定期续期租约，直至 ctx 结束
+func (s *documentService) heartbeat(ctx context.Context, docID string) {
+	ticker := time.NewTicker(s.lease() / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.docRepo.Heartbeat(ctx, docID); err != nil && ctx.Err() == nil {
+				logger.Warn("续期文档导入租约失败", zap.String("document_id", docID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// ProcessPending 在当前协程中依次导入全部待处理文档，返回处理的文档数
+// 导入失败的文档记录为错误状态，不中断后续文档；供命令行工具使用，服务端由后台 Worker 导入
+func (s *documentService) ProcessPending(ctx context.Context) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		doc, err := s.docRepo.ClaimPending(ctx)
+		if err != nil {
+			return processed, err
+		}
+		if doc == nil {
+			break
+		}
+		s.process(ctx, doc)
+		processed++
+	}
+	return processed, ctx.Err()
+}
+
+// process 处理单个文档并记录结果
+func (s *documentService) process(ctx context.Context, doc *model.Document) {
+	timeout := s.cfg.ProcessTimeout
+	if timeout <= 0 {
+		timeout = defaultIngestTimeout
+	}
+	processCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	go s.heartbeat(processCtx, doc.ID)
+
+	start := time.Now()
+	chunks, cacheHits, err := s.ingest(processCtx, doc)
+	if err == nil {
+		logger.Info("文档导入完成",
+			zap.String("document_id", doc.ID),
+			zap.String("name", doc.Name),
+			zap.Int("chunks", chunks),
+			zap.Int("embedding_cache_hits", cacheHits),
+			zap.Duration("elapsed", time.Since(start)),
+		)
+		return
+	}
+
+	// 服务关闭导致的中断保持处理中状态，租约过期后由任一实例重新导入
+	if ctx.Err() != nil {
+		logger.Info("服务关闭，文档导入中断", zap.String("document_id", doc.ID))
+		return
+	}
+	if errors.Is(err, repository.ErrDocumentNotFound) {
+		logger.Info("文档已在导入过程中删除", zap.String("document_id", doc.ID))
+		return
+	}
+
+	logger.Warn("文档导入失败", zap.String("document_id", doc.ID), zap.Error(err))
+	if err := s.docRepo.UpdateFields(context.WithoutCancel(ctx), doc.ID, map[string]interface{}{
+		"status":        model.DocStatusError,
+		"process_error": err.Error(),
+	}); err != nil && !errors.Is(err, repository.ErrDocumentNotFound) {
+		logger.Error("记录文档导入错误失败", zap.String("document_id", doc.ID), zap.Error(err))
+	}
+}
+
+// ingest 导入流水线: 提取文本 → 分块与哈希 → 向量化 → 写入向量库 → 保存分块
+// 返回分块数与向量缓存命中数
+func (s *documentService) ingest(ctx context.Context, doc *model.Document) (int, int, error) {
+	text, err := s.extractText(ctx, doc)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 新分块尚未纳入任何知识库版本，VersionID 与 Merkle 证明在版本确认时回填；
+	// 内容未变化的分块由 ReplaceChunks 沿用原有证明
+	chunks := chunker.Split(text, chunker.Options{
+		DocumentID: doc.ID,
+		LawName:    lawSource(doc),
+	})
+	if len(chunks) == 0 {
+		return 0, 0, docparse.ErrEmptyText
+	}
+
+	inputs := make([]client.EmbedInput, len(chunks))
+	for i := range chunks {
+		inputs[i] = client.EmbedInput{ContentHash: chunks[i].ContentHash, Text: chunks[i].Content}
+	}
+	vectors, cacheHits, err := s.embedder.EmbedChunks(ctx, inputs)
+	if err != nil {
+		return 0, cacheHits, err
+	}
+
+	// 条文时效取自文档，单独设置过时效的条文沿用其设置
+	base, byArticle, err := s.chunkValidity(ctx, doc)
+	if err != nil {
+		return 0, cacheHits, err
+	}
+	records := make([]client.VectorRecord, len(chunks))
+	for i := range chunks {
+		v, ok := byArticle[chunks[i].ArticleNumber]
+		if !ok {
+			v = base
+		}
+		chunks[i].EffectiveDate = v.EffectiveDate
+		chunks[i].ExpiryDate = v.ExpiryDate
+		chunks[i].SupersededBy = v.SupersededBy
+
+		records[i] = client.VectorRecord{
+			ChunkID:       chunks[i].ChunkID,
+			DocumentID:    doc.ID,
+			LawType:       doc.LawType,
+			ArticleNumber: chunks[i].ArticleNumber,
+			EffectiveDate: client.OptionalDateKey(v.EffectiveDate),
+			ExpiryDate:    client.OptionalDateKey(v.ExpiryDate),
+			Vector:        vectors[i],
+		}
+	}
+
+	// 先清除旧向量，避免重新导入后分块减少时残留
+	if err := s.store.DeleteByDocument(ctx, doc.ID); err != nil {
+		return 0, cacheHits, err
+	}
+	if err := s.store.Upsert(ctx, records); err != nil {
+		return 0, cacheHits, err
+	}
+
+	now := time.Now()
+	for i := range chunks {
+		chunks[i].IsEmbedded = true
+		chunks[i].EmbeddedAt = &now
+	}
+	if err := s.docRepo.ReplaceChunks(ctx, doc.ID, chunks); err != nil {
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			// 导入过程中文档被删除，清理刚写入的向量
+			_ = s.store.DeleteByDocument(context.WithoutCancel(ctx), doc.ID)
+		}
+		return 0, cacheHits, err
+	}
+	return len(chunks), cacheHits, nil
+}
+
+// extractText 提取文档文本，URL 文档每次导入时重新抓取
+func (s *documentService) extractText(ctx context.Context, doc *model.Document) (string, error) {
+	if doc.Type != model.DocTypeURL {
+		return docparse.ExtractFile(doc.FilePath, doc.Type)
+	}
+
+	url := sourceURL(doc)
+	page, err := docparse.FetchURL(ctx, s.httpClient, url)
+	if err != nil {
+		return "", err
+	}
+
+	// 登记时未指定名称的，使用网页标题
+	if doc.Name == url && page.Title != "" {
+		if err := s.docRepo.UpdateFields(ctx, doc.ID, map[string]interface{}{"name": page.Title}); err != nil {
+			return "", err
+		}
+		doc.Name = page.Title
+	}
+	return page.Text, nil
+}
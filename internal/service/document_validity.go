@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// UpdateValidity 设置文档时效
+// 条文时效写入分块后同步到向量库；向量库同步失败不影响结果，检索时以数据库中的时效为准
+func (s *documentService) UpdateValidity(ctx context.Context, id string, req *dto.UpdateDocumentValidityRequest) (*dto.DocumentResponse, error) {
+	doc, err := s.findDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveDate, expiryDate, err := parseValidity(req.EffectiveDate, req.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+	articles := make([]articleValidity, 0, len(req.Articles))
+	for _, a := range req.Articles {
+		effective, expiry, err := parseValidity(a.EffectiveDate, a.ExpiryDate)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, articleValidity{
+			ArticleNumber: strings.TrimSpace(a.ArticleNumber),
+			EffectiveDate: effective,
+			ExpiryDate:    expiry,
+			SupersededBy:  a.SupersededBy,
+		})
+	}
+	if req.SupersededBy != "" {
+		if req.SupersededBy == id {
+			return nil, ErrSupersedingDocument
+		}
+		if _, err := s.docRepo.FindByID(ctx, req.SupersededBy); err != nil {
+			if errors.Is(err, repository.ErrDocumentNotFound) {
+				return nil, ErrSupersedingDocument
+			}
+			return nil, err
+		}
+	}
+
+	meta := readMetadata(doc)
+	meta.ArticleValidity = articles
+	metadata, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.EffectiveDate = effectiveDate
+	doc.ExpiryDate = expiryDate
+	doc.SupersededBy = req.SupersededBy
+	doc.Metadata = datatypes.JSON(metadata)
+	base, byArticle, err := s.chunkValidity(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, err := s.docRepo.UpdateValidity(ctx, id, map[string]interface{}{
+		"effective_date": effectiveDate,
+		"expiry_date":    expiryDate,
+		"superseded_by":  req.SupersededBy,
+		"metadata":       doc.Metadata,
+	}, base, byArticle)
+	if err != nil {
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+
+	dates := make([]client.ChunkDates, 0, len(chunks))
+	for _, c := range chunks {
+		if c.IsEmbedded {
+			dates = append(dates, client.ChunkDates{
+				ChunkID:       c.ChunkID,
+				EffectiveDate: client.OptionalDateKey(c.EffectiveDate),
+				ExpiryDate:    client.OptionalDateKey(c.ExpiryDate),
+			})
+		}
+	}
+	if err := s.store.UpdateDates(ctx, dates); err != nil {
+		logger.Warn("同步向量库时效失败", zap.String("document_id", id), zap.Error(err))
+	}
+
+	return s.GetDocument(ctx, id)
+}
+
+// chunkValidity 计算文档分块的时效: 默认取文档时效，单独设置的条文覆盖非空字段
+// 文档被替代时，替代说明为替代文档的法律名称
+func (s *documentService) chunkValidity(ctx context.Context, doc *model.Document) (repository.ChunkValidity, map[string]repository.ChunkValidity, error) {
+	base := repository.ChunkValidity{
+		EffectiveDate: doc.EffectiveDate,
+		ExpiryDate:    doc.ExpiryDate,
+	}
+	if doc.SupersededBy != "" {
+		successor, err := s.docRepo.FindByID(ctx, doc.SupersededBy)
+		switch {
+		case err == nil:
+			base.SupersededBy = "《" + lawSource(successor) + "》"
+		case !errors.Is(err, repository.ErrDocumentNotFound):
+			return base, nil, err
+		}
+	}
+
+	byArticle := make(map[string]repository.ChunkValidity)
+	for _, a := range readMetadata(doc).ArticleValidity {
+		v := base
+		if a.EffectiveDate != nil {
+			v.EffectiveDate = a.EffectiveDate
+		}
+		if a.ExpiryDate != nil {
+			v.ExpiryDate = a.ExpiryDate
+		}
+		if a.SupersededBy != "" {
+			v.SupersededBy = a.SupersededBy
+		}
+		byArticle[a.ArticleNumber] = v
+	}
+	return base, byArticle, nil
+}
+
+// lawSource 文档对应的法律名称，未填写时为文档名称
+func lawSource(doc *model.Document) string {
+	if doc.LawName != "" {
+		return doc.LawName
+	}
+	return doc.Name
+}
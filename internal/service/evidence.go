@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/evidence"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMessageNotFound         = errors.New("消息不存在")
+	ErrEvidenceNotAnswer       = errors.New("仅可导出助手回答的证据包")
+	ErrEvidenceSigningDisabled = errors.New("未配置独立的证据包签名私钥，无法导出证据包")
+)
+
+// EvidenceFile 导出的证据包文件
+type EvidenceFile struct {
+	Name string
+	Data []byte
+}
+
+// EvidenceService 问答证据包服务接口
+type EvidenceService interface {
+	// Enabled 是否可导出证据包 (已配置独立的签名私钥)
+	Enabled() bool
+	// Export 导出回答的签名证据包 (ZIP)，可使用 cmd/verify-evidence 离线复核
+	Export(ctx context.Context, owner ChatOwner, messageID string) (*EvidenceFile, error)
+}
+
+// evidenceService 问答证据包服务实现
+type evidenceService struct {
+	chatRepo    repository.ChatRepository
+	versionRepo repository.VersionRepository
+	// signer 证据包签名器，为 nil 时不可导出
+	signer *evidence.Signer
+	// chain 区块链客户端，用于写入链 ID 与合约地址 (可为 nil)
+	chain *client.BlockchainClient
+}
+
+// NewEvidenceService 创建问答证据包服务
+// 签名地址是第三方长期用于核验证据包的服务端身份，必须使用独立的签名私钥：
+// 未配置或与区块链存证私钥相同时不可导出证据包，存证账户轮换不影响已签发证据包的可信度
+func NewEvidenceService(cfg *config.EvidenceConfig, chainCfg *config.BlockchainConfig, chain *client.BlockchainClient) EvidenceService {
+	s := &evidenceService{
+		chatRepo:    repository.NewChatRepository(),
+		versionRepo: repository.NewVersionRepository(),
+		chain:       chain,
+	}
+	if cfg.SigningKey == "" {
+		logger.Warn("未配置证据包签名私钥 (evidence.signing_key)，证据包导出不可用")
+		return s
+	}
+	signer, err := evidence.NewSigner(cfg.SigningKey)
+	if err != nil {
+		logger.Warn("证据包签名私钥无效，证据包导出不可用", zap.Error(err))
+		return s
+	}
+	if chainCfg.PrivateKey != "" {
+		if anchor, err := evidence.NewSigner(chainCfg.PrivateKey); err == nil && anchor.Address() == signer.Address() {
+			logger.Warn("证据包签名私钥与区块链存证私钥相同，证据包导出不可用，请配置独立的签名私钥",
+				zap.String("signer", signer.Address()))
+			return s
+		}
+	}
+	s.signer = signer
+	return s
+}
+
+// Enabled 是否可导出证据包
+func (s *evidenceService) Enabled() bool {
+	return s.signer != nil
+}
+
+// Export 导出证据包
+// 引用原文取自回答时保存的快照，证明取自所属版本的冻结快照，与回答时展示给用户的内容一致
+func (s *evidenceService) Export(ctx context.Context, owner ChatOwner, messageID string) (*EvidenceFile, error) {
+	if s.signer == nil {
+		return nil, ErrEvidenceSigningDisabled
+	}
+
+	msg, session, err := s.findOwnedMessage(ctx, owner, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Role != model.RoleAssistant {
+		return nil, ErrEvidenceNotAnswer
+	}
+
+	manifest := &evidence.Manifest{
+		MessageID:   msg.ID,
+		SessionID:   session.ID,
+		Answer:      msg.Content,
+		AnsweredAt:  msg.CreatedAt,
+		Citations:   make([]evidence.Citation, 0, len(msg.Citations)),
+		Versions:    make([]evidence.Version, 0),
+		GeneratedAt: time.Now().UTC(),
+	}
+	question, err := s.chatRepo.FindPrecedingUserMessage(ctx, session.ID, msg.CreatedAt)
+	switch {
+	case err == nil:
+		manifest.Question = question.Content
+	case !errors.Is(err, repository.ErrMessageNotFound):
+		return nil, err
+	}
+	if s.chain != nil {
+		manifest.Chain = evidence.Chain{
+			ChainID:         s.chain.ChainID(),
+			ContractAddress: s.chain.ContractAddress(),
+		}
+	}
+
+	versionIDs := make([]int, 0)
+	seen := make(map[int]bool)
+	for i := range msg.Citations {
+		citation, err := s.newCitation(ctx, i+1, &msg.Citations[i])
+		if err != nil {
+			return nil, err
+		}
+		manifest.Citations = append(manifest.Citations, citation)
+		if citation.VersionID != 0 && !seen[citation.VersionID] {
+			seen[citation.VersionID] = true
+			versionIDs = append(versionIDs, citation.VersionID)
+		}
+	}
+	for _, id := range versionIDs {
+		version, err := s.versionRepo.FindByID(ctx, id)
+		if err != nil {
+			// 版本缺失时仍导出，离线验证会将对应引用标记为无法证明
+			if errors.Is(err, repository.ErrVersionNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		manifest.Versions = append(manifest.Versions, evidence.Version{
+			ID:          version.ID,
+			MerkleRoot:  version.MerkleRoot,
+			ChunkCount:  version.ChunkCount,
+			Status:      string(version.Status),
+			TxHash:      version.TxHash,
+			BlockNumber: version.BlockNumber,
+			ConfirmedAt: version.ConfirmedAt,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := evidence.Write(&buf, manifest, s.signer); err != nil {
+		return nil, fmt.Errorf("生成证据包失败: %w", err)
+	}
+
+	logger.Info("导出问答证据包",
+		zap.String("message_id", msg.ID),
+		zap.Int("citations", len(manifest.Citations)),
+		zap.String("signer", s.signer.Address()),
+	)
+	return &EvidenceFile{
+		Name: fmt.Sprintf("lexveritas-evidence-%s.zip", msg.ID),
+		Data: buf.Bytes(),
+	}, nil
+}
+
+// findOwnedMessage 查询消息并校验所属会话归属
+func (s *evidenceService) findOwnedMessage(ctx context.Context, owner ChatOwner, messageID string) (*model.ChatMessage, *model.ChatSession, error) {
+	msg, err := s.chatRepo.FindMessageWithCitations(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			return nil, nil, ErrMessageNotFound
+		}
+		return nil, nil, err
+	}
+
+	session, err := s.chatRepo.FindSessionByID(ctx, msg.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrChatSessionNotFound) {
+			return nil, nil, ErrMessageNotFound
+		}
+		return nil, nil, err
+	}
+	// 不属于当前请求者的消息按不存在处理
+	if !owner.owns(session) {
+		return nil, nil, ErrMessageNotFound
+	}
+	return msg, session, nil
+}
+
+// newCitation 由消息引用生成证据包引用
+func (s *evidenceService) newCitation(ctx context.Context, index int, c *model.MessageCitation) (evidence.Citation, error) {
+	out := evidence.Citation{
+		Index:         index,
+		CitationID:    c.ID,
+		ChunkID:       c.ChunkID,
+		Source:        c.Source,
+		ArticleNumber: c.ArticleNumber,
+		LawHierarchy:  c.LawHierarchy,
+		Text:          c.Text,
+		ContentHash:   c.ChunkHash,
+		VersionID:     c.VersionID,
+		MerkleProof:   []string{},
+		Verified:      c.Verified,
+	}
+	if out.ContentHash == "" {
+		out.ContentHash = chunker.ContentHash(c.Text)
+	}
+
+	snapshot, err := findCitationSnapshot(ctx, s.versionRepo, c)
+	if err != nil {
+		return out, err
+	}
+	if snapshot != nil {
+		out.VersionID = snapshot.VersionID
+		out.MerkleIndex = &snapshot.MerkleIndex
+		if len(snapshot.MerkleProof) > 0 {
+			_ = json.Unmarshal(snapshot.MerkleProof, &out.MerkleProof)
+		}
+	}
+	return out, nil
+}
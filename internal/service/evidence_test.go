@@ -0,0 +1,35 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+)
+
+func TestEvidenceSigningKey(t *testing.T) {
+	signingKey := strings.Repeat("1", 64)
+	anchorKey := strings.Repeat("2", 64)
+
+	tests := []struct {
+		name       string
+		signingKey string
+		anchorKey  string
+		enabled    bool
+	}{
+		{name: "独立签名私钥", signingKey: signingKey, anchorKey: anchorKey, enabled: true},
+		{name: "未配置区块链私钥", signingKey: signingKey, enabled: true},
+		{name: "未配置签名私钥时不使用区块链私钥", anchorKey: anchorKey},
+		{name: "与区块链私钥相同", signingKey: anchorKey, anchorKey: anchorKey},
+		{name: "与区块链私钥相同 (格式不同)", signingKey: "0x" + anchorKey, anchorKey: " " + anchorKey},
+		{name: "签名私钥无效", signingKey: "0x1234", anchorKey: anchorKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewEvidenceService(&config.EvidenceConfig{SigningKey: tt.signingKey}, &config.BlockchainConfig{PrivateKey: tt.anchorKey}, nil)
+			if svc.Enabled() != tt.enabled {
+				t.Errorf("Enabled() = %v, want %v", svc.Enabled(), tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/cache"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrIntegrityReportNotFound = errors.New("审计报告不存在")
+	ErrIntegrityAuditDisabled  = errors.New("未启用区块链存证，无法执行完整性审计")
+	ErrIntegrityAuditRunning   = errors.New("完整性审计正在执行中")
+)
+
+const (
+	// defaultIntegrityBatchSize 默认每批验证的分块数
+	defaultIntegrityBatchSize = 500
+	// defaultIntegrityLockTTL 默认分布式锁有效期
+	defaultIntegrityLockTTL = 2 * time.Minute
+	// integrityMaxFindings 单个报告最多记录的未通过分块数，完整记录见 ProofRecord
+	integrityMaxFindings = 200
+	// integrityListDefaultPageSize 审计报告列表默认每页数量
+	integrityListDefaultPageSize = 10
+)
+
+// IntegrityService 全量完整性审计服务接口
+type IntegrityService interface {
+	// Start 按配置的 cron 表达式定时审计，并继续上次中断的审计
+	Start(ctx context.Context)
+	// RunNow 立即在后台执行审计 (有未完成的审计时从检查点继续)，返回审计报告
+	RunNow(ctx context.Context, userID string) (*dto.IntegrityReportResponse, error)
+
+	ListReports(ctx context.Context, req *dto.IntegrityReportListRequest) (*dto.IntegrityReportListResponse, error)
+	GetReport(ctx context.Context, id int64) (*dto.IntegrityReportResponse, error)
+}
+
+// integrityService 全量完整性审计服务实现
+//
+// 遍历已确认版本中的全部 DocumentChunk，由数据库中的内容与证明重新计算 Root 并与链上比对，
+// 直接修改数据库中的分块内容、证明或所属版本都会在下一轮审计中被发现。
+// 多实例部署时通过 Redis 分布式锁保证同一时间只有一个实例执行；
+// 每批验证后将游标与统计写入审计报告，实例中断或锁丢失后由任意实例从检查点继续。
+type integrityService struct {
+	cfg       *config.AuditConfig
+	verifier  *nodes.Verifier
+	repo      repository.IntegrityRepository
+	batchSize int
+	lockTTL   time.Duration
+
+	// running 本实例是否正在执行审计
+	running atomic.Bool
+	// runCtx 后台审计的生命周期
+	runCtx context.Context
+}
+
+// NewIntegrityService 创建完整性审计服务
+// 与问答验证共用 Verifier (链上 Root 缓存)，审计结果以 scheduled 触发来源写入 ProofRecord
+func NewIntegrityService(cfg *config.AuditConfig, verifier *nodes.Verifier) IntegrityService {
+	s := &integrityService{
+		cfg:       cfg,
+		verifier:  verifier,
+		repo:      repository.NewIntegrityRepository(),
+		batchSize: cfg.BatchSize,
+		lockTTL:   cfg.LockTTL,
+		runCtx:    context.Background(),
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = defaultIntegrityBatchSize
+	}
+	if s.lockTTL <= 0 {
+		s.lockTTL = defaultIntegrityLockTTL
+	}
+	return s
+}
+
+// Start 启动定时审计
+func (s *integrityService) Start(ctx context.Context) {
+	s.runCtx = ctx
+	if !s.cfg.Enabled {
+		return
+	}
+	if !s.verifier.ChainEnabled() {
+		logger.Info("未启用区块链存证，定时完整性审计未启动")
+		return
+	}
+
+	schedule, err := cron.ParseStandard(s.cfg.Schedule)
+	if err != nil {
+		logger.Warn("完整性审计 cron 表达式无效，定时审计未启动", zap.String("schedule", s.cfg.Schedule), zap.Error(err))
+		return
+	}
+	c := cron.New()
+	c.Schedule(schedule, cron.FuncJob(func() { s.runScheduled(ctx) }))
+	c.Start()
+	go func() {
+		<-ctx.Done()
+		c.Stop()
+	}()
+	logger.Info("定时完整性审计已启动",
+		zap.String("schedule", s.cfg.Schedule),
+		zap.Time("next", schedule.Next(time.Now())),
+	)
+
+	// 上次中断的审计不等下一个周期，立即继续
+	if report, err := s.repo.FindRunning(ctx); err != nil {
+		logger.Warn("查询未完成的完整性审计失败", zap.Error(err))
+	} else if report != nil {
+		go s.runScheduled(ctx)
+	}
+}
+
+// RunNow 立即执行审计
+func (s *integrityService) RunNow(ctx context.Context, userID string) (*dto.IntegrityReportResponse, error) {
+	if !s.verifier.ChainEnabled() {
+		return nil, ErrIntegrityAuditDisabled
+	}
+
+	lock, report, err := s.prepare(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toIntegrityReportResponse(report)
+	go s.execute(s.runCtx, lock, report)
+	return resp, nil
+}
+
+// runScheduled 定时触发的审计，其他实例正在执行时跳过
+func (s *integrityService) runScheduled(ctx context.Context) {
+	lock, report, err := s.prepare(ctx, "")
+	if errors.Is(err, ErrIntegrityAuditRunning) {
+		logger.Info("完整性审计正在其他实例或本实例执行，跳过本次调度")
+		return
+	}
+	if err != nil {
+		logger.Warn("启动完整性审计失败", zap.Error(err))
+		return
+	}
+	s.execute(ctx, lock, report)
+}
+
+// prepare 获取分布式锁并创建审计报告，有未完成的报告时继续该报告
+func (s *integrityService) prepare(ctx context.Context, userID string) (*cache.Lock, *model.IntegrityReport, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, nil, ErrIntegrityAuditRunning
+	}
+	lock, err := cache.TryLock(ctx, cache.KeyLockIntegrityAudit, s.lockTTL)
+	if err != nil {
+		s.running.Store(false)
+		if errors.Is(err, cache.ErrLockNotAcquired) {
+			return nil, nil, ErrIntegrityAuditRunning
+		}
+		return nil, nil, fmt.Errorf("获取完整性审计锁失败: %w", err)
+	}
+
+	report, err := s.openReport(ctx, userID)
+	if err != nil {
+		s.release(ctx, lock)
+		return nil, nil, err
+	}
+	return lock, report, nil
+}
+
+// openReport 继续未完成的审计报告，不存在时按当前审计范围创建新报告
+func (s *integrityService) openReport(ctx context.Context, userID string) (*model.IntegrityReport, error) {
+	report, err := s.repo.FindRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if report != nil {
+		report.Resumes++
+		logger.Info("继续未完成的完整性审计",
+			zap.Int64("report_id", report.ID),
+			zap.Int64("cursor", report.Cursor),
+			zap.Int64("checked", report.Checked),
+		)
+		return report, nil
+	}
+
+	chunks, versions, err := s.repo.CountScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report = &model.IntegrityReport{
+		Status:       model.IntegrityRunning,
+		TotalChunks:  chunks,
+		VersionCount: versions,
+		TriggeredBy:  userID,
+	}
+	if err := s.repo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+	logger.Info("开始完整性审计",
+		zap.Int64("report_id", report.ID),
+		zap.Int64("chunks", chunks),
+		zap.Int("versions", versions),
+	)
+	return report, nil
+}
+
+// release 释放分布式锁与本实例执行标记
+func (s *integrityService) release(ctx context.Context, lock *cache.Lock) {
+	if err := lock.Unlock(context.WithoutCancel(ctx)); err != nil {
+		logger.Warn("释放完整性审计锁失败", zap.Error(err))
+	}
+	s.running.Store(false)
+}
+
+// execute 分批验证审计范围内的分块，每批结束后保存检查点
+// 服务关闭或锁丢失时直接退出，报告保持执行中，由下次执行从检查点继续
+func (s *integrityService) execute(ctx context.Context, lock *cache.Lock, report *model.IntegrityReport) {
+	defer s.release(ctx, lock)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.keepLock(runCtx, lock, cancel)
+
+	log := logger.L().With(zap.Int64("report_id", report.ID))
+	tally := newIntegrityTally(report)
+	trigger := nodes.Trigger{Type: model.TriggerScheduled, By: report.TriggeredBy}
+	start := time.Now()
+
+	for {
+		chunks, err := s.repo.ListScopeChunks(runCtx, report.Cursor, s.batchSize)
+		if err != nil {
+			s.interrupt(ctx, report, fmt.Errorf("查询待审计分块失败: %w", err))
+			return
+		}
+		if len(chunks) == 0 {
+			break
+		}
+
+		results, err := s.verifier.Verify(runCtx, trigger, toVerifyInputs(chunks))
+		// 中断时本批可能因链上查询被取消而验证失败，不推进检查点
+		if runCtx.Err() != nil {
+			log.Info("完整性审计中断，下次从检查点继续", zap.Int64("cursor", report.Cursor))
+			return
+		}
+		if err != nil {
+			s.interrupt(ctx, report, err)
+			return
+		}
+
+		tally.add(report, chunks, results)
+		report.Cursor = chunks[len(chunks)-1].ID
+		if err := s.repo.Save(runCtx, report); err != nil {
+			s.interrupt(ctx, report, fmt.Errorf("保存审计进度失败: %w", err))
+			return
+		}
+	}
+
+	now := time.Now()
+	report.Status = model.IntegrityCompleted
+	report.FinishedAt = &now
+	report.LastError = ""
+	if err := s.repo.Save(context.WithoutCancel(ctx), report); err != nil {
+		log.Error("保存完整性审计报告失败", zap.Error(err))
+		return
+	}
+
+	fields := []zap.Field{
+		zap.Int64("checked", report.Checked),
+		zap.Int64("verified", report.Verified),
+		zap.Int64("tampered", report.Tampered),
+		zap.Int64("unverified", report.Unverified),
+		zap.Duration("elapsed", time.Since(start)),
+	}
+	if report.Tampered > 0 {
+		log.Error("完整性审计完成，发现分块与链上存证不一致", fields...)
+		return
+	}
+	log.Info("完整性审计完成", fields...)
+}
+
+// keepLock 定期续期分布式锁
+// 锁已被其他实例获取，或连续续期失败超过 TTL (锁可能已过期) 时取消审计
+func (s *integrityService) keepLock(ctx context.Context, lock *cache.Lock, cancel context.CancelFunc) {
+	ticker := time.NewTicker(lock.TTL() / 3)
+	defer ticker.Stop()
+
+	lastRefresh := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := lock.Refresh(ctx)
+		if err == nil {
+			lastRefresh = time.Now()
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, cache.ErrLockLost) || time.Since(lastRefresh) >= lock.TTL() {
+			logger.Warn("完整性审计锁已丢失，停止审计", zap.Error(err))
+			cancel()
+			return
+		}
+		logger.Warn("完整性审计锁续期失败，稍后重试", zap.Error(err))
+	}
+}
+
+// interrupt 记录审计中断原因，报告保持执行中以便从检查点继续
+func (s *integrityService) interrupt(ctx context.Context, report *model.IntegrityReport, cause error) {
+	logger.Warn("完整性审计中断", zap.Int64("report_id", report.ID), zap.Int64("cursor", report.Cursor), zap.Error(cause))
+	report.LastError = cause.Error()
+	if err := s.repo.Save(context.WithoutCancel(ctx), report); err != nil {
+		logger.Error("保存完整性审计报告失败", zap.Int64("report_id", report.ID), zap.Error(err))
+	}
+}
+
+// ListReports 分页查询审计报告
+func (s *integrityService) ListReports(ctx context.Context, req *dto.IntegrityReportListRequest) (*dto.IntegrityReportListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = integrityListDefaultPageSize
+	}
+
+	reports, total, err := s.repo.List(ctx, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.IntegrityReportResponse, len(reports))
+	for i := range reports {
+		list[i] = *toIntegrityReportResponse(&reports[i])
+	}
+	return &dto.IntegrityReportListResponse{
+		List:  list,
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+	}, nil
+}
+
+// GetReport 获取审计报告
+func (s *integrityService) GetReport(ctx context.Context, id int64) (*dto.IntegrityReportResponse, error) {
+	report, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrIntegrityReportNotFound) {
+			return nil, ErrIntegrityReportNotFound
+		}
+		return nil, err
+	}
+	return toIntegrityReportResponse(report), nil
+}
+
+// integrityTally 审计统计累加器 (从检查点继续时载入已有统计)
+type integrityTally struct {
+	reasons  map[string]int64
+	findings []dto.IntegrityFinding
+}
+
+// newIntegrityTally 由审计报告载入已有统计
+func newIntegrityTally(report *model.IntegrityReport) *integrityTally {
+	t := &integrityTally{reasons: make(map[string]int64)}
+	if len(report.FailReasons) > 0 {
+		_ = json.Unmarshal(report.FailReasons, &t.reasons)
+	}
+	if len(report.Findings) > 0 {
+		_ = json.Unmarshal(report.Findings, &t.findings)
+	}
+	return t
+}
+
+// add 累加一批验证结果并写回报告
+func (t *integrityTally) add(report *model.IntegrityReport, chunks []model.DocumentChunk, results []nodes.VerifyResult) {
+	for i := range results {
+		res := &results[i]
+		report.Checked++
+		switch {
+		case res.Verified:
+			report.Verified++
+			continue
+		case res.Tampered:
+			report.Tampered++
+		default:
+			report.Unverified++
+		}
+
+		t.reasons[res.FailReason]++
+		if len(t.findings) < integrityMaxFindings {
+			t.findings = append(t.findings, dto.IntegrityFinding{
+				ChunkID:    res.ChunkID,
+				DocumentID: chunks[i].DocumentID,
+				VersionID:  res.VersionID,
+				Tampered:   res.Tampered,
+				Reason:     res.FailReason,
+			})
+		}
+	}
+
+	if data, err := json.Marshal(t.reasons); err == nil {
+		report.FailReasons = datatypes.JSON(data)
+	}
+	if data, err := json.Marshal(t.findings); err == nil {
+		report.Findings = datatypes.JSON(data)
+	}
+}
+
+// toVerifyInputs 分块转换为验证输入
+// 证明无法解析时按空证明验证，已纳入版本的分块会被判定为不一致
+func toVerifyInputs(chunks []model.DocumentChunk) []nodes.VerifyInput {
+	inputs := make([]nodes.VerifyInput, len(chunks))
+	for i := range chunks {
+		var proof []string
+		if len(chunks[i].MerkleProof) > 0 {
+			_ = json.Unmarshal(chunks[i].MerkleProof, &proof)
+		}
+		inputs[i] = nodes.VerifyInput{
+			ChunkID:     chunks[i].ChunkID,
+			DocumentID:  chunks[i].DocumentID,
+			VersionID:   chunks[i].VersionID,
+			Content:     chunks[i].Content,
+			MerkleProof: proof,
+		}
+	}
+	return inputs
+}
+
+// toIntegrityReportResponse 审计报告转换为响应
+func toIntegrityReportResponse(r *model.IntegrityReport) *dto.IntegrityReportResponse {
+	resp := &dto.IntegrityReportResponse{
+		ID:           r.ID,
+		Status:       string(r.Status),
+		TotalChunks:  r.TotalChunks,
+		VersionCount: r.VersionCount,
+		Checked:      r.Checked,
+		Verified:     r.Verified,
+		Tampered:     r.Tampered,
+		Unverified:   r.Unverified,
+		TriggeredBy:  r.TriggeredBy,
+		Resumes:      r.Resumes,
+		LastError:    r.LastError,
+		StartedAt:    r.StartedAt,
+		UpdatedAt:    r.UpdatedAt,
+		FinishedAt:   r.FinishedAt,
+	}
+	if len(r.FailReasons) > 0 {
+		_ = json.Unmarshal(r.FailReasons, &resp.FailReasons)
+	}
+	if len(r.Findings) > 0 {
+		_ = json.Unmarshal(r.Findings, &resp.Findings)
+	}
+	return resp
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/config"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/cache"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/database"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// reasonTampered 与 nodes.Verifier 写入的篡改原因一致
+const reasonTampered = "分块内容或证明与链上存证不一致"
+
+// fakeRoots 模拟链上存证合约
+type fakeRoots map[int64]string
+
+func (f fakeRoots) GetMerkleRoot(_ context.Context, versionID int64) (string, error) {
+	root, ok := f[versionID]
+	if !ok {
+		return "", client.ErrRootNotAnchored
+	}
+	return root, nil
+}
+
+// setupIntegrity 使用 SQLite 内存数据库与内存 Redis，导入一部法律并创建、确认一个版本
+// 返回按 ID 排序的已确认分块与模拟链上存证
+func setupIntegrity(t *testing.T) ([]model.DocumentChunk, fakeRoots) {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:                 gormlogger.Default.LogMode(gormlogger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		t.Fatal(err)
+	}
+	database.Use(db)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	mr := miniredis.RunT(t)
+	cache.Use(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	var text strings.Builder
+	for i := 1; i <= 7; i++ {
+		fmt.Fprintf(&text, "第%s条　第%d条的正文内容。\n", []string{"一", "二", "三", "四", "五", "六", "七"}[i-1], i)
+	}
+	doc := &model.Document{
+		ID:      uuid.New().String(),
+		Name:    "测试法",
+		LawName: "测试法",
+		Type:    model.DocTypeTXT,
+		Status:  model.DocStatusProcessing,
+	}
+	docRepo := repository.NewDocumentRepository()
+	if err := docRepo.Create(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := docRepo.ReplaceChunks(ctx, doc.ID, chunker.Split(text.String(), chunker.Options{DocumentID: doc.ID, LawName: doc.LawName})); err != nil {
+		t.Fatal(err)
+	}
+
+	versionSvc := NewVersionService(nil)
+	version, err := versionSvc.CreateVersion(ctx, &dto.CreateVersionRequest{Description: "v1"})
+	if err != nil {
+		t.Fatalf("CreateVersion() error = %v", err)
+	}
+	txHash := "0x" + strings.Repeat("ab", 32)
+	if _, err := versionSvc.ConfirmVersion(ctx, version.ID, &dto.ConfirmVersionRequest{TxHash: txHash, BlockNumber: 1}); err != nil {
+		t.Fatalf("ConfirmVersion() error = %v", err)
+	}
+
+	var chunks []model.DocumentChunk
+	if err := db.Order("id").Find(&chunks).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 7 {
+		t.Fatalf("chunks = %d, want 7", len(chunks))
+	}
+	for _, c := range chunks {
+		if c.VersionID != version.ID || len(c.MerkleProof) == 0 {
+			t.Fatalf("分块 %s 未回填版本证明", c.ChunkID)
+		}
+	}
+	return chunks, fakeRoots{int64(version.ID): version.MerkleRoot}
+}
+
+// newTestIntegrityService 创建每批验证 batchSize 个分块的审计服务
+func newTestIntegrityService(roots fakeRoots, batchSize int) *integrityService {
+	cfg := &config.AuditConfig{BatchSize: batchSize, LockTTL: time.Minute}
+	return NewIntegrityService(cfg, nodes.NewVerifier(roots)).(*integrityService)
+}
+
+// latestReport 查询最新的审计报告
+func latestReport(t *testing.T) *dto.IntegrityReportResponse {
+	t.Helper()
+	var report model.IntegrityReport
+	if err := database.DB().Order("id DESC").First(&report).Error; err != nil {
+		t.Fatal(err)
+	}
+	return toIntegrityReportResponse(&report)
+}
+
+func TestIntegrityDetectsTamper(t *testing.T) {
+	ctx := context.Background()
+	chunks, roots := setupIntegrity(t)
+	db := database.DB()
+
+	// 绕过业务流程直接修改数据库: 一个分块改内容，一个分块改证明
+	contentTampered, proofTampered := chunks[1], chunks[4]
+	if err := db.Model(&model.DocumentChunk{}).Where("id = ?", contentTampered.ID).
+		Update("content", contentTampered.Content+"（已篡改）").Error; err != nil {
+		t.Fatal(err)
+	}
+	forged := `["0x` + strings.Repeat("0", 64) + `"]`
+	if err := db.Model(&model.DocumentChunk{}).Where("id = ?", proofTampered.ID).
+		Update("merkle_proof", forged).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	newTestIntegrityService(roots, 3).runScheduled(ctx)
+
+	report := latestReport(t)
+	if report.Status != string(model.IntegrityCompleted) {
+		t.Fatalf("report status = %s (%s), want completed", report.Status, report.LastError)
+	}
+	if report.TotalChunks != 7 || report.Checked != 7 || report.Verified != 5 || report.Tampered != 2 || report.Unverified != 0 {
+		t.Errorf("report = total %d checked %d verified %d tampered %d unverified %d, want 7/7/5/2/0",
+			report.TotalChunks, report.Checked, report.Verified, report.Tampered, report.Unverified)
+	}
+	if report.FailReasons[reasonTampered] != 2 {
+		t.Errorf("failReasons = %v", report.FailReasons)
+	}
+	found := map[string]bool{}
+	for _, f := range report.Findings {
+		if f.Tampered {
+			found[f.ChunkID] = true
+		}
+	}
+	if !found[contentTampered.ChunkID] || !found[proofTampered.ChunkID] || len(found) != 2 {
+		t.Errorf("findings = %+v, want %s and %s", report.Findings, contentTampered.ChunkID, proofTampered.ChunkID)
+	}
+
+	// 每个分块一条 scheduled 验证记录，篡改的分块未通过
+	var records []model.ProofRecord
+	if err := db.Where("trigger_type = ?", model.TriggerScheduled).Order("id").Find(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 7 {
+		t.Fatalf("scheduled proof records = %d, want 7", len(records))
+	}
+	for _, r := range records {
+		tampered := r.ChunkID == contentTampered.ChunkID || r.ChunkID == proofTampered.ChunkID
+		if r.Verified == tampered {
+			t.Errorf("record %s verified = %v", r.ChunkID, r.Verified)
+		}
+		if tampered && (r.FailReason != reasonTampered || r.OnChainRoot != roots[int64(r.VersionID)]) {
+			t.Errorf("record %s = %+v, want tampered against on-chain root", r.ChunkID, r)
+		}
+		if !strings.HasPrefix(r.LeafHash, "0x") || len(r.LeafHash) != 66 || len(r.ContentHash) != 64 {
+			t.Errorf("record %s leafHash = %q, contentHash = %q", r.ChunkID, r.LeafHash, r.ContentHash)
+		}
+	}
+
+	// 每个篡改分块一条高危审计日志
+	var audits []model.AuditLog
+	if err := db.Where("type = ?", model.AuditTamper).Find(&audits).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(audits) != 2 {
+		t.Fatalf("tamper audit logs = %d, want 2", len(audits))
+	}
+	for _, a := range audits {
+		if a.Severity != model.SeverityHigh || a.SourceType != "chunk" || (a.Source != contentTampered.ChunkID && a.Source != proofTampered.ChunkID) {
+			t.Errorf("audit log = %+v", a)
+		}
+	}
+
+	// 未处理的告警不重复写入
+	newTestIntegrityService(roots, 3).runScheduled(ctx)
+	var count int64
+	db.Model(&model.AuditLog{}).Where("type = ?", model.AuditTamper).Count(&count)
+	if count != 2 {
+		t.Errorf("tamper audit logs after second run = %d, want 2", count)
+	}
+}
+
+func TestIntegrityResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	chunks, roots := setupIntegrity(t)
+	db := database.DB()
+
+	// 上次审计验证完前 3 个分块后中断 (其中 1 个被判定篡改)
+	interrupted := &model.IntegrityReport{
+		Status:       model.IntegrityRunning,
+		TotalChunks:  7,
+		VersionCount: 1,
+		Cursor:       chunks[2].ID,
+		Checked:      3,
+		Verified:     2,
+		Tampered:     1,
+		FailReasons:  []byte(`{"` + reasonTampered + `":1}`),
+		Findings:     []byte(`[{"chunkId":"` + chunks[0].ChunkID + `","tampered":true,"reason":"` + reasonTampered + `"}]`),
+		LastError:    "查询待审计分块失败: connection reset",
+	}
+	if err := db.Create(interrupted).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 检查点之后的分块被篡改
+	if err := db.Model(&model.DocumentChunk{}).Where("id = ?", chunks[5].ID).
+		Update("content", "篡改后的内容").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	newTestIntegrityService(roots, 2).runScheduled(ctx)
+
+	report := latestReport(t)
+	if report.ID != interrupted.ID {
+		t.Fatalf("report id = %d, want resumed report %d", report.ID, interrupted.ID)
+	}
+	if report.Status != string(model.IntegrityCompleted) || report.Resumes != 1 || report.LastError != "" {
+		t.Errorf("report status = %s, resumes = %d, lastError = %q", report.Status, report.Resumes, report.LastError)
+	}
+	if report.Checked != 7 || report.Verified != 5 || report.Tampered != 2 {
+		t.Errorf("report = checked %d verified %d tampered %d, want 7/5/2", report.Checked, report.Verified, report.Tampered)
+	}
+	if report.FailReasons[reasonTampered] != 2 || len(report.Findings) != 2 || report.Findings[1].ChunkID != chunks[5].ChunkID {
+		t.Errorf("failReasons = %v, findings = %+v", report.FailReasons, report.Findings)
+	}
+
+	// 只验证检查点之后的分块
+	var records []model.ProofRecord
+	if err := db.Where("trigger_type = ?", model.TriggerScheduled).Order("id").Find(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || records[0].ChunkID != chunks[3].ChunkID {
+		t.Errorf("proof records = %d (first %s), want 4 starting at %s", len(records), records[0].ChunkID, chunks[3].ChunkID)
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/chunker"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCitationNotFound = errors.New("引用不存在")
+	ErrInvalidProof     = errors.New("Merkle 证明格式错误")
+)
+
+// 公开验证未通过原因 (Verifier 之外的情形)
+const (
+	reasonNoMatchingVersion = "未找到 Merkle Root 与证明一致的知识库版本"
+	reasonRootMismatch      = "链上 Merkle Root 与版本记录不一致"
+	reasonRootLookupFailed  = "链上 Merkle Root 查询失败"
+)
+
+// proofMethod 分块验证算法说明，与 pkg/merkle、chunker.ContentHash 保持一致
+var proofMethod = dto.ProofMethod{
+	ContentHash: "contentHash = SHA-256(text 的 UTF-8 字节)，64 位十六进制",
+	LeafHash:    "leafHash = keccak256(keccak256(contentHash 的 32 字节))",
+	NodeHash:    "node = keccak256(min(a, b) ‖ max(a, b))，由 leafHash 依次与 merkleProof 各项折叠得到 computedRoot",
+	RootLookup:  "在 chainId 对应的链上调用 contractAddress 的 getRoot(uint256 versionId) → bytes32，与 computedRoot 相等即通过；等价于 OpenZeppelin MerkleProof.verify(merkleProof, root, leafHash)",
+}
+
+// versionProofMethod 版本验证方法说明
+const versionProofMethod = "在 chainId 对应的链上调用 contractAddress 的 getRoot(uint256 versionId) → bytes32，与 merkleRoot 相等即通过；txHash 交易中的 RootAnchored(versionId, root) 事件为存证记录"
+
+// ProofService 公开验证服务接口
+// 面向法官、对方律师等外部方，无需登录即可复核引用与分块的链上存证
+type ProofService interface {
+	// VerifyCitation 重新验证消息引用的文本快照，返回完整的证明路径
+	VerifyCitation(ctx context.Context, id int64) (*dto.CitationProofResponse, error)
+	// VerifyChunk 验证外部提交的文本与证明是否属于链上存证的版本
+	VerifyChunk(ctx context.Context, req *dto.VerifyChunkRequest) (*dto.ChunkProof, error)
+	// VerifyVersion 比对版本记录与链上存证的 Merkle Root
+	VerifyVersion(ctx context.Context, id int) (*dto.VersionProofResponse, error)
+}
+
+// proofService 公开验证服务实现
+type proofService struct {
+	verifier    *nodes.Verifier
+	chatRepo    repository.ChatRepository
+	versionRepo repository.VersionRepository
+	// chain 区块链客户端，为 nil 时 (未启用区块链) 所有验证均不通过
+	chain *client.BlockchainClient
+}
+
+// NewProofService 创建公开验证服务
+// 与问答验证共用 Verifier (链上 Root 缓存)
+func NewProofService(verifier *nodes.Verifier, chain *client.BlockchainClient) ProofService {
+	return &proofService{
+		verifier:    verifier,
+		chatRepo:    repository.NewChatRepository(),
+		versionRepo: repository.NewVersionRepository(),
+		chain:       chain,
+	}
+}
+
+// VerifyCitation 验证消息引用
+// 以引用保存的文本快照为准重新计算哈希，证明取自验证时所属版本的冻结快照；
+// 结果以 manual 触发来源写入 ProofRecord，快照与链上存证不一致时产生篡改告警
+func (s *proofService) VerifyCitation(ctx context.Context, id int64) (*dto.CitationProofResponse, error) {
+	citation, err := s.chatRepo.FindCitationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCitationNotFound) {
+			return nil, ErrCitationNotFound
+		}
+		return nil, err
+	}
+
+	snapshot, err := findCitationSnapshot(ctx, s.versionRepo, citation)
+	if err != nil {
+		return nil, err
+	}
+
+	in := nodes.VerifyInput{
+		ChunkID:   citation.ChunkID,
+		VersionID: citation.VersionID,
+		Content:   citation.Text,
+	}
+	var index *int
+	if snapshot != nil {
+		in.DocumentID = snapshot.DocumentID
+		in.VersionID = snapshot.VersionID
+		if len(snapshot.MerkleProof) > 0 {
+			_ = json.Unmarshal(snapshot.MerkleProof, &in.MerkleProof)
+		}
+		index = &snapshot.MerkleIndex
+	}
+
+	results, err := s.verifier.Verify(ctx, nodes.Trigger{Type: model.TriggerManual}, []nodes.VerifyInput{in})
+	if err != nil {
+		logger.Warn("引用公开验证记录写入失败", zap.Int64("citation_id", id), zap.Error(err))
+	}
+
+	proof := s.newChunkProof(&results[0], in.MerkleProof)
+	proof.MerkleIndex = index
+	return &dto.CitationProofResponse{
+		CitationID:    citation.ID,
+		ChunkID:       citation.ChunkID,
+		Text:          citation.Text,
+		Source:        citation.Source,
+		ArticleNumber: citation.ArticleNumber,
+		LawHierarchy:  citation.LawHierarchy,
+		ChunkProof:    *proof,
+	}, nil
+}
+
+// findCitationSnapshot 查询引用分块的版本快照，分块未纳入版本时返回 nil
+// 早期引用未记录版本，按验证时的内容哈希匹配最新的已确认版本
+func findCitationSnapshot(ctx context.Context, versionRepo repository.VersionRepository, citation *model.MessageCitation) (*model.VersionChunk, error) {
+	var (
+		snapshot *model.VersionChunk
+		err      error
+	)
+	if citation.VersionID != 0 {
+		snapshot, err = versionRepo.FindChunk(ctx, citation.VersionID, citation.ChunkID)
+	} else {
+		snapshot, err = versionRepo.FindLatestChunkByHash(ctx, citation.ChunkID, citation.ChunkHash)
+	}
+	if errors.Is(err, repository.ErrVersionChunkNotFound) {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// VerifyChunk 验证外部提交的分块
+// 未指定版本时按证明计算出的 Root 匹配版本；外部输入不写入验证记录
+func (s *proofService) VerifyChunk(ctx context.Context, req *dto.VerifyChunkRequest) (*dto.ChunkProof, error) {
+	hashes, err := merkle.ParseProof(req.MerkleProof)
+	if err != nil {
+		return nil, ErrInvalidProof
+	}
+
+	versionID := req.VersionID
+	if versionID == 0 {
+		contentHash := chunker.ContentHash(req.Text)
+		leaf, err := merkle.ContentLeaf(contentHash)
+		if err != nil {
+			return nil, err
+		}
+		computed := merkle.ComputeRoot(hashes, leaf)
+
+		version, err := s.versionRepo.FindByRoot(ctx, computed.Hex())
+		if err != nil {
+			if !errors.Is(err, repository.ErrVersionNotFound) {
+				return nil, err
+			}
+			proof := s.newChunkProof(&nodes.VerifyResult{
+				ChunkHash:    contentHash,
+				ComputedRoot: computed.Hex(),
+				FailReason:   reasonNoMatchingVersion,
+			}, req.MerkleProof)
+			return proof, nil
+		}
+		versionID = version.ID
+	}
+
+	res := s.verifier.Check(ctx, nodes.VerifyInput{
+		VersionID:   versionID,
+		Content:     req.Text,
+		MerkleProof: req.MerkleProof,
+	})
+	return s.newChunkProof(&res, req.MerkleProof), nil
+}
+
+// VerifyVersion 验证版本存证
+func (s *proofService) VerifyVersion(ctx context.Context, id int) (*dto.VersionProofResponse, error) {
+	version, err := s.versionRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	resp := &dto.VersionProofResponse{
+		VersionID:   version.ID,
+		MerkleRoot:  version.MerkleRoot,
+		ChunkCount:  version.ChunkCount,
+		Status:      string(version.Status),
+		TxHash:      version.TxHash,
+		BlockNumber: version.BlockNumber,
+		Chain:       s.chainInfo(),
+		Method:      versionProofMethod,
+		CheckedAt:   time.Now(),
+	}
+	if s.chain == nil {
+		resp.FailReason = ErrBlockchainDisabled.Error()
+		return resp, nil
+	}
+
+	root, err := s.chain.GetMerkleRoot(ctx, int64(version.ID))
+	switch {
+	case errors.Is(err, client.ErrRootNotAnchored):
+		resp.FailReason = err.Error()
+	case err != nil:
+		logger.Warn("查询链上 Merkle Root 失败", zap.Int("version_id", version.ID), zap.Error(err))
+		resp.FailReason = reasonRootLookupFailed
+	case root != version.MerkleRoot:
+		resp.AnchoredRoot = root
+		resp.FailReason = reasonRootMismatch
+	default:
+		resp.AnchoredRoot = root
+		resp.Verified = true
+	}
+	return resp, nil
+}
+
+// newChunkProof 由验证结果生成证明响应
+func (s *proofService) newChunkProof(res *nodes.VerifyResult, proof []string) *dto.ChunkProof {
+	if proof == nil {
+		proof = []string{}
+	}
+	out := &dto.ChunkProof{
+		ContentHash:  res.ChunkHash,
+		LeafHash:     res.LeafHash,
+		MerkleProof:  proof,
+		ComputedRoot: res.ComputedRoot,
+		VersionID:    res.VersionID,
+		AnchoredRoot: res.OnChainRoot,
+		TxHash:       res.TxHash,
+		BlockNumber:  res.BlockNumber,
+		Chain:        s.chainInfo(),
+		Verified:     res.Verified,
+		FailReason:   res.FailReason,
+		Method:       proofMethod,
+		CheckedAt:    time.Now(),
+	}
+	return out
+}
+
+// chainInfo 存证链与合约信息
+func (s *proofService) chainInfo() dto.ChainInfo {
+	if s.chain == nil {
+		return dto.ChainInfo{}
+	}
+	return dto.ChainInfo{
+		ChainID:         s.chain.ChainID(),
+		ContractAddress: s.chain.ContractAddress(),
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/graph/nodes"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSystemConfigNotFound = errors.New("配置项不存在")
+	ErrInvalidConfigValue   = errors.New("配置值与配置类型不匹配")
+	ErrUnknownPromptVersion = errors.New("提示词模板版本不存在")
+)
+
+// systemConfigCacheTTL 运行时配置本地缓存时间，多实例部署时修改在该时间内生效
+const systemConfigCacheTTL = 30 * time.Second
+
+// SystemConfigService 系统配置服务接口
+type SystemConfigService interface {
+	// List 列出配置项 (含尚未写入数据库的默认配置项)
+	List(ctx context.Context, category string) ([]dto.SystemConfigResponse, error)
+	// Update 更新配置值，值需符合配置类型
+	Update(ctx context.Context, key string, req *dto.UpdateSystemConfigRequest, updatedBy string) (*dto.SystemConfigResponse, error)
+
+	// RefusalThresholds 读取 retrieval 分类下的拒答阈值
+	RefusalThresholds(ctx context.Context) nodes.RefusalThresholds
+	// PromptSettings 读取 prompt 分类下的提示词配置
+	PromptSettings(ctx context.Context) nodes.PromptSettings
+}
+
+// systemConfigService 系统配置服务实现
+type systemConfigService struct {
+	repo     repository.SystemConfigRepository
+	defaults map[string]model.SystemConfig
+
+	mu       sync.Mutex
+	values   map[string]string
+	loadedAt time.Time
+}
+
+// NewSystemConfigService 创建系统配置服务
+func NewSystemConfigService() SystemConfigService {
+	defaults := make(map[string]model.SystemConfig)
+	for _, cfg := range model.DefaultSystemConfigs() {
+		defaults[cfg.Key] = cfg
+	}
+	return &systemConfigService{
+		repo:     repository.NewSystemConfigRepository(),
+		defaults: defaults,
+	}
+}
+
+// List 列出配置项
+func (s *systemConfigService) List(ctx context.Context, category string) ([]dto.SystemConfigResponse, error) {
+	configs, err := s.repo.List(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]bool, len(configs))
+	list := make([]dto.SystemConfigResponse, 0, len(configs)+len(s.defaults))
+	for i := range configs {
+		stored[configs[i].Key] = true
+		list = append(list, toSystemConfigResponse(&configs[i]))
+	}
+	for _, cfg := range model.DefaultSystemConfigs() {
+		if stored[cfg.Key] || (category != "" && cfg.Category != category) {
+			continue
+		}
+		list = append(list, toSystemConfigResponse(&cfg))
+	}
+	return list, nil
+}
+
+// Update 更新配置值
+func (s *systemConfigService) Update(ctx context.Context, key string, req *dto.UpdateSystemConfigRequest, updatedBy string) (*dto.SystemConfigResponse, error) {
+	cfg, err := s.repo.FindByKey(ctx, key)
+	stored := err == nil
+	if err != nil {
+		if !errors.Is(err, repository.ErrSystemConfigNotFound) {
+			return nil, err
+		}
+		def, ok := s.defaults[key]
+		if !ok {
+			return nil, ErrSystemConfigNotFound
+		}
+		cfg = &def
+	}
+
+	value := strings.TrimSpace(req.Value)
+	if !validConfigValue(cfg.Type, value) {
+		return nil, ErrInvalidConfigValue
+	}
+	if key == model.ConfigPromptVersion && !nodes.HasPromptVersion(value) {
+		return nil, ErrUnknownPromptVersion
+	}
+	// 默认配置项尚未写入数据库时先写入默认值
+	if !stored {
+		if err := s.repo.CreateMissing(ctx, []model.SystemConfig{*cfg}); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.UpdateValue(ctx, key, value, updatedBy); err != nil {
+		if errors.Is(err, repository.ErrSystemConfigNotFound) {
+			return nil, ErrSystemConfigNotFound
+		}
+		return nil, err
+	}
+	s.invalidate()
+
+	logger.Info("系统配置已更新",
+		zap.String("key", key),
+		zap.String("value", value),
+		zap.String("updated_by", updatedBy),
+	)
+	cfg.Value = value
+	cfg.UpdatedBy = updatedBy
+	cfg.UpdatedAt = time.Now()
+	resp := toSystemConfigResponse(cfg)
+	return &resp, nil
+}
+
+// RefusalThresholds 读取拒答阈值，配置缺失或格式错误时使用默认值
+func (s *systemConfigService) RefusalThresholds(ctx context.Context) nodes.RefusalThresholds {
+	values := s.load(ctx)
+	t := nodes.DefaultRefusalThresholds()
+	if v, ok := parseBool(values[model.ConfigRefusalEnabled]); ok {
+		t.Enabled = v
+	}
+	if v, ok := parseFloat(values[model.ConfigMinScore]); ok {
+		t.MinScore = v
+	}
+	if v, ok := parseFloat(values[model.ConfigOutOfScopeScore]); ok {
+		t.OutOfScopeScore = v
+	}
+	if v, ok := parseBool(values[model.ConfigRequireVerified]); ok {
+		t.RequireVerified = v
+	}
+	if v, err := strconv.Atoi(values[model.ConfigRefusalCandidates]); err == nil && v >= 0 {
+		t.Candidates = v
+	}
+	return t
+}
+
+// PromptSettings 读取提示词配置，配置缺失或格式错误时使用默认值
+func (s *systemConfigService) PromptSettings(ctx context.Context) nodes.PromptSettings {
+	values := s.load(ctx)
+	settings := nodes.DefaultPromptSettings()
+	if v := values[model.ConfigPromptVersion]; v != "" {
+		settings.Version = v
+	}
+	if v, err := strconv.Atoi(values[model.ConfigPromptTokenBudget]); err == nil && v >= 0 {
+		settings.TokenBudget = v
+	}
+	if v, err := strconv.Atoi(values[model.ConfigPromptHistory]); err == nil && v >= 0 {
+		settings.HistoryMaxTokens = v
+	}
+	return settings
+}
+
+// load 读取全部配置值 (带本地缓存)，读取失败时沿用上次结果
+func (s *systemConfigService) load(ctx context.Context) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values != nil && time.Since(s.loadedAt) < systemConfigCacheTTL {
+		return s.values
+	}
+
+	configs, err := s.repo.List(ctx, "")
+	if err != nil {
+		logger.Warn("读取系统配置失败，使用上次配置或默认值", zap.Error(err))
+		if s.values == nil {
+			return map[string]string{}
+		}
+		return s.values
+	}
+
+	values := make(map[string]string, len(configs))
+	for _, cfg := range configs {
+		values[cfg.Key] = cfg.Value
+	}
+	s.values = values
+	s.loadedAt = time.Now()
+	return values
+}
+
+// invalidate 清除本地缓存
+func (s *systemConfigService) invalidate() {
+	s.mu.Lock()
+	s.values = nil
+	s.mu.Unlock()
+}
+
+// validConfigValue 校验配置值是否符合类型
+func validConfigValue(typ, value string) bool {
+	switch typ {
+	case model.ConfigTypeInt:
+		_, err := strconv.Atoi(value)
+		return err == nil
+	case model.ConfigTypeFloat:
+		_, ok := parseFloat(value)
+		return ok
+	case model.ConfigTypeBool:
+		_, ok := parseBool(value)
+		return ok
+	case model.ConfigTypeJSON:
+		return json.Valid([]byte(value))
+	default:
+		return true
+	}
+}
+
+// parseBool 解析布尔配置值
+func parseBool(value string) (bool, bool) {
+	v, err := strconv.ParseBool(value)
+	return v, err == nil
+}
+
+// parseFloat 解析浮点配置值
+func parseFloat(value string) (float32, bool) {
+	v, err := strconv.ParseFloat(value, 32)
+	return float32(v), err == nil
+}
+
+// toSystemConfigResponse 转换系统配置响应
+func toSystemConfigResponse(cfg *model.SystemConfig) dto.SystemConfigResponse {
+	return dto.SystemConfigResponse{
+		Key:         cfg.Key,
+		Value:       cfg.Value,
+		Type:        cfg.Type,
+		Category:    cfg.Category,
+		Description: cfg.Description,
+		UpdatedBy:   cfg.UpdatedBy,
+		UpdatedAt:   cfg.UpdatedAt,
+	}
+}
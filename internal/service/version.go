@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/merkle"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrVersionNotFound   = errors.New("知识库版本不存在")
+	ErrVersionNotPending = errors.New("仅待确认的版本可以确认上链")
+	ErrVersionUnchanged  = errors.New("知识库内容与已有版本相同，无需创建新版本")
+	ErrNoIndexedChunks   = errors.New("没有已索引的文档，无法创建版本")
+
+	ErrBlockchainDisabled = errors.New("未启用区块链存证")
+	ErrAnchorRootMismatch = errors.New("该版本 ID 在链上已存证了不同的 Merkle Root")
+	ErrAnchorFailed       = errors.New("提交上链交易失败，请稍后重试")
+	ErrAnchorTxInvalid    = errors.New("上链交易不存在、未达到确认数或未存证该版本")
+	ErrChainQueryFailed   = errors.New("查询链上存证失败，请稍后重试")
+)
+
+// versionListDefaultPageSize 版本列表默认每页数量
+const versionListDefaultPageSize = 10
+
+// VersionService 知识库版本服务接口
+type VersionService interface {
+	// CreateVersion 冻结当前已索引文档的分块集合，计算 Merkle Root 与分块证明 (版本确认后回填至分块)
+	CreateVersion(ctx context.Context, req *dto.CreateVersionRequest) (*dto.VersionResponse, error)
+	// ConfirmVersion 版本 Merkle Root 上链后确认版本
+	// 启用区块链时核对交易回执与链上 Root，未启用时仅登记交易信息
+	ConfirmVersion(ctx context.Context, id int, req *dto.ConfirmVersionRequest) (*dto.VersionResponse, error)
+	// AnchorVersion 提交版本 Merkle Root 上链，后台等待回执后确认版本
+	AnchorVersion(ctx context.Context, id int) (*dto.VersionResponse, error)
+	// Start 恢复已提交上链交易但尚未确认的版本
+	Start(ctx context.Context)
+
+	ListVersions(ctx context.Context, req *dto.VersionListRequest) (*dto.VersionListResponse, error)
+	GetVersion(ctx context.Context, id int) (*dto.VersionDetailResponse, error)
+	DiffVersions(ctx context.Context, req *dto.VersionDiffRequest) (*dto.VersionDiffResponse, error)
+}
+
+// versionService 知识库版本服务实现
+type versionService struct {
+	versionRepo repository.VersionRepository
+	// chain 区块链客户端，为 nil 时不支持自动上链
+	chain *client.BlockchainClient
+
+	// anchorMu 串行化上链提交，保护 anchoring 与 runCtx
+	anchorMu sync.Mutex
+	// anchoring 正在后台等待回执的版本
+	anchoring map[int]bool
+	// runCtx 后台等待回执的生命周期
+	runCtx context.Context
+}
+
+// NewVersionService 创建知识库版本服务
+// chain 为 nil 时仍可通过 ConfirmVersion 手动登记上链交易 (不做链上核对)
+func NewVersionService(chain *client.BlockchainClient) VersionService {
+	return &versionService{
+		versionRepo: repository.NewVersionRepository(),
+		chain:       chain,
+		anchoring:   make(map[int]bool),
+		runCtx:      context.Background(),
+	}
+}
+
+// CreateVersion 创建知识库版本
+func (s *versionService) CreateVersion(ctx context.Context, req *dto.CreateVersionRequest) (*dto.VersionResponse, error) {
+	chunks, err := s.versionRepo.ListSnapshotSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, ErrNoIndexedChunks
+	}
+
+	hashes := make([]string, len(chunks))
+	for i := range chunks {
+		hashes[i] = chunks[i].ContentHash
+	}
+	tree, err := merkle.FromContentHashes(hashes)
+	if err != nil {
+		return nil, fmt.Errorf("构建 Merkle 树失败: %w", err)
+	}
+	root := tree.Root().Hex()
+
+	// 内容未变化时 Merkle Root 相同，不重复创建
+	if _, err := s.versionRepo.FindByRoot(ctx, root); err == nil {
+		return nil, ErrVersionUnchanged
+	} else if !errors.Is(err, repository.ErrVersionNotFound) {
+		return nil, err
+	}
+
+	snapshot := make([]model.VersionChunk, len(chunks))
+	for i := range chunks {
+		proof, err := tree.ProofHex(i)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(proof)
+		if err != nil {
+			return nil, err
+		}
+		snapshot[i] = model.VersionChunk{
+			ChunkID:       chunks[i].ChunkID,
+			DocumentID:    chunks[i].DocumentID,
+			ArticleNumber: chunks[i].ArticleNumber,
+			ContentHash:   chunks[i].ContentHash,
+			MerkleIndex:   i,
+			MerkleProof:   datatypes.JSON(data),
+		}
+	}
+
+	version := &model.KnowledgeVersion{
+		MerkleRoot:  root,
+		ChunkCount:  len(snapshot),
+		Description: req.Description,
+		Status:      model.VersionStatusPending,
+	}
+	if err := s.versionRepo.CreateSnapshot(ctx, version, snapshot); err != nil {
+		if errors.Is(err, repository.ErrVersionRootConflict) {
+			return nil, ErrVersionUnchanged
+		}
+		return nil, err
+	}
+
+	logger.Info("知识库版本已创建",
+		zap.Int("version_id", version.ID),
+		zap.String("merkle_root", root),
+		zap.Int("chunks", version.ChunkCount),
+	)
+	return toVersionResponse(version), nil
+}
+
+// ConfirmVersion 确认版本上链
+func (s *versionService) ConfirmVersion(ctx context.Context, id int, req *dto.ConfirmVersionRequest) (*dto.VersionResponse, error) {
+	blockNumber := req.BlockNumber
+	if s.chain != nil {
+		anchor, err := s.verifyAnchorTx(ctx, id, req.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		blockNumber = anchor.BlockNumber
+	}
+
+	if err := s.versionRepo.Confirm(ctx, id, req.TxHash, blockNumber); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionNotFound):
+			return nil, ErrVersionNotFound
+		case errors.Is(err, repository.ErrVersionNotPending):
+			return nil, ErrVersionNotPending
+		}
+		return nil, err
+	}
+
+	version, err := s.findVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toVersionResponse(version), nil
+}
+
+// verifyAnchorTx 核对手动登记的上链交易: 交易回执中的存证事件与合约 getRoot 均须等于版本 Merkle Root
+// 区块号以回执为准
+func (s *versionService) verifyAnchorTx(ctx context.Context, id int, txHash string) (*client.Anchor, error) {
+	version, err := s.findVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != model.VersionStatusPending {
+		return nil, ErrVersionNotPending
+	}
+	log := logger.L().With(zap.Int("version_id", id), zap.String("tx_hash", txHash))
+
+	anchor, err := s.chain.AnchorInTx(ctx, txHash, int64(id))
+	if err != nil {
+		if errors.Is(err, client.ErrTxNotFound) || errors.Is(err, client.ErrTxPending) ||
+			errors.Is(err, client.ErrTxReverted) || errors.Is(err, client.ErrAnchorNotInTx) {
+			log.Warn("手动确认的上链交易无效", zap.Error(err))
+			return nil, ErrAnchorTxInvalid
+		}
+		log.Error("查询上链交易回执失败", zap.Error(err))
+		return nil, ErrChainQueryFailed
+	}
+	if !strings.EqualFold(anchor.Root, version.MerkleRoot) {
+		log.Error("交易存证的 Merkle Root 与版本不一致", zap.String("merkle_root", version.MerkleRoot), zap.String("tx_root", anchor.Root))
+		return nil, ErrAnchorRootMismatch
+	}
+
+	root, err := s.chain.GetMerkleRoot(ctx, int64(id))
+	if err != nil {
+		if errors.Is(err, client.ErrRootNotAnchored) {
+			return nil, ErrAnchorTxInvalid
+		}
+		log.Error("读取链上 Merkle Root 失败", zap.Error(err))
+		return nil, ErrChainQueryFailed
+	}
+	if !strings.EqualFold(root, version.MerkleRoot) {
+		log.Error("链上 Merkle Root 与版本不一致", zap.String("merkle_root", version.MerkleRoot), zap.String("on_chain_root", root))
+		return nil, ErrAnchorRootMismatch
+	}
+	return anchor, nil
+}
+
+// ListVersions 分页查询版本
+func (s *versionService) ListVersions(ctx context.Context, req *dto.VersionListRequest) (*dto.VersionListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = versionListDefaultPageSize
+	}
+
+	versions, total, err := s.versionRepo.List(ctx, req.Page, req.PageSize, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.VersionResponse, len(versions))
+	for i := range versions {
+		list[i] = *toVersionResponse(&versions[i])
+	}
+	return &dto.VersionListResponse{
+		List:  list,
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+	}, nil
+}
+
+// GetVersion 获取版本详情 (含文档列表)
+func (s *versionService) GetVersion(ctx context.Context, id int) (*dto.VersionDetailResponse, error) {
+	version, err := s.findVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.versionRepo.ListDocuments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.versionRepo.CountChunksByDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]dto.VersionDocumentResponse, len(docs))
+	for i, d := range docs {
+		documents[i] = dto.VersionDocumentResponse{
+			ID:         d.ID,
+			Name:       d.Name,
+			LawName:    d.LawName,
+			ChunkCount: counts[d.ID],
+			Deleted:    d.DeletedAt.Valid,
+		}
+	}
+	return &dto.VersionDetailResponse{
+		VersionResponse: *toVersionResponse(version),
+		Documents:       documents,
+	}, nil
+}
+
+// DiffVersions 对比两个版本的分块集合
+// 以 ChunkID 对齐: 仅在 to 中存在为新增，仅在 from 中存在为删除，内容哈希不同为修改
+func (s *versionService) DiffVersions(ctx context.Context, req *dto.VersionDiffRequest) (*dto.VersionDiffResponse, error) {
+	from, err := s.findVersion(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findVersion(ctx, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	fromChunks, err := s.versionRepo.ListChunks(ctx, from.ID)
+	if err != nil {
+		return nil, err
+	}
+	toChunks, err := s.versionRepo.ListChunks(ctx, to.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.VersionDiffResponse{
+		From:     from.ID,
+		To:       to.ID,
+		FromRoot: from.MerkleRoot,
+		ToRoot:   to.MerkleRoot,
+		Added:    []dto.VersionChunkDiff{},
+		Removed:  []dto.VersionChunkDiff{},
+		Modified: []dto.VersionChunkDiff{},
+	}
+
+	old := make(map[string]*model.VersionChunk, len(fromChunks))
+	for i := range fromChunks {
+		old[fromChunks[i].ChunkID] = &fromChunks[i]
+	}
+	for i := range toChunks {
+		c := &toChunks[i]
+		prev, ok := old[c.ChunkID]
+		switch {
+		case !ok:
+			resp.Added = append(resp.Added, dto.VersionChunkDiff{
+				ChunkID: c.ChunkID, DocumentID: c.DocumentID, ArticleNumber: c.ArticleNumber, NewHash: c.ContentHash,
+			})
+		case prev.ContentHash != c.ContentHash:
+			resp.Modified = append(resp.Modified, dto.VersionChunkDiff{
+				ChunkID: c.ChunkID, DocumentID: c.DocumentID, ArticleNumber: c.ArticleNumber,
+				OldHash: prev.ContentHash, NewHash: c.ContentHash,
+			})
+		default:
+			resp.Unchanged++
+		}
+		delete(old, c.ChunkID)
+	}
+	for _, c := range old {
+		resp.Removed = append(resp.Removed, dto.VersionChunkDiff{
+			ChunkID: c.ChunkID, DocumentID: c.DocumentID, ArticleNumber: c.ArticleNumber, OldHash: c.ContentHash,
+		})
+	}
+	sort.Slice(resp.Removed, func(i, j int) bool { return resp.Removed[i].ChunkID < resp.Removed[j].ChunkID })
+
+	return resp, nil
+}
+
+// findVersion 查询版本并转换数据访问层错误
+func (s *versionService) findVersion(ctx context.Context, id int) (*model.KnowledgeVersion, error) {
+	version, err := s.versionRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return version, nil
+}
+
+// toVersionResponse 转换为版本响应
+func toVersionResponse(v *model.KnowledgeVersion) *dto.VersionResponse {
+	return &dto.VersionResponse{
+		ID:          v.ID,
+		MerkleRoot:  v.MerkleRoot,
+		ChunkCount:  v.ChunkCount,
+		Description: v.Description,
+		TxHash:      v.TxHash,
+		BlockNumber: v.BlockNumber,
+		Status:      string(v.Status),
+		CreatedAt:   v.CreatedAt,
+		ConfirmedAt: v.ConfirmedAt,
+	}
+}
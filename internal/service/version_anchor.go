@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/lexveritas/lex-veritas-backend/internal/client"
+	"github.com/lexveritas/lex-veritas-backend/internal/dto"
+	"github.com/lexveritas/lex-veritas-backend/internal/model"
+	"github.com/lexveritas/lex-veritas-backend/internal/pkg/logger"
+	"github.com/lexveritas/lex-veritas-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// anchorMaxBumps 单个版本最多提高费用替换交易的次数，超过后只继续等待
+const anchorMaxBumps = 5
+
+// AnchorVersion 提交版本 Merkle Root 上链
+// 交易发出后立即返回 (版本仍为 pending 并带 TxHash)，由后台等待回执后确认；
+// 链上已存证该版本时 (如上次提交后服务中断) 直接找回交易并确认
+func (s *versionService) AnchorVersion(ctx context.Context, id int) (*dto.VersionResponse, error) {
+	if s.chain == nil {
+		return nil, ErrBlockchainDisabled
+	}
+
+	s.anchorMu.Lock()
+	defer s.anchorMu.Unlock()
+
+	version, err := s.findVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != model.VersionStatusPending {
+		return nil, ErrVersionNotPending
+	}
+	// 已提交且正在等待回执
+	if s.anchoring[id] {
+		return toVersionResponse(version), nil
+	}
+
+	anchor, err := s.findAnchor(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	if anchor != nil {
+		s.confirmAnchor(ctx, id, anchor.TxHash, anchor.BlockNumber)
+		return s.reloadVersion(ctx, id)
+	}
+
+	txHash, err := s.chain.SubmitRoot(ctx, int64(id), version.MerkleRoot)
+	if err != nil {
+		logger.Error("提交版本上链交易失败", zap.Int("version_id", id), zap.Error(err))
+		return nil, ErrAnchorFailed
+	}
+	if err := s.versionRepo.SetTxHash(ctx, id, txHash); err != nil {
+		// 交易已发出，重启后无法从数据库恢复，但可通过链上事件找回
+		logger.Error("记录上链交易失败", zap.Int("version_id", id), zap.String("tx_hash", txHash), zap.Error(err))
+	}
+	version.TxHash = txHash
+
+	logger.Info("版本上链交易已提交",
+		zap.Int("version_id", id),
+		zap.String("merkle_root", version.MerkleRoot),
+		zap.String("tx_hash", txHash),
+	)
+	s.watchLocked(id, version.MerkleRoot, txHash)
+	return toVersionResponse(version), nil
+}
+
+// Start 恢复上次运行中已提交上链交易但尚未确认的版本
+func (s *versionService) Start(ctx context.Context) {
+	if s.chain == nil {
+		return
+	}
+
+	s.anchorMu.Lock()
+	defer s.anchorMu.Unlock()
+	s.runCtx = ctx
+
+	versions, err := s.versionRepo.ListAnchoring(ctx)
+	if err != nil {
+		logger.Warn("恢复待确认的上链交易失败", zap.Error(err))
+		return
+	}
+	for i := range versions {
+		if !s.anchoring[versions[i].ID] {
+			s.watchLocked(versions[i].ID, versions[i].MerkleRoot, versions[i].TxHash)
+		}
+	}
+	if len(versions) > 0 {
+		logger.Info("恢复待确认的上链交易", zap.Int("count", len(versions)))
+	}
+}
+
+// watchLocked 启动后台回执等待，调用方需持有 anchorMu
+func (s *versionService) watchLocked(id int, root, txHash string) {
+	s.anchoring[id] = true
+	go s.awaitAnchor(s.runCtx, id, root, txHash)
+}
+
+// awaitAnchor 等待上链交易回执并确认版本
+// 超时未打包时以更高费用替换交易，交易被节点丢弃时重新提交；
+// 服务关闭时直接退出，版本保留 TxHash，重启后由 Start 继续
+func (s *versionService) awaitAnchor(ctx context.Context, id int, root, txHash string) {
+	defer func() {
+		s.anchorMu.Lock()
+		delete(s.anchoring, id)
+		s.anchorMu.Unlock()
+	}()
+
+	log := logger.L().With(zap.Int("version_id", id))
+	bumps := 0
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, s.chain.ReceiptTimeout())
+		receipt, err := s.chain.WaitReceipt(waitCtx, txHash)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			s.confirmAnchor(ctx, id, receipt.TxHash, receipt.BlockNumber)
+			return
+		}
+
+		// 被替换的旧交易或重发前的交易可能已完成存证
+		anchor, findErr := s.findAnchor(ctx, &model.KnowledgeVersion{ID: id, MerkleRoot: root})
+		if errors.Is(findErr, ErrAnchorRootMismatch) {
+			log.Error("版本上链失败", zap.Error(findErr))
+			return
+		}
+		if anchor != nil {
+			s.confirmAnchor(ctx, id, anchor.TxHash, anchor.BlockNumber)
+			return
+		}
+
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			if bumps >= anchorMaxBumps {
+				log.Warn("上链交易长时间未打包，继续等待", zap.String("tx_hash", txHash))
+				continue
+			}
+			newHash, bumpErr := s.chain.BumpFee(ctx, txHash)
+			if bumpErr != nil {
+				log.Warn("替换上链交易失败，继续等待原交易", zap.String("tx_hash", txHash), zap.Error(bumpErr))
+				continue
+			}
+			bumps++
+			log.Info("上链交易超时未打包，已提高费用替换", zap.String("old_tx_hash", txHash), zap.String("tx_hash", newHash))
+			txHash = s.replaceTxHash(ctx, id, newHash)
+
+		case errors.Is(err, client.ErrTxNotFound):
+			newHash, submitErr := s.chain.SubmitRoot(ctx, int64(id), root)
+			if submitErr != nil {
+				log.Error("重新提交上链交易失败", zap.Error(submitErr))
+				return
+			}
+			log.Info("上链交易已被节点丢弃，已重新提交", zap.String("old_tx_hash", txHash), zap.String("tx_hash", newHash))
+			txHash = s.replaceTxHash(ctx, id, newHash)
+
+		default:
+			log.Error("上链交易执行失败", zap.String("tx_hash", txHash), zap.Error(err))
+			return
+		}
+	}
+}
+
+// findAnchor 查询版本在链上的存证，未存证时返回 nil
+func (s *versionService) findAnchor(ctx context.Context, version *model.KnowledgeVersion) (*client.Anchor, error) {
+	root, err := s.chain.GetMerkleRoot(ctx, int64(version.ID))
+	if errors.Is(err, client.ErrRootNotAnchored) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("读取链上 Merkle Root 失败", zap.Int("version_id", version.ID), zap.Error(err))
+		return nil, ErrAnchorFailed
+	}
+	if !strings.EqualFold(root, version.MerkleRoot) {
+		logger.Error("链上 Merkle Root 与版本不一致",
+			zap.Int("version_id", version.ID),
+			zap.String("merkle_root", version.MerkleRoot),
+			zap.String("on_chain_root", root),
+		)
+		return nil, ErrAnchorRootMismatch
+	}
+
+	anchor, err := s.chain.FindAnchor(ctx, int64(version.ID))
+	if err != nil {
+		logger.Error("查询链上存证事件失败", zap.Int("version_id", version.ID), zap.Error(err))
+		return nil, ErrAnchorFailed
+	}
+	return anchor, nil
+}
+
+// replaceTxHash 记录替换后的交易哈希，返回新哈希
+func (s *versionService) replaceTxHash(ctx context.Context, id int, txHash string) string {
+	if err := s.versionRepo.SetTxHash(ctx, id, txHash); err != nil {
+		logger.Warn("记录上链交易失败", zap.Int("version_id", id), zap.String("tx_hash", txHash), zap.Error(err))
+	}
+	return txHash
+}
+
+// confirmAnchor 按链上交易确认版本，版本已被手动确认时忽略
+func (s *versionService) confirmAnchor(ctx context.Context, id int, txHash string, blockNumber int64) {
+	err := s.versionRepo.Confirm(ctx, id, txHash, blockNumber)
+	if err != nil && !errors.Is(err, repository.ErrVersionNotPending) {
+		logger.Error("确认版本上链失败", zap.Int("version_id", id), zap.String("tx_hash", txHash), zap.Error(err))
+		return
+	}
+	logger.Info("版本已上链确认",
+		zap.Int("version_id", id),
+		zap.String("tx_hash", txHash),
+		zap.Int64("block_number", blockNumber),
+	)
+}
+
+// reloadVersion 重新查询版本并转换为响应
+func (s *versionService) reloadVersion(ctx context.Context, id int) (*dto.VersionResponse, error) {
+	version, err := s.findVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toVersionResponse(version), nil
+}